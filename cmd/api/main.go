@@ -106,6 +106,9 @@ func main() {
 	if err := httpServer.Shutdown(ctx); err != nil {
 		log.Fatalf("Could not gracefully shutdown the server: %v\n", err)
 	}
+	if err := srv.Shutdown(); err != nil {
+		log.Printf("Error during shutdown: %v", err)
+	}
 	log.Println("Server stopped")
 }
 
@@ -10,6 +10,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"time"
 
@@ -201,7 +202,8 @@ func (s *service) Ping() error {
 	return s.db.PingContext(ctx)
 }
 
-// Migrate runs database migrations
+// Migrate runs database migrations. Up migrations run in file order and skip the files recorded
+// in schema_migrations, down migrations run in reverse order and only for recorded files.
 func (s *service) Migrate(file string, direction string) error {
 	files, err := utils.FindFilesInDir("", file, direction)
 	if err != nil {
@@ -209,12 +211,27 @@ func (s *service) Migrate(file string, direction string) error {
 	}
 
 	if s.db != nil {
+		if err := migrations.EnsureVersionTable(s.db); err != nil {
+			return err
+		}
+
+		run := migrations.Up
+		if direction == "down" {
+			run = migrations.Down
+			slices.Reverse(files)
+		}
+
 		log.Printf("Running %s migrations...", direction)
 		for _, sqlFile := range files {
-			log.Printf("Executing migration: %s", filepath.Base(sqlFile))
-			if err := migrations.Migrate(s.db, sqlFile); err != nil {
+			ran, err := run(s.db, sqlFile)
+			if err != nil {
 				return fmt.Errorf("failed to run migration %s: %w", filepath.Base(sqlFile), err)
 			}
+			if !ran {
+				log.Printf("Skipping migration: %s", filepath.Base(sqlFile))
+				continue
+			}
+			log.Printf("Executed migration: %s", filepath.Base(sqlFile))
 		}
 	}
 	return nil
@@ -10,7 +10,7 @@ SELECT json_pretty(
                     json_object(
                         'id', requirement.id
                         , 'standard_id', requirement.standard_id
-                        , 'level_id', requirement.level_id
+                        , 'level_id', requirement.requirement_level_id
                         , 'parent_id', requirement.parent_id
                         , 'reference_code', requirement.reference_code
                         , 'name', requirement.name
@@ -64,7 +64,7 @@ reference_values_data AS (
             json_object(
                 'id', r.id
                 , 'standard_id', r.standard_id
-                , 'level_id', r.level_id
+                , 'level_id', r.requirement_level_id
                 , 'parent_id', r.parent_id
                 , 'reference_code', r.reference_code
                 , 'name', r.name
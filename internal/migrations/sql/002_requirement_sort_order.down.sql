@@ -0,0 +1,13 @@
+-- Disable foreign key checks and set proper character encoding
+SET FOREIGN_KEY_CHECKS = 0;
+SET NAMES utf8mb4;
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+-- Down migrations run in reverse order, so the tables this file altered still exist.
+-- Changes are undone newest first.
+ALTER TABLE requirement
+    DROP INDEX idx_requirement_parent
+    , DROP COLUMN sort_order;
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,15 @@
+-- Enable strict mode and proper character encoding
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- Order requirements among their siblings
+ALTER TABLE requirement
+    ADD COLUMN sort_order INT NOT NULL DEFAULT 0 COMMENT 'Position among requirements sharing the same parent'
+    , ADD INDEX idx_requirement_parent (parent_id, sort_order);
+
+-- Keep the current id based order for existing rows
+UPDATE requirement SET sort_order = id WHERE sort_order = 0;
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
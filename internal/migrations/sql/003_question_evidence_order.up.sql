@@ -0,0 +1,19 @@
+-- Enable strict mode and proper character encoding
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- Order questions within a requirement and expected evidence within a question
+ALTER TABLE questions
+    ADD COLUMN sort_order INT NOT NULL DEFAULT 0 COMMENT 'Position among the questions of the requirement'
+    , ADD INDEX idx_questions_order (requirement_id, sort_order);
+
+ALTER TABLE evidence
+    ADD COLUMN sort_order INT NOT NULL DEFAULT 0 COMMENT 'Position among the expected evidence of the question'
+    , ADD INDEX idx_evidence_order (question_id, sort_order);
+
+UPDATE questions SET sort_order = id WHERE sort_order = 0;
+UPDATE evidence SET sort_order = id WHERE sort_order = 0;
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,11 @@
+-- Enable strict mode and proper character encoding
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- A standard can only name each depth of its requirement tree once
+ALTER TABLE requirement_level
+    ADD UNIQUE INDEX uq_requirement_level_order (standard_id, level_order);
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,13 @@
+-- Enable strict mode and proper character encoding
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- Draft type used when audit content is edited, already present on freshly seeded databases
+INSERT IGNORE INTO reference_values (type_id, code, name, description)
+SELECT id, 'AUDIT_CONTENT', 'Audit Content', 'Changes to the requirements and questions of a standard.'
+FROM reference_types
+WHERE name = 'drafts.type_id';
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,16 @@
+-- Disable foreign key checks and set proper character encoding
+SET FOREIGN_KEY_CHECKS = 0;
+SET NAMES utf8mb4;
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+ALTER TABLE audit_questions
+    DROP INDEX uq_audit_question;
+
+ALTER TABLE audit_plan_requirements
+    DROP FOREIGN KEY fk_audit_plan_requirements_requirement;
+ALTER TABLE audit_plan_requirements
+    DROP INDEX uq_audit_plan_requirement
+    , DROP COLUMN requirement_id;
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,17 @@
+-- Enable strict mode and proper character encoding
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- Audit scope points at requirement subtrees, the free text column keeps the reference code
+ALTER TABLE audit_plan_requirements
+    ADD COLUMN requirement_id INT NULL COMMENT 'Root requirement of a subtree in the audit scope'
+    , ADD CONSTRAINT fk_audit_plan_requirements_requirement FOREIGN KEY (requirement_id) REFERENCES requirement (id)
+    , ADD UNIQUE INDEX uq_audit_plan_requirement (audit_plan_id, requirement_id);
+
+-- A question appears at most once in the checklist of an audit
+ALTER TABLE audit_questions
+    ADD UNIQUE INDEX uq_audit_question (audit_id, question_id);
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
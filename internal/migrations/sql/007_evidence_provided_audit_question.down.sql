@@ -0,0 +1,13 @@
+-- Disable foreign key checks and set proper character encoding
+SET FOREIGN_KEY_CHECKS = 0;
+SET NAMES utf8mb4;
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+ALTER TABLE evidence_provided
+    DROP FOREIGN KEY fk_evidence_provided_audit_question;
+ALTER TABLE evidence_provided
+    DROP INDEX idx_evidence_provided_audit_question
+    , DROP COLUMN audit_question_id;
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,13 @@
+-- Enable strict mode and proper character encoding
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- Provided evidence belongs to the audit question it answers
+ALTER TABLE evidence_provided
+    ADD COLUMN audit_question_id INT NULL COMMENT 'Audit question the evidence was provided for' AFTER evidence_id
+    , ADD CONSTRAINT fk_evidence_provided_audit_question FOREIGN KEY (audit_question_id) REFERENCES audit_questions (id)
+    , ADD INDEX idx_evidence_provided_audit_question (audit_question_id, deleted_at);
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
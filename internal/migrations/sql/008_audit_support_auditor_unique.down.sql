@@ -0,0 +1,10 @@
+-- Disable foreign key checks and set proper character encoding
+SET FOREIGN_KEY_CHECKS = 0;
+SET NAMES utf8mb4;
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+ALTER TABLE audit_support_auditors
+    DROP INDEX uq_audit_support_auditor;
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
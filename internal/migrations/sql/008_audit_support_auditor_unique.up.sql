@@ -0,0 +1,11 @@
+-- Enable strict mode and proper character encoding
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- A user supports an audit at most once
+ALTER TABLE audit_support_auditors
+    ADD UNIQUE INDEX uq_audit_support_auditor (audit_id, user_id);
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,22 @@
+-- Disable foreign key checks and set proper character encoding
+SET FOREIGN_KEY_CHECKS = 0;
+SET NAMES utf8mb4;
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+ALTER TABLE findings
+    DROP COLUMN status_reason;
+
+ALTER TABLE finding_corrective_actions
+    DROP FOREIGN KEY fk_finding_corrective_actions_owner
+    , DROP FOREIGN KEY fk_finding_corrective_actions_verifier;
+ALTER TABLE finding_corrective_actions
+    DROP COLUMN owner_id
+    , DROP COLUMN target_date
+    , DROP COLUMN completion_note
+    , DROP COLUMN completed_at
+    , DROP COLUMN verified_by
+    , DROP COLUMN verified_at
+    , DROP COLUMN updated_at;
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
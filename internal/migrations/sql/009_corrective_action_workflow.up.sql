@@ -0,0 +1,23 @@
+-- Enable strict mode and proper character encoding
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- Corrective actions have an owner and target date and are verified by an auditor once completed
+ALTER TABLE finding_corrective_actions
+    ADD COLUMN owner_id INT NULL COMMENT 'User carrying out the action' AFTER `action`
+    , ADD COLUMN target_date DATE NULL COMMENT 'Date the action should be completed by' AFTER owner_id
+    , ADD COLUMN completion_note TEXT NULL COMMENT 'What was done, given when the action is completed' AFTER target_date
+    , ADD COLUMN completed_at TIMESTAMP NULL AFTER completion_note
+    , ADD COLUMN verified_by INT NULL COMMENT 'Auditor who verified the completed action' AFTER completed_at
+    , ADD COLUMN verified_at TIMESTAMP NULL AFTER verified_by
+    , ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP AFTER created_at
+    , ADD CONSTRAINT fk_finding_corrective_actions_owner FOREIGN KEY (owner_id) REFERENCES users (id)
+    , ADD CONSTRAINT fk_finding_corrective_actions_verifier FOREIGN KEY (verified_by) REFERENCES users (id);
+
+-- Justification of the last finding status change, required to waive a finding
+ALTER TABLE findings
+    ADD COLUMN status_reason TEXT NULL COMMENT 'Justification given with the last status change' AFTER status_id;
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
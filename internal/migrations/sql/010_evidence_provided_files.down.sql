@@ -0,0 +1,15 @@
+-- Disable foreign key checks and set proper character encoding
+SET FOREIGN_KEY_CHECKS = 0;
+SET NAMES utf8mb4;
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+ALTER TABLE evidence_provided
+    DROP INDEX idx_evidence_provided_file_sha256
+    , DROP COLUMN file_key
+    , DROP COLUMN file_name
+    , DROP COLUMN file_size
+    , DROP COLUMN file_sha256
+    , DROP COLUMN file_mime_type;
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
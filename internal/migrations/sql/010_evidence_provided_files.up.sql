@@ -0,0 +1,16 @@
+-- Enable strict mode and proper character encoding
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- Uploaded evidence files live in the blob store, the row keeps where and what they are
+ALTER TABLE evidence_provided
+    ADD COLUMN file_key VARCHAR(255) NULL COMMENT 'Blob store key of the uploaded file' AFTER evidence
+    , ADD COLUMN file_name VARCHAR(255) NULL COMMENT 'File name given by the uploader' AFTER file_key
+    , ADD COLUMN file_size BIGINT NULL COMMENT 'Size of the uploaded file in bytes' AFTER file_name
+    , ADD COLUMN file_sha256 CHAR(64) NULL COMMENT 'Hex encoded SHA-256 of the uploaded file' AFTER file_size
+    , ADD COLUMN file_mime_type VARCHAR(127) NULL COMMENT 'MIME type sniffed from the uploaded content' AFTER file_sha256
+    , ADD INDEX idx_evidence_provided_file_sha256 (file_sha256);
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,21 @@
+-- Enable strict mode and proper character encoding
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- CLASSIFIED provided evidence is only shown to users granted access to it
+CREATE TABLE IF NOT EXISTS evidence_access_grants (
+    id INT AUTO_INCREMENT PRIMARY KEY
+    , evidence_provided_id INT NOT NULL
+    , user_id INT NOT NULL COMMENT 'User allowed to see the evidence'
+    , granted_by INT NOT NULL COMMENT 'User who granted the access'
+    , created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    , CONSTRAINT fk_evidence_access_grants_provided FOREIGN KEY (evidence_provided_id) REFERENCES evidence_provided (id)
+    , CONSTRAINT fk_evidence_access_grants_user FOREIGN KEY (user_id) REFERENCES users (id)
+    , CONSTRAINT fk_evidence_access_grants_granted_by FOREIGN KEY (granted_by) REFERENCES users (id)
+    , UNIQUE INDEX uq_evidence_access_grant (evidence_provided_id, user_id)
+    , INDEX idx_evidence_access_grants_user (user_id)
+) ENGINE = InnoDB COMMENT = 'Per-user access to CLASSIFIED provided evidence';
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
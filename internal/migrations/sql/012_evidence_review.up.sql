@@ -0,0 +1,17 @@
+-- Enable strict mode and proper character encoding
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- Provided evidence is reviewed with a reason and points at the evidence that replaced it
+ALTER TABLE evidence_provided
+    ADD COLUMN review_reason TEXT NULL COMMENT 'Justification given with the review' AFTER status_id
+    , ADD COLUMN reviewed_by INT NULL COMMENT 'Auditor who accepted or rejected the evidence' AFTER review_reason
+    , ADD COLUMN reviewed_at TIMESTAMP NULL AFTER reviewed_by
+    , ADD COLUMN superseded_by INT NULL COMMENT 'Provided evidence replacing this one' AFTER reviewed_at
+    , ADD CONSTRAINT fk_evidence_provided_reviewed_by FOREIGN KEY (reviewed_by) REFERENCES users (id)
+    , ADD CONSTRAINT fk_evidence_provided_superseded_by FOREIGN KEY (superseded_by) REFERENCES evidence_provided (id)
+    , ADD INDEX idx_evidence_provided_superseded_by (superseded_by);
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,18 @@
+-- Disable foreign key checks and set proper character encoding
+SET FOREIGN_KEY_CHECKS = 0;
+SET NAMES utf8mb4;
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+DROP TABLE IF EXISTS comment_mentions;
+DROP TABLE IF EXISTS comment_revisions;
+ALTER TABLE comments
+    DROP FOREIGN KEY fk_comments_parent;
+ALTER TABLE comments
+    DROP INDEX idx_comments_parent
+    , DROP COLUMN parent_id
+    , DROP COLUMN edited_at
+    , DROP COLUMN is_active
+    , DROP COLUMN deleted_at;
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
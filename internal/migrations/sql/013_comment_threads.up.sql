@@ -0,0 +1,40 @@
+-- Enable strict mode and proper character encoding
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- Comments are threaded, marked when edited and soft deleted so replies keep their place
+ALTER TABLE comments
+    ADD COLUMN parent_id INT NULL COMMENT 'Comment this one replies to' AFTER id
+    , ADD COLUMN edited_at TIMESTAMP NULL COMMENT 'Last time the text was changed' AFTER created_at
+    , ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT TRUE AFTER edited_at
+    , ADD COLUMN deleted_at TIMESTAMP NULL COMMENT 'Soft delete timestamp' AFTER updated_at
+    , ADD CONSTRAINT fk_comments_parent FOREIGN KEY (parent_id) REFERENCES comments (id)
+    , ADD INDEX idx_comments_parent (parent_id);
+
+-- Text a comment had before each edit
+CREATE TABLE IF NOT EXISTS comment_revisions (
+    id INT AUTO_INCREMENT PRIMARY KEY
+    , comment_id INT NOT NULL
+    , `comment` TEXT NOT NULL COMMENT 'Text replaced by the edit'
+    , edited_by INT NOT NULL COMMENT 'User who made the edit'
+    , created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    , CONSTRAINT fk_comment_revisions_comment FOREIGN KEY (comment_id) REFERENCES comments (id)
+    , CONSTRAINT fk_comment_revisions_edited_by FOREIGN KEY (edited_by) REFERENCES users (id)
+    , INDEX idx_comment_revisions_comment (comment_id)
+) ENGINE = InnoDB COMMENT = 'Stores the previous text of edited comments';
+
+-- Users mentioned with @ in the current text of a comment
+CREATE TABLE IF NOT EXISTS comment_mentions (
+    id INT AUTO_INCREMENT PRIMARY KEY
+    , comment_id INT NOT NULL
+    , user_id INT NOT NULL
+    , created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    , CONSTRAINT fk_comment_mentions_comment FOREIGN KEY (comment_id) REFERENCES comments (id)
+    , CONSTRAINT fk_comment_mentions_user FOREIGN KEY (user_id) REFERENCES users (id)
+    , UNIQUE INDEX uq_comment_mention (comment_id, user_id)
+    , INDEX idx_comment_mentions_user (user_id)
+) ENGINE = InnoDB COMMENT = 'Links comments to the users they mention';
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,12 @@
+-- Enable strict mode and proper character encoding
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- Open findings of a deactivated user that were not handed over wait for a new owner
+ALTER TABLE findings
+    ADD COLUMN needs_owner BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Set when the responsible user was deactivated' AFTER responsible_user_id
+    , ADD INDEX idx_findings_needs_owner (needs_owner);
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
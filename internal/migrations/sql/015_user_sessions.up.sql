@@ -0,0 +1,23 @@
+-- Enable strict mode and proper character encoding
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- Local password login, users without a hash can only sign in once one is set
+ALTER TABLE users
+    ADD COLUMN password_hash VARCHAR(255) NULL COMMENT 'bcrypt hash of the password' AFTER email;
+
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id INT AUTO_INCREMENT PRIMARY KEY
+    , user_id INT NOT NULL
+    , token_hash CHAR(64) NOT NULL COMMENT 'SHA-256 of the session cookie, the token itself is never stored'
+    , expires_at TIMESTAMP NOT NULL
+    , created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    , CONSTRAINT fk_user_sessions_user FOREIGN KEY (user_id) REFERENCES users (id)
+    , UNIQUE INDEX uq_user_sessions_token (token_hash)
+    , INDEX idx_user_sessions_user (user_id)
+    , INDEX idx_user_sessions_expires (expires_at)
+) ENGINE = InnoDB COMMENT = 'Login sessions of users';
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
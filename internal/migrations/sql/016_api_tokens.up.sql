@@ -0,0 +1,22 @@
+-- Enable strict mode and proper character encoding
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+CREATE TABLE IF NOT EXISTS api_tokens (
+    id INT AUTO_INCREMENT PRIMARY KEY
+    , user_id INT NOT NULL
+    , name VARCHAR(100) NOT NULL
+    , token_hash CHAR(64) NOT NULL COMMENT 'SHA-256 of the token, the token itself is never stored'
+    , scopes SET('read', 'write') NOT NULL COMMENT 'read allows GET and HEAD requests, write all others'
+    , expires_at TIMESTAMP NOT NULL
+    , last_used_at TIMESTAMP NULL
+    , revoked_at TIMESTAMP NULL
+    , created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    , CONSTRAINT fk_api_tokens_user FOREIGN KEY (user_id) REFERENCES users (id)
+    , UNIQUE INDEX uq_api_tokens_token (token_hash)
+    , INDEX idx_api_tokens_user (user_id)
+) ENGINE = InnoDB COMMENT = 'Personal API tokens of users for scripted clients';
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
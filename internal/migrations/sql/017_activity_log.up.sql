@@ -0,0 +1,28 @@
+-- Enable strict mode and proper character encoding
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- Append-only record of every write, there is no foreign key so entries outlive what they describe
+CREATE TABLE IF NOT EXISTS activity_log (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY
+    , actor_user_id INT NULL COMMENT 'User the request acted for, NULL for background jobs'
+    , action VARCHAR(20) NOT NULL COMMENT 'created, updated or deleted'
+    , entity_type VARCHAR(50) NOT NULL
+    , entity_id INT NOT NULL
+    , before_data JSON NULL COMMENT 'Row before the write, NULL for creates'
+    , after_data JSON NULL COMMENT 'Row after the write, NULL for hard deletes'
+    , request_id VARCHAR(64) NULL
+    , created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+    , INDEX idx_activity_log_entity (entity_type, entity_id, id)
+    , INDEX idx_activity_log_actor (actor_user_id, id)
+    , INDEX idx_activity_log_created (created_at)
+) ENGINE = InnoDB COMMENT = 'Immutable history of the writes made to audit data';
+
+DROP TRIGGER IF EXISTS activity_log_no_update;
+CREATE TRIGGER activity_log_no_update BEFORE UPDATE ON activity_log FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'activity_log is append-only';
+DROP TRIGGER IF EXISTS activity_log_no_delete;
+CREATE TRIGGER activity_log_no_delete BEFORE DELETE ON activity_log FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'activity_log is append-only';
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,25 @@
+-- Enable strict mode and proper character encoding
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- Steps of the approval workflow of drafts with the comment given for each. Deleting a draft takes its
+-- steps along, the activity log keeps the states the draft went through.
+CREATE TABLE IF NOT EXISTS draft_transitions (
+    id INT AUTO_INCREMENT PRIMARY KEY
+    , draft_id INT NOT NULL
+    , action VARCHAR(20) NOT NULL COMMENT 'submit, approve, reject or publish'
+    , from_status_id INT NOT NULL COMMENT 'Reference to reference_values.id of drafts.status_id'
+    , to_status_id INT NOT NULL COMMENT 'Equal to from_status_id for approvals, which keep the draft pending'
+    , user_id INT NOT NULL COMMENT 'User who took the step'
+    , `comment` TEXT NOT NULL
+    , created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    , CONSTRAINT fk_draft_transitions_draft FOREIGN KEY (draft_id) REFERENCES drafts (id) ON DELETE CASCADE
+    , CONSTRAINT fk_draft_transitions_from_status FOREIGN KEY (from_status_id) REFERENCES reference_values (id)
+    , CONSTRAINT fk_draft_transitions_to_status FOREIGN KEY (to_status_id) REFERENCES reference_values (id)
+    , CONSTRAINT fk_draft_transitions_user FOREIGN KEY (user_id) REFERENCES users (id)
+    , INDEX idx_draft_transitions_draft (draft_id, id)
+) ENGINE = InnoDB COMMENT = 'History of the submissions, reviews and publication of drafts';
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,12 @@
+-- Disable foreign key checks and set proper character encoding
+SET FOREIGN_KEY_CHECKS = 0;
+SET NAMES utf8mb4;
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+
+ALTER TABLE draft_transitions
+    DROP FOREIGN KEY fk_draft_transitions_draft;
+ALTER TABLE draft_transitions
+    ADD CONSTRAINT fk_draft_transitions_draft FOREIGN KEY (draft_id) REFERENCES drafts (id) ON DELETE CASCADE;
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
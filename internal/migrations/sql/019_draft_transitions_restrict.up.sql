@@ -0,0 +1,13 @@
+-- Enable strict mode and proper character encoding
+SET sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION';
+SET NAMES utf8mb4;
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- Published drafts are kept, so their approval history must not be deleted along with a draft
+ALTER TABLE draft_transitions
+    DROP FOREIGN KEY fk_draft_transitions_draft;
+ALTER TABLE draft_transitions
+    ADD CONSTRAINT fk_draft_transitions_draft FOREIGN KEY (draft_id) REFERENCES drafts (id) ON DELETE RESTRICT;
+
+-- Re-enable foreign key checks
+SET FOREIGN_KEY_CHECKS = 1;
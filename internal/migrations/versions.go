@@ -0,0 +1,77 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// versionTableQuery creates the table recording which migration files have been applied, so
+// migrate up only runs the files a database has not seen yet
+const versionTableQuery = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version VARCHAR(255) NOT NULL PRIMARY KEY COMMENT 'Migration file name without direction and extension'
+    , applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+) ENGINE = InnoDB COMMENT = 'Migrations applied to this database'`
+
+// EnsureVersionTable creates the schema_migrations table when it does not exist yet
+func EnsureVersionTable(db *sql.DB) error {
+	if _, err := db.Exec(versionTableQuery); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// Version returns the version a migration file is recorded under, its base name without the
+// direction and extension, e.g. 002_requirement_sort_order
+func Version(filename string) string {
+	name := strings.TrimSuffix(filepath.Base(filename), ".sql")
+	name = strings.TrimSuffix(name, ".up")
+	return strings.TrimSuffix(name, ".down")
+}
+
+// Up runs the up migration in filename and records its version. It returns false without
+// running anything when the version was already applied.
+func Up(db *sql.DB, filename string) (bool, error) {
+	version := Version(filename)
+	applied, err := isApplied(db, version)
+	if err != nil || applied {
+		return false, err
+	}
+
+	if err := Migrate(db, filename); err != nil {
+		return false, err
+	}
+	if _, err := db.Exec("INSERT INTO schema_migrations (version) VALUES (?)", version); err != nil {
+		return false, fmt.Errorf("failed to record migration %s: %w", version, err)
+	}
+	return true, nil
+}
+
+// Down runs the down migration in filename and removes its version. It returns false without
+// running anything when the version was never applied.
+func Down(db *sql.DB, filename string) (bool, error) {
+	version := Version(filename)
+	applied, err := isApplied(db, version)
+	if err != nil || !applied {
+		return false, err
+	}
+
+	if err := Migrate(db, filename); err != nil {
+		return false, err
+	}
+	if _, err := db.Exec("DELETE FROM schema_migrations WHERE version = ?", version); err != nil {
+		return false, fmt.Errorf("failed to remove migration %s: %w", version, err)
+	}
+	return true, nil
+}
+
+// isApplied reports whether version is recorded in schema_migrations
+func isApplied(db *sql.DB, version string) (bool, error) {
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM schema_migrations WHERE version = ?", version).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check migration %s: %w", version, err)
+	}
+	return count > 0, nil
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"ISO_Auditing_Tool/pkg/middleware"
+	"ISO_Auditing_Tool/pkg/services"
 	"github.com/gin-gonic/gin"
 
 	"ISO_Auditing_Tool/internal/database"
@@ -12,6 +13,7 @@ import (
 
 func (s *Server) RegisterRoutes(db *sql.DB) http.Handler {
 	r := gin.Default()
+	r.Use(middleware.RequestID())
 	s.db = database.New()
 
 	r.GET("/", s.HelloWorldHandler)
@@ -21,29 +23,221 @@ func (s *Server) RegisterRoutes(db *sql.DB) http.Handler {
 
 	// API routes group
 	api := r.Group("/api")
-	api.Use(middleware.ErrorHandler())
+	api.Use(middleware.ErrorHandler(), middleware.Authenticate(s.authService, s.apiTokenService))
+	api.POST("/auth/login", s.apiAuthController.Login)
+	api.POST("/auth/logout", s.apiAuthController.Logout)
+
+	// Everything else needs a signed in user, any role may read what is not limited to an audit below
+	protected := api.Group("")
+	protected.Use(middleware.RequireUser())
+	{
+		protected.GET("/auth/me", s.apiAuthController.Me)
+		protected.GET("/auth/tokens", s.apiAPITokenController.GetMine)
+		protected.POST("/auth/tokens", s.apiAPITokenController.CreateMine)
+		protected.DELETE("/auth/tokens/:id", s.apiAPITokenController.RevokeMine)
+		protected.GET("/standards", s.apiStandardController.GetAll)
+		protected.GET("/standards/:id", s.apiStandardController.GetByID)
+		protected.GET("/standards/:id/requirements", s.apiRequirementController.GetTree)
+		protected.GET("/standards/:id/levels", s.apiRequirementLevelController.GetByStandardID)
+		protected.GET("/requirement-levels/:id", s.apiRequirementLevelController.GetByID)
+		protected.GET("/requirements/:id", s.apiRequirementController.GetByID)
+		protected.GET("/requirements/:id/questions", s.apiQuestionController.GetByRequirementID)
+		protected.GET("/questions/:id", s.apiQuestionController.GetByID)
+		protected.GET("/evidence/:id", s.apiEvidenceController.GetByID)
+		protected.GET("/audit-plans", s.apiAuditPlanController.GetAll)
+		protected.GET("/auditors/:id/assignments", s.apiAuditAssignmentController.GetByUserID)
+		protected.GET("/findings", s.apiFindingController.GetAll)
+		protected.GET("/findings/overdue", s.apiFindingController.GetOverdue)
+		protected.GET("/evidence-provided/:id/file", s.apiEvidenceFileController.Download)
+		protected.GET("/reference-data", s.apiReferenceDataController.GetAll)
+		protected.GET("/reference-data/:type", s.apiReferenceDataController.GetByType)
+		protected.GET("/query/:name", s.apiMaterializedJSONQueryController.GetByName)
+	}
+
+	// An audit is only read by administrators and its team, lists of findings are narrowed to
+	// their audits by the finding service. Clients following up a finding also read it and take part
+	// in the discussion of its audit.
+	auditReaders := protected.Group("")
+	auditReaders.Use(middleware.RequireAuditAccess(s.authorizationService))
+	{
+		auditReaders.GET("/audit-plans/:id", s.apiAuditPlanController.GetByID)
+		auditReaders.GET("/audit-plans/:id/checklist", s.apiAuditChecklistController.GetByAuditPlanID)
+		auditReaders.GET("/audit-plans/:id/questions", s.apiAuditExecutionController.GetQuestions)
+		auditReaders.GET("/audit-plans/:id/progress", s.apiAuditExecutionController.GetProgress)
+		auditReaders.GET("/audit-plans/:id/auditors", s.apiAuditAssignmentController.GetByAuditPlanID)
+	}
+	protected.GET("/audit-questions/:id",
+		middleware.RequireAuditAccessOf(s.authorizationService, "Audit question", s.authorizationService.AuditPlanOfQuestion),
+		s.apiAuditExecutionController.GetQuestion)
+	protected.GET("/evidence-provided/:id/chain",
+		middleware.RequireAuditAccessOf(s.authorizationService, "Provided evidence", s.authorizationService.AuditPlanOfEvidence),
+		s.apiAuditExecutionController.GetEvidenceChain)
+	protected.GET("/audit-questions/:id/comments",
+		middleware.RequireAuditParticipationOf(s.authorizationService, "Audit question", s.authorizationService.AuditPlanOfQuestion),
+		s.apiCommentController.GetThread)
+	protected.GET("/comments/:id/revisions",
+		middleware.RequireAuditParticipationOf(s.authorizationService, "Comment", s.authorizationService.AuditPlanOfComment),
+		s.apiCommentController.GetRevisions)
+	protected.GET("/findings/:id",
+		middleware.RequireAuditParticipationOf(s.authorizationService, "Finding", s.authorizationService.AuditPlanOfFinding),
+		s.apiFindingController.GetByID)
+
+	// Standards content and audit planning, changed by administrators and lead auditors
+	authors := protected.Group("")
+	authors.Use(middleware.RequireRole(services.UserRoleAdmin, services.UserRoleLead))
+	{
+		authors.POST("/drafts", s.apiDraftController.Create)
+		authors.PUT("/drafts/:id", s.apiDraftController.Update)
+		authors.GET("/drafts", s.apiDraftController.GetAll)
+		authors.GET("/drafts/:id", s.apiDraftController.GetByID)
+		authors.POST("/drafts/:id/submit", s.apiDraftController.Submit)
+		authors.POST("/drafts/:id/approve", s.apiDraftController.Approve)
+		authors.POST("/drafts/:id/reject", s.apiDraftController.Reject)
+		authors.POST("/drafts/:id/publish", s.apiDraftController.Publish)
+		authors.POST("/standards", s.apiStandardController.Create)
+		authors.PUT("/standards/:id", s.apiStandardController.Update)
+		authors.DELETE("/standards/:id", s.apiStandardController.Delete)
+		authors.POST("/standards/:id/requirements", s.apiRequirementController.Create)
+		authors.PUT("/standards/:id/requirements/order", s.apiRequirementController.Reorder)
+		authors.POST("/standards/:id/levels", s.apiRequirementLevelController.Create)
+		authors.PUT("/requirement-levels/:id", s.apiRequirementLevelController.Update)
+		authors.DELETE("/requirement-levels/:id", s.apiRequirementLevelController.Delete)
+		authors.PUT("/requirements/:id", s.apiRequirementController.Update)
+		authors.POST("/requirements/:id/move", s.apiRequirementController.Move)
+		authors.POST("/requirements/:id/questions", s.apiQuestionController.Create)
+		authors.PUT("/requirements/:id/questions/order", s.apiQuestionController.Reorder)
+		authors.PUT("/questions/:id", s.apiQuestionController.Update)
+		authors.DELETE("/questions/:id", s.apiQuestionController.Delete)
+		authors.POST("/questions/:id/evidence", s.apiEvidenceController.Create)
+		authors.PUT("/questions/:id/evidence/order", s.apiEvidenceController.Reorder)
+		authors.PUT("/evidence/:id", s.apiEvidenceController.Update)
+		authors.DELETE("/evidence/:id", s.apiEvidenceController.Delete)
+		authors.POST("/audit-plans", s.apiAuditPlanController.Create)
+	}
+
+	// An existing audit plan is only managed by administrators and its own team
+	auditManagers := authors.Group("")
+	auditManagers.Use(middleware.RequireAuditAccess(s.authorizationService))
+	{
+		auditManagers.PUT("/audit-plans/:id", s.apiAuditPlanController.Update)
+		auditManagers.DELETE("/audit-plans/:id", s.apiAuditPlanController.Delete)
+		auditManagers.POST("/audit-plans/:id/status", s.apiAuditPlanController.ChangeStatus)
+		auditManagers.PUT("/audit-plans/:id/checklist", s.apiAuditChecklistController.Generate)
+		auditManagers.POST("/audit-plans/:id/auditors", s.apiAuditAssignmentController.Assign)
+		auditManagers.DELETE("/audit-plans/:id/auditors/:user_id", s.apiAuditAssignmentController.Unassign)
+	}
+
+	// Audit work, done by auditors. The services check that they are on the team of the audit.
+	auditors := protected.Group("")
+	auditors.Use(middleware.RequireRole(services.UserRoleAdmin, services.UserRoleLead, services.UserRoleSupport))
 	{
-		api.POST("/drafts", s.apiDraftController.Create)
-		api.PUT("/drafts/:id", s.apiDraftController.Update)
-		api.GET("/drafts", s.apiDraftController.GetAll)
-		// api.GET("/iso_standards", s.apiIsoStandardController.GetAllISOStandards)
-		// api.GET("/iso_standards/:id", s.apiIsoStandardController.GetISOStandardByID)
-		// api.POST("/iso_standards", s.apiIsoStandardController.CreateISOStandard)
-		// api.PUT("/iso_standards/:id", s.apiIsoStandardController.UpdateISOStandard)
-		// api.DELETE("/iso_standards/:id", s.apiIsoStandardController.DeleteISOStandard)
-		api.GET("/query/:name", s.apiMaterializedJSONQueryController.GetByName)
-		api.POST("/query", s.apiMaterializedJSONQueryController.CreateOrUpdateJSONQuery)
+		auditors.POST("/audit-questions/:id/evidence", s.apiAuditExecutionController.ProvideEvidence)
+		auditors.POST("/audit-questions/:id/findings", s.apiFindingController.Create)
+		auditors.PUT("/findings/:id", s.apiFindingController.Update)
+		auditors.DELETE("/findings/:id", s.apiFindingController.Delete)
+		auditors.POST("/findings/:id/status", s.apiFindingController.ChangeStatus)
+		auditors.POST("/findings/:id/actions", s.apiFindingController.AddCorrectiveAction)
+		auditors.PUT("/corrective-actions/:id", s.apiFindingController.UpdateCorrectiveAction)
+		auditors.POST("/corrective-actions/:id/verify", s.apiFindingController.VerifyCorrectiveAction)
+		auditors.PUT("/evidence-provided/:id", s.apiAuditExecutionController.UpdateEvidence)
+		auditors.DELETE("/evidence-provided/:id", s.apiAuditExecutionController.DeleteEvidence)
+		auditors.POST("/evidence-provided/:id/review", s.apiAuditExecutionController.ReviewEvidence)
+		auditors.POST("/evidence-provided/:id/replace", s.apiAuditExecutionController.ReplaceEvidence)
+		auditors.POST("/evidence-provided/:id/file", s.apiEvidenceFileController.Upload)
+		auditors.GET("/evidence-provided/:id/grants", s.apiEvidenceAccessController.GetGrants)
+		auditors.POST("/evidence-provided/:id/grants", s.apiEvidenceAccessController.Grant)
+		auditors.DELETE("/evidence-provided/:id/grants/:user_id", s.apiEvidenceAccessController.Revoke)
+	}
+
+	// Discussion and follow up, open to clients as well but not to viewers. Comments are only made
+	// by those taking part in the audit.
+	participants := protected.Group("")
+	participants.Use(middleware.RequireRole(services.UserRoleAdmin, services.UserRoleLead, services.UserRoleSupport, services.UserRoleClient))
+	{
+		participants.POST("/audit-questions/:id/comments",
+			middleware.RequireAuditParticipationOf(s.authorizationService, "Audit question", s.authorizationService.AuditPlanOfQuestion),
+			s.apiCommentController.Create)
+		participants.PUT("/comments/:id",
+			middleware.RequireAuditParticipationOf(s.authorizationService, "Comment", s.authorizationService.AuditPlanOfComment),
+			s.apiCommentController.Update)
+		participants.DELETE("/comments/:id",
+			middleware.RequireAuditParticipationOf(s.authorizationService, "Comment", s.authorizationService.AuditPlanOfComment),
+			s.apiCommentController.Delete)
+		participants.POST("/corrective-actions/:id/complete",
+			middleware.RequireAuditParticipationOf(s.authorizationService, "Corrective action", s.authorizationService.AuditPlanOfCorrectiveAction),
+			s.apiFindingController.CompleteCorrectiveAction)
+	}
+
+	// Users, retention, reference data, the activity log and materialized queries are administration
+	admins := protected.Group("")
+	admins.Use(middleware.RequireRole(services.UserRoleAdmin))
+	{
+		admins.GET("/users", s.apiUserController.GetAll)
+		admins.POST("/users", s.apiUserController.Create)
+		admins.GET("/users/:id", s.apiUserController.GetByID)
+		admins.PUT("/users/:id/role", s.apiUserController.ChangeRole)
+		admins.POST("/users/:id/deactivate", s.apiUserController.Deactivate)
+		admins.POST("/users/:id/reactivate", s.apiUserController.Reactivate)
+		admins.PUT("/users/:id/password", s.apiUserController.SetPassword)
+		admins.GET("/users/:id/tokens", s.apiAPITokenController.GetByUserID)
+		admins.DELETE("/users/:id/tokens/:token_id", s.apiAPITokenController.RevokeByUserID)
+		admins.GET("/retention/report", s.apiRetentionController.GetReport)
+		admins.POST("/retention/sweep", s.apiRetentionController.Sweep)
+		admins.POST("/reference-data/:type/values", s.apiReferenceDataController.Create)
+		admins.PUT("/reference-values/:id", s.apiReferenceDataController.Update)
+		admins.DELETE("/reference-values/:id", s.apiReferenceDataController.Delete)
+		admins.GET("/activity", s.apiActivityLogController.GetAll)
+		admins.POST("/query", s.apiMaterializedJSONQueryController.CreateOrUpdateJSONQuery)
 	}
 
 	// // HTML routes group
 	html := r.Group("/web")
-	html.Use(middleware.ErrorHandler())
+	html.Use(middleware.ErrorHandler(), middleware.Authenticate(s.authService, s.apiTokenService))
+	html.GET("/login", s.webAuthController.GetLogin)
+	html.POST("/login", s.webAuthController.Login)
+	html.POST("/logout", s.webAuthController.Logout)
+	if s.webSSOController != nil {
+		html.GET("/sso/login", s.webSSOController.Begin)
+		html.GET("/sso/callback", s.webSSOController.Callback)
+	}
+
+	// Other pages send anonymous visitors to the login page
+	pages := html.Group("")
+	pages.Use(middleware.RequireWebUser("/web/login"))
 	{
 		// html.GET("/iso_standards", s.webIsoStandardController.GetAllISOStandards)
 		// html.GET("/iso_standards/add", s.webIsoStandardController.RenderAddISOStandardForm)
 		// html.POST("/iso_standards", s.webIsoStandardController.CreateISOStandard)
 		// html.GET("/iso_standards/:id", s.webIsoStandardController.GetISOStandardByID)
-		html.GET("/standards/:id", s.webStandardController.GetByID)
+		pages.GET("/standards/:id", s.webStandardController.GetByID)
+		pages.GET("/findings", s.webFindingController.GetAll)
+	}
+
+	// Pages of an audit are limited to those who may read it through the API
+	pages.GET("/findings/:id",
+		middleware.RequireAuditParticipationOf(s.authorizationService, "Finding", s.authorizationService.AuditPlanOfFinding),
+		s.webFindingController.GetByID)
+	pages.GET("/audit-plans/:id", middleware.RequireAuditAccess(s.authorizationService), s.webCommentController.GetAuditPage)
+	pages.GET("/audit-questions/:id/comments",
+		middleware.RequireAuditParticipationOf(s.authorizationService, "Audit question", s.authorizationService.AuditPlanOfQuestion),
+		s.webCommentController.GetThread)
+	pages.GET("/comments/:id/revisions",
+		middleware.RequireAuditParticipationOf(s.authorizationService, "Comment", s.authorizationService.AuditPlanOfComment),
+		s.webCommentController.GetRevisions)
+
+	// Comments are made by those taking part in the audit, never by viewers
+	commenters := pages.Group("")
+	commenters.Use(middleware.RequireRole(services.UserRoleAdmin, services.UserRoleLead, services.UserRoleSupport, services.UserRoleClient))
+	{
+		commenters.POST("/audit-questions/:id/comments",
+			middleware.RequireAuditParticipationOf(s.authorizationService, "Audit question", s.authorizationService.AuditPlanOfQuestion),
+			s.webCommentController.Create)
+		commenters.PUT("/comments/:id",
+			middleware.RequireAuditParticipationOf(s.authorizationService, "Comment", s.authorizationService.AuditPlanOfComment),
+			s.webCommentController.Update)
+		commenters.DELETE("/comments/:id",
+			middleware.RequireAuditParticipationOf(s.authorizationService, "Comment", s.authorizationService.AuditPlanOfComment),
+			s.webCommentController.Delete)
 	}
 
 	return r
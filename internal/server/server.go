@@ -1,11 +1,13 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"strconv"
+	"sync"
 	"time"
 
 	_ "github.com/joho/godotenv/autoload"
@@ -14,8 +16,10 @@ import (
 	apiControllers "ISO_Auditing_Tool/pkg/controllers/api"
 	webControllers "ISO_Auditing_Tool/pkg/controllers/web"
 	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/oidc"
 	"ISO_Auditing_Tool/pkg/repositories"
 	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/storage"
 )
 
 // Config holds all configuration for the server
@@ -26,6 +30,24 @@ type Config struct {
 	WriteTimeout   time.Duration    `json:"write_timeout"`
 	IdleTimeout    time.Duration    `json:"idle_timeout"`
 	DatabaseConfig *database.Config `json:"database_config"`
+	BlobConfig     *storage.Config  `json:"blob_config"`
+	OIDCConfig     *oidc.Config     `json:"-"`
+
+	// Overdue finding job
+	OverdueCheckInterval time.Duration `json:"overdue_check_interval"`
+	DueWarningDays       int           `json:"due_warning_days"`
+
+	// Evidence retention sweep
+	RetentionSweepInterval time.Duration `json:"retention_sweep_interval"`
+	RetentionGraceDays     int           `json:"retention_grace_days"`
+
+	// Sessions of signed in users
+	SessionTTL          time.Duration `json:"session_ttl"`
+	SessionCookieSecure bool          `json:"session_cookie_secure"`
+
+	// First administrator, created on start when no user has the email yet
+	BootstrapAdminEmail    string `json:"bootstrap_admin_email"`
+	BootstrapAdminPassword string `json:"-"`
 }
 
 // LoadConfig loads configuration from environment variables with defaults
@@ -71,6 +93,66 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	// Overdue check interval with default
+	overdueIntervalStr := os.Getenv("OVERDUE_CHECK_INTERVAL")
+	overdueInterval := time.Hour
+	if overdueIntervalStr != "" {
+		overdueIntervalSec, err := strconv.Atoi(overdueIntervalStr)
+		if err == nil && overdueIntervalSec > 0 {
+			overdueInterval = time.Duration(overdueIntervalSec) * time.Second
+		}
+	}
+
+	// Due date warning window in days with default
+	warningDaysStr := os.Getenv("DUE_WARNING_DAYS")
+	warningDays := 7
+	if warningDaysStr != "" {
+		days, err := strconv.Atoi(warningDaysStr)
+		if err == nil && days >= 0 {
+			warningDays = days
+		}
+	}
+
+	// Retention sweep interval with default
+	retentionIntervalStr := os.Getenv("RETENTION_SWEEP_INTERVAL")
+	retentionInterval := 24 * time.Hour
+	if retentionIntervalStr != "" {
+		retentionIntervalSec, err := strconv.Atoi(retentionIntervalStr)
+		if err == nil && retentionIntervalSec > 0 {
+			retentionInterval = time.Duration(retentionIntervalSec) * time.Second
+		}
+	}
+
+	// Grace period in days between expiry and purge with default
+	graceDaysStr := os.Getenv("RETENTION_GRACE_DAYS")
+	graceDays := 30
+	if graceDaysStr != "" {
+		days, err := strconv.Atoi(graceDaysStr)
+		if err == nil && days >= 0 {
+			graceDays = days
+		}
+	}
+
+	// Session lifetime with default
+	sessionTTLStr := os.Getenv("SESSION_TTL")
+	sessionTTL := 12 * time.Hour
+	if sessionTTLStr != "" {
+		sessionTTLSec, err := strconv.Atoi(sessionTTLStr)
+		if err == nil && sessionTTLSec > 0 {
+			sessionTTL = time.Duration(sessionTTLSec) * time.Second
+		}
+	}
+
+	// Session cookie is HTTPS only unless turned off for local development
+	cookieSecureStr := os.Getenv("SESSION_COOKIE_SECURE")
+	cookieSecure := true
+	if cookieSecureStr != "" {
+		secure, err := strconv.ParseBool(cookieSecureStr)
+		if err == nil {
+			cookieSecure = secure
+		}
+	}
+
 	// Load database configuration
 	dbConfig := database.LoadConfigFromEnv()
 
@@ -81,6 +163,20 @@ func LoadConfig() (*Config, error) {
 		WriteTimeout:   writeTimeout,
 		IdleTimeout:    idleTimeout,
 		DatabaseConfig: dbConfig,
+		BlobConfig:     storage.LoadConfigFromEnv(),
+		OIDCConfig:     oidc.LoadConfigFromEnv(),
+
+		OverdueCheckInterval: overdueInterval,
+		DueWarningDays:       warningDays,
+
+		RetentionSweepInterval: retentionInterval,
+		RetentionGraceDays:     graceDays,
+
+		SessionTTL:          sessionTTL,
+		SessionCookieSecure: cookieSecure,
+
+		BootstrapAdminEmail:    os.Getenv("BOOTSTRAP_ADMIN_EMAIL"),
+		BootstrapAdminPassword: os.Getenv("BOOTSTRAP_ADMIN_PASSWORD"),
 	}, nil
 }
 
@@ -89,8 +185,40 @@ type Server struct {
 	db                                 database.Service
 	eventBus                           *events.EventBus
 	apiDraftController                 *apiControllers.ApiDraftController
+	apiStandardController              *apiControllers.ApiStandardController
+	apiRequirementController           *apiControllers.ApiRequirementController
+	apiRequirementLevelController      *apiControllers.ApiRequirementLevelController
+	apiReferenceDataController         *apiControllers.ApiReferenceDataController
+	apiQuestionController              *apiControllers.ApiQuestionController
+	apiEvidenceController              *apiControllers.ApiEvidenceController
+	apiAuditPlanController             *apiControllers.ApiAuditPlanController
+	apiAuditChecklistController        *apiControllers.ApiAuditChecklistController
+	apiAuditExecutionController        *apiControllers.ApiAuditExecutionController
+	apiAuditAssignmentController       *apiControllers.ApiAuditAssignmentController
+	apiFindingController               *apiControllers.ApiFindingController
+	apiEvidenceFileController          *apiControllers.ApiEvidenceFileController
+	apiEvidenceAccessController        *apiControllers.ApiEvidenceAccessController
+	apiRetentionController             *apiControllers.ApiRetentionController
+	apiCommentController               *apiControllers.ApiCommentController
+	apiUserController                  *apiControllers.ApiUserController
+	apiAuthController                  *apiControllers.ApiAuthController
+	apiAPITokenController              *apiControllers.ApiAPITokenController
+	apiActivityLogController           *apiControllers.ApiActivityLogController
 	webStandardController              *webControllers.WebStandardController
+	webFindingController               *webControllers.WebFindingController
+	webCommentController               *webControllers.WebCommentController
+	webAuthController                  *webControllers.WebAuthController
+	webSSOController                   *webControllers.WebSSOController // nil unless single sign-on is configured
 	apiMaterializedJSONQueryController *apiControllers.ApiMaterializedJSONQueryController
+	authService                        *services.AuthService
+	authorizationService               *services.AuthorizationService
+	apiTokenService                    *services.APITokenService
+
+	// Background jobs, started by Start and stopped by Shutdown
+	overdueFindingJob *services.OverdueFindingJob
+	retentionService  *services.RetentionService
+	stopJobs          context.CancelFunc
+	jobs              sync.WaitGroup
 }
 
 // NewServer creates a new server instance with the given configuration
@@ -111,6 +239,9 @@ func NewServer() (*Server, error) {
 	eventBus.Subscribe(events.MaterializedQueryCreated, events.LoggingHandler())
 	eventBus.Subscribe(events.MaterializedQueryUpdated, events.LoggingHandler())
 	eventBus.Subscribe(events.MaterializedQueryRefreshRequested, events.LoggingHandler())
+	eventBus.Subscribe(events.FindingDueSoon, events.LoggingHandler())
+	eventBus.Subscribe(events.FindingOverdue, events.LoggingHandler())
+	eventBus.Subscribe(events.EvidenceAccessDenied, events.LoggingHandler())
 
 	// Setup repositories
 	draftRepo, err := repositories.NewDraftRepository(db.DB())
@@ -128,6 +259,11 @@ func NewServer() (*Server, error) {
 		return nil, fmt.Errorf("failed to create materialized HTML query repository: %w", err)
 	}
 
+	referenceDataRepo, err := repositories.NewReferenceDataRepository(db.DB())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reference data repository: %w", err)
+	}
+
 	standardRepo, err := repositories.NewStandardRepository(db.DB())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create standard repository: %w", err)
@@ -138,6 +274,11 @@ func NewServer() (*Server, error) {
 		return nil, fmt.Errorf("failed to create requirement repository: %w", err)
 	}
 
+	requirementLevelRepo, err := repositories.NewRequirementLevelRepository(db.DB())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create requirement level repository: %w", err)
+	}
+
 	questionRepo, err := repositories.NewQuestionRepository(db.DB())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create question repository: %w", err)
@@ -148,25 +289,177 @@ func NewServer() (*Server, error) {
 		return nil, fmt.Errorf("failed to create evidence repository: %w", err)
 	}
 
+	auditPlanRepo, err := repositories.NewAuditPlanRepository(db.DB())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audit plan repository: %w", err)
+	}
+
+	auditChecklistRepo, err := repositories.NewAuditChecklistRepository(db.DB())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audit checklist repository: %w", err)
+	}
+
+	auditQuestionRepo, err := repositories.NewAuditQuestionRepository(db.DB())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audit question repository: %w", err)
+	}
+
+	commentRepo, err := repositories.NewCommentRepository(db.DB())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create comment repository: %w", err)
+	}
+
+	evidenceProvidedRepo, err := repositories.NewEvidenceProvidedRepository(db.DB())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create provided evidence repository: %w", err)
+	}
+
+	evidenceAccessRepo, err := repositories.NewEvidenceAccessRepository(db.DB())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create evidence access repository: %w", err)
+	}
+
+	auditAssignmentRepo, err := repositories.NewAuditAssignmentRepository(db.DB())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audit assignment repository: %w", err)
+	}
+
+	findingRepo, err := repositories.NewFindingRepository(db.DB())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create finding repository: %w", err)
+	}
+	correctiveActionRepo, err := repositories.NewCorrectiveActionRepository(db.DB())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create corrective action repository: %w", err)
+	}
+
+	userRepo, err := repositories.NewUserRepository(db.DB())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user repository: %w", err)
+	}
+
+	sessionRepo, err := repositories.NewSessionRepository(db.DB())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session repository: %w", err)
+	}
+
+	apiTokenRepo, err := repositories.NewAPITokenRepository(db.DB())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API token repository: %w", err)
+	}
+
+	activityLogRepo, err := repositories.NewActivityLogRepository(db.DB())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create activity log repository: %w", err)
+	}
+
+	// Setup blob store for evidence files
+	blobStore, err := storage.New(config.BlobConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create blob store: %w", err)
+	}
+
 	// Setup services
-	draftService := services.NewDraftService(draftRepo)
+	referenceDataService := services.NewReferenceDataService(referenceDataRepo, eventBus)
+	draftService := services.NewDraftService(draftRepo, referenceDataService, nil)
+	auditContentService := services.NewAuditContentService(draftService, requirementRepo, questionRepo, evidenceRepo, referenceDataService, eventBus)
+	draftService.RegisterPublisher(services.DraftTypeAuditContent, auditContentService)
 	// apiMaterializedQueryService := services.NewMaterializedJSONService(apiMaterializedQueryRepo, eventBus)
 	materializedJSONQueryService := services.NewMaterializedJSONService(materializedJSONQueryRepo, standardRepo, requirementRepo, questionRepo, evidenceRepo, eventBus)
 	htmlCacheService := services.NewHTMLCacheService(materializedHTMLQueryRepo, materializedJSONQueryRepo, standardRepo, requirementRepo, eventBus)
-	standardService := services.NewStandardService(standardRepo)
+	standardService := services.NewStandardService(standardRepo, eventBus)
+	requirementService := services.NewRequirementService(requirementRepo, standardRepo, requirementLevelRepo, eventBus)
+	requirementLevelService := services.NewRequirementLevelService(requirementLevelRepo, standardRepo)
+	questionService := services.NewQuestionService(questionRepo, requirementRepo, eventBus)
+	evidenceService := services.NewEvidenceService(evidenceRepo, questionRepo, eventBus)
+	auditPlanService := services.NewAuditPlanService(auditPlanRepo, standardRepo, referenceDataService, eventBus)
+	auditChecklistService := services.NewAuditChecklistService(auditChecklistRepo, requirementRepo, auditPlanService, eventBus)
+	auditAssignmentService := services.NewAuditAssignmentService(auditAssignmentRepo, auditPlanService, referenceDataService, eventBus)
+	evidenceAccessService := services.NewEvidenceAccessService(evidenceAccessRepo, evidenceProvidedRepo, auditQuestionRepo, auditAssignmentRepo, referenceDataService, eventBus)
+	evidenceFileService := services.NewEvidenceFileService(evidenceProvidedRepo, auditQuestionRepo, auditPlanService, auditAssignmentService, evidenceAccessService, referenceDataService, blobStore, config.BlobConfig.MaxSize, eventBus)
+	findingService := services.NewFindingService(findingRepo, correctiveActionRepo, auditQuestionRepo, auditPlanService, auditAssignmentService, referenceDataService, eventBus)
+	auditExecutionService := services.NewAuditExecutionService(auditQuestionRepo, evidenceProvidedRepo, requirementRepo, auditPlanService, auditAssignmentService, evidenceAccessService, referenceDataService, eventBus)
+	commentService := services.NewCommentService(commentRepo, auditQuestionRepo, auditPlanService, eventBus)
+	userService := services.NewUserService(userRepo, referenceDataService, eventBus)
+	authorizationService := services.NewAuthorizationService(auditAssignmentRepo, auditQuestionRepo, commentRepo, findingRepo, correctiveActionRepo, evidenceProvidedRepo)
+	apiTokenService := services.NewAPITokenService(apiTokenRepo, userRepo, referenceDataService, nil, eventBus)
+	authService := services.NewAuthService(userRepo, sessionRepo, referenceDataService, config.SessionTTL, nil, eventBus)
+	retentionService := services.NewRetentionService(evidenceProvidedRepo, referenceDataService, blobStore, config.RetentionGraceDays, config.RetentionSweepInterval, nil, eventBus)
 
 	// Setup controllers
 	apiDraftController := apiControllers.NewAPIDraftController(draftService)
+	apiStandardController := apiControllers.NewAPIStandardController(standardService)
+	apiRequirementController := apiControllers.NewAPIRequirementController(requirementService)
+	apiRequirementLevelController := apiControllers.NewAPIRequirementLevelController(requirementLevelService)
+	apiReferenceDataController := apiControllers.NewAPIReferenceDataController(referenceDataService)
+	apiQuestionController := apiControllers.NewAPIQuestionController(questionService)
+	apiEvidenceController := apiControllers.NewAPIEvidenceController(evidenceService)
+	apiAuditPlanController := apiControllers.NewAPIAuditPlanController(auditPlanService)
+	apiAuditChecklistController := apiControllers.NewAPIAuditChecklistController(auditChecklistService)
+	apiAuditExecutionController := apiControllers.NewAPIAuditExecutionController(auditExecutionService)
+	apiAuditAssignmentController := apiControllers.NewAPIAuditAssignmentController(auditAssignmentService)
+	apiEvidenceFileController := apiControllers.NewAPIEvidenceFileController(evidenceFileService)
+	apiEvidenceAccessController := apiControllers.NewAPIEvidenceAccessController(evidenceAccessService)
+	apiRetentionController := apiControllers.NewAPIRetentionController(retentionService)
+	apiCommentController := apiControllers.NewAPICommentController(commentService)
+	apiUserController := apiControllers.NewAPIUserController(userService, authService)
+	apiAuthController := apiControllers.NewAPIAuthController(authService, config.SessionCookieSecure)
+	apiAPITokenController := apiControllers.NewAPIAPITokenController(apiTokenService)
+	apiActivityLogController := apiControllers.NewAPIActivityLogController(services.NewActivityLogService(activityLogRepo))
+	overdueFindingJob := services.NewOverdueFindingJob(findingService, eventBus, config.OverdueCheckInterval, config.DueWarningDays, nil)
+	apiFindingController := apiControllers.NewAPIFindingController(findingService, overdueFindingJob)
 	apiMaterializedQueryController := apiControllers.NewApiMaterializedJSONQueryController(materializedJSONQueryService, htmlCacheService, eventBus)
 	webStandardController := webControllers.NewWebStandardController(standardService)
+	webFindingController := webControllers.NewWebFindingController(findingService)
+	webCommentController := webControllers.NewWebCommentController(commentService, auditExecutionService)
+	webAuthController := webControllers.NewWebAuthController(authService, config.SessionCookieSecure, config.OIDCConfig.Enabled())
+
+	// Setup single sign-on when an identity provider is configured
+	var webSSOController *webControllers.WebSSOController
+	if config.OIDCConfig.Enabled() {
+		oidcClient, err := oidc.NewClient(*config.OIDCConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OIDC client: %w", err)
+		}
+		ssoService := services.NewSSOService(oidcClient, config.OIDCConfig.GroupRoles, config.OIDCConfig.ProvisionUsers, userRepo, referenceDataService, authService, eventBus)
+		webSSOController = webControllers.NewWebSSOController(ssoService, config.SessionCookieSecure)
+	}
 
 	return &Server{
 		config:                             config,
 		db:                                 db,
 		eventBus:                           eventBus,
 		apiDraftController:                 apiDraftController,
+		apiStandardController:              apiStandardController,
+		apiRequirementController:           apiRequirementController,
+		apiRequirementLevelController:      apiRequirementLevelController,
+		apiReferenceDataController:         apiReferenceDataController,
+		apiQuestionController:              apiQuestionController,
+		apiEvidenceController:              apiEvidenceController,
+		apiAuditPlanController:             apiAuditPlanController,
+		apiAuditChecklistController:        apiAuditChecklistController,
+		apiAuditExecutionController:        apiAuditExecutionController,
+		apiAuditAssignmentController:       apiAuditAssignmentController,
+		apiFindingController:               apiFindingController,
+		apiEvidenceFileController:          apiEvidenceFileController,
+		apiEvidenceAccessController:        apiEvidenceAccessController,
+		apiRetentionController:             apiRetentionController,
+		apiCommentController:               apiCommentController,
+		apiUserController:                  apiUserController,
+		apiAuthController:                  apiAuthController,
+		apiAPITokenController:              apiAPITokenController,
+		apiActivityLogController:           apiActivityLogController,
 		apiMaterializedJSONQueryController: apiMaterializedQueryController,
 		webStandardController:              webStandardController,
+		webFindingController:               webFindingController,
+		webCommentController:               webCommentController,
+		webAuthController:                  webAuthController,
+		webSSOController:                   webSSOController,
+		authService:                        authService,
+		authorizationService:               authorizationService,
+		apiTokenService:                    apiTokenService,
+		overdueFindingJob:                  overdueFindingJob,
+		retentionService:                   retentionService,
 	}, nil
 }
 
@@ -183,6 +476,13 @@ func (s *Server) Start() (*http.Server, error) {
 		addr = fmt.Sprintf(":%d", s.config.Port)
 	}
 
+	// Create the first administrator so someone can sign in
+	if s.config.BootstrapAdminEmail != "" && s.config.BootstrapAdminPassword != "" {
+		if err := s.authService.Bootstrap(context.Background(), s.config.BootstrapAdminEmail, s.config.BootstrapAdminPassword); err != nil {
+			log.Printf("Failed to create bootstrap administrator: %v", err)
+		}
+	}
+
 	// Declare server config
 	server := &http.Server{
 		Addr:         addr,
@@ -192,6 +492,19 @@ func (s *Server) Start() (*http.Server, error) {
 		WriteTimeout: s.config.WriteTimeout,
 	}
 
+	// Start background jobs
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	s.stopJobs = stopJobs
+	s.jobs.Add(2)
+	go func() {
+		defer s.jobs.Done()
+		s.overdueFindingJob.Run(jobsCtx)
+	}()
+	go func() {
+		defer s.jobs.Done()
+		s.retentionService.Run(jobsCtx)
+	}()
+
 	// Log server startup
 	log.Printf("Starting server on %s", addr)
 	return server, nil
@@ -199,6 +512,12 @@ func (s *Server) Start() (*http.Server, error) {
 
 // Shutdown gracefully shuts down the server
 func (s *Server) Shutdown() error {
+	// Stop background jobs before the database they use goes away
+	if s.stopJobs != nil {
+		s.stopJobs()
+	}
+	s.jobs.Wait()
+
 	// Close database connections
 	if err := s.db.Close(); err != nil {
 		return fmt.Errorf("error closing database connections: %w", err)
@@ -0,0 +1,38 @@
+// Only handles API request validation and response formatting for the activity log
+package controllers
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiActivityLogController struct {
+	Service services.ActivityLogServiceInterface
+}
+
+// NewAPIActivityLogController creates a new instance of ApiActivityLogController
+func NewAPIActivityLogController(service services.ActivityLogServiceInterface) *ApiActivityLogController {
+	return &ApiActivityLogController{Service: service}
+}
+
+// GetAll returns the entries matching the entity_type, entity_id, user_id, from, to, before_id and
+// limit query parameters, newest first
+func (cc *ApiActivityLogController) GetAll(c *gin.Context) {
+	var filter types.ActivityFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.Error(custom_errors.InvalidData(c.Request.Context(), "ids and limit must be integers and times formatted as RFC 3339"))
+		return
+	}
+
+	entries, err := cc.Service.GetAll(c.Request.Context(), filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": entries, "total": len(entries)})
+}
@@ -0,0 +1,91 @@
+// Only handles API request validation and response formatting for personal API tokens
+package controllers
+
+import (
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiAPITokenController struct {
+	Service services.APITokenServiceInterface
+}
+
+// NewAPIAPITokenController creates a new instance of ApiAPITokenController
+func NewAPIAPITokenController(service services.APITokenServiceInterface) *ApiAPITokenController {
+	return &ApiAPITokenController{Service: service}
+}
+
+// GetMine returns the API tokens of the signed in user
+func (cc *ApiAPITokenController) GetMine(c *gin.Context) {
+	cc.list(c, currentUserID(c))
+}
+
+// CreateMine mints an API token for the signed in user. Its secret is only in this response.
+func (cc *ApiAPITokenController) CreateMine(c *gin.Context) {
+	var form types.APITokenForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	token, err := cc.Service.Create(c.Request.Context(), currentUserID(c), form)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, token)
+}
+
+// RevokeMine revokes the API token in the path of the signed in user
+func (cc *ApiAPITokenController) RevokeMine(c *gin.Context) {
+	id, ok := idParam(c, "API token")
+	if !ok {
+		return
+	}
+	cc.revoke(c, currentUserID(c), id)
+}
+
+// GetByUserID returns the API tokens of the user in the path
+func (cc *ApiAPITokenController) GetByUserID(c *gin.Context) {
+	userID, ok := idParam(c, "User")
+	if !ok {
+		return
+	}
+	cc.list(c, userID)
+}
+
+// RevokeByUserID revokes the API token token_id of the user in the path
+func (cc *ApiAPITokenController) RevokeByUserID(c *gin.Context) {
+	userID, ok := idParam(c, "User")
+	if !ok {
+		return
+	}
+	id, ok := intParam(c, "token_id", "API token")
+	if !ok {
+		return
+	}
+	cc.revoke(c, userID, id)
+}
+
+func (cc *ApiAPITokenController) list(c *gin.Context, userID int) {
+	tokens, err := cc.Service.GetByUserID(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tokens, "total": len(tokens)})
+}
+
+func (cc *ApiAPITokenController) revoke(c *gin.Context, userID, id int) {
+	token, err := cc.Service.Revoke(c.Request.Context(), userID, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, token)
+}
@@ -0,0 +1,91 @@
+// Only handles API request validation and response formatting for audit teams
+package controllers
+
+import (
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiAuditAssignmentController struct {
+	Service services.AuditAssignmentServiceInterface
+}
+
+// NewAPIAuditAssignmentController creates a new instance of ApiAuditAssignmentController
+func NewAPIAuditAssignmentController(service services.AuditAssignmentServiceInterface) *ApiAuditAssignmentController {
+	return &ApiAuditAssignmentController{Service: service}
+}
+
+// GetByAuditPlanID returns the lead and support auditors of the audit plan in the path
+func (cc *ApiAuditAssignmentController) GetByAuditPlanID(c *gin.Context) {
+	id, ok := idParam(c, "Audit plan")
+	if !ok {
+		return
+	}
+
+	team, err := cc.Service.GetByAuditPlanID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": team, "total": len(team)})
+}
+
+// GetByUserID returns the audit plans the auditor in the path leads or supports
+func (cc *ApiAuditAssignmentController) GetByUserID(c *gin.Context) {
+	id, ok := idParam(c, "User")
+	if !ok {
+		return
+	}
+
+	assignments, err := cc.Service.GetByUserID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": assignments, "total": len(assignments)})
+}
+
+// Assign adds a support auditor to the audit plan in the path and returns the new team
+func (cc *ApiAuditAssignmentController) Assign(c *gin.Context) {
+	id, ok := idParam(c, "Audit plan")
+	if !ok {
+		return
+	}
+
+	var form types.AuditAssignmentForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	team, err := cc.Service.Assign(c.Request.Context(), id, form.UserID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": team, "total": len(team)})
+}
+
+func (cc *ApiAuditAssignmentController) Unassign(c *gin.Context) {
+	id, ok := idParam(c, "Audit plan")
+	if !ok {
+		return
+	}
+
+	userID, ok := intParam(c, "user_id", "User")
+	if !ok {
+		return
+	}
+
+	if err := cc.Service.Unassign(c.Request.Context(), id, userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
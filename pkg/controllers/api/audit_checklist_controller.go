@@ -0,0 +1,57 @@
+// Only handles API request validation and response formatting for audit checklists
+package controllers
+
+import (
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiAuditChecklistController struct {
+	Service services.AuditChecklistServiceInterface
+}
+
+// NewAPIAuditChecklistController creates a new instance of ApiAuditChecklistController
+func NewAPIAuditChecklistController(service services.AuditChecklistServiceInterface) *ApiAuditChecklistController {
+	return &ApiAuditChecklistController{Service: service}
+}
+
+// GetByAuditPlanID returns the scope and audit questions of the plan in the path
+func (cc *ApiAuditChecklistController) GetByAuditPlanID(c *gin.Context) {
+	id, ok := idParam(c, "Audit plan")
+	if !ok {
+		return
+	}
+
+	checklist, err := cc.Service.GetByAuditPlanID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, checklist)
+}
+
+// Generate replaces the scope of the plan with the requirement subtrees in the body and returns
+// the resulting checklist with the number of audit questions added, removed and kept
+func (cc *ApiAuditChecklistController) Generate(c *gin.Context) {
+	id, ok := idParam(c, "Audit plan")
+	if !ok {
+		return
+	}
+
+	var form types.AuditChecklistForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	checklist, err := cc.Service.Generate(c.Request.Context(), id, form.RequirementIDs)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, checklist)
+}
@@ -0,0 +1,205 @@
+// Only handles API request validation and response formatting for running audits
+package controllers
+
+import (
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiAuditExecutionController struct {
+	Service services.AuditExecutionServiceInterface
+}
+
+// NewAPIAuditExecutionController creates a new instance of ApiAuditExecutionController
+func NewAPIAuditExecutionController(service services.AuditExecutionServiceInterface) *ApiAuditExecutionController {
+	return &ApiAuditExecutionController{Service: service}
+}
+
+// GetQuestions returns the questions of the audit plan in the path with everything recorded so far.
+// Provided evidence is redacted for the signed in user.
+func (cc *ApiAuditExecutionController) GetQuestions(c *gin.Context) {
+	id, ok := idParam(c, "Audit plan")
+	if !ok {
+		return
+	}
+	userID := currentUserID(c)
+
+	questions, err := cc.Service.GetQuestions(c.Request.Context(), id, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": questions, "total": len(questions)})
+}
+
+// GetProgress returns how many questions of the audit plan in the path are answered
+func (cc *ApiAuditExecutionController) GetProgress(c *gin.Context) {
+	id, ok := idParam(c, "Audit plan")
+	if !ok {
+		return
+	}
+
+	progress, err := cc.Service.GetProgress(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// GetQuestion returns the audit question in the path, provided evidence is redacted for the
+// signed in user
+func (cc *ApiAuditExecutionController) GetQuestion(c *gin.Context) {
+	id, ok := idParam(c, "Audit question")
+	if !ok {
+		return
+	}
+	userID := currentUserID(c)
+
+	question, err := cc.Service.GetQuestion(c.Request.Context(), types.AuditQuestion{ID: id}, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, question)
+}
+
+// ProvideEvidence records evidence for the audit question in the path
+func (cc *ApiAuditExecutionController) ProvideEvidence(c *gin.Context) {
+	id, ok := idParam(c, "Audit question")
+	if !ok {
+		return
+	}
+
+	var form types.EvidenceProvidedForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	provided := evidenceProvidedFromForm(c, form)
+	provided.AuditQuestionID = id
+
+	created, err := cc.Service.ProvideEvidence(c.Request.Context(), provided)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+func (cc *ApiAuditExecutionController) UpdateEvidence(c *gin.Context) {
+	id, ok := idParam(c, "Provided evidence")
+	if !ok {
+		return
+	}
+
+	var form types.EvidenceProvidedForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	provided := evidenceProvidedFromForm(c, form)
+	provided.ID = id
+
+	updated, err := cc.Service.UpdateEvidence(c.Request.Context(), provided)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+func (cc *ApiAuditExecutionController) DeleteEvidence(c *gin.Context) {
+	id, ok := idParam(c, "Provided evidence")
+	if !ok {
+		return
+	}
+
+	if err := cc.Service.DeleteEvidence(c.Request.Context(), types.EvidenceProvided{ID: id, UserID: currentUserID(c)}); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ReviewEvidence accepts or rejects the provided evidence in the path
+func (cc *ApiAuditExecutionController) ReviewEvidence(c *gin.Context) {
+	id, ok := idParam(c, "Provided evidence")
+	if !ok {
+		return
+	}
+
+	var form types.EvidenceReviewForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+	form.ReviewedBy = currentUserID(c)
+
+	reviewed, err := cc.Service.ReviewEvidence(c.Request.Context(), id, form)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reviewed)
+}
+
+// ReplaceEvidence supersedes the provided evidence in the path with the evidence in the body
+func (cc *ApiAuditExecutionController) ReplaceEvidence(c *gin.Context) {
+	id, ok := idParam(c, "Provided evidence")
+	if !ok {
+		return
+	}
+
+	var form types.EvidenceProvidedForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	created, err := cc.Service.ReplaceEvidence(c.Request.Context(), id, evidenceProvidedFromForm(c, form))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// GetEvidenceChain returns the submissions the provided evidence in the path replaced or was
+// replaced by, redacted for the signed in user
+func (cc *ApiAuditExecutionController) GetEvidenceChain(c *gin.Context) {
+	id, ok := idParam(c, "Provided evidence")
+	if !ok {
+		return
+	}
+	userID := currentUserID(c)
+
+	chain, err := cc.Service.GetEvidenceChain(c.Request.Context(), id, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": chain, "total": len(chain)})
+}
+
+// evidenceProvidedFromForm records the evidence of form on behalf of the signed in user
+func evidenceProvidedFromForm(c *gin.Context, form types.EvidenceProvidedForm) types.EvidenceProvided {
+	return types.EvidenceProvided{
+		EvidenceID:         form.EvidenceID,
+		UserID:             currentUserID(c),
+		Provided:           form.Provided,
+		TypeVal:            types.ReferenceValue{ID: form.TypeID},
+		ConfidentialityVal: types.ReferenceValue{ID: form.ConfidentialityID},
+		RetentionDays:      form.RetentionDays,
+	}
+}
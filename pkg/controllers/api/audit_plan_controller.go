@@ -0,0 +1,187 @@
+// Only handles API request validation and response formatting for audit plans
+package controllers
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// dateLayout is the format of the from and to query parameters
+const dateLayout = "2006-01-02"
+
+type ApiAuditPlanController struct {
+	Service services.AuditPlanServiceInterface
+}
+
+// NewAPIAuditPlanController creates a new instance of ApiAuditPlanController
+func NewAPIAuditPlanController(service services.AuditPlanServiceInterface) *ApiAuditPlanController {
+	return &ApiAuditPlanController{Service: service}
+}
+
+// GetAll lists the audit plans, optionally filtered by the standard_id, auditor_id, status, type,
+// from and to query parameters. Dates use YYYY-MM-DD and to is inclusive.
+func (cc *ApiAuditPlanController) GetAll(c *gin.Context) {
+	filter, ok := auditPlanFilter(c)
+	if !ok {
+		return
+	}
+
+	plans, err := cc.Service.GetAll(c.Request.Context(), filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": plans, "total": len(plans)})
+}
+
+func (cc *ApiAuditPlanController) GetByID(c *gin.Context) {
+	id, ok := idParam(c, "Audit plan")
+	if !ok {
+		return
+	}
+
+	plan, err := cc.Service.GetByID(c.Request.Context(), types.AuditPlan{ID: id})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// Create adds an audit plan, new plans always start as DRAFT
+func (cc *ApiAuditPlanController) Create(c *gin.Context) {
+	var form types.AuditPlanForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	plan, err := cc.Service.Create(c.Request.Context(), auditPlanFromForm(form))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, plan)
+}
+
+func (cc *ApiAuditPlanController) Update(c *gin.Context) {
+	id, ok := idParam(c, "Audit plan")
+	if !ok {
+		return
+	}
+
+	var form types.AuditPlanForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	plan := auditPlanFromForm(form)
+	plan.ID = id
+
+	updated, err := cc.Service.Update(c.Request.Context(), plan)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// ChangeStatus moves the plan to the status code in the body, e.g. {"status": "SCHEDULED"}
+func (cc *ApiAuditPlanController) ChangeStatus(c *gin.Context) {
+	id, ok := idParam(c, "Audit plan")
+	if !ok {
+		return
+	}
+
+	var form types.AuditPlanStatusForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	plan, err := cc.Service.ChangeStatus(c.Request.Context(), types.AuditPlan{ID: id}, form.Status)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+func (cc *ApiAuditPlanController) Delete(c *gin.Context) {
+	id, ok := idParam(c, "Audit plan")
+	if !ok {
+		return
+	}
+
+	if err := cc.Service.Delete(c.Request.Context(), types.AuditPlan{ID: id}); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func auditPlanFromForm(form types.AuditPlanForm) types.AuditPlan {
+	return types.AuditPlan{
+		StandardID:    form.StandardID,
+		LeadAuditorID: form.LeadAuditorID,
+		Name:          form.Name,
+		ScheduledDate: form.ScheduledDate,
+		Team:          form.Team,
+		Scope:         form.Scope,
+		TypeVal:       types.ReferenceValue{ID: form.TypeID},
+	}
+}
+
+// auditPlanFilter reads the list query parameters. On failure it records an INVALID_DATA error
+// for the error middleware and returns false.
+func auditPlanFilter(c *gin.Context) (types.AuditPlanFilter, bool) {
+	ctx := c.Request.Context()
+	filter := types.AuditPlanFilter{
+		Status: c.Query("status"),
+		Type:   c.Query("type"),
+	}
+
+	for name, target := range map[string]*int{"standard_id": &filter.StandardID, "auditor_id": &filter.AuditorID} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.Atoi(value)
+		if err != nil || id <= 0 {
+			c.Error(custom_errors.InvalidData(ctx, fmt.Sprintf("%s must be a positive integer", name)))
+			return types.AuditPlanFilter{}, false
+		}
+		*target = id
+	}
+
+	for name, target := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		date, err := time.Parse(dateLayout, value)
+		if err != nil {
+			c.Error(custom_errors.InvalidData(ctx, fmt.Sprintf("%s must be a date formatted as YYYY-MM-DD", name)))
+			return types.AuditPlanFilter{}, false
+		}
+		*target = date
+	}
+
+	// The repository compares with <, so move to the start of the next day to include it
+	if !filter.To.IsZero() {
+		filter.To = filter.To.AddDate(0, 0, 1)
+	}
+
+	return filter, true
+}
@@ -0,0 +1,63 @@
+// Only handles API request validation and response formatting for signing in and out
+package controllers
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/middleware"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiAuthController struct {
+	Service      services.AuthServiceInterface
+	SecureCookie bool
+}
+
+// NewAPIAuthController creates a new instance of ApiAuthController. secureCookie limits the
+// session cookie to HTTPS and is only turned off for local development.
+func NewAPIAuthController(service services.AuthServiceInterface, secureCookie bool) *ApiAuthController {
+	return &ApiAuthController{Service: service, SecureCookie: secureCookie}
+}
+
+// Login checks the email and password of the body and sets the session cookie
+func (cc *ApiAuthController) Login(c *gin.Context) {
+	var form types.LoginForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	session, err := cc.Service.Login(c.Request.Context(), form.Email, form.Password)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	middleware.SetSessionCookie(c, session, cc.SecureCookie)
+	c.JSON(http.StatusOK, session)
+}
+
+// Logout ends the session of the cookie and removes it
+func (cc *ApiAuthController) Logout(c *gin.Context) {
+	token, _ := c.Cookie(middleware.SessionCookie)
+	if err := cc.Service.Logout(c.Request.Context(), token); err != nil {
+		c.Error(err)
+		return
+	}
+
+	middleware.ClearSessionCookie(c, cc.SecureCookie)
+	c.Status(http.StatusNoContent)
+}
+
+// Me returns the signed in user
+func (cc *ApiAuthController) Me(c *gin.Context) {
+	user, ok := services.CurrentUser(c.Request.Context())
+	if !ok {
+		c.Error(custom_errors.Unauthorized(c.Request.Context(), "sign in required"))
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
@@ -0,0 +1,110 @@
+// Only handles API request validation and response formatting for audit question comments
+package controllers
+
+import (
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiCommentController struct {
+	Service services.CommentServiceInterface
+}
+
+// NewAPICommentController creates a new instance of ApiCommentController
+func NewAPICommentController(service services.CommentServiceInterface) *ApiCommentController {
+	return &ApiCommentController{Service: service}
+}
+
+// GetThread returns the comments of the audit question in the path with their replies nested
+func (cc *ApiCommentController) GetThread(c *gin.Context) {
+	id, ok := idParam(c, "Audit question")
+	if !ok {
+		return
+	}
+
+	comments, err := cc.Service.GetThread(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": comments, "total": len(comments)})
+}
+
+// Create attaches a comment of the signed in user, or a reply when parent_id is set, to the audit
+// question in the path
+func (cc *ApiCommentController) Create(c *gin.Context) {
+	id, ok := idParam(c, "Audit question")
+	if !ok {
+		return
+	}
+
+	var form types.CommentForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	comment, err := cc.Service.Add(c.Request.Context(), id, types.Comment{UserID: currentUserID(c), Text: form.Text, ParentID: form.ParentID})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// Update replaces the text of the comment in the path
+func (cc *ApiCommentController) Update(c *gin.Context) {
+	id, ok := idParam(c, "Comment")
+	if !ok {
+		return
+	}
+
+	var form types.CommentEditForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+	form.UserID = currentUserID(c)
+
+	comment, err := cc.Service.Edit(c.Request.Context(), id, form)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// Delete removes the comment in the path on behalf of the signed in user
+func (cc *ApiCommentController) Delete(c *gin.Context) {
+	id, ok := idParam(c, "Comment")
+	if !ok {
+		return
+	}
+
+	if _, err := cc.Service.Delete(c.Request.Context(), id, currentUserID(c)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetRevisions returns the earlier texts of the comment in the path
+func (cc *ApiCommentController) GetRevisions(c *gin.Context) {
+	id, ok := idParam(c, "Comment")
+	if !ok {
+		return
+	}
+
+	revisions, err := cc.Service.GetRevisions(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": revisions, "total": len(revisions)})
+}
@@ -4,8 +4,8 @@ package controllers
 import (
 	"ISO_Auditing_Tool/pkg/services"
 	"ISO_Auditing_Tool/pkg/types"
+	"context"
 	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -19,42 +19,39 @@ func NewAPIDraftController(service services.DraftServiceInterface) *ApiDraftCont
 	return &ApiDraftController{Service: service}
 }
 
+// Create saves a draft of the signed in user, a user_id in the body is ignored
 func (cc *ApiDraftController) Create(c *gin.Context) {
 	var draft types.Draft
-
-	if err := c.ShouldBindJSON(&draft); err != nil {
-		// TODO: Implement custom errors
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindAndValidate(c, &draft) {
 		return
 	}
+	draft.UserID = currentUserID(c)
 
 	draft, err := cc.Service.Create(c.Request.Context(), draft)
 	if err != nil {
-		// TODO: Implement custom errors
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{"id": draft.ID})
 }
 
+// Update changes a draft of the signed in user, a user_id in the body is ignored
 func (cc *ApiDraftController) Update(c *gin.Context) {
-	var draft types.Draft
-
-	if err := c.ShouldBindJSON(&draft); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	id, ok := idParam(c, "Draft")
+	if !ok {
 		return
 	}
 
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var draft types.Draft
+	if !bindAndValidate(c, &draft) {
 		return
 	}
 
 	draft.ID = id
-	if draft, err := cc.Service.Update(c.Request.Context(), draft); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "draft": draft})
+	draft.UserID = currentUserID(c)
+	if _, err := cc.Service.Update(c.Request.Context(), draft); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -62,11 +59,69 @@ func (cc *ApiDraftController) Update(c *gin.Context) {
 }
 
 func (cc *ApiDraftController) GetAll(c *gin.Context) {
-	drafts, err := cc.Service.GetAll(c)
+	drafts, err := cc.Service.GetAll(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"data": drafts, "total": len(drafts)})
 }
+
+// GetByID returns a draft with the steps of its approval workflow
+func (cc *ApiDraftController) GetByID(c *gin.Context) {
+	id, ok := idParam(c, "Draft")
+	if !ok {
+		return
+	}
+
+	draft, err := cc.Service.GetByID(c.Request.Context(), types.Draft{ID: id})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, draft)
+}
+
+// Submit asks for approval of a draft of the signed in user
+func (cc *ApiDraftController) Submit(c *gin.Context) {
+	cc.transition(c, cc.Service.Submit)
+}
+
+// Approve records the approval of a pending draft by the signed in user
+func (cc *ApiDraftController) Approve(c *gin.Context) {
+	cc.transition(c, cc.Service.Approve)
+}
+
+// Reject sends a pending draft back to its author
+func (cc *ApiDraftController) Reject(c *gin.Context) {
+	cc.transition(c, cc.Service.Reject)
+}
+
+// Publish marks an approved draft as published
+func (cc *ApiDraftController) Publish(c *gin.Context) {
+	cc.transition(c, cc.Service.Publish)
+}
+
+// transition binds the comment of a workflow step and runs it for the signed in user
+func (cc *ApiDraftController) transition(c *gin.Context, step func(ctx context.Context, id int, form types.DraftTransitionForm) (types.Draft, error)) {
+	id, ok := idParam(c, "Draft")
+	if !ok {
+		return
+	}
+
+	var form types.DraftTransitionForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+	form.UserID = currentUserID(c)
+
+	draft, err := step(c.Request.Context(), id, form)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, draft)
+}
@@ -0,0 +1,76 @@
+// Only handles API request validation and response formatting for access to provided evidence
+package controllers
+
+import (
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiEvidenceAccessController struct {
+	Service services.EvidenceAccessServiceInterface
+}
+
+// NewAPIEvidenceAccessController creates a new instance of ApiEvidenceAccessController
+func NewAPIEvidenceAccessController(service services.EvidenceAccessServiceInterface) *ApiEvidenceAccessController {
+	return &ApiEvidenceAccessController{Service: service}
+}
+
+// GetGrants lists who may see the provided evidence in the path to the signed in user
+func (cc *ApiEvidenceAccessController) GetGrants(c *gin.Context) {
+	id, ok := idParam(c, "Provided evidence")
+	if !ok {
+		return
+	}
+
+	grants, err := cc.Service.GetGrants(c.Request.Context(), id, currentUserID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": grants, "total": len(grants)})
+}
+
+// Grant lets a user see the provided evidence in the path
+func (cc *ApiEvidenceAccessController) Grant(c *gin.Context) {
+	id, ok := idParam(c, "Provided evidence")
+	if !ok {
+		return
+	}
+
+	var form types.EvidenceAccessGrantForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+	form.GrantedBy = currentUserID(c)
+
+	grants, err := cc.Service.Grant(c.Request.Context(), id, form)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": grants, "total": len(grants)})
+}
+
+// Revoke removes the access of the user in the path on behalf of the signed in user
+func (cc *ApiEvidenceAccessController) Revoke(c *gin.Context) {
+	id, ok := idParam(c, "Provided evidence")
+	if !ok {
+		return
+	}
+	userID, ok := intParam(c, "user_id", "User")
+	if !ok {
+		return
+	}
+
+	if err := cc.Service.Revoke(c.Request.Context(), id, userID, currentUserID(c)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
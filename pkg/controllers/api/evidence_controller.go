@@ -0,0 +1,117 @@
+// Only handles API request validation and response formatting for expected evidence
+package controllers
+
+import (
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiEvidenceController struct {
+	Service services.EvidenceServiceInterface
+}
+
+// NewAPIEvidenceController creates a new instance of ApiEvidenceController
+func NewAPIEvidenceController(service services.EvidenceServiceInterface) *ApiEvidenceController {
+	return &ApiEvidenceController{Service: service}
+}
+
+func (cc *ApiEvidenceController) GetByID(c *gin.Context) {
+	id, ok := idParam(c, "Evidence")
+	if !ok {
+		return
+	}
+
+	evidence, err := cc.Service.GetByID(c.Request.Context(), types.Evidence{ID: id})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, evidence)
+}
+
+// Create adds expected evidence to the question in the path
+func (cc *ApiEvidenceController) Create(c *gin.Context) {
+	questionID, ok := idParam(c, "Question")
+	if !ok {
+		return
+	}
+
+	var form types.EvidenceForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	evidence, err := cc.Service.Create(c.Request.Context(), types.Evidence{
+		QuestionID: questionID,
+		TypeVal:    types.ReferenceValue{ID: form.TypeID},
+		Expected:   form.Expected,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, evidence)
+}
+
+func (cc *ApiEvidenceController) Update(c *gin.Context) {
+	id, ok := idParam(c, "Evidence")
+	if !ok {
+		return
+	}
+
+	var form types.EvidenceForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	evidence, err := cc.Service.Update(c.Request.Context(), types.Evidence{
+		ID:       id,
+		TypeVal:  types.ReferenceValue{ID: form.TypeID},
+		Expected: form.Expected,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, evidence)
+}
+
+func (cc *ApiEvidenceController) Delete(c *gin.Context) {
+	id, ok := idParam(c, "Evidence")
+	if !ok {
+		return
+	}
+
+	if err := cc.Service.Delete(c.Request.Context(), types.Evidence{ID: id}); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Reorder sets the order of the expected evidence of the question in the path
+func (cc *ApiEvidenceController) Reorder(c *gin.Context) {
+	questionID, ok := idParam(c, "Question")
+	if !ok {
+		return
+	}
+
+	var form types.EvidenceReorderForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	if err := cc.Service.Reorder(c.Request.Context(), questionID, form.EvidenceIDs); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
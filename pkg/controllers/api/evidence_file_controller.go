@@ -0,0 +1,87 @@
+// Only handles API request validation and response formatting for evidence files
+package controllers
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiEvidenceFileController struct {
+	Service services.EvidenceFileServiceInterface
+}
+
+// NewAPIEvidenceFileController creates a new instance of ApiEvidenceFileController
+func NewAPIEvidenceFileController(service services.EvidenceFileServiceInterface) *ApiEvidenceFileController {
+	return &ApiEvidenceFileController{Service: service}
+}
+
+// Upload streams the "file" part of a multipart/form-data body into the file of the provided
+// evidence in the path on behalf of the signed in user. The body is never buffered.
+func (cc *ApiEvidenceFileController) Upload(c *gin.Context) {
+	id, ok := idParam(c, "Provided evidence")
+	if !ok {
+		return
+	}
+
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		c.Error(custom_errors.InvalidData(c.Request.Context(), "body must be multipart/form-data"))
+		return
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			c.Error(custom_errors.InvalidData(c.Request.Context(), "file part is missing"))
+			return
+		}
+		if err != nil {
+			c.Error(custom_errors.InvalidData(c.Request.Context(), "malformed multipart body"))
+			return
+		}
+		if part.FormName() != "file" {
+			continue
+		}
+
+		name := part.FileName()
+		if name == "" {
+			name = "file"
+		}
+
+		provided, err := cc.Service.Upload(c.Request.Context(), types.EvidenceProvided{ID: id, UserID: currentUserID(c)}, name, part)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, provided)
+		return
+	}
+}
+
+// Download streams the file of the provided evidence in the path as an attachment to the signed in
+// user. The ETag is the SHA-256 recorded at upload.
+func (cc *ApiEvidenceFileController) Download(c *gin.Context) {
+	id, ok := idParam(c, "Provided evidence")
+	if !ok {
+		return
+	}
+
+	file, content, err := cc.Service.Download(c.Request.Context(), types.EvidenceProvided{ID: id, UserID: currentUserID(c)})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	defer content.Close()
+
+	c.Header("ETag", `"`+file.SHA256+`"`)
+	c.DataFromReader(http.StatusOK, file.Size, file.MIMEType, content, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": file.Name}),
+	})
+}
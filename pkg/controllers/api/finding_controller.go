@@ -0,0 +1,251 @@
+// Only handles API request validation and response formatting for findings
+package controllers
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiFindingController struct {
+	Service services.FindingServiceInterface
+	Overdue services.OverdueFindingJobInterface
+}
+
+// NewAPIFindingController creates a new instance of ApiFindingController
+func NewAPIFindingController(service services.FindingServiceInterface, overdue services.OverdueFindingJobInterface) *ApiFindingController {
+	return &ApiFindingController{Service: service, Overdue: overdue}
+}
+
+// GetAll lists the findings, optionally filtered by the audit_id, owner_id, severity, status,
+// due_from and due_to query parameters. Dates use YYYY-MM-DD and due_to is inclusive.
+func (cc *ApiFindingController) GetAll(c *gin.Context) {
+	var query types.FindingQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(custom_errors.InvalidData(c.Request.Context(), "ids must be integers and dates formatted as YYYY-MM-DD"))
+		return
+	}
+
+	findings, err := cc.Service.GetAll(c.Request.Context(), query.Filter())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": findings, "total": len(findings)})
+}
+
+// GetOverdue reports the open findings past their due date or within the warning window of the
+// overdue finding job
+func (cc *ApiFindingController) GetOverdue(c *gin.Context) {
+	report, err := cc.Overdue.Report(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (cc *ApiFindingController) GetByID(c *gin.Context) {
+	id, ok := idParam(c, "Finding")
+	if !ok {
+		return
+	}
+
+	finding, err := cc.Service.GetByID(c.Request.Context(), types.Finding{ID: id})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, finding)
+}
+
+// Create raises a finding against the audit question in the path
+func (cc *ApiFindingController) Create(c *gin.Context) {
+	id, ok := idParam(c, "Audit question")
+	if !ok {
+		return
+	}
+
+	var form types.FindingForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	finding := findingFromForm(form)
+	finding.AuditQuestionID = id
+	finding.CreatedBy = currentUserID(c)
+
+	created, err := cc.Service.Create(c.Request.Context(), finding)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+func (cc *ApiFindingController) Update(c *gin.Context) {
+	id, ok := idParam(c, "Finding")
+	if !ok {
+		return
+	}
+
+	var form types.FindingForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	finding := findingFromForm(form)
+	finding.ID = id
+
+	updated, err := cc.Service.Update(c.Request.Context(), finding)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// ChangeStatus moves the finding to the status code in the body, e.g.
+// {"status": "WAIVED", "reason": "..."}, on behalf of the signed in user
+func (cc *ApiFindingController) ChangeStatus(c *gin.Context) {
+	id, ok := idParam(c, "Finding")
+	if !ok {
+		return
+	}
+
+	var form types.FindingStatusForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	finding, err := cc.Service.ChangeStatus(c.Request.Context(), types.Finding{ID: id, StatusReason: form.Reason}, form.Status, currentUserID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, finding)
+}
+
+func (cc *ApiFindingController) Delete(c *gin.Context) {
+	id, ok := idParam(c, "Finding")
+	if !ok {
+		return
+	}
+
+	if err := cc.Service.Delete(c.Request.Context(), types.Finding{ID: id}); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AddCorrectiveAction plans a corrective action for the finding in the path
+func (cc *ApiFindingController) AddCorrectiveAction(c *gin.Context) {
+	id, ok := idParam(c, "Finding")
+	if !ok {
+		return
+	}
+
+	var form types.CorrectiveActionForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	action := correctiveActionFromForm(form)
+	action.FindingID = id
+
+	created, err := cc.Service.AddCorrectiveAction(c.Request.Context(), action)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+func (cc *ApiFindingController) UpdateCorrectiveAction(c *gin.Context) {
+	id, ok := idParam(c, "Corrective action")
+	if !ok {
+		return
+	}
+
+	var form types.CorrectiveActionForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	action := correctiveActionFromForm(form)
+	action.ID = id
+
+	updated, err := cc.Service.UpdateCorrectiveAction(c.Request.Context(), action)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+func (cc *ApiFindingController) CompleteCorrectiveAction(c *gin.Context) {
+	id, ok := idParam(c, "Corrective action")
+	if !ok {
+		return
+	}
+
+	var form types.CorrectiveActionCompleteForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	completed, err := cc.Service.CompleteCorrectiveAction(c.Request.Context(), types.CorrectiveAction{ID: id, CompletionNote: form.CompletionNote})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, completed)
+}
+
+// VerifyCorrectiveAction records that the signed in auditor checked the completed corrective action
+func (cc *ApiFindingController) VerifyCorrectiveAction(c *gin.Context) {
+	id, ok := idParam(c, "Corrective action")
+	if !ok {
+		return
+	}
+
+	verified, err := cc.Service.VerifyCorrectiveAction(c.Request.Context(), types.CorrectiveAction{ID: id}, currentUserID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, verified)
+}
+
+func findingFromForm(form types.FindingForm) types.Finding {
+	return types.Finding{
+		TypeVal:           types.ReferenceValue{ID: form.TypeID},
+		SeverityVal:       types.ReferenceValue{ID: form.SeverityID},
+		Description:       form.Description,
+		DueDate:           form.DueDate,
+		ResponsibleUserID: form.ResponsibleUserID,
+	}
+}
+
+func correctiveActionFromForm(form types.CorrectiveActionForm) types.CorrectiveAction {
+	return types.CorrectiveAction{
+		Action:     form.Action,
+		OwnerID:    form.OwnerID,
+		TargetDate: form.TargetDate,
+	}
+}
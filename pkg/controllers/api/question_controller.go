@@ -0,0 +1,134 @@
+// Only handles API request validation and response formatting for audit questions
+package controllers
+
+import (
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiQuestionController struct {
+	Service services.QuestionServiceInterface
+}
+
+// NewAPIQuestionController creates a new instance of ApiQuestionController
+func NewAPIQuestionController(service services.QuestionServiceInterface) *ApiQuestionController {
+	return &ApiQuestionController{Service: service}
+}
+
+// GetByRequirementID returns the questions of the requirement in the path
+func (cc *ApiQuestionController) GetByRequirementID(c *gin.Context) {
+	requirementID, ok := idParam(c, "Requirement")
+	if !ok {
+		return
+	}
+
+	questions, err := cc.Service.GetByRequirementID(c.Request.Context(), requirementID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": questions})
+}
+
+// GetByID returns a question with its expected evidence
+func (cc *ApiQuestionController) GetByID(c *gin.Context) {
+	id, ok := idParam(c, "Question")
+	if !ok {
+		return
+	}
+
+	question, err := cc.Service.GetByID(c.Request.Context(), types.Question{ID: id})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, question)
+}
+
+// Create adds a question to the requirement in the path
+func (cc *ApiQuestionController) Create(c *gin.Context) {
+	requirementID, ok := idParam(c, "Requirement")
+	if !ok {
+		return
+	}
+
+	var form types.QuestionForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	question, err := cc.Service.Create(c.Request.Context(), types.Question{
+		RequirementID: requirementID,
+		Question:      form.Question,
+		Guidance:      form.Guidance,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, question)
+}
+
+func (cc *ApiQuestionController) Update(c *gin.Context) {
+	id, ok := idParam(c, "Question")
+	if !ok {
+		return
+	}
+
+	var form types.QuestionForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	question, err := cc.Service.Update(c.Request.Context(), types.Question{
+		ID:       id,
+		Question: form.Question,
+		Guidance: form.Guidance,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, question)
+}
+
+func (cc *ApiQuestionController) Delete(c *gin.Context) {
+	id, ok := idParam(c, "Question")
+	if !ok {
+		return
+	}
+
+	if err := cc.Service.Delete(c.Request.Context(), types.Question{ID: id}); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Reorder sets the order of the questions of the requirement in the path
+func (cc *ApiQuestionController) Reorder(c *gin.Context) {
+	requirementID, ok := idParam(c, "Requirement")
+	if !ok {
+		return
+	}
+
+	var form types.QuestionReorderForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	if err := cc.Service.Reorder(c.Request.Context(), requirementID, form.QuestionIDs); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
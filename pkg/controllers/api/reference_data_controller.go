@@ -0,0 +1,102 @@
+// Only handles API request validation and response formatting for reference data
+package controllers
+
+import (
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiReferenceDataController struct {
+	Service services.ReferenceDataServiceInterface
+}
+
+// NewAPIReferenceDataController creates a new instance of ApiReferenceDataController
+func NewAPIReferenceDataController(service services.ReferenceDataServiceInterface) *ApiReferenceDataController {
+	return &ApiReferenceDataController{Service: service}
+}
+
+// GetAll returns every reference type with its values
+func (cc *ApiReferenceDataController) GetAll(c *gin.Context) {
+	referenceTypes, err := cc.Service.GetTypes(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": referenceTypes, "total": len(referenceTypes)})
+}
+
+// GetByType returns the reference type named in the path, e.g. /reference-data/drafts.status_id
+func (cc *ApiReferenceDataController) GetByType(c *gin.Context) {
+	referenceType, err := cc.Service.GetType(c.Request.Context(), c.Param("type"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, referenceType)
+}
+
+// Create adds a value to the reference type named in the path
+func (cc *ApiReferenceDataController) Create(c *gin.Context) {
+	var form types.ReferenceValueForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	value, err := cc.Service.Create(c.Request.Context(), c.Param("type"), types.ReferenceValue{
+		Code:        form.Code,
+		Name:        form.Name,
+		Description: form.Description,
+		IsActive:    form.IsActive,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, value)
+}
+
+func (cc *ApiReferenceDataController) Update(c *gin.Context) {
+	id, ok := idParam(c, "Reference value")
+	if !ok {
+		return
+	}
+
+	var form types.ReferenceValueForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	value, err := cc.Service.Update(c.Request.Context(), types.ReferenceValue{
+		ID:          id,
+		Code:        form.Code,
+		Name:        form.Name,
+		Description: form.Description,
+		IsActive:    form.IsActive,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, value)
+}
+
+func (cc *ApiReferenceDataController) Delete(c *gin.Context) {
+	id, ok := idParam(c, "Reference value")
+	if !ok {
+		return
+	}
+
+	if err := cc.Service.Delete(c.Request.Context(), types.ReferenceValue{ID: id}); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
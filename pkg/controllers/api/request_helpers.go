@@ -0,0 +1,48 @@
+// Shared request parsing for the API controllers
+package controllers
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/validators"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// idParam reads the positive integer ":id" path parameter. On failure it records an
+// INVALID_ID error for the error middleware and returns false.
+func idParam(c *gin.Context, objectType string) (int, bool) {
+	return intParam(c, "id", objectType)
+}
+
+func intParam(c *gin.Context, name, objectType string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(name))
+	if err != nil || id <= 0 {
+		c.Error(custom_errors.InvalidID(c.Request.Context(), objectType))
+		return 0, false
+	}
+	return id, true
+}
+
+// currentUserID returns the ID of the signed in user, zero for anonymous requests. Routes behind
+// middleware.RequireUser always have one.
+func currentUserID(c *gin.Context) int {
+	user, _ := services.CurrentUser(c.Request.Context())
+	return user.ID
+}
+
+// bindAndValidate decodes the JSON body into form and runs the struct validators.
+// On failure it records the custom error for the error middleware and returns false.
+func bindAndValidate(c *gin.Context, form any) bool {
+	if err := c.ShouldBindJSON(form); err != nil {
+		c.Error(custom_errors.ErrInvalidJSON)
+		return false
+	}
+
+	if validationErr := validators.ValidateStruct(form); validationErr != nil {
+		c.Error(validationErr)
+		return false
+	}
+	return true
+}
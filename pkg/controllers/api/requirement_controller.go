@@ -0,0 +1,146 @@
+// Only handles API request validation and response formatting for the requirement tree
+package controllers
+
+import (
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiRequirementController struct {
+	Service services.RequirementServiceInterface
+}
+
+// NewAPIRequirementController creates a new instance of ApiRequirementController
+func NewAPIRequirementController(service services.RequirementServiceInterface) *ApiRequirementController {
+	return &ApiRequirementController{Service: service}
+}
+
+// GetTree returns the requirements of the standard in the path as a nested tree
+func (cc *ApiRequirementController) GetTree(c *gin.Context) {
+	standardID, ok := idParam(c, "Standard")
+	if !ok {
+		return
+	}
+
+	tree, err := cc.Service.GetTree(c.Request.Context(), standardID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tree})
+}
+
+func (cc *ApiRequirementController) GetByID(c *gin.Context) {
+	id, ok := idParam(c, "Requirement")
+	if !ok {
+		return
+	}
+
+	requirement, err := cc.Service.GetByID(c.Request.Context(), types.Requirement{ID: id})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, requirement)
+}
+
+// Create adds a requirement to the standard in the path, under parent_id when it is set
+func (cc *ApiRequirementController) Create(c *gin.Context) {
+	standardID, ok := idParam(c, "Standard")
+	if !ok {
+		return
+	}
+
+	var form types.RequirementForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	requirement, err := cc.Service.Create(c.Request.Context(), types.Requirement{
+		StandardID:    standardID,
+		ParentID:      form.ParentID,
+		LevelID:       form.LevelID,
+		ReferenceCode: form.ReferenceCode,
+		Name:          form.Name,
+		Description:   form.Description,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, requirement)
+}
+
+// Update changes the content of a requirement. The parent_id in the body is ignored, use Move instead.
+func (cc *ApiRequirementController) Update(c *gin.Context) {
+	id, ok := idParam(c, "Requirement")
+	if !ok {
+		return
+	}
+
+	var form types.RequirementForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	requirement, err := cc.Service.Update(c.Request.Context(), types.Requirement{
+		ID:            id,
+		LevelID:       form.LevelID,
+		ReferenceCode: form.ReferenceCode,
+		Name:          form.Name,
+		Description:   form.Description,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, requirement)
+}
+
+// Move reparents a requirement together with its subtree
+func (cc *ApiRequirementController) Move(c *gin.Context) {
+	id, ok := idParam(c, "Requirement")
+	if !ok {
+		return
+	}
+
+	var form types.RequirementMoveForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	requirement, err := cc.Service.Move(c.Request.Context(), types.Requirement{ID: id}, form.ParentID, form.Position)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, requirement)
+}
+
+// Reorder sets the order of the children of parent_id within the standard in the path
+func (cc *ApiRequirementController) Reorder(c *gin.Context) {
+	standardID, ok := idParam(c, "Standard")
+	if !ok {
+		return
+	}
+
+	var form types.RequirementReorderForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	if err := cc.Service.Reorder(c.Request.Context(), standardID, form.ParentID, form.RequirementIDs); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
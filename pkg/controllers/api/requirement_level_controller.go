@@ -0,0 +1,113 @@
+// Only handles API request validation and response formatting for requirement levels
+package controllers
+
+import (
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiRequirementLevelController struct {
+	Service services.RequirementLevelServiceInterface
+}
+
+// NewAPIRequirementLevelController creates a new instance of ApiRequirementLevelController
+func NewAPIRequirementLevelController(service services.RequirementLevelServiceInterface) *ApiRequirementLevelController {
+	return &ApiRequirementLevelController{Service: service}
+}
+
+// GetByStandardID returns the levels of the standard in the path ordered by level_order
+func (cc *ApiRequirementLevelController) GetByStandardID(c *gin.Context) {
+	standardID, ok := idParam(c, "Standard")
+	if !ok {
+		return
+	}
+
+	levels, err := cc.Service.GetByStandardID(c.Request.Context(), standardID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": levels})
+}
+
+func (cc *ApiRequirementLevelController) GetByID(c *gin.Context) {
+	id, ok := idParam(c, "Requirement level")
+	if !ok {
+		return
+	}
+
+	level, err := cc.Service.GetByID(c.Request.Context(), types.RequirementLevel{ID: id})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, level)
+}
+
+// Create adds a level to the standard in the path
+func (cc *ApiRequirementLevelController) Create(c *gin.Context) {
+	standardID, ok := idParam(c, "Standard")
+	if !ok {
+		return
+	}
+
+	var form types.RequirementLevelForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	level, err := cc.Service.Create(c.Request.Context(), types.RequirementLevel{
+		StandardID: standardID,
+		LevelOrder: form.LevelOrder,
+		LevelName:  form.LevelName,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, level)
+}
+
+func (cc *ApiRequirementLevelController) Update(c *gin.Context) {
+	id, ok := idParam(c, "Requirement level")
+	if !ok {
+		return
+	}
+
+	var form types.RequirementLevelForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	level, err := cc.Service.Update(c.Request.Context(), types.RequirementLevel{
+		ID:         id,
+		LevelOrder: form.LevelOrder,
+		LevelName:  form.LevelName,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, level)
+}
+
+func (cc *ApiRequirementLevelController) Delete(c *gin.Context) {
+	id, ok := idParam(c, "Requirement level")
+	if !ok {
+		return
+	}
+
+	if err := cc.Service.Delete(c.Request.Context(), types.RequirementLevel{ID: id}); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,48 @@
+// Only handles API request validation and response formatting for evidence retention
+package controllers
+
+import (
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiRetentionController struct {
+	Service services.RetentionServiceInterface
+}
+
+// NewAPIRetentionController creates a new instance of ApiRetentionController
+func NewAPIRetentionController(service services.RetentionServiceInterface) *ApiRetentionController {
+	return &ApiRetentionController{Service: service}
+}
+
+// GetReport returns the dry run of a retention sweep, the IDs listed under purge are the ones that
+// can be approved
+func (cc *ApiRetentionController) GetReport(c *gin.Context) {
+	report, err := cc.Service.Report(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// Sweep marks expired evidence and purges the approved IDs taken from a dry-run report, approved by
+// the signed in user
+func (cc *ApiRetentionController) Sweep(c *gin.Context) {
+	var form types.RetentionSweepForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	report, err := cc.Service.Sweep(c.Request.Context(), currentUserID(c), form.PurgeIDs)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,111 @@
+// Only handles API request validation and response formatting for standards
+package controllers
+
+import (
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiStandardController struct {
+	Service services.StandardServiceInterface
+}
+
+// NewAPIStandardController creates a new instance of ApiStandardController
+func NewAPIStandardController(service services.StandardServiceInterface) *ApiStandardController {
+	return &ApiStandardController{Service: service}
+}
+
+func (cc *ApiStandardController) GetAll(c *gin.Context) {
+	standards, err := cc.Service.GetAll(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": standards, "total": len(standards)})
+}
+
+// GetByID returns a single standard. Passing ?full=true includes the requirement hierarchy.
+func (cc *ApiStandardController) GetByID(c *gin.Context) {
+	id, ok := idParam(c, "Standard")
+	if !ok {
+		return
+	}
+
+	var (
+		standard types.Standard
+		err      error
+	)
+	if c.Query("full") == "true" {
+		standard, err = cc.Service.GetByIDWithFullHierarchy(c.Request.Context(), types.Standard{ID: id})
+	} else {
+		standard, err = cc.Service.GetByID(c.Request.Context(), types.Standard{ID: id})
+	}
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, standard)
+}
+
+func (cc *ApiStandardController) Create(c *gin.Context) {
+	var form types.ISOStandardForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	standard, err := cc.Service.Create(c.Request.Context(), types.Standard{
+		Name:        form.Name,
+		Description: form.Description,
+		Version:     form.Version,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, standard)
+}
+
+func (cc *ApiStandardController) Update(c *gin.Context) {
+	id, ok := idParam(c, "Standard")
+	if !ok {
+		return
+	}
+
+	var form types.ISOStandardForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	standard, err := cc.Service.Update(c.Request.Context(), types.Standard{
+		ID:          id,
+		Name:        form.Name,
+		Description: form.Description,
+		Version:     form.Version,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, standard)
+}
+
+func (cc *ApiStandardController) Delete(c *gin.Context) {
+	id, ok := idParam(c, "Standard")
+	if !ok {
+		return
+	}
+
+	if err := cc.Service.Delete(c.Request.Context(), types.Standard{ID: id}); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,145 @@
+// Only handles API request validation and response formatting for the user directory
+package controllers
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiUserController struct {
+	Service services.UserServiceInterface
+	Auth    services.AuthServiceInterface
+}
+
+// NewAPIUserController creates a new instance of ApiUserController
+func NewAPIUserController(service services.UserServiceInterface, auth services.AuthServiceInterface) *ApiUserController {
+	return &ApiUserController{Service: service, Auth: auth}
+}
+
+// GetAll returns the users matching the role_id and include_inactive query parameters
+func (cc *ApiUserController) GetAll(c *gin.Context) {
+	var filter types.UserFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.Error(custom_errors.InvalidData(c.Request.Context(), "role_id must be an integer and include_inactive a boolean"))
+		return
+	}
+
+	users, err := cc.Service.GetAll(c.Request.Context(), filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": users, "total": len(users)})
+}
+
+func (cc *ApiUserController) GetByID(c *gin.Context) {
+	id, ok := idParam(c, "User")
+	if !ok {
+		return
+	}
+
+	user, err := cc.Service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (cc *ApiUserController) Create(c *gin.Context) {
+	var form types.UserForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	user, err := cc.Service.Create(c.Request.Context(), form)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, user)
+}
+
+// ChangeRole gives the user in the path the role_id of the body
+func (cc *ApiUserController) ChangeRole(c *gin.Context) {
+	id, ok := idParam(c, "User")
+	if !ok {
+		return
+	}
+
+	var form types.UserRoleForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	user, err := cc.Service.ChangeRole(c.Request.Context(), id, form.RoleID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// Deactivate marks the user in the path inactive and reports what happened to their open findings
+func (cc *ApiUserController) Deactivate(c *gin.Context) {
+	id, ok := idParam(c, "User")
+	if !ok {
+		return
+	}
+
+	var form types.UserDeactivationForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	report, err := cc.Service.Deactivate(c.Request.Context(), id, form)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (cc *ApiUserController) Reactivate(c *gin.Context) {
+	id, ok := idParam(c, "User")
+	if !ok {
+		return
+	}
+
+	user, err := cc.Service.Reactivate(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// SetPassword replaces the password of the user in the path, signing them out everywhere
+func (cc *ApiUserController) SetPassword(c *gin.Context) {
+	id, ok := idParam(c, "User")
+	if !ok {
+		return
+	}
+
+	var form types.UserPasswordForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	if err := cc.Auth.SetPassword(c.Request.Context(), id, form.Password); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -71,8 +71,7 @@ func (c *WebAuditContentController) UpdateRequirement(ctx *gin.Context) {
 		return
 	}
 
-	// Get current user (you'll need to implement user session management)
-	userID := c.getCurrentUserID(ctx) // Placeholder - implement based on your auth system
+	userID := currentUserID(ctx)
 
 	// Modify the requirement
 	err = c.Service.ModifyRequirementDescription(
@@ -107,11 +106,3 @@ func (c *WebAuditContentController) ListRequirements(ctx *gin.Context) {
 		"title":       "Manage Requirements",
 	})
 }
-
-// getCurrentUserID is a placeholder for getting the current user ID
-// Implement this based on your authentication system
-func (c *WebAuditContentController) getCurrentUserID(ctx *gin.Context) int {
-	// Placeholder implementation
-	// In a real system, you'd get this from session, JWT, etc.
-	return 1 // Default admin user
-}
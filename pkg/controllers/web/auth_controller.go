@@ -0,0 +1,91 @@
+// Only handles HTML request validation and response formatting for the login page
+package controllers
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/middleware"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"ISO_Auditing_Tool/templates"
+	"net/http"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultLandingPath is where users go after signing in when no page was asked for
+const defaultLandingPath = "/web/findings"
+
+// wrongPasswordMessage is shown when the email or password was not correct
+const wrongPasswordMessage = "The email or password is not correct."
+
+type WebAuthController struct {
+	Service      services.AuthServiceInterface
+	SecureCookie bool
+	SSOEnabled   bool
+}
+
+// NewWebAuthController creates a new instance of WebAuthController. ssoEnabled offers single
+// sign-on on the login page.
+func NewWebAuthController(service services.AuthServiceInterface, secureCookie, ssoEnabled bool) *WebAuthController {
+	return &WebAuthController{Service: service, SecureCookie: secureCookie, SSOEnabled: ssoEnabled}
+}
+
+// GetLogin renders the login form, returning to the next query parameter once signed in
+func (cc *WebAuthController) GetLogin(c *gin.Context) {
+	render(c, templates.LoginPage("", localPath(c.Query("next")), "", cc.SSOEnabled))
+}
+
+// Login checks the submitted email and password, sets the session cookie and redirects to the
+// page asked for. A wrong email or password renders the form again.
+func (cc *WebAuthController) Login(c *gin.Context) {
+	var form types.LoginForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+	next := localPath(form.Next)
+
+	session, err := cc.Service.Login(c.Request.Context(), form.Email, form.Password)
+	if custom_errors.IsErrorCode(err, custom_errors.ErrCodeUnauthorized) {
+		renderLoginFailure(c, templates.LoginPage(form.Email, next, wrongPasswordMessage, cc.SSOEnabled))
+		return
+	}
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	middleware.SetSessionCookie(c, session, cc.SecureCookie)
+	c.Redirect(http.StatusSeeOther, next)
+}
+
+// Logout ends the session of the cookie and returns to the login page
+func (cc *WebAuthController) Logout(c *gin.Context) {
+	token, _ := c.Cookie(middleware.SessionCookie)
+	if err := cc.Service.Logout(c.Request.Context(), token); err != nil {
+		c.Error(err)
+		return
+	}
+
+	middleware.ClearSessionCookie(c, cc.SecureCookie)
+	c.Redirect(http.StatusSeeOther, "/web/login")
+}
+
+// localPath keeps redirects after signing in on this site. Anything that is not a path on it,
+// including protocol relative URLs such as //example.com, falls back to the findings register.
+func localPath(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return defaultLandingPath
+	}
+	return next
+}
+
+// renderLoginFailure renders the login page again with a 401 status
+func renderLoginFailure(c *gin.Context, page templ.Component) {
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(http.StatusUnauthorized)
+	if err := page.Render(c.Request.Context(), c.Writer); err != nil {
+		c.Error(err)
+	}
+}
@@ -0,0 +1,134 @@
+// Only handles HTML request validation and response formatting for the audit page and its
+// comment threads
+package controllers
+
+import (
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"ISO_Auditing_Tool/templates"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebCommentController struct {
+	Service   services.CommentServiceInterface
+	Execution services.AuditExecutionServiceInterface
+}
+
+func NewWebCommentController(service services.CommentServiceInterface, execution services.AuditExecutionServiceInterface) *WebCommentController {
+	return &WebCommentController{Service: service, Execution: execution}
+}
+
+// GetAuditPage renders the questions of the audit plan in the path for the signed in user.
+// Comment threads are loaded by htmx.
+func (cc *WebCommentController) GetAuditPage(c *gin.Context) {
+	id, ok := idParam(c, "Audit plan")
+	if !ok {
+		return
+	}
+	userID := currentUserID(c)
+
+	questions, err := cc.Execution.GetQuestions(c.Request.Context(), id, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	render(c, templates.AuditPage(id, questions))
+}
+
+// GetThread renders the comment thread of the audit question in the path
+func (cc *WebCommentController) GetThread(c *gin.Context) {
+	id, ok := idParam(c, "Audit question")
+	if !ok {
+		return
+	}
+	cc.renderThread(c, id, currentUserID(c))
+}
+
+// Create adds the submitted comment or reply and renders the updated thread
+func (cc *WebCommentController) Create(c *gin.Context) {
+	id, ok := idParam(c, "Audit question")
+	if !ok {
+		return
+	}
+
+	var form types.CommentForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+
+	comment := types.Comment{UserID: currentUserID(c), Text: form.Text, ParentID: form.ParentID}
+	if _, err := cc.Service.Add(c.Request.Context(), id, comment); err != nil {
+		c.Error(err)
+		return
+	}
+
+	cc.renderThread(c, id, comment.UserID)
+}
+
+// Update saves the submitted text of the comment in the path and renders the updated thread
+func (cc *WebCommentController) Update(c *gin.Context) {
+	id, ok := idParam(c, "Comment")
+	if !ok {
+		return
+	}
+
+	var form types.CommentEditForm
+	if !bindAndValidate(c, &form) {
+		return
+	}
+	form.UserID = currentUserID(c)
+
+	comment, err := cc.Service.Edit(c.Request.Context(), id, form)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	cc.renderThread(c, comment.AuditQuestionID, form.UserID)
+}
+
+// Delete removes the comment in the path on behalf of the signed in user and renders the updated
+// thread
+func (cc *WebCommentController) Delete(c *gin.Context) {
+	id, ok := idParam(c, "Comment")
+	if !ok {
+		return
+	}
+	userID := currentUserID(c)
+
+	comment, err := cc.Service.Delete(c.Request.Context(), id, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	cc.renderThread(c, comment.AuditQuestionID, userID)
+}
+
+// GetRevisions renders the earlier texts of the comment in the path
+func (cc *WebCommentController) GetRevisions(c *gin.Context) {
+	id, ok := idParam(c, "Comment")
+	if !ok {
+		return
+	}
+
+	revisions, err := cc.Service.GetRevisions(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	render(c, templates.CommentRevisions(revisions))
+}
+
+func (cc *WebCommentController) renderThread(c *gin.Context, auditQuestionID, userID int) {
+	comments, err := cc.Service.GetThread(c.Request.Context(), auditQuestionID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	render(c, templates.CommentThread(auditQuestionID, comments, userID))
+}
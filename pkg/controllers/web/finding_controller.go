@@ -0,0 +1,51 @@
+// Only handles HTML request validation and response formatting for findings
+package controllers
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"ISO_Auditing_Tool/templates"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebFindingController struct {
+	Service services.FindingServiceInterface
+}
+
+func NewWebFindingController(service services.FindingServiceInterface) *WebFindingController {
+	return &WebFindingController{Service: service}
+}
+
+// GetAll renders the findings register filtered by the same query parameters as the API
+func (cc *WebFindingController) GetAll(c *gin.Context) {
+	var query types.FindingQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(custom_errors.InvalidData(c.Request.Context(), "ids must be integers and dates formatted as YYYY-MM-DD"))
+		return
+	}
+
+	findings, err := cc.Service.GetAll(c.Request.Context(), query.Filter())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	render(c, templates.FindingsPage(findings, query))
+}
+
+func (cc *WebFindingController) GetByID(c *gin.Context) {
+	id, ok := idParam(c, "Finding")
+	if !ok {
+		return
+	}
+
+	finding, err := cc.Service.GetByID(c.Request.Context(), types.Finding{ID: id})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	render(c, templates.FindingDetail(finding))
+}
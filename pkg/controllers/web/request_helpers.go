@@ -0,0 +1,55 @@
+// Shared request parsing and rendering for the HTML controllers
+package controllers
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/validators"
+	"net/http"
+	"strconv"
+
+	"github.com/a-h/templ"
+	"github.com/gin-gonic/gin"
+)
+
+// idParam reads the positive integer ":id" path parameter. On failure it records an INVALID_ID
+// error for the error middleware and returns false.
+func idParam(c *gin.Context, objectType string) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.Error(custom_errors.InvalidID(c.Request.Context(), objectType))
+		return 0, false
+	}
+	return id, true
+}
+
+// currentUserID returns the ID of the signed in user, zero for anonymous requests. Pages behind
+// middleware.RequireWebUser always have one.
+func currentUserID(c *gin.Context) int {
+	user, _ := services.CurrentUser(c.Request.Context())
+	return user.ID
+}
+
+// bindAndValidate decodes the submitted form into form and runs the struct validators. On failure
+// it records the custom error for the error middleware and returns false.
+func bindAndValidate(c *gin.Context, form any) bool {
+	if err := c.ShouldBind(form); err != nil {
+		c.Error(custom_errors.InvalidData(c.Request.Context(), "the submitted form is incomplete"))
+		return false
+	}
+
+	if validationErr := validators.ValidateStruct(form); validationErr != nil {
+		c.Error(validationErr)
+		return false
+	}
+	return true
+}
+
+// render writes a templ component as the HTML response
+func render(c *gin.Context, component templ.Component) {
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := component.Render(c.Request.Context(), c.Writer); err != nil {
+		c.Error(err)
+	}
+}
@@ -0,0 +1,98 @@
+// Only handles HTML request validation and response formatting for single sign-on
+package controllers
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/middleware"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"ISO_Auditing_Tool/templates"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// ssoAttemptCookie keeps the state, nonce and PKCE verifier of a sign in until the identity
+	// provider sends the user back
+	ssoAttemptCookie = "sso_attempt"
+	ssoCookiePath    = "/web/sso"
+	ssoAttemptMaxAge = 10 * 60 // seconds
+)
+
+type WebSSOController struct {
+	Service      services.SSOServiceInterface
+	SecureCookie bool
+}
+
+func NewWebSSOController(service services.SSOServiceInterface, secureCookie bool) *WebSSOController {
+	return &WebSSOController{Service: service, SecureCookie: secureCookie}
+}
+
+// Begin sends the browser to the identity provider, returning to the next query parameter once
+// signed in
+func (cc *WebSSOController) Begin(c *gin.Context) {
+	attempt, err := cc.Service.Begin(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	attempt.Next = localPath(c.Query("next"))
+
+	value, err := json.Marshal(attempt)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Lax, so the cookie comes along when the provider redirects the user back
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(ssoAttemptCookie, base64.RawURLEncoding.EncodeToString(value), ssoAttemptMaxAge, ssoCookiePath, "", cc.SecureCookie, true)
+	c.Redirect(http.StatusFound, attempt.URL)
+}
+
+// Callback completes the sign in the identity provider sent the user back from, sets the session
+// cookie and redirects to the page asked for. Failures render the login page with the reason.
+func (cc *WebSSOController) Callback(c *gin.Context) {
+	attempt := readSSOAttempt(c)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(ssoAttemptCookie, "", -1, ssoCookiePath, "", cc.SecureCookie, true)
+
+	if c.Query("error") != "" {
+		renderLoginFailure(c, templates.LoginPage("", localPath(attempt.Next), "Single sign-on was cancelled or refused.", true))
+		return
+	}
+
+	session, err := cc.Service.Complete(c.Request.Context(), attempt, c.Query("state"), c.Query("code"))
+	var customErr *custom_errors.CustomError
+	if custom_errors.IsErrorCode(err, custom_errors.ErrCodeUnauthorized) && custom_errors.As(err, &customErr) {
+		renderLoginFailure(c, templates.LoginPage("", localPath(attempt.Next), customErr.Message, true))
+		return
+	}
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	middleware.SetSessionCookie(c, session, cc.SecureCookie)
+	c.Redirect(http.StatusSeeOther, localPath(attempt.Next))
+}
+
+// readSSOAttempt decodes the attempt cookie, an empty attempt when it is missing or malformed
+func readSSOAttempt(c *gin.Context) types.SSOAttempt {
+	var attempt types.SSOAttempt
+	value, err := c.Cookie(ssoAttemptCookie)
+	if err != nil {
+		return attempt
+	}
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return types.SSOAttempt{}
+	}
+	if err := json.Unmarshal(data, &attempt); err != nil {
+		return types.SSOAttempt{}
+	}
+	return attempt
+}
@@ -1,6 +1,7 @@
 package custom_errors
 
 import (
+	"ISO_Auditing_Tool/pkg/requestctx"
 	"context"
 	"errors"
 	"fmt"
@@ -25,6 +26,9 @@ const (
 	ErrCodeMinChars        ErrorCode = "MIN_CHARACTERS"
 	ErrCodeMaxChars        ErrorCode = "MAX_CHARACTERS"
 	ErrCodeInvalidData     ErrorCode = "INVALID_DATA"
+	ErrCodeConflict        ErrorCode = "CONFLICT"
+	ErrCodeForbidden       ErrorCode = "FORBIDDEN"
+	ErrCodeUnauthorized    ErrorCode = "UNAUTHORIZED"
 )
 
 // Predefined errors for common cases
@@ -66,7 +70,7 @@ func (e *CustomError) WithContext(ctx map[string]any) *CustomError {
 // NewError creates a new CustomError with context
 func NewError(ctx context.Context, code ErrorCode, message string, statusCode int, err error) *CustomError {
 	ctxValues := make(map[string]any)
-	if requestID := ctx.Value("request_id"); requestID != nil {
+	if requestID := requestctx.RequestID(ctx); requestID != "" {
 		ctxValues["request_id"] = requestID
 	}
 
@@ -104,6 +108,22 @@ func NotFound(ctx context.Context, objectType string) *CustomError {
 	return NewError(ctx, ErrCodeNotFound, fmt.Sprintf("%v not found", objectType), http.StatusNotFound, nil)
 }
 
+func InvalidData(ctx context.Context, reason string) *CustomError {
+	return NewError(ctx, ErrCodeInvalidData, fmt.Sprintf("Invalid data - %v", reason), http.StatusBadRequest, nil)
+}
+
+func Conflict(ctx context.Context, objectType, reason string) *CustomError {
+	return NewError(ctx, ErrCodeConflict, fmt.Sprintf("%v %v", objectType, reason), http.StatusConflict, nil)
+}
+
+func Forbidden(ctx context.Context, reason string) *CustomError {
+	return NewError(ctx, ErrCodeForbidden, fmt.Sprintf("Forbidden - %v", reason), http.StatusForbidden, nil)
+}
+
+func Unauthorized(ctx context.Context, reason string) *CustomError {
+	return NewError(ctx, ErrCodeUnauthorized, fmt.Sprintf("Unauthorized - %v", reason), http.StatusUnauthorized, nil)
+}
+
 func EmptyField(ctx context.Context, typeName, typeField string) *CustomError {
 	return NewError(ctx, ErrCodeEmptyField, fmt.Sprintf("%v %v should not be empty", typeName, typeField), http.StatusBadRequest, nil)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 )
 
 type EventType string
@@ -14,6 +15,23 @@ const (
 	EntityRequirement EntityType = "requirement"
 	EntityQuestion    EntityType = "question"
 	EntityEvidence    EntityType = "evidence"
+
+	EntityRequirementLevel EntityType = "requirement_level"
+	EntityDraft            EntityType = "draft"
+
+	EntityAuditPlan        EntityType = "audit_plan"
+	EntityAuditQuestion    EntityType = "audit_question"
+	EntityEvidenceProvided EntityType = "evidence_provided"
+	EntityComment          EntityType = "comment"
+	EntityAuditAssignment  EntityType = "audit_assignment"
+	EntityEvidenceGrant    EntityType = "evidence_access_grant"
+	EntityFinding          EntityType = "finding"
+	EntityCorrectiveAction EntityType = "corrective_action"
+	EntityUser             EntityType = "user"
+	EntityAPIToken         EntityType = "api_token"
+
+	EntityReferenceType  EntityType = "reference_type"
+	EntityReferenceValue EntityType = "reference_value"
 )
 
 type ChangeType string
@@ -31,6 +49,18 @@ const (
 	MaterializedQueryUpdated          EventType = "materialized_query_updated"
 )
 
+// Published by the overdue finding job when an open finding enters the warning window or passes
+// its due date
+const (
+	FindingDueSoon EventType = "finding_due_soon"
+	FindingOverdue EventType = "finding_overdue"
+)
+
+// Published when provided evidence is withheld from a user because of its confidentiality
+const (
+	EvidenceAccessDenied EventType = "evidence_access_denied"
+)
+
 const (
 	DataCreated EventType = "data_created"
 	DataUpdated EventType = "data_updated"
@@ -55,6 +85,24 @@ type EntityChangePayload struct {
 	Data          any        `json:"data,omitempty"` // Optional entity data for direct use
 }
 
+type FindingDuePayload struct {
+	FindingID         int       `json:"finding_id"`
+	AuditID           int       `json:"audit_id"`
+	AuditQuestionID   int       `json:"audit_question_id"`
+	ResponsibleUserID int       `json:"responsible_user_id"`
+	DueDate           time.Time `json:"due_date"`
+	DaysLeft          int       `json:"days_left"` // Negative once the finding is overdue
+}
+
+type EvidenceAccessPayload struct {
+	EvidenceProvidedID int    `json:"evidence_provided_id"`
+	AuditQuestionID    int    `json:"audit_question_id"`
+	UserID             int    `json:"user_id"` // Zero for callers who did not identify themselves
+	Role               string `json:"role"`
+	Confidentiality    string `json:"confidentiality"`
+	Action             string `json:"action"` // read or download
+}
+
 type MaterializedQueryPayload struct {
 	QueryName       string          `json:"query_name"`
 	QuerySQL        string          `json:"query_definition"`
@@ -105,6 +153,50 @@ func NewEvidenceEvent(evidenceID any, changeType ChangeType, questionID any, aff
 	return NewEntityChangeEvent(EntityEvidence, evidenceID, changeType, affectedQuery, EntityQuestion, questionID, data)
 }
 
+func NewAuditPlanEvent(auditPlanID any, changeType ChangeType, standardID any, affectedQuery string, data any) Event {
+	return NewEntityChangeEvent(EntityAuditPlan, auditPlanID, changeType, affectedQuery, EntityStandard, standardID, data)
+}
+
+func NewEvidenceProvidedEvent(providedID any, changeType ChangeType, auditQuestionID any, affectedQuery string, data any) Event {
+	return NewEntityChangeEvent(EntityEvidenceProvided, providedID, changeType, affectedQuery, EntityAuditQuestion, auditQuestionID, data)
+}
+
+func NewCommentEvent(commentID any, changeType ChangeType, auditQuestionID any, affectedQuery string, data any) Event {
+	return NewEntityChangeEvent(EntityComment, commentID, changeType, affectedQuery, EntityAuditQuestion, auditQuestionID, data)
+}
+
+func NewFindingEvent(findingID any, changeType ChangeType, auditQuestionID any, affectedQuery string, data any) Event {
+	return NewEntityChangeEvent(EntityFinding, findingID, changeType, affectedQuery, EntityAuditQuestion, auditQuestionID, data)
+}
+
+func NewCorrectiveActionEvent(actionID any, changeType ChangeType, findingID any, affectedQuery string, data any) Event {
+	return NewEntityChangeEvent(EntityCorrectiveAction, actionID, changeType, affectedQuery, EntityFinding, findingID, data)
+}
+
+func NewUserEvent(userID any, changeType ChangeType, roleID any, affectedQuery string, data any) Event {
+	return NewEntityChangeEvent(EntityUser, userID, changeType, affectedQuery, EntityReferenceValue, roleID, data)
+}
+
+func NewAPITokenEvent(tokenID any, changeType ChangeType, userID any, affectedQuery string, data any) Event {
+	return NewEntityChangeEvent(EntityAPIToken, tokenID, changeType, affectedQuery, EntityUser, userID, data)
+}
+
+func NewFindingDueSoonEvent(payload FindingDuePayload) Event {
+	return Event{Type: FindingDueSoon, Payload: payload}
+}
+
+func NewFindingOverdueEvent(payload FindingDuePayload) Event {
+	return Event{Type: FindingOverdue, Payload: payload}
+}
+
+func NewEvidenceAccessDeniedEvent(payload EvidenceAccessPayload) Event {
+	return Event{Type: EvidenceAccessDenied, Payload: payload}
+}
+
+func NewReferenceValueEvent(valueID any, changeType ChangeType, typeID any, affectedQuery string, data any) Event {
+	return NewEntityChangeEvent(EntityReferenceValue, valueID, changeType, affectedQuery, EntityReferenceType, typeID, data)
+}
+
 // For backward compatibility
 func NewDataCreatedEvent(entityType string, entityID any, affectedQuery string) Event {
 	return Event{
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionCookie holds the session token of a signed in user
+const SessionCookie = "session"
+
+// Authenticate puts the user of a Bearer API token or a valid session cookie into the request
+// context. A Bearer token that does not work is rejected as UNAUTHORIZED. Requests without
+// credentials stay anonymous, RequireUser and RequireWebUser turn them away.
+func Authenticate(auth services.AuthServiceInterface, tokens services.APITokenServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if secret, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+			user, apiToken, err := tokens.Authenticate(c.Request.Context(), strings.TrimSpace(secret))
+			if err != nil {
+				c.Error(err)
+				c.Abort()
+				return
+			}
+			ctx := services.WithAPIToken(services.WithCurrentUser(c.Request.Context(), user), apiToken)
+			c.Request = c.Request.WithContext(ctx)
+			c.Next()
+			return
+		}
+
+		token, err := c.Cookie(SessionCookie)
+		if err == nil && token != "" {
+			if user, err := auth.Authenticate(c.Request.Context(), token); err == nil {
+				c.Request = c.Request.WithContext(services.WithCurrentUser(c.Request.Context(), user))
+			}
+		}
+		c.Next()
+	}
+}
+
+// RequireUser rejects anonymous API requests as UNAUTHORIZED, and requests made with an API token
+// whose scopes do not cover the HTTP method as FORBIDDEN
+func RequireUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := services.CurrentUser(c.Request.Context()); !ok {
+			c.Error(custom_errors.Unauthorized(c.Request.Context(), "sign in required"))
+			c.Abort()
+			return
+		}
+		if err := services.CheckAPITokenScope(c.Request.Context(), c.Request.Method); err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireWebUser sends anonymous page requests to the login page, which returns to the page
+// asked for. htmx requests are redirected through the HX-Redirect header. API token scopes apply
+// as they do for RequireUser.
+func RequireWebUser(loginPath string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := services.CurrentUser(c.Request.Context()); ok {
+			if err := services.CheckAPITokenScope(c.Request.Context(), c.Request.Method); err != nil {
+				c.Error(err)
+				c.Abort()
+				return
+			}
+			c.Next()
+			return
+		}
+
+		next := c.Request.URL.Path
+		if c.GetHeader("HX-Request") == "true" {
+			if current, err := url.Parse(c.GetHeader("HX-Current-URL")); err == nil && current.Path != "" {
+				next = current.Path
+			}
+			c.Header("HX-Redirect", loginPath+"?next="+url.QueryEscape(next))
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		if c.Request.URL.RawQuery != "" {
+			next += "?" + c.Request.URL.RawQuery
+		}
+		c.Redirect(http.StatusFound, loginPath+"?next="+url.QueryEscape(next))
+		c.Abort()
+	}
+}
+
+// SetSessionCookie hands the token of a new session to the browser. The cookie is HttpOnly and
+// SameSite=Lax, so scripts cannot read it and other sites cannot send it along with their forms.
+func SetSessionCookie(c *gin.Context, session types.Session, secure bool) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(SessionCookie, session.Token, int(time.Until(session.ExpiresAt).Seconds()), "/", "", secure, true)
+}
+
+// ClearSessionCookie removes the session cookie from the browser
+func ClearSessionCookie(c *gin.Context, secure bool) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(SessionCookie, "", -1, "/", "", secure, true)
+}
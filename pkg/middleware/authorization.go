@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/services"
+	"context"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole lets through signed in users whose users.role_id code is one of roles. Others are
+// rejected as FORBIDDEN, anonymous requests as UNAUTHORIZED.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := services.RequireRole(c.Request.Context(), roles...); err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireAuditAccess lets administrators and the team of the audit plan in the ":id" path
+// parameter through, others are rejected as FORBIDDEN
+func RequireAuditAccess(authz services.AuthorizationServiceInterface) gin.HandlerFunc {
+	return RequireAuditAccessOf(authz, "Audit plan", func(ctx context.Context, id int) (int, error) {
+		return id, nil
+	})
+}
+
+// RequireAuditAccessOf is RequireAuditAccess for routes whose ":id" path parameter is another
+// object of an audit, auditPlanOf looks up the audit plan it belongs to
+func RequireAuditAccessOf(authz services.AuthorizationServiceInterface, objectType string, auditPlanOf func(ctx context.Context, id int) (int, error)) gin.HandlerFunc {
+	return requireAuditCheck(objectType, auditPlanOf, authz.CheckAuditAccess)
+}
+
+// RequireAuditParticipationOf lets administrators, the team and the clients following up findings
+// of the audit plan of the object in the ":id" path parameter through, others are rejected as
+// FORBIDDEN
+func RequireAuditParticipationOf(authz services.AuthorizationServiceInterface, objectType string, auditPlanOf func(ctx context.Context, id int) (int, error)) gin.HandlerFunc {
+	return requireAuditCheck(objectType, auditPlanOf, authz.CheckAuditParticipation)
+}
+
+// requireAuditCheck runs check on the audit plan that auditPlanOf finds for the ":id" path parameter
+func requireAuditCheck(objectType string, auditPlanOf func(ctx context.Context, id int) (int, error), check func(ctx context.Context, auditPlanID int) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			c.Error(custom_errors.InvalidID(c.Request.Context(), objectType))
+			c.Abort()
+			return
+		}
+
+		auditPlanID, err := auditPlanOf(c.Request.Context(), id)
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		if err := check(c.Request.Context(), auditPlanID); err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
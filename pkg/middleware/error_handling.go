@@ -23,7 +23,7 @@ func ErrorHandler() gin.HandlerFunc {
 }
 
 func respondWithError(c *gin.Context, customErr *custom_errors.CustomError) {
-	response := gin.H{"error": customErr.Error()}
+	response := gin.H{"error": customErr.Error(), "code": customErr.Code}
 	// if customErr.Context != nil {
 	// 	response["Context"] = customErr.Context
 	// }
@@ -0,0 +1,376 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidIDToken is returned by Exchange for an ID token that is malformed, not signed by the
+// provider, meant for another client, expired, not valid yet or from another sign in attempt
+var ErrInvalidIDToken = errors.New("invalid ID token")
+
+// clockSkew is how far the clocks of the provider and this server may drift apart
+const clockSkew = time.Minute
+
+// requestTimeout bounds each request to the provider, so a hanging provider does not hold a sign
+// in, or the key set lock, forever
+const requestTimeout = 10 * time.Second
+
+// keyRefreshInterval is how long after fetching the key set a token naming an unknown key is
+// rejected without fetching it again, so such tokens cannot make us hammer the provider
+const keyRefreshInterval = time.Minute
+
+// Claims are the parts of an ID token used to find or create the user
+type Claims struct {
+	Subject       string
+	Email         string
+	EmailVerified *bool // nil when the provider does not say, which is not a confirmation
+	Name          string
+	Groups        []string
+}
+
+// discovery is the part of the provider metadata at /.well-known/openid-configuration in use
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Client talks to one identity provider. Its metadata and signing keys are fetched on first use
+// and the keys again when a token names a key that is not known yet, at most once per
+// keyRefreshInterval.
+type Client struct {
+	config Config
+	client *http.Client
+	now    func() time.Time
+
+	mu          sync.Mutex
+	discovery   *discovery
+	keys        map[string]*rsa.PublicKey
+	keysFetched time.Time
+}
+
+func NewClient(config Config) (*Client, error) {
+	if config.IssuerURL == "" || config.ClientID == "" || config.RedirectURL == "" {
+		return nil, errors.New("OIDC_ISSUER_URL, OIDC_CLIENT_ID and OIDC_REDIRECT_URL are required for single sign-on")
+	}
+	config.IssuerURL = strings.TrimSuffix(config.IssuerURL, "/")
+	if config.GroupsClaim == "" {
+		config.GroupsClaim = "groups"
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+
+	return &Client{config: config, client: &http.Client{Timeout: requestTimeout}, now: time.Now}, nil
+}
+
+// NewVerifier returns a random PKCE code verifier
+func NewVerifier() (string, error) {
+	return randomString()
+}
+
+// NewState returns a random value for the state and nonce parameters
+func NewState() (string, error) {
+	return randomString()
+}
+
+// Challenge returns the S256 PKCE code challenge of a verifier
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthCodeURL returns the address the browser is sent to so the user signs in with the provider
+func (c *Client) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	meta, err := c.metadata(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {c.config.ClientID},
+		"redirect_uri":          {c.config.RedirectURL},
+		"scope":                 {strings.Join(c.config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {Challenge(verifier)},
+		"code_challenge_method": {"S256"},
+	}
+	separator := "?"
+	if strings.Contains(meta.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return meta.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange redeems the authorization code the provider sent the user back with and returns the
+// claims of the verified ID token. nonce must be the one sent with the authorization request.
+func (c *Client) Exchange(ctx context.Context, code, verifier, nonce string) (Claims, error) {
+	meta, err := c.metadata(ctx)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {c.config.RedirectURL},
+		"client_id":     {c.config.ClientID},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Claims{}, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if c.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(c.config.ClientID), url.QueryEscape(c.config.ClientSecret))
+	}
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := c.do(req, "token", &tokens); err != nil {
+		return Claims{}, err
+	}
+	if tokens.IDToken == "" {
+		return Claims{}, fmt.Errorf("%w: the token response has no id_token", ErrInvalidIDToken)
+	}
+
+	return c.verify(ctx, meta, tokens.IDToken, nonce)
+}
+
+// verify checks the signature, issuer, audience, validity period and nonce of an ID token
+func (c *Client) verify(ctx context.Context, meta *discovery, raw, nonce string) (Claims, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return Claims{}, fmt.Errorf("%w: not a signed JWT", ErrInvalidIDToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return Claims{}, err
+	}
+	if header.Alg != "RS256" {
+		return Claims{}, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidIDToken, header.Alg)
+	}
+
+	key, err := c.key(ctx, meta, header.Kid)
+	if err != nil {
+		return Claims{}, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: malformed signature", ErrInvalidIDToken)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return Claims{}, fmt.Errorf("%w: bad signature", ErrInvalidIDToken)
+	}
+
+	var payload map[string]any
+	if err := decodeSegment(parts[1], &payload); err != nil {
+		return Claims{}, err
+	}
+
+	if iss, _ := payload["iss"].(string); !sameIssuer(iss, meta.Issuer) {
+		return Claims{}, fmt.Errorf("%w: issued by %q", ErrInvalidIDToken, iss)
+	}
+	if !stringsClaim(payload["aud"], c.config.ClientID) {
+		return Claims{}, fmt.Errorf("%w: not meant for this client", ErrInvalidIDToken)
+	}
+	exp, ok := payload["exp"].(float64)
+	if !ok || c.now().After(time.Unix(int64(exp), 0).Add(clockSkew)) {
+		return Claims{}, fmt.Errorf("%w: expired", ErrInvalidIDToken)
+	}
+	iat, ok := payload["iat"].(float64)
+	if !ok || time.Unix(int64(iat), 0).After(c.now().Add(clockSkew)) {
+		return Claims{}, fmt.Errorf("%w: issued in the future", ErrInvalidIDToken)
+	}
+	if nbf, ok := payload["nbf"].(float64); ok && time.Unix(int64(nbf), 0).After(c.now().Add(clockSkew)) {
+		return Claims{}, fmt.Errorf("%w: not valid yet", ErrInvalidIDToken)
+	}
+	tokenNonce, _ := payload["nonce"].(string)
+	if subtle.ConstantTimeCompare([]byte(tokenNonce), []byte(nonce)) != 1 {
+		return Claims{}, fmt.Errorf("%w: nonce does not match the sign in attempt", ErrInvalidIDToken)
+	}
+
+	claims := Claims{Groups: []string{}}
+	claims.Subject, _ = payload["sub"].(string)
+	claims.Email, _ = payload["email"].(string)
+	claims.Name, _ = payload["name"].(string)
+	if verified, ok := payload["email_verified"].(bool); ok {
+		claims.EmailVerified = &verified
+	}
+	switch groups := payload[c.config.GroupsClaim].(type) {
+	case []any:
+		for _, group := range groups {
+			if name, ok := group.(string); ok {
+				claims.Groups = append(claims.Groups, name)
+			}
+		}
+	case string:
+		claims.Groups = append(claims.Groups, groups)
+	}
+	return claims, nil
+}
+
+// metadata returns the provider metadata, fetching it on first use
+func (c *Client) metadata(ctx context.Context) (*discovery, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.discovery != nil {
+		return c.discovery, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.IssuerURL+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery request: %w", err)
+	}
+	var meta discovery
+	if err := c.do(req, "discovery", &meta); err != nil {
+		return nil, err
+	}
+	if !sameIssuer(meta.Issuer, c.config.IssuerURL) {
+		return nil, fmt.Errorf("the provider reports issuer %q instead of %q", meta.Issuer, c.config.IssuerURL)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, errors.New("the provider metadata lacks an authorization, token or JWKS endpoint")
+	}
+
+	c.discovery = &meta
+	return c.discovery, nil
+}
+
+// key returns the signing key with the given ID, fetching the key set again for an unknown one
+// unless it was fetched within keyRefreshInterval
+func (c *Client) key(ctx context.Context, meta *discovery, kid string) (*rsa.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if key, ok := c.keys[kid]; ok {
+		return key, nil
+	}
+	if c.keys != nil && c.now().Before(c.keysFetched.Add(keyRefreshInterval)) {
+		return nil, fmt.Errorf("%w: signed with unknown key %q", ErrInvalidIDToken, kid)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, meta.JWKSURI, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JWKS request: %w", err)
+	}
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := c.do(req, "JWKS", &set); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, jwk := range set.Keys {
+		if jwk.Kty != "RSA" {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(jwk.N)
+		e, errE := base64.RawURLEncoding.DecodeString(jwk.E)
+		if errN != nil || errE != nil {
+			continue
+		}
+		keys[jwk.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	c.keys = keys
+	c.keysFetched = c.now()
+
+	key, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: signed with unknown key %q", ErrInvalidIDToken, kid)
+	}
+	return key, nil
+}
+
+// do sends req and decodes the JSON response into v
+func (c *Client) do(req *http.Request, what string, v any) error {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach the provider %s endpoint: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read the provider %s response: %w", what, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("the provider %s endpoint returned %s: %s", what, resp.Status, strings.TrimSpace(string(body)))
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to decode the provider %s response: %w", what, err)
+	}
+	return nil
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("%w: malformed segment", ErrInvalidIDToken)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: malformed segment", ErrInvalidIDToken)
+	}
+	return nil
+}
+
+// sameIssuer reports whether two issuer URLs name the same provider. Providers differ in whether
+// their issuer ends in a slash, so a trailing slash is ignored on both sides.
+func sameIssuer(a, b string) bool {
+	return strings.TrimSuffix(a, "/") == strings.TrimSuffix(b, "/")
+}
+
+// stringsClaim reports whether a claim that is a string or a list of strings contains want
+func stringsClaim(claim any, want string) bool {
+	switch value := claim.(type) {
+	case string:
+		return value == want
+	case []any:
+		for _, item := range value {
+			if item == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func randomString() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random value: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
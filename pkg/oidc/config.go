@@ -0,0 +1,71 @@
+// Package oidc signs users in with an OpenID Connect identity provider, using the authorization
+// code flow with PKCE and RS256 signed ID tokens
+package oidc
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Config holds the OpenID Connect client registration and how its claims map to users. Single
+// sign-on is off unless IssuerURL is set.
+type Config struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+
+	// GroupsClaim names the ID token claim listing the groups of the user
+	GroupsClaim string
+	// GroupRoles maps a group to a users.role_id code such as LEAD
+	GroupRoles map[string]string
+	// ProvisionUsers creates users on their first sign in instead of turning them away
+	ProvisionUsers bool
+}
+
+// Enabled reports whether single sign-on is configured
+func (c *Config) Enabled() bool {
+	return c.IssuerURL != ""
+}
+
+// LoadConfigFromEnv loads OpenID Connect configuration from environment variables.
+// OIDC_GROUP_ROLES lists group=ROLE pairs separated by commas, e.g. "iso-admins=ADMIN,qa=LEAD".
+func LoadConfigFromEnv() *Config {
+	scopes := strings.Fields(os.Getenv("OIDC_SCOPES"))
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+
+	groupsClaim := os.Getenv("OIDC_GROUPS_CLAIM")
+	if groupsClaim == "" {
+		groupsClaim = "groups"
+	}
+
+	groupRoles := map[string]string{}
+	for _, pair := range strings.Split(os.Getenv("OIDC_GROUP_ROLES"), ",") {
+		group, role, ok := strings.Cut(pair, "=")
+		if ok && strings.TrimSpace(group) != "" && strings.TrimSpace(role) != "" {
+			groupRoles[strings.TrimSpace(group)] = strings.ToUpper(strings.TrimSpace(role))
+		}
+	}
+
+	provision := false
+	if provisionStr := os.Getenv("OIDC_PROVISION_USERS"); provisionStr != "" {
+		if val, err := strconv.ParseBool(provisionStr); err == nil {
+			provision = val
+		}
+	}
+
+	return &Config{
+		IssuerURL:      strings.TrimSuffix(os.Getenv("OIDC_ISSUER_URL"), "/"),
+		ClientID:       os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret:   os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:    os.Getenv("OIDC_REDIRECT_URL"),
+		Scopes:         scopes,
+		GroupsClaim:    groupsClaim,
+		GroupRoles:     groupRoles,
+		ProvisionUsers: provision,
+	}
+}
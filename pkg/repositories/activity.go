@@ -0,0 +1,194 @@
+package repositories
+
+import (
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/requestctx"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+)
+
+// Helpers that feed the append-only activity_log. Every write of the repositories adds its entry
+// in the transaction of the write, so a change is never stored without the record of it. Entries
+// hold the row before and after the write, the user the request acted for and the request ID.
+// Login sessions, last used and last login times and the materialized query caches are
+// bookkeeping rather than changes to audit data and are not recorded.
+
+// activityTables maps the entity types recorded in activity_log to the table of their rows
+var activityTables = map[events.EntityType]string{
+	events.EntityStandard:         "standards",
+	events.EntityRequirementLevel: "requirement_level",
+	events.EntityRequirement:      "requirement",
+	events.EntityQuestion:         "questions",
+	events.EntityEvidence:         "evidence",
+	events.EntityDraft:            "drafts",
+	events.EntityAuditPlan:        "audit_plans",
+	events.EntityAuditAssignment:  "audit_support_auditors",
+	events.EntityEvidenceProvided: "evidence_provided",
+	events.EntityEvidenceGrant:    "evidence_access_grants",
+	events.EntityComment:          "comments",
+	events.EntityFinding:          "findings",
+	events.EntityCorrectiveAction: "finding_corrective_actions",
+	events.EntityUser:             "users",
+	events.EntityAPIToken:         "api_tokens",
+	events.EntityReferenceValue:   "reference_values",
+}
+
+// secretColumns never leave their table, not even into activity_log
+var secretColumns = map[string]bool{
+	"password_hash": true,
+	"token_hash":    true,
+}
+
+// snapshotRow returns the row of entity with the given id as a JSON object, nil when there is no
+// such row. The row stays locked until the transaction ends.
+func snapshotRow(ctx context.Context, tx *sql.Tx, entity events.EntityType, id int) (json.RawMessage, error) {
+	table, ok := activityTables[entity]
+	if !ok {
+		return nil, fmt.Errorf("no table recorded for %s activity", entity)
+	}
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %s WHERE id = ? FOR UPDATE;", table), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s snapshot: %w", table, err)
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s columns: %w", table, err)
+	}
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+
+	values := make([]any, len(columnTypes))
+	pointers := make([]any, len(columnTypes))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	if err := rows.Scan(pointers...); err != nil {
+		return nil, fmt.Errorf("failed to scan %s snapshot: %w", table, err)
+	}
+
+	row := make(map[string]any, len(columnTypes))
+	for i, columnType := range columnTypes {
+		name := columnType.Name()
+		if secretColumns[name] {
+			continue
+		}
+		raw, isBytes := values[i].([]byte)
+		switch {
+		case isBytes && columnType.DatabaseTypeName() == "JSON" && json.Valid(raw):
+			row[name] = json.RawMessage(raw)
+		case isBytes:
+			row[name] = string(raw)
+		default:
+			row[name] = values[i]
+		}
+	}
+
+	snapshot, err := json.Marshal(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s snapshot: %w", table, err)
+	}
+	return snapshot, rows.Err()
+}
+
+// recordActivity appends the entry of a write to the row of entity with the given id, reading
+// the row as the write left it. before is the snapshot taken ahead of the write.
+func recordActivity(ctx context.Context, tx *sql.Tx, action events.ChangeType, entity events.EntityType, id int, before json.RawMessage) error {
+	after, err := snapshotRow(ctx, tx, entity, id)
+	if err != nil {
+		return err
+	}
+	return appendActivity(ctx, tx, action, entity, id, before, after)
+}
+
+// appendActivity adds an entry to activity_log for changes that are not a single row, such as a
+// new order of siblings
+func appendActivity(ctx context.Context, tx *sql.Tx, action events.ChangeType, entity events.EntityType, id int, before, after json.RawMessage) error {
+	var actor sql.NullInt64
+	if userID, ok := requestctx.Actor(ctx); ok {
+		actor = sql.NullInt64{Int64: int64(userID), Valid: true}
+	}
+	requestID := requestctx.RequestID(ctx)
+
+	query := `
+	INSERT INTO activity_log (actor_user_id, action, entity_type, entity_id, before_data, after_data, request_id)
+	VALUES (?, ?, ?, ?, ?, ?, ?);
+	`
+	_, err := tx.ExecContext(ctx, query,
+		actor,
+		string(action),
+		string(entity),
+		id,
+		nullJSON(before),
+		nullJSON(after),
+		sql.NullString{String: requestID, Valid: requestID != ""},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record %s activity: %w", entity, err)
+	}
+	return nil
+}
+
+// createTracked runs insert in a transaction together with the activity_log entry of the row it
+// adds. insert returns the ID of the new row, which is returned in turn.
+func createTracked(ctx context.Context, db *sql.DB, entity events.EntityType, insert func(tx *sql.Tx) (int, error)) (int, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() // Safe to call even after commit
+
+	id, err := insert(tx)
+	if err != nil {
+		return 0, err
+	}
+	if err := recordActivity(ctx, tx, events.ChangeCreated, entity, id, nil); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return id, nil
+}
+
+// changeTracked runs change, an update or delete of the row of entity with the given id, in a
+// transaction together with its activity_log entry. Errors of change are returned as they are.
+func changeTracked(ctx context.Context, db *sql.DB, action events.ChangeType, entity events.EntityType, id int, change func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() // Safe to call even after commit
+
+	before, err := snapshotRow(ctx, tx, entity, id)
+	if err != nil {
+		return err
+	}
+	if err := change(tx); err != nil {
+		return err
+	}
+	if err := recordActivity(ctx, tx, action, entity, id, before); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// idsSnapshot encodes named lists of IDs, as recorded for writes to many rows such as reorders
+func idsSnapshot(lists map[string][]int) json.RawMessage {
+	snapshot, _ := json.Marshal(lists)
+	return snapshot
+}
+
+func nullJSON(data json.RawMessage) sql.NullString {
+	return sql.NullString{String: string(data), Valid: len(data) > 0}
+}
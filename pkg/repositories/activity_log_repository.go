@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ActivityLogRepository is the concrete implementation. It only reads, entries are appended by
+// the repositories that make the writes.
+type ActivityLogRepository struct {
+	db *sql.DB
+}
+
+// Ensure ActivityLogRepository implements ActivityLogRepositoryInterface
+var _ ActivityLogRepositoryInterface = (*ActivityLogRepository)(nil)
+
+func NewActivityLogRepository(db *sql.DB) (ActivityLogRepositoryInterface, error) {
+	return &ActivityLogRepository{db: db}, nil
+}
+
+// GetActivityEntries returns at most filter.Limit entries matching the filter, newest first
+func (r *ActivityLogRepository) GetActivityEntries(ctx context.Context, filter types.ActivityFilter) ([]types.ActivityEntry, error) {
+	conditions := []string{}
+	args := []any{}
+
+	if filter.EntityType != "" {
+		conditions = append(conditions, "entity_type = ?")
+		args = append(args, filter.EntityType)
+	}
+	if filter.EntityID != 0 {
+		conditions = append(conditions, "entity_id = ?")
+		args = append(args, filter.EntityID)
+	}
+	if filter.UserID != 0 {
+		conditions = append(conditions, "actor_user_id = ?")
+		args = append(args, filter.UserID)
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.From)
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.To)
+	}
+	if filter.BeforeID != 0 {
+		conditions = append(conditions, "id < ?")
+		args = append(args, filter.BeforeID)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := `
+	SELECT id, actor_user_id, action, entity_type, entity_id, before_data, after_data, request_id, created_at
+	FROM activity_log
+	` + where + `
+	ORDER BY id DESC
+	LIMIT ?;
+	`
+	rows, err := r.db.QueryContext(ctx, query, append(args, filter.Limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query activity log: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []types.ActivityEntry{}
+	for rows.Next() {
+		entry, err := scanActivityEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan activity log row: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over activity log rows: %w", err)
+	}
+
+	return entries, nil
+}
+
+func scanActivityEntry(row rowScanner) (types.ActivityEntry, error) {
+	var (
+		entry         types.ActivityEntry
+		actorUserID   sql.NullInt64
+		before, after sql.NullString
+		requestID     sql.NullString
+	)
+	err := row.Scan(
+		&entry.ID,
+		&actorUserID,
+		&entry.Action,
+		&entry.EntityType,
+		&entry.EntityID,
+		&before,
+		&after,
+		&requestID,
+		&entry.CreatedAt,
+	)
+	if err != nil {
+		return types.ActivityEntry{}, err
+	}
+
+	if actorUserID.Valid {
+		userID := int(actorUserID.Int64)
+		entry.ActorUserID = &userID
+	}
+	if before.Valid {
+		entry.Before = json.RawMessage(before.String)
+	}
+	if after.Valid {
+		entry.After = json.RawMessage(after.String)
+	}
+	entry.RequestID = requestID.String
+	return entry, nil
+}
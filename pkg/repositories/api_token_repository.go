@@ -0,0 +1,212 @@
+package repositories
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// APITokenRepository is the concrete implementation
+type APITokenRepository struct {
+	db *sql.DB
+}
+
+// Ensure APITokenRepository implements APITokenRepositoryInterface
+var _ APITokenRepositoryInterface = (*APITokenRepository)(nil)
+
+func NewAPITokenRepository(db *sql.DB) (APITokenRepositoryInterface, error) {
+	return &APITokenRepository{db: db}, nil
+}
+
+const apiTokenColumns = `
+	t.id, t.user_id, t.name, t.scopes, t.expires_at, t.last_used_at, t.revoked_at, t.created_at`
+
+// GetByUserIDAPITokens returns the tokens of a user, revoked and expired ones included, newest first
+func (r *APITokenRepository) GetByUserIDAPITokens(ctx context.Context, userID int) ([]types.APIToken, error) {
+	query := `
+	SELECT` + apiTokenColumns + `
+	FROM api_tokens AS t
+	WHERE t.user_id = ?
+	ORDER BY t.created_at DESC, t.id DESC;
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API tokens: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := []types.APIToken{}
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan API token row: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over API token rows: %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (r *APITokenRepository) GetByIDAPIToken(ctx context.Context, id int) (types.APIToken, error) {
+	query := `
+	SELECT` + apiTokenColumns + `
+	FROM api_tokens AS t
+	WHERE t.id = ?;
+	`
+	token, err := scanAPIToken(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return types.APIToken{}, custom_errors.NotFound(ctx, "API token")
+	}
+	if err != nil {
+		return types.APIToken{}, fmt.Errorf("failed to scan API token: %w", err)
+	}
+	return token, nil
+}
+
+// GetUserByAPIToken returns a token that is neither revoked nor expired at asOf, and its user.
+// Tokens of users that were deactivated or deleted since are not found. Only the ID of the role
+// is set.
+func (r *APITokenRepository) GetUserByAPIToken(ctx context.Context, tokenHash string, asOf time.Time) (types.User, types.APIToken, error) {
+	query := `
+	SELECT u.id, u.email, u.name, u.role_id, u.is_active, u.last_login_at, u.created_at, u.updated_at,` + apiTokenColumns + `
+	FROM api_tokens AS t
+	INNER JOIN users AS u ON u.id = t.user_id
+	WHERE t.token_hash = ? AND t.revoked_at IS NULL AND t.expires_at > ? AND u.is_active = TRUE AND u.deleted_at IS NULL;
+	`
+	var (
+		user        types.User
+		token       types.APIToken
+		lastLoginAt sql.NullTime
+		scopes      string
+		lastUsedAt  sql.NullTime
+		revokedAt   sql.NullTime
+	)
+	err := r.db.QueryRowContext(ctx, query, tokenHash, asOf).Scan(
+		&user.ID,
+		&user.Email,
+		&user.Name,
+		&user.RoleVal.ID,
+		&user.IsActive,
+		&lastLoginAt,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&token.ID,
+		&token.UserID,
+		&token.Name,
+		&scopes,
+		&token.ExpiresAt,
+		&lastUsedAt,
+		&revokedAt,
+		&token.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return types.User{}, types.APIToken{}, custom_errors.NotFound(ctx, "API token")
+	}
+	if err != nil {
+		return types.User{}, types.APIToken{}, fmt.Errorf("failed to scan API token user: %w", err)
+	}
+
+	if lastLoginAt.Valid {
+		user.LastLoginAt = &lastLoginAt.Time
+	}
+	setAPITokenFields(&token, scopes, lastUsedAt, revokedAt)
+	return user, token, nil
+}
+
+// CreateAPIToken stores a new token of token.UserID under the SHA-256 of its secret
+func (r *APITokenRepository) CreateAPIToken(ctx context.Context, token types.APIToken, tokenHash string) (types.APIToken, error) {
+	query := "INSERT INTO api_tokens (user_id, name, token_hash, scopes, expires_at) VALUES (?, ?, ?, ?, ?);"
+	id, err := createTracked(ctx, r.db, events.EntityAPIToken, func(tx *sql.Tx) (int, error) {
+		result, err := tx.ExecContext(ctx, query, token.UserID, token.Name, tokenHash, strings.Join(token.Scopes, ","), token.ExpiresAt)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create API token: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+		return int(id), nil
+	})
+	if err != nil {
+		return types.APIToken{}, err
+	}
+
+	return r.GetByIDAPIToken(ctx, id)
+}
+
+// RevokeAPIToken stops a token from working. A token that is already revoked is reported as a
+// CONFLICT error.
+func (r *APITokenRepository) RevokeAPIToken(ctx context.Context, id int) error {
+	query := "UPDATE api_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE id = ? AND revoked_at IS NULL;"
+	return changeTracked(ctx, r.db, events.ChangeUpdated, events.EntityAPIToken, id, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return fmt.Errorf("failed to revoke API token: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return custom_errors.Conflict(ctx, "API token", "is already revoked")
+		}
+		return nil
+	})
+}
+
+func (r *APITokenRepository) UpdateAPITokenLastUsed(ctx context.Context, id int, usedAt time.Time) error {
+	if _, err := r.db.ExecContext(ctx, "UPDATE api_tokens SET last_used_at = ? WHERE id = ?;", usedAt, id); err != nil {
+		return fmt.Errorf("failed to update API token last use: %w", err)
+	}
+	return nil
+}
+
+func scanAPIToken(row rowScanner) (types.APIToken, error) {
+	var (
+		token      types.APIToken
+		scopes     string
+		lastUsedAt sql.NullTime
+		revokedAt  sql.NullTime
+	)
+	err := row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Name,
+		&scopes,
+		&token.ExpiresAt,
+		&lastUsedAt,
+		&revokedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		return types.APIToken{}, err
+	}
+
+	setAPITokenFields(&token, scopes, lastUsedAt, revokedAt)
+	return token, nil
+}
+
+// setAPITokenFields splits the scopes SET column and sets the optional timestamps
+func setAPITokenFields(token *types.APIToken, scopes string, lastUsedAt, revokedAt sql.NullTime) {
+	token.Scopes = []string{}
+	if scopes != "" {
+		token.Scopes = strings.Split(scopes, ",")
+	}
+	if lastUsedAt.Valid {
+		token.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+}
@@ -0,0 +1,216 @@
+package repositories
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// AuditAssignmentRepository is the concrete implementation
+type AuditAssignmentRepository struct {
+	db *sql.DB
+}
+
+// Ensure AuditAssignmentRepository implements AuditAssignmentRepositoryInterface
+var _ AuditAssignmentRepositoryInterface = (*AuditAssignmentRepository)(nil)
+
+func NewAuditAssignmentRepository(db *sql.DB) (AuditAssignmentRepositoryInterface, error) {
+	return &AuditAssignmentRepository{db: db}, nil
+}
+
+// auditAssignmentQuery lists the lead auditors of audit_plans next to the support auditors of
+// audit_support_auditors. Each condition takes one argument.
+func auditAssignmentQuery(leadCondition, supportCondition, orderBy string) string {
+	return `
+	SELECT ap.id, ap.name, ap.status_id, ap.scheduled_date, u.id, u.name, '` + types.AuditRoleLead + `' AS role, ap.created_at
+	FROM audit_plans AS ap
+	INNER JOIN users AS u ON u.id = ap.lead_auditor_id
+	WHERE ` + leadCondition + ` AND ap.deleted_at IS NULL
+	UNION ALL
+	SELECT ap.id, ap.name, ap.status_id, ap.scheduled_date, u.id, u.name, '` + types.AuditRoleSupport + `' AS role, asa.created_at
+	FROM audit_support_auditors AS asa
+	INNER JOIN audit_plans AS ap ON ap.id = asa.audit_id
+	INNER JOIN users AS u ON u.id = asa.user_id
+	WHERE ` + supportCondition + ` AND ap.deleted_at IS NULL
+	ORDER BY ` + orderBy + `;
+	`
+}
+
+// GetByAuditPlanIDAuditAssignments returns the team of a plan, the lead auditor first. Only the ID
+// of the plan status is set.
+func (r *AuditAssignmentRepository) GetByAuditPlanIDAuditAssignments(ctx context.Context, auditPlanID int) ([]types.AuditAssignment, error) {
+	query := auditAssignmentQuery("ap.id = ?", "asa.audit_id = ?", "7, 6")
+	return r.queryAuditAssignments(ctx, query, auditPlanID, auditPlanID)
+}
+
+// GetByUserIDAuditAssignments returns every plan a user leads or supports, ordered by scheduled
+// date. Only the ID of the plan status is set.
+func (r *AuditAssignmentRepository) GetByUserIDAuditAssignments(ctx context.Context, userID int) ([]types.AuditAssignment, error) {
+	query := auditAssignmentQuery("ap.lead_auditor_id = ?", "asa.user_id = ?", "4, 1")
+	return r.queryAuditAssignments(ctx, query, userID, userID)
+}
+
+// CreateAuditAssignment adds an active user as support auditor of a plan. The lead auditor and
+// users already on the team are reported as a CONFLICT error.
+func (r *AuditAssignmentRepository) CreateAuditAssignment(ctx context.Context, auditPlanID, userID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() // Safe to call even after commit
+
+	// Serializes concurrent changes to the team of the plan
+	var leadAuditorID int
+	err = tx.QueryRowContext(ctx, "SELECT lead_auditor_id FROM audit_plans WHERE id = ? AND deleted_at IS NULL FOR UPDATE;", auditPlanID).Scan(&leadAuditorID)
+	if err == sql.ErrNoRows {
+		return custom_errors.NotFound(ctx, "Audit plan")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock audit plan: %w", err)
+	}
+
+	var active int
+	query := "SELECT COUNT(*) FROM users WHERE id = ? AND is_active = TRUE AND deleted_at IS NULL;"
+	if err := tx.QueryRowContext(ctx, query, userID).Scan(&active); err != nil {
+		return fmt.Errorf("failed to check support auditor: %w", err)
+	}
+	if active == 0 {
+		return custom_errors.InvalidData(ctx, "user_id is not an active user")
+	}
+
+	if userID == leadAuditorID {
+		return custom_errors.Conflict(ctx, "User", "is already the lead auditor of the audit plan")
+	}
+
+	var assigned int
+	query = "SELECT COUNT(*) FROM audit_support_auditors WHERE audit_id = ? AND user_id = ?;"
+	if err := tx.QueryRowContext(ctx, query, auditPlanID, userID).Scan(&assigned); err != nil {
+		return fmt.Errorf("failed to check support auditor: %w", err)
+	}
+	if assigned > 0 {
+		return custom_errors.Conflict(ctx, "User", "is already a support auditor of the audit plan")
+	}
+
+	query = "INSERT INTO audit_support_auditors (audit_id, user_id) VALUES (?, ?);"
+	result, err := tx.ExecContext(ctx, query, auditPlanID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to assign support auditor: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+	if err := recordActivity(ctx, tx, events.ChangeCreated, events.EntityAuditAssignment, int(id), nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteAuditAssignment removes a support auditor from a plan, the lead auditor is changed on the plan
+func (r *AuditAssignmentRepository) DeleteAuditAssignment(ctx context.Context, auditPlanID, userID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() // Safe to call even after commit
+
+	var id int
+	query := "SELECT id FROM audit_support_auditors WHERE audit_id = ? AND user_id = ? FOR UPDATE;"
+	err = tx.QueryRowContext(ctx, query, auditPlanID, userID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return custom_errors.NotFound(ctx, "Audit assignment")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock audit assignment: %w", err)
+	}
+
+	before, err := snapshotRow(ctx, tx, events.EntityAuditAssignment, id)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM audit_support_auditors WHERE id = ?;", id); err != nil {
+		return fmt.Errorf("failed to unassign support auditor: %w", err)
+	}
+	if err := recordActivity(ctx, tx, events.ChangeDeleted, events.EntityAuditAssignment, id, before); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// IsAuditTeamMember reports whether a user is the lead auditor or a support auditor of a plan
+func (r *AuditAssignmentRepository) IsAuditTeamMember(ctx context.Context, auditPlanID, userID int) (bool, error) {
+	query := `
+	SELECT EXISTS (SELECT 1 FROM audit_plans WHERE id = ? AND lead_auditor_id = ? AND deleted_at IS NULL)
+		OR EXISTS (SELECT 1 FROM audit_support_auditors WHERE audit_id = ? AND user_id = ?);
+	`
+	var member bool
+	if err := r.db.QueryRowContext(ctx, query, auditPlanID, userID, auditPlanID, userID).Scan(&member); err != nil {
+		return false, fmt.Errorf("failed to check audit team: %w", err)
+	}
+	return member, nil
+}
+
+// IsAuditFollowUpOwner reports whether a user is responsible for a finding of a plan or owns one
+// of the corrective actions of its findings
+func (r *AuditAssignmentRepository) IsAuditFollowUpOwner(ctx context.Context, auditPlanID, userID int) (bool, error) {
+	query := `
+	SELECT EXISTS (
+		SELECT 1 FROM findings AS f
+		WHERE f.audit_id = ? AND f.deleted_at IS NULL
+			AND (f.responsible_user_id = ? OR EXISTS (
+				SELECT 1 FROM finding_corrective_actions AS fca WHERE fca.finding_id = f.id AND fca.owner_id = ?))
+	);
+	`
+	var owner bool
+	if err := r.db.QueryRowContext(ctx, query, auditPlanID, userID, userID).Scan(&owner); err != nil {
+		return false, fmt.Errorf("failed to check audit follow up: %w", err)
+	}
+	return owner, nil
+}
+
+func (r *AuditAssignmentRepository) queryAuditAssignments(ctx context.Context, query string, args ...any) ([]types.AuditAssignment, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit assignments: %w", err)
+	}
+	defer rows.Close()
+
+	assignments := []types.AuditAssignment{}
+	for rows.Next() {
+		var assignment types.AuditAssignment
+		err := rows.Scan(
+			&assignment.AuditPlanID,
+			&assignment.AuditPlanName,
+			&assignment.StatusVal.ID,
+			&assignment.ScheduledDate,
+			&assignment.UserID,
+			&assignment.UserName,
+			&assignment.Role,
+			&assignment.AssignedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit assignment row: %w", err)
+		}
+		assignments = append(assignments, assignment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over audit assignment rows: %w", err)
+	}
+
+	return assignments, nil
+}
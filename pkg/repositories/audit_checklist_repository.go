@@ -0,0 +1,329 @@
+package repositories
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// AuditChecklistRepository is the concrete implementation
+type AuditChecklistRepository struct {
+	db *sql.DB
+}
+
+// Ensure AuditChecklistRepository implements AuditChecklistRepositoryInterface
+var _ AuditChecklistRepositoryInterface = (*AuditChecklistRepository)(nil)
+
+func NewAuditChecklistRepository(db *sql.DB) (AuditChecklistRepositoryInterface, error) {
+	return &AuditChecklistRepository{db: db}, nil
+}
+
+// GetAuditChecklist returns the scope of a plan and its audit questions, grouped by requirement
+func (r *AuditChecklistRepository) GetAuditChecklist(ctx context.Context, auditPlanID int) (types.AuditChecklist, error) {
+	checklist := types.AuditChecklist{AuditPlanID: auditPlanID, RequirementIDs: []int{}, Questions: []types.AuditQuestion{}}
+
+	scopeQuery := `
+	SELECT requirement_id
+	FROM audit_plan_requirements
+	WHERE audit_plan_id = ? AND requirement_id IS NOT NULL
+	ORDER BY id;
+	`
+	scopeRows, err := r.db.QueryContext(ctx, scopeQuery, auditPlanID)
+	if err != nil {
+		return types.AuditChecklist{}, fmt.Errorf("failed to query audit scope: %w", err)
+	}
+	defer scopeRows.Close()
+
+	for scopeRows.Next() {
+		var requirementID int
+		if err := scopeRows.Scan(&requirementID); err != nil {
+			return types.AuditChecklist{}, fmt.Errorf("failed to scan audit scope row: %w", err)
+		}
+		checklist.RequirementIDs = append(checklist.RequirementIDs, requirementID)
+	}
+
+	if err = scopeRows.Err(); err != nil {
+		return types.AuditChecklist{}, fmt.Errorf("error iterating over audit scope rows: %w", err)
+	}
+
+	questionQuery := `
+	SELECT aq.id, aq.audit_id, aq.question_id, q.requirement_id, r.reference_code, q.question
+	FROM audit_questions AS aq
+	INNER JOIN questions AS q ON q.id = aq.question_id
+	INNER JOIN requirement AS r ON r.id = q.requirement_id
+	WHERE aq.audit_id = ?
+	ORDER BY q.requirement_id, q.sort_order, aq.id;
+	`
+	rows, err := r.db.QueryContext(ctx, questionQuery, auditPlanID)
+	if err != nil {
+		return types.AuditChecklist{}, fmt.Errorf("failed to query audit questions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		question := types.AuditQuestion{EvidenceProvided: []types.EvidenceProvided{}, Comments: []types.Comment{}}
+		err := rows.Scan(
+			&question.ID,
+			&question.AuditID,
+			&question.QuestionID,
+			&question.RequirementID,
+			&question.ReferenceCode,
+			&question.Question,
+		)
+		if err != nil {
+			return types.AuditChecklist{}, fmt.Errorf("failed to scan audit question row: %w", err)
+		}
+		checklist.Questions = append(checklist.Questions, question)
+	}
+
+	if err = rows.Err(); err != nil {
+		return types.AuditChecklist{}, fmt.Errorf("error iterating over audit question rows: %w", err)
+	}
+
+	return checklist, nil
+}
+
+// SyncAuditChecklist makes the audit questions of a plan match the questions of requirementIDs, the
+// expanded subtrees of scopeIDs. Audit questions that are still in scope keep their ID so answers
+// stay attached, removing one that auditors already worked on is a CONFLICT error. Everything
+// happens in one transaction, running it again with the same scope changes nothing.
+func (r *AuditChecklistRepository) SyncAuditChecklist(ctx context.Context, auditPlanID int, scopeIDs, requirementIDs []int) (types.AuditChecklistDiff, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return types.AuditChecklistDiff{}, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() // Safe to call even after commit
+
+	// Serializes concurrent generations for the same plan
+	var lockedID int
+	err = tx.QueryRowContext(ctx, "SELECT id FROM audit_plans WHERE id = ? AND deleted_at IS NULL FOR UPDATE;", auditPlanID).Scan(&lockedID)
+	if err == sql.ErrNoRows {
+		return types.AuditChecklistDiff{}, custom_errors.NotFound(ctx, "Audit plan")
+	}
+	if err != nil {
+		return types.AuditChecklistDiff{}, fmt.Errorf("failed to lock audit plan: %w", err)
+	}
+
+	wanted, err := scopeQuestionIDs(ctx, tx, requirementIDs)
+	if err != nil {
+		return types.AuditChecklistDiff{}, err
+	}
+
+	current, err := lockAuditQuestions(ctx, tx, auditPlanID)
+	if err != nil {
+		return types.AuditChecklistDiff{}, err
+	}
+
+	var diff types.AuditChecklistDiff
+	removed := []int{}
+	for questionID, auditQuestionID := range current {
+		if !wanted[questionID] {
+			removed = append(removed, auditQuestionID)
+		}
+	}
+	added := []int{}
+	for questionID := range wanted {
+		if _, ok := current[questionID]; !ok {
+			added = append(added, questionID)
+		}
+	}
+	// Maps have no order, sorted IDs keep the statements deterministic
+	sort.Ints(removed)
+	sort.Ints(added)
+
+	diff.Added = len(added)
+	diff.Removed = len(removed)
+	diff.Kept = len(current) - len(removed)
+
+	if len(removed) > 0 {
+		if err := checkAuditQuestionsUnused(ctx, tx, removed); err != nil {
+			return types.AuditChecklistDiff{}, err
+		}
+
+		query := "DELETE FROM audit_questions WHERE id IN (" + placeholders(len(removed)) + ");"
+		if _, err := tx.ExecContext(ctx, query, intArgs(removed)...); err != nil {
+			return types.AuditChecklistDiff{}, fmt.Errorf("failed to delete audit questions: %w", err)
+		}
+	}
+
+	if len(added) > 0 {
+		values := make([]string, len(added))
+		args := make([]any, 0, len(added)*2)
+		for i, questionID := range added {
+			values[i] = "(?, ?)"
+			args = append(args, auditPlanID, questionID)
+		}
+
+		query := "INSERT INTO audit_questions (audit_id, question_id) VALUES " + strings.Join(values, ", ") + ";"
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return types.AuditChecklistDiff{}, fmt.Errorf("failed to create audit questions: %w", err)
+		}
+	}
+
+	currentScopeIDs, err := auditScopeIDs(ctx, tx, auditPlanID)
+	if err != nil {
+		return types.AuditChecklistDiff{}, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM audit_plan_requirements WHERE audit_plan_id = ?;", auditPlanID); err != nil {
+		return types.AuditChecklistDiff{}, fmt.Errorf("failed to clear audit scope: %w", err)
+	}
+	if len(scopeIDs) > 0 {
+		query := `
+		INSERT INTO audit_plan_requirements (audit_plan_id, requirement_id, requirement)
+		SELECT ?, id, reference_code
+		FROM requirement
+		WHERE id IN (` + placeholders(len(scopeIDs)) + `);
+		`
+		args := append([]any{auditPlanID}, intArgs(scopeIDs)...)
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return types.AuditChecklistDiff{}, fmt.Errorf("failed to save audit scope: %w", err)
+		}
+	}
+
+	scopeIDs = append([]int{}, scopeIDs...)
+	sort.Ints(scopeIDs)
+	if len(added) > 0 || len(removed) > 0 || !slices.Equal(currentScopeIDs, scopeIDs) {
+		currentQuestionIDs := make([]int, 0, len(current))
+		for questionID := range current {
+			currentQuestionIDs = append(currentQuestionIDs, questionID)
+		}
+		wantedQuestionIDs := make([]int, 0, len(wanted))
+		for questionID := range wanted {
+			wantedQuestionIDs = append(wantedQuestionIDs, questionID)
+		}
+		sort.Ints(currentQuestionIDs)
+		sort.Ints(wantedQuestionIDs)
+
+		before := idsSnapshot(map[string][]int{"requirement_ids": currentScopeIDs, "question_ids": currentQuestionIDs})
+		after := idsSnapshot(map[string][]int{"requirement_ids": scopeIDs, "question_ids": wantedQuestionIDs})
+		if err := appendActivity(ctx, tx, events.ChangeUpdated, events.EntityAuditPlan, auditPlanID, before, after); err != nil {
+			return types.AuditChecklistDiff{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return types.AuditChecklistDiff{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return diff, nil
+}
+
+// auditScopeIDs returns the requirements a plan is scoped to, in ID order
+func auditScopeIDs(ctx context.Context, tx *sql.Tx, auditPlanID int) ([]int, error) {
+	query := "SELECT requirement_id FROM audit_plan_requirements WHERE audit_plan_id = ? ORDER BY requirement_id;"
+	rows, err := tx.QueryContext(ctx, query, auditPlanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit scope: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan audit scope row: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over audit scope rows: %w", err)
+	}
+
+	return ids, nil
+}
+
+// scopeQuestionIDs returns the set of question IDs belonging to the requirements
+func scopeQuestionIDs(ctx context.Context, tx *sql.Tx, requirementIDs []int) (map[int]bool, error) {
+	questionIDs := make(map[int]bool)
+	if len(requirementIDs) == 0 {
+		return questionIDs, nil
+	}
+
+	query := "SELECT id FROM questions WHERE requirement_id IN (" + placeholders(len(requirementIDs)) + ");"
+	rows, err := tx.QueryContext(ctx, query, intArgs(requirementIDs)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query scope questions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan question id: %w", err)
+		}
+		questionIDs[id] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over question ids: %w", err)
+	}
+
+	return questionIDs, nil
+}
+
+// lockAuditQuestions maps the question IDs already in the checklist to their audit question IDs
+func lockAuditQuestions(ctx context.Context, tx *sql.Tx, auditPlanID int) (map[int]int, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT id, question_id FROM audit_questions WHERE audit_id = ? FOR UPDATE;", auditPlanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit questions: %w", err)
+	}
+	defer rows.Close()
+
+	current := make(map[int]int)
+	for rows.Next() {
+		var id, questionID int
+		if err := rows.Scan(&id, &questionID); err != nil {
+			return nil, fmt.Errorf("failed to scan audit question: %w", err)
+		}
+		current[questionID] = id
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over audit questions: %w", err)
+	}
+
+	return current, nil
+}
+
+// checkAuditQuestionsUnused rejects removing audit questions auditors already worked on
+func checkAuditQuestionsUnused(ctx context.Context, tx *sql.Tx, auditQuestionIDs []int) error {
+	in := placeholders(len(auditQuestionIDs))
+	query := `
+	SELECT
+		(SELECT COUNT(*) FROM audit_question_findings WHERE audit_question_id IN (` + in + `)),
+		(SELECT COUNT(*) FROM audit_questions_comments WHERE audit_question_id IN (` + in + `)),
+		(SELECT COUNT(*) FROM evidence_provided WHERE audit_question_id IN (` + in + `));
+	`
+	args := append(intArgs(auditQuestionIDs), intArgs(auditQuestionIDs)...)
+	args = append(args, intArgs(auditQuestionIDs)...)
+
+	var findings, comments, provided int
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&findings, &comments, &provided); err != nil {
+		return fmt.Errorf("failed to count audit question dependencies: %w", err)
+	}
+	if findings > 0 || comments > 0 || provided > 0 {
+		return custom_errors.Conflict(ctx, "Audit plan", "scope would remove questions that already have findings, comments or provided evidence")
+	}
+	return nil
+}
+
+// placeholders returns "?, ?, ?" for an IN clause of n values, n must be positive
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+func intArgs(values []int) []any {
+	args := make([]any, len(values))
+	for i, value := range values {
+		args[i] = value
+	}
+	return args
+}
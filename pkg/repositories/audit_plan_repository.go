@@ -0,0 +1,251 @@
+package repositories
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// AuditPlanRepository is the concrete implementation
+type AuditPlanRepository struct {
+	db *sql.DB
+}
+
+// Ensure AuditPlanRepository implements AuditPlanRepositoryInterface
+var _ AuditPlanRepositoryInterface = (*AuditPlanRepository)(nil)
+
+func NewAuditPlanRepository(db *sql.DB) (AuditPlanRepositoryInterface, error) {
+	return &AuditPlanRepository{db: db}, nil
+}
+
+const auditPlanColumns = `
+	ap.id, ap.standard_id, ap.lead_auditor_id, ap.name, ap.status_id, ap.scheduled_date,
+	ap.team, ap.scope, ap.type_id, ap.created_at, ap.updated_at`
+
+// GetAllAuditPlans returns the plans that are not deleted, ordered by scheduled date. The filter
+// expects status and type as reference value IDs, zero values are ignored.
+func (r *AuditPlanRepository) GetAllAuditPlans(ctx context.Context, filter types.AuditPlanFilter) ([]types.AuditPlan, error) {
+	conditions := []string{"ap.deleted_at IS NULL"}
+	args := []any{}
+
+	if filter.StandardID != 0 {
+		conditions = append(conditions, "ap.standard_id = ?")
+		args = append(args, filter.StandardID)
+	}
+	if filter.AuditorID != 0 {
+		conditions = append(conditions, "(ap.lead_auditor_id = ? OR EXISTS (SELECT 1 FROM audit_support_auditors AS asa WHERE asa.audit_id = ap.id AND asa.user_id = ?))")
+		args = append(args, filter.AuditorID, filter.AuditorID)
+	}
+	if filter.StatusID != 0 {
+		conditions = append(conditions, "ap.status_id = ?")
+		args = append(args, filter.StatusID)
+	}
+	if filter.TypeID != 0 {
+		conditions = append(conditions, "ap.type_id = ?")
+		args = append(args, filter.TypeID)
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "ap.scheduled_date >= ?")
+		args = append(args, filter.From)
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "ap.scheduled_date < ?")
+		args = append(args, filter.To)
+	}
+
+	query := `
+	SELECT` + auditPlanColumns + `
+	FROM audit_plans AS ap
+	WHERE ` + strings.Join(conditions, " AND ") + `
+	ORDER BY ap.scheduled_date, ap.id;
+	`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit plans: %w", err)
+	}
+	defer rows.Close()
+
+	plans := []types.AuditPlan{}
+	for rows.Next() {
+		plan, err := scanAuditPlan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit plan row: %w", err)
+		}
+		plans = append(plans, plan)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over audit plan rows: %w", err)
+	}
+
+	return plans, nil
+}
+
+// GetByIDAuditPlan returns a plan that is not deleted. Only the IDs of its status and type are set.
+func (r *AuditPlanRepository) GetByIDAuditPlan(ctx context.Context, plan types.AuditPlan) (types.AuditPlan, error) {
+	query := `
+	SELECT` + auditPlanColumns + `
+	FROM audit_plans AS ap
+	WHERE ap.id = ? AND ap.deleted_at IS NULL;
+	`
+	result, err := scanAuditPlan(r.db.QueryRowContext(ctx, query, plan.ID))
+	if err == sql.ErrNoRows {
+		return types.AuditPlan{}, custom_errors.NotFound(ctx, "Audit plan")
+	}
+	if err != nil {
+		return types.AuditPlan{}, fmt.Errorf("failed to scan audit plan: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *AuditPlanRepository) CreateAuditPlan(ctx context.Context, plan types.AuditPlan) (types.AuditPlan, error) {
+	if err := r.checkLeadAuditor(ctx, plan.LeadAuditorID); err != nil {
+		return types.AuditPlan{}, err
+	}
+
+	query := `
+	INSERT INTO audit_plans (standard_id, lead_auditor_id, name, status_id, scheduled_date, team, scope, type_id)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+	`
+	id, err := createTracked(ctx, r.db, events.EntityAuditPlan, func(tx *sql.Tx) (int, error) {
+		result, err := tx.ExecContext(ctx, query,
+			plan.StandardID, plan.LeadAuditorID, plan.Name, plan.StatusVal.ID,
+			plan.ScheduledDate, plan.Team, plan.Scope, plan.TypeVal.ID,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create audit plan: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+		return int(id), nil
+	})
+	if err != nil {
+		return types.AuditPlan{}, err
+	}
+
+	plan.ID = id
+	return r.GetByIDAuditPlan(ctx, plan)
+}
+
+// UpdateAuditPlan changes everything but the status, which only moves through UpdateAuditPlanStatus
+func (r *AuditPlanRepository) UpdateAuditPlan(ctx context.Context, plan types.AuditPlan) (types.AuditPlan, error) {
+	if err := r.checkLeadAuditor(ctx, plan.LeadAuditorID); err != nil {
+		return types.AuditPlan{}, err
+	}
+
+	query := `
+	UPDATE audit_plans
+	SET standard_id = ?, lead_auditor_id = ?, name = ?, scheduled_date = ?, team = ?, scope = ?, type_id = ?
+	WHERE id = ? AND deleted_at IS NULL;
+	`
+	err := changeTracked(ctx, r.db, events.ChangeUpdated, events.EntityAuditPlan, plan.ID, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query,
+			plan.StandardID, plan.LeadAuditorID, plan.Name, plan.ScheduledDate,
+			plan.Team, plan.Scope, plan.TypeVal.ID, plan.ID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update audit plan: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return types.AuditPlan{}, err
+	}
+
+	return r.GetByIDAuditPlan(ctx, plan)
+}
+
+// UpdateAuditPlanStatus moves a plan from one status to another. The update only applies while the
+// plan is still in fromStatusID, so a concurrent change is reported as a CONFLICT error.
+func (r *AuditPlanRepository) UpdateAuditPlanStatus(ctx context.Context, id, fromStatusID, toStatusID int) error {
+	query := `
+	UPDATE audit_plans
+	SET status_id = ?
+	WHERE id = ? AND status_id = ? AND deleted_at IS NULL;
+	`
+	return changeTracked(ctx, r.db, events.ChangeUpdated, events.EntityAuditPlan, id, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, toStatusID, id, fromStatusID)
+		if err != nil {
+			return fmt.Errorf("failed to update audit plan status: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return custom_errors.Conflict(ctx, "Audit plan", "status was changed by another request")
+		}
+
+		return nil
+	})
+}
+
+// DeleteAuditPlan soft deletes a plan, its checklist and results stay in place for the records
+func (r *AuditPlanRepository) DeleteAuditPlan(ctx context.Context, id int) error {
+	query := `
+	UPDATE audit_plans
+	SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP
+	WHERE id = ? AND deleted_at IS NULL;
+	`
+	return changeTracked(ctx, r.db, events.ChangeDeleted, events.EntityAuditPlan, id, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete audit plan: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return custom_errors.NotFound(ctx, "Audit plan")
+		}
+
+		return nil
+	})
+}
+
+// checkLeadAuditor reports a lead auditor that is not an active user as INVALID_DATA
+func (r *AuditPlanRepository) checkLeadAuditor(ctx context.Context, userID int) error {
+	var count int
+	query := "SELECT COUNT(*) FROM users WHERE id = ? AND is_active = TRUE AND deleted_at IS NULL;"
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return fmt.Errorf("failed to check lead auditor: %w", err)
+	}
+	if count == 0 {
+		return custom_errors.InvalidData(ctx, "lead_auditor_id is not an active user")
+	}
+	return nil
+}
+
+func scanAuditPlan(row rowScanner) (types.AuditPlan, error) {
+	var plan types.AuditPlan
+
+	err := row.Scan(
+		&plan.ID,
+		&plan.StandardID,
+		&plan.LeadAuditorID,
+		&plan.Name,
+		&plan.StatusVal.ID,
+		&plan.ScheduledDate,
+		&plan.Team,
+		&plan.Scope,
+		&plan.TypeVal.ID,
+		&plan.CreatedAt,
+		&plan.UpdatedAt,
+	)
+	if err != nil {
+		return types.AuditPlan{}, err
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,214 @@
+package repositories
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// AuditQuestionRepository is the concrete implementation
+type AuditQuestionRepository struct {
+	db *sql.DB
+}
+
+// Ensure AuditQuestionRepository implements AuditQuestionRepositoryInterface
+var _ AuditQuestionRepositoryInterface = (*AuditQuestionRepository)(nil)
+
+func NewAuditQuestionRepository(db *sql.DB) (AuditQuestionRepositoryInterface, error) {
+	return &AuditQuestionRepository{db: db}, nil
+}
+
+// GetByAuditIDAuditQuestions returns every question of an audit with its expected evidence,
+// provided evidence and comments
+func (r *AuditQuestionRepository) GetByAuditIDAuditQuestions(ctx context.Context, auditID int) ([]types.AuditQuestion, error) {
+	return r.loadAuditQuestions(ctx, "aq.audit_id = ?", auditID)
+}
+
+// GetByIDAuditQuestion returns an audit question with its expected evidence, provided evidence and comments
+func (r *AuditQuestionRepository) GetByIDAuditQuestion(ctx context.Context, question types.AuditQuestion) (types.AuditQuestion, error) {
+	questions, err := r.loadAuditQuestions(ctx, "aq.id = ?", question.ID)
+	if err != nil {
+		return types.AuditQuestion{}, err
+	}
+	if len(questions) == 0 {
+		return types.AuditQuestion{}, custom_errors.NotFound(ctx, "Audit question")
+	}
+	return questions[0], nil
+}
+
+// GetAuditProgress counts the questions of an audit that have provided evidence or comments, and
+// those where every expected evidence has a submission in acceptedStatusID
+func (r *AuditQuestionRepository) GetAuditProgress(ctx context.Context, auditID, acceptedStatusID int) (types.AuditProgress, error) {
+	query := `
+	SELECT
+		COUNT(*),
+		COALESCE(SUM(EXISTS (SELECT 1 FROM evidence_provided AS ep WHERE ep.audit_question_id = aq.id AND ep.deleted_at IS NULL)), 0),
+		COALESCE(SUM(EXISTS (SELECT 1 FROM evidence AS e WHERE e.question_id = aq.question_id)
+			AND NOT EXISTS (
+				SELECT 1 FROM evidence AS e
+				WHERE e.question_id = aq.question_id AND NOT EXISTS (
+					SELECT 1 FROM evidence_provided AS ep
+					WHERE ep.audit_question_id = aq.id AND ep.evidence_id = e.id AND ep.status_id = ? AND ep.deleted_at IS NULL))), 0),
+		COALESCE(SUM(EXISTS (SELECT 1 FROM audit_questions_comments AS aqc WHERE aqc.audit_question_id = aq.id)), 0),
+		(SELECT COUNT(*)
+			FROM evidence_provided AS ep
+			INNER JOIN audit_questions AS aq2 ON aq2.id = ep.audit_question_id
+			WHERE aq2.audit_id = ? AND ep.deleted_at IS NULL)
+	FROM audit_questions AS aq
+	WHERE aq.audit_id = ?;
+	`
+	progress := types.AuditProgress{AuditPlanID: auditID}
+	err := r.db.QueryRowContext(ctx, query, acceptedStatusID, auditID, auditID).Scan(
+		&progress.Questions,
+		&progress.Answered,
+		&progress.Satisfied,
+		&progress.Commented,
+		&progress.EvidenceProvided,
+	)
+	if err != nil {
+		return types.AuditProgress{}, fmt.Errorf("failed to count audit progress: %w", err)
+	}
+
+	progress.Unanswered = progress.Questions - progress.Answered
+	return progress, nil
+}
+
+// loadAuditQuestions reads the audit questions matching filter, a condition on the audit_questions
+// alias aq, and fills their children with one query per kind rather than one per question
+func (r *AuditQuestionRepository) loadAuditQuestions(ctx context.Context, filter string, arg int) ([]types.AuditQuestion, error) {
+	query := `
+	SELECT aq.id, aq.audit_id, aq.question_id, q.requirement_id, r.reference_code, q.question, q.guidance
+	FROM audit_questions AS aq
+	INNER JOIN questions AS q ON q.id = aq.question_id
+	INNER JOIN requirement AS r ON r.id = q.requirement_id
+	WHERE ` + filter + `
+	ORDER BY q.requirement_id, q.sort_order, aq.id;
+	`
+	rows, err := r.db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit questions: %w", err)
+	}
+	defer rows.Close()
+
+	questions := []types.AuditQuestion{}
+	byQuestionID := make(map[int]int)
+	byID := make(map[int]int)
+	for rows.Next() {
+		var (
+			question = types.AuditQuestion{
+				Evidence:         []types.Evidence{},
+				EvidenceProvided: []types.EvidenceProvided{},
+				Comments:         []types.Comment{},
+			}
+			guidance sql.NullString
+		)
+		err := rows.Scan(
+			&question.ID,
+			&question.AuditID,
+			&question.QuestionID,
+			&question.RequirementID,
+			&question.ReferenceCode,
+			&question.Question,
+			&guidance,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit question row: %w", err)
+		}
+
+		question.Guidance = guidance.String
+		byQuestionID[question.QuestionID] = len(questions)
+		byID[question.ID] = len(questions)
+		questions = append(questions, question)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over audit question rows: %w", err)
+	}
+	if len(questions) == 0 {
+		return questions, nil
+	}
+
+	// A question appears once per audit, so the question ID identifies the audit question
+	evidenceQuery := `
+	SELECT e.id, e.question_id, e.expected, e.created_at, e.updated_at, e.sort_order,
+				rv.id, rv.type_id, rv.code, rv.name, rv.description, rv.is_active, rv.created_at, rv.updated_at
+	FROM evidence AS e
+	INNER JOIN reference_values AS rv ON rv.id = e.type_id
+	INNER JOIN audit_questions AS aq ON aq.question_id = e.question_id
+	WHERE ` + filter + `
+	ORDER BY e.sort_order, e.id;
+	`
+	evidenceRows, err := r.db.QueryContext(ctx, evidenceQuery, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expected evidence: %w", err)
+	}
+	defer evidenceRows.Close()
+
+	for evidenceRows.Next() {
+		evidence, err := scanEvidence(evidenceRows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan expected evidence row: %w", err)
+		}
+		index := byQuestionID[evidence.QuestionID]
+		questions[index].Evidence = append(questions[index].Evidence, evidence)
+	}
+
+	if err = evidenceRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over expected evidence rows: %w", err)
+	}
+
+	providedQuery := `
+	SELECT` + evidenceProvidedColumns + `
+	FROM evidence_provided AS ep
+	INNER JOIN audit_questions AS aq ON aq.id = ep.audit_question_id
+	WHERE ` + filter + ` AND ep.deleted_at IS NULL
+	ORDER BY ep.created_at, ep.id;
+	`
+	providedRows, err := r.db.QueryContext(ctx, providedQuery, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query provided evidence: %w", err)
+	}
+	defer providedRows.Close()
+
+	for providedRows.Next() {
+		provided, err := scanEvidenceProvided(providedRows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan provided evidence row: %w", err)
+		}
+		index := byID[provided.AuditQuestionID]
+		questions[index].EvidenceProvided = append(questions[index].EvidenceProvided, provided)
+	}
+
+	if err = providedRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over provided evidence rows: %w", err)
+	}
+
+	commentQuery := `
+	SELECT` + commentColumns + commentJoins + `
+	INNER JOIN audit_questions AS aq ON aq.id = aqc.audit_question_id
+	WHERE ` + filter + `
+	ORDER BY c.created_at, c.id;
+	`
+	commentRows, err := r.db.QueryContext(ctx, commentQuery, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comments: %w", err)
+	}
+	defer commentRows.Close()
+
+	for commentRows.Next() {
+		comment, err := scanComment(commentRows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan comment row: %w", err)
+		}
+		index := byID[comment.AuditQuestionID]
+		questions[index].Comments = append(questions[index].Comments, comment)
+	}
+
+	if err = commentRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over comment rows: %w", err)
+	}
+
+	return questions, nil
+}
@@ -0,0 +1,382 @@
+package repositories
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// CommentRepository is the concrete implementation
+type CommentRepository struct {
+	db *sql.DB
+}
+
+// Ensure CommentRepository implements CommentRepositoryInterface
+var _ CommentRepositoryInterface = (*CommentRepository)(nil)
+
+func NewCommentRepository(db *sql.DB) (CommentRepositoryInterface, error) {
+	return &CommentRepository{db: db}, nil
+}
+
+const commentColumns = `
+	c.id, c.parent_id, aqc.audit_question_id, c.user_id, c.comment, c.created_at, c.edited_at,
+	c.deleted_at, u.id, u.name`
+
+const commentJoins = `
+	FROM comments AS c
+	INNER JOIN audit_questions_comments AS aqc ON aqc.comment_id = c.id
+	INNER JOIN users AS u ON u.id = c.user_id`
+
+// GetByAuditQuestionIDComments returns every comment of an audit question with its mentions,
+// oldest first. Deleted comments are kept without their text so their replies stay in place.
+func (r *CommentRepository) GetByAuditQuestionIDComments(ctx context.Context, auditQuestionID int) ([]types.Comment, error) {
+	query := `
+	SELECT` + commentColumns + commentJoins + `
+	WHERE aqc.audit_question_id = ?
+	ORDER BY c.created_at, c.id;
+	`
+	rows, err := r.db.QueryContext(ctx, query, auditQuestionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []types.Comment{}
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan comment row: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over comment rows: %w", err)
+	}
+
+	if err := r.loadMentions(ctx, comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// GetByIDComment returns a comment with its mentions, deleted comments are returned without text
+func (r *CommentRepository) GetByIDComment(ctx context.Context, comment types.Comment) (types.Comment, error) {
+	query := `
+	SELECT` + commentColumns + commentJoins + `
+	WHERE c.id = ?;
+	`
+	result, err := scanComment(r.db.QueryRowContext(ctx, query, comment.ID))
+	if err == sql.ErrNoRows {
+		return types.Comment{}, custom_errors.NotFound(ctx, "Comment")
+	}
+	if err != nil {
+		return types.Comment{}, fmt.Errorf("failed to scan comment: %w", err)
+	}
+
+	comments := []types.Comment{result}
+	if err := r.loadMentions(ctx, comments); err != nil {
+		return types.Comment{}, err
+	}
+	return comments[0], nil
+}
+
+// CreateAuditQuestionComment adds a comment, links it to the audit question and records the users
+// it mentions in one transaction
+func (r *CommentRepository) CreateAuditQuestionComment(ctx context.Context, auditQuestionID int, comment types.Comment, mentionIDs []int) (types.Comment, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return types.Comment{}, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() // Safe to call even after commit
+
+	query := "INSERT INTO comments (parent_id, user_id, comment) VALUES (?, ?, ?);"
+	result, err := tx.ExecContext(ctx, query, nullInt(comment.ParentID), comment.UserID, comment.Text)
+	if err != nil {
+		return types.Comment{}, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return types.Comment{}, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	linkQuery := "INSERT INTO audit_questions_comments (audit_question_id, comment_id) VALUES (?, ?);"
+	if _, err := tx.ExecContext(ctx, linkQuery, auditQuestionID, id); err != nil {
+		return types.Comment{}, fmt.Errorf("failed to link comment: %w", err)
+	}
+
+	if err := insertMentions(ctx, tx, int(id), mentionIDs); err != nil {
+		return types.Comment{}, err
+	}
+	if err := recordActivity(ctx, tx, events.ChangeCreated, events.EntityComment, int(id), nil); err != nil {
+		return types.Comment{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return types.Comment{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	comment.ID = int(id)
+	return r.GetByIDComment(ctx, comment)
+}
+
+// UpdateComment replaces the text and mentions of a comment that is not deleted. The text it had
+// before is kept as a revision by editedBy.
+func (r *CommentRepository) UpdateComment(ctx context.Context, comment types.Comment, editedBy int, mentionIDs []int) (types.Comment, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return types.Comment{}, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() // Safe to call even after commit
+
+	var previous string
+	query := "SELECT comment FROM comments WHERE id = ? AND deleted_at IS NULL FOR UPDATE;"
+	err = tx.QueryRowContext(ctx, query, comment.ID).Scan(&previous)
+	if err == sql.ErrNoRows {
+		return types.Comment{}, custom_errors.NotFound(ctx, "Comment")
+	}
+	if err != nil {
+		return types.Comment{}, fmt.Errorf("failed to query comment: %w", err)
+	}
+
+	before, err := snapshotRow(ctx, tx, events.EntityComment, comment.ID)
+	if err != nil {
+		return types.Comment{}, err
+	}
+
+	query = "INSERT INTO comment_revisions (comment_id, comment, edited_by) VALUES (?, ?, ?);"
+	if _, err := tx.ExecContext(ctx, query, comment.ID, previous, editedBy); err != nil {
+		return types.Comment{}, fmt.Errorf("failed to create comment revision: %w", err)
+	}
+
+	query = "UPDATE comments SET comment = ?, edited_at = CURRENT_TIMESTAMP WHERE id = ?;"
+	if _, err := tx.ExecContext(ctx, query, comment.Text, comment.ID); err != nil {
+		return types.Comment{}, fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM comment_mentions WHERE comment_id = ?;", comment.ID); err != nil {
+		return types.Comment{}, fmt.Errorf("failed to delete comment mentions: %w", err)
+	}
+	if err := insertMentions(ctx, tx, comment.ID, mentionIDs); err != nil {
+		return types.Comment{}, err
+	}
+	if err := recordActivity(ctx, tx, events.ChangeUpdated, events.EntityComment, comment.ID, before); err != nil {
+		return types.Comment{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return types.Comment{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetByIDComment(ctx, comment)
+}
+
+// DeleteComment soft deletes a comment, its replies and revisions stay
+func (r *CommentRepository) DeleteComment(ctx context.Context, id int) error {
+	query := `
+	UPDATE comments
+	SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP
+	WHERE id = ? AND deleted_at IS NULL;
+	`
+	return changeTracked(ctx, r.db, events.ChangeDeleted, events.EntityComment, id, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete comment: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return custom_errors.NotFound(ctx, "Comment")
+		}
+		return nil
+	})
+}
+
+// GetRevisionsComment returns the earlier texts of a comment, oldest first
+func (r *CommentRepository) GetRevisionsComment(ctx context.Context, commentID int) ([]types.CommentRevision, error) {
+	query := `
+	SELECT id, comment_id, comment, edited_by, created_at
+	FROM comment_revisions
+	WHERE comment_id = ?
+	ORDER BY created_at, id;
+	`
+	rows, err := r.db.QueryContext(ctx, query, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comment revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []types.CommentRevision{}
+	for rows.Next() {
+		var revision types.CommentRevision
+		if err := rows.Scan(&revision.ID, &revision.CommentID, &revision.Text, &revision.EditedBy, &revision.EditedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan comment revision row: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over comment revision rows: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// GetMentionedUserIDs looks up active users by mention handle. A handle is either a full email
+// address or the part before the @ of one, compared case-insensitively. Handles are expected in
+// lower case and map to every user they match.
+func (r *CommentRepository) GetMentionedUserIDs(ctx context.Context, handles []string) (map[string][]int, error) {
+	matches := make(map[string][]int)
+	if len(handles) == 0 {
+		return matches, nil
+	}
+
+	args := make([]any, 0, 2*len(handles))
+	for _, handle := range handles {
+		args = append(args, handle)
+	}
+	args = append(args, args...)
+
+	query := `
+	SELECT id, LOWER(email)
+	FROM users
+	WHERE is_active = TRUE AND deleted_at IS NULL
+		AND (LOWER(email) IN (` + placeholders(len(handles)) + `)
+			OR LOWER(SUBSTRING_INDEX(email, '@', 1)) IN (` + placeholders(len(handles)) + `))
+	ORDER BY id;
+	`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query mentioned users: %w", err)
+	}
+	defer rows.Close()
+
+	wanted := make(map[string]bool, len(handles))
+	for _, handle := range handles {
+		wanted[handle] = true
+	}
+
+	for rows.Next() {
+		var (
+			id    int
+			email string
+		)
+		if err := rows.Scan(&id, &email); err != nil {
+			return nil, fmt.Errorf("failed to scan mentioned user row: %w", err)
+		}
+		if wanted[email] {
+			matches[email] = append(matches[email], id)
+		}
+		if local, _, ok := strings.Cut(email, "@"); ok && wanted[local] {
+			matches[local] = append(matches[local], id)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over mentioned user rows: %w", err)
+	}
+
+	return matches, nil
+}
+
+// loadMentions fills the mentioned users of the comments with one query
+func (r *CommentRepository) loadMentions(ctx context.Context, comments []types.Comment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(comments))
+	byID := make(map[int]int, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.ID
+		byID[comment.ID] = i
+	}
+
+	query := `
+	SELECT cm.comment_id, u.id, u.name
+	FROM comment_mentions AS cm
+	INNER JOIN users AS u ON u.id = cm.user_id
+	WHERE cm.comment_id IN (` + placeholders(len(ids)) + `)
+	ORDER BY cm.id;
+	`
+	rows, err := r.db.QueryContext(ctx, query, intArgs(ids)...)
+	if err != nil {
+		return fmt.Errorf("failed to query comment mentions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			commentID int
+			user      types.UserRef
+		)
+		if err := rows.Scan(&commentID, &user.ID, &user.Name); err != nil {
+			return fmt.Errorf("failed to scan comment mention row: %w", err)
+		}
+		index := byID[commentID]
+		if comments[index].Deleted {
+			continue
+		}
+		comments[index].Mentions = append(comments[index].Mentions, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating over comment mention rows: %w", err)
+	}
+
+	return nil
+}
+
+func insertMentions(ctx context.Context, tx *sql.Tx, commentID int, userIDs []int) error {
+	query := "INSERT INTO comment_mentions (comment_id, user_id) VALUES (?, ?);"
+	for _, userID := range userIDs {
+		if _, err := tx.ExecContext(ctx, query, commentID, userID); err != nil {
+			return fmt.Errorf("failed to create comment mention: %w", err)
+		}
+	}
+	return nil
+}
+
+// scanComment reads a row selected with commentColumns. The text of deleted comments is dropped.
+func scanComment(row rowScanner) (types.Comment, error) {
+	var (
+		comment   types.Comment
+		parentID  sql.NullInt64
+		editedAt  sql.NullTime
+		deletedAt sql.NullTime
+	)
+
+	err := row.Scan(
+		&comment.ID,
+		&parentID,
+		&comment.AuditQuestionID,
+		&comment.UserID,
+		&comment.Text,
+		&comment.CreatedAt,
+		&editedAt,
+		&deletedAt,
+		&comment.User.ID,
+		&comment.User.Name,
+	)
+	if err != nil {
+		return types.Comment{}, err
+	}
+
+	comment.ParentID = int(parentID.Int64)
+	if editedAt.Valid {
+		comment.EditedAt = &editedAt.Time
+	}
+	if deletedAt.Valid {
+		comment.Text = ""
+		comment.Deleted = true
+	}
+	return comment, nil
+}
@@ -0,0 +1,230 @@
+package repositories
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// CorrectiveActionRepository is the concrete implementation
+type CorrectiveActionRepository struct {
+	db *sql.DB
+}
+
+// Ensure CorrectiveActionRepository implements CorrectiveActionRepositoryInterface
+var _ CorrectiveActionRepositoryInterface = (*CorrectiveActionRepository)(nil)
+
+func NewCorrectiveActionRepository(db *sql.DB) (CorrectiveActionRepositoryInterface, error) {
+	return &CorrectiveActionRepository{db: db}, nil
+}
+
+// Actions recorded before owners were introduced have no owner or target date, hence the LEFT JOIN
+const correctiveActionColumns = `
+	ca.id, ca.finding_id, ca.action, ca.owner_id, u.name, ca.target_date, ca.completion_note,
+	ca.completed_at, ca.verified_by, ca.verified_at, ca.created_at, ca.updated_at
+	FROM finding_corrective_actions AS ca
+	LEFT JOIN users AS u ON u.id = ca.owner_id`
+
+// GetByFindingIDCorrectiveActions returns the actions of a finding in the order they were planned
+func (r *CorrectiveActionRepository) GetByFindingIDCorrectiveActions(ctx context.Context, findingID int) ([]types.CorrectiveAction, error) {
+	query := `
+	SELECT` + correctiveActionColumns + `
+	WHERE ca.finding_id = ?
+	ORDER BY ca.id;
+	`
+	rows, err := r.db.QueryContext(ctx, query, findingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query corrective actions: %w", err)
+	}
+	defer rows.Close()
+
+	actions := []types.CorrectiveAction{}
+	for rows.Next() {
+		action, err := scanCorrectiveAction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan corrective action row: %w", err)
+		}
+		actions = append(actions, action)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over corrective action rows: %w", err)
+	}
+
+	return actions, nil
+}
+
+func (r *CorrectiveActionRepository) GetByIDCorrectiveAction(ctx context.Context, action types.CorrectiveAction) (types.CorrectiveAction, error) {
+	query := `
+	SELECT` + correctiveActionColumns + `
+	WHERE ca.id = ?;
+	`
+	result, err := scanCorrectiveAction(r.db.QueryRowContext(ctx, query, action.ID))
+	if err == sql.ErrNoRows {
+		return types.CorrectiveAction{}, custom_errors.NotFound(ctx, "Corrective action")
+	}
+	if err != nil {
+		return types.CorrectiveAction{}, fmt.Errorf("failed to scan corrective action: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *CorrectiveActionRepository) CreateCorrectiveAction(ctx context.Context, action types.CorrectiveAction) (types.CorrectiveAction, error) {
+	if err := r.checkOwner(ctx, action.OwnerID); err != nil {
+		return types.CorrectiveAction{}, err
+	}
+
+	query := `
+	INSERT INTO finding_corrective_actions (finding_id, action, owner_id, target_date)
+	VALUES (?, ?, ?, ?);
+	`
+	id, err := createTracked(ctx, r.db, events.EntityCorrectiveAction, func(tx *sql.Tx) (int, error) {
+		result, err := tx.ExecContext(ctx, query, action.FindingID, action.Action, action.OwnerID, action.TargetDate)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create corrective action: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+		return int(id), nil
+	})
+	if err != nil {
+		return types.CorrectiveAction{}, err
+	}
+
+	action.ID = id
+	return r.GetByIDCorrectiveAction(ctx, action)
+}
+
+// UpdateCorrectiveAction changes what is planned. Verified actions are part of the record and are
+// reported as a CONFLICT error.
+func (r *CorrectiveActionRepository) UpdateCorrectiveAction(ctx context.Context, action types.CorrectiveAction) (types.CorrectiveAction, error) {
+	if err := r.checkOwner(ctx, action.OwnerID); err != nil {
+		return types.CorrectiveAction{}, err
+	}
+
+	query := `
+	UPDATE finding_corrective_actions
+	SET action = ?, owner_id = ?, target_date = ?
+	WHERE id = ? AND verified_at IS NULL;
+	`
+	err := changeTracked(ctx, r.db, events.ChangeUpdated, events.EntityCorrectiveAction, action.ID, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, action.Action, action.OwnerID, action.TargetDate, action.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update corrective action: %w", err)
+		}
+
+		return r.checkChanged(ctx, result, "is verified and can no longer be changed")
+	})
+	if err != nil {
+		return types.CorrectiveAction{}, err
+	}
+
+	return r.GetByIDCorrectiveAction(ctx, action)
+}
+
+// CompleteCorrectiveAction records what was done. Completing again replaces the note until the
+// action is verified.
+func (r *CorrectiveActionRepository) CompleteCorrectiveAction(ctx context.Context, id int, note string) error {
+	query := `
+	UPDATE finding_corrective_actions
+	SET completion_note = ?, completed_at = CURRENT_TIMESTAMP
+	WHERE id = ? AND verified_at IS NULL;
+	`
+	return changeTracked(ctx, r.db, events.ChangeUpdated, events.EntityCorrectiveAction, id, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, note, id)
+		if err != nil {
+			return fmt.Errorf("failed to complete corrective action: %w", err)
+		}
+
+		return r.checkChanged(ctx, result, "is verified and can no longer be changed")
+	})
+}
+
+// VerifyCorrectiveAction marks a completed action as verified by an auditor
+func (r *CorrectiveActionRepository) VerifyCorrectiveAction(ctx context.Context, id, userID int) error {
+	query := `
+	UPDATE finding_corrective_actions
+	SET verified_by = ?, verified_at = CURRENT_TIMESTAMP
+	WHERE id = ? AND completed_at IS NOT NULL AND verified_at IS NULL;
+	`
+	return changeTracked(ctx, r.db, events.ChangeUpdated, events.EntityCorrectiveAction, id, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, userID, id)
+		if err != nil {
+			return fmt.Errorf("failed to verify corrective action: %w", err)
+		}
+
+		return r.checkChanged(ctx, result, "must be completed and not yet verified")
+	})
+}
+
+// checkChanged reports a conditional update that matched no row as a CONFLICT error. The caller
+// loads the action first, so a missing row means the condition did not hold.
+func (r *CorrectiveActionRepository) checkChanged(ctx context.Context, result sql.Result, reason string) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return custom_errors.Conflict(ctx, "Corrective action", reason)
+	}
+	return nil
+}
+
+// checkOwner reports an owner that is not an active user as INVALID_DATA
+func (r *CorrectiveActionRepository) checkOwner(ctx context.Context, userID int) error {
+	var count int
+	query := "SELECT COUNT(*) FROM users WHERE id = ? AND is_active = TRUE AND deleted_at IS NULL;"
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return fmt.Errorf("failed to check corrective action owner: %w", err)
+	}
+	if count == 0 {
+		return custom_errors.InvalidData(ctx, "owner_id is not an active user")
+	}
+	return nil
+}
+
+func scanCorrectiveAction(row rowScanner) (types.CorrectiveAction, error) {
+	var action types.CorrectiveAction
+	var ownerID, verifiedBy sql.NullInt64
+	var owner, completionNote sql.NullString
+	var targetDate, completedAt, verifiedAt sql.NullTime
+
+	err := row.Scan(
+		&action.ID,
+		&action.FindingID,
+		&action.Action,
+		&ownerID,
+		&owner,
+		&targetDate,
+		&completionNote,
+		&completedAt,
+		&verifiedBy,
+		&verifiedAt,
+		&action.CreatedAt,
+		&action.UpdatedAt,
+	)
+	if err != nil {
+		return types.CorrectiveAction{}, err
+	}
+
+	action.OwnerID = int(ownerID.Int64)
+	action.Owner = owner.String
+	action.TargetDate = targetDate.Time
+	action.CompletionNote = completionNote.String
+	action.VerifiedBy = int(verifiedBy.Int64)
+	if completedAt.Valid {
+		action.CompletedAt = &completedAt.Time
+	}
+	if verifiedAt.Valid {
+		action.VerifiedAt = &verifiedAt.Time
+	}
+
+	return action, nil
+}
@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
 	"ISO_Auditing_Tool/pkg/types"
 	"context"
 	"database/sql"
@@ -21,216 +23,259 @@ func NewDraftRepository(db *sql.DB) (DraftRepositoryInterface, error) {
 	return &DraftRepository{db: db}, nil
 }
 
+// draftColumns are the columns read by scanDraft
+const draftColumns = `
+	id, type_id, object_id, status_id, version, data, diff,
+	user_id, approver_id, approval_comment, published_at, publish_error,
+	created_at, updated_at, expires_at
+`
+
 func (r *DraftRepository) GetAllDrafts(ctx context.Context) ([]types.Draft, error) {
 	query := `
-	SELECT id, type_id, object_id, status_id, version, data, diff,
-				user_id, approver_id, approval_comment, publish_error, 
-				created_at, updated_at, expires_at
+	SELECT ` + draftColumns + `
 	FROM drafts
 	ORDER BY created_at DESC;
 	`
-	rows, err := r.db.QueryContext(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query drafts: %w", err)
-	}
-	defer rows.Close()
+	return r.queryDrafts(ctx, query)
+}
 
-	var drafts []types.Draft
-	for rows.Next() {
-		var draft types.Draft
-		var updatedAt, expiresAt sql.NullTime
-		var data, diff sql.NullString
+func (r *DraftRepository) CreateDraft(ctx context.Context, draft types.Draft) (types.Draft, error) {
+	query := `
+  INSERT INTO drafts (
+    type_id, object_id, status_id, version, data, diff,
+    user_id, approver_id, approval_comment, publish_error
+  ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+  `
 
-		err := rows.Scan(
-			&draft.ID,
-			&draft.TypeID,
-			&draft.ObjectID,
-			&draft.StatusID,
-			&draft.Version,
-			&data,
-			&diff,
-			&draft.UserID,
-			&draft.ApproverID,
-			&draft.ApprovalComment,
-			&draft.PublishError,
-			&draft.CreatedAt,
-			&updatedAt,
-			&expiresAt,
+	id, err := createTracked(ctx, r.db, events.EntityDraft, func(tx *sql.Tx) (int, error) {
+		result, err := tx.ExecContext(
+			ctx,
+			query,
+			draft.TypeID,
+			nullInt(draft.ObjectID),
+			draft.StatusID,
+			draft.Version,
+			draft.Data,
+			draft.Diff,
+			draft.UserID,
+			nullInt(draft.ApproverID),
+			nullString(draft.ApprovalComment),
+			nullString(draft.PublishError),
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan draft row: %w", err)
-		}
-
-		// Handle nullable data field
-		if data.Valid {
-			draft.Data = json.RawMessage(data.String)
-		} else {
-			draft.Data = nil
+			return 0, fmt.Errorf("failed to create draft: %w", err)
 		}
 
-		// Handle nullable diff field
-		if diff.Valid {
-			draft.Diff = json.RawMessage(data.String)
-		} else {
-			draft.Diff = nil
-		}
-
-		// Handle nullable updated_at field
-		if updatedAt.Valid {
-			draft.UpdatedAt = updatedAt.Time
+		id, err := result.LastInsertId()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get last insert ID: %w", err)
 		}
+		return int(id), nil
+	})
+	if err != nil {
+		return types.Draft{}, err
+	}
 
-		// Handle nullable expires_at field
-		if expiresAt.Valid {
-			draft.ExpiresAt = expiresAt.Time
-		}
+	draft.ID = id
+	return draft, nil
+}
 
-		drafts = append(drafts, draft)
+// GetDraftByID returns the draft with the ID of draft, NOT_FOUND when there is none
+func (r *DraftRepository) GetDraftByID(ctx context.Context, draft types.Draft) (types.Draft, error) {
+	query := `
+	SELECT ` + draftColumns + `
+	FROM drafts
+	WHERE id = ?;
+	`
+	found, err := scanDraft(r.db.QueryRowContext(ctx, query, draft.ID))
+	if err == sql.ErrNoRows {
+		return types.Draft{}, custom_errors.NotFound(ctx, "Draft")
 	}
-
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating over draft rows: %w", err)
+	if err != nil {
+		return types.Draft{}, fmt.Errorf("failed to get draft: %w", err)
 	}
 
-	return drafts, nil
+	return found, nil
 }
 
-func (r *DraftRepository) CreateDraft(ctx context.Context, draft types.Draft) (types.Draft, error) {
+// UpdateDraft replaces the data of a draft while it is still in fromStatusID. The row is locked
+// before its status is checked, so a draft submitted or approved by a concurrent request is
+// reported as a CONFLICT error instead of changing after the fact. A missing draft is NOT_FOUND.
+func (r *DraftRepository) UpdateDraft(ctx context.Context, draft types.Draft, fromStatusID int) (types.Draft, error) {
 	query := `
-  INSERT INTO drafts (
-    type_id, object_id, status_id, version, data, diff,
-    user_id, approver_id, approval_comment, publish_error
-  ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
-  `
+	UPDATE drafts
+	SET data = ?
+	WHERE id = ? AND status_id = ?;
+	`
 
-	result, err := r.db.ExecContext(
-		ctx,
-		query,
-		draft.TypeID,
-		draft.ObjectID,
-		draft.StatusID,
-		draft.Version,
-		draft.Data,
-		draft.Diff,
-		draft.UserID,
-		draft.ApproverID,
-		draft.ApprovalComment,
-		draft.PublishError,
-	)
-	if err != nil {
-		return types.Draft{}, fmt.Errorf("failed to create draft: %w", err)
-	}
+	err := changeTracked(ctx, r.db, events.ChangeUpdated, events.EntityDraft, draft.ID, func(tx *sql.Tx) error {
+		var statusID int
+		err := tx.QueryRowContext(ctx, "SELECT status_id FROM drafts WHERE id = ? FOR UPDATE;", draft.ID).Scan(&statusID)
+		if err == sql.ErrNoRows {
+			return custom_errors.NotFound(ctx, "Draft")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get draft status: %w", err)
+		}
+		if statusID != fromStatusID {
+			return custom_errors.Conflict(ctx, "Draft", "was changed by another request")
+		}
 
-	id, err := result.LastInsertId()
+		if _, err := tx.ExecContext(ctx, query, draft.Data, draft.ID, fromStatusID); err != nil {
+			return fmt.Errorf("failed to update draft: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return types.Draft{}, fmt.Errorf("failed to get last insert ID: %w", err)
+		return types.Draft{}, err
 	}
 
-	draft.ID = int(id)
 	return draft, nil
 }
 
-func (r *DraftRepository) GetDraftByID(ctx context.Context, draft types.Draft) (types.Draft, error) {
-	query := ``
-	result, err := r.db.ExecContext(
-		ctx,
-		query,
-		draft.ID,
-	)
-	if err != nil {
-		return draft, fmt.Errorf("Failed to get draft by ID: %w", err)
-	}
+// DeleteDraft removes a draft, what it proposed stays in the activity log
+func (r *DraftRepository) DeleteDraft(ctx context.Context, draft types.Draft) (types.Draft, error) {
+	query := "DELETE FROM drafts WHERE id = ?;"
+	err := changeTracked(ctx, r.db, events.ChangeDeleted, events.EntityDraft, draft.ID, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(
+			ctx,
+			query,
+			draft.ID,
+		)
+		if err != nil {
+			return fmt.Errorf("Failed to delete draft by ID: %w", err)
+		}
 
-	rows, err := result.RowsAffected()
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("Failed to get rows affected: %d, %w", rows, err)
+		}
+		return nil
+	})
 	if err != nil {
-		return draft, fmt.Errorf("Failed to get rows affected: %d, %w", rows, err)
+		return draft, err
 	}
 
 	return draft, nil
 }
 
-func (r *DraftRepository) UpdateDraft(ctx context.Context, draft types.Draft) (types.Draft, error) {
+func (r *DraftRepository) GetDraftsByTypeAndObject(ctx context.Context, typeID, objectID int) ([]types.Draft, error) {
 	query := `
-  UPDATE drafts
-  SET  data = ?
-  WHERE id = ?;
-  `
+	SELECT ` + draftColumns + `
+	FROM drafts
+	WHERE type_id = ? AND object_id = ?
+	ORDER BY created_at DESC;
+	`
+	return r.queryDrafts(ctx, query, typeID, objectID)
+}
 
-	result, err := r.db.ExecContext(
-		ctx,
-		query,
-		draft.Data,
-		draft.ID,
-	)
+// GetDraftTransitions returns the steps of the approval workflow of a draft, oldest first
+func (r *DraftRepository) GetDraftTransitions(ctx context.Context, draftID int) ([]types.DraftTransition, error) {
+	query := `
+	SELECT id, draft_id, action, from_status_id, to_status_id, user_id, comment, created_at
+	FROM draft_transitions
+	WHERE draft_id = ?
+	ORDER BY id;
+	`
+	rows, err := r.db.QueryContext(ctx, query, draftID)
 	if err != nil {
-		return draft, fmt.Errorf("Failed to update draft: %w", err)
+		return nil, fmt.Errorf("failed to query draft transitions: %w", err)
 	}
+	defer rows.Close()
 
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return draft, fmt.Errorf("Failed to get rows affected: %d, %w", rows, err)
+	transitions := []types.DraftTransition{}
+	for rows.Next() {
+		var transition types.DraftTransition
+		err := rows.Scan(
+			&transition.ID,
+			&transition.DraftID,
+			&transition.Action,
+			&transition.FromStatusID,
+			&transition.ToStatusID,
+			&transition.UserID,
+			&transition.Comment,
+			&transition.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan draft transition row: %w", err)
+		}
+		transitions = append(transitions, transition)
 	}
 
-	return draft, nil
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over draft transition rows: %w", err)
+	}
+
+	return transitions, nil
 }
 
-func (r *DraftRepository) DeleteDraft(ctx context.Context, draft types.Draft) (types.Draft, error) {
-	query := ``
-	result, err := r.db.ExecContext(
-		ctx,
-		query,
-		draft.ID,
+// TransitionDraft moves a draft from its current state to next, recording the step with its
+// comment. Only the status, approver, approval comment and publication time of next are saved.
+// A draft whose status or approver no longer match current is reported as a CONFLICT error.
+func (r *DraftRepository) TransitionDraft(ctx context.Context, current, next types.Draft, transition types.DraftTransition) error {
+	return changeTracked(ctx, r.db, events.ChangeUpdated, events.EntityDraft, current.ID, func(tx *sql.Tx) error {
+		return transitionDraft(ctx, tx, current, next, transition)
+	})
+}
+
+// transitionDraft runs the status change of TransitionDraft inside tx, for repositories that store
+// the change a draft publishes in the same transaction
+func transitionDraft(ctx context.Context, tx *sql.Tx, current, next types.Draft, transition types.DraftTransition) error {
+	query := `
+	UPDATE drafts
+	SET status_id = ?, approver_id = ?, approval_comment = ?, published_at = ?
+	WHERE id = ? AND status_id = ? AND COALESCE(approver_id, 0) = ?;
+	`
+	var publishedAt sql.NullTime
+	if next.PublishedAt != nil {
+		publishedAt = sql.NullTime{Time: *next.PublishedAt, Valid: true}
+	}
+
+	result, err := tx.ExecContext(ctx, query,
+		next.StatusID,
+		nullInt(next.ApproverID),
+		nullString(next.ApprovalComment),
+		publishedAt,
+		current.ID,
+		current.StatusID,
+		current.ApproverID,
 	)
 	if err != nil {
-		return draft, fmt.Errorf("Failed to delete draft by ID: %w", err)
+		return fmt.Errorf("failed to update draft status: %w", err)
 	}
 
 	rows, err := result.RowsAffected()
 	if err != nil {
-		return draft, fmt.Errorf("Failed to get rows affected: %d, %w", rows, err)
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return custom_errors.Conflict(ctx, "Draft", "was changed by another request")
 	}
 
-	return draft, nil
+	query = `
+	INSERT INTO draft_transitions (draft_id, action, from_status_id, to_status_id, user_id, comment)
+	VALUES (?, ?, ?, ?, ?, ?);
+	`
+	_, err = tx.ExecContext(ctx, query, current.ID, transition.Action, current.StatusID, next.StatusID, transition.UserID, transition.Comment)
+	if err != nil {
+		return fmt.Errorf("failed to record draft transition: %w", err)
+	}
+	return nil
 }
 
-func (r *DraftRepository) GetDraftsByTypeAndObject(ctx context.Context, typeID, objectID int) ([]types.Draft, error) {
-	query := ``
-	rows, err := r.db.QueryContext(ctx, query, typeID, objectID)
+func (r *DraftRepository) queryDrafts(ctx context.Context, query string, args ...any) ([]types.Draft, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query drafts: %w", err)
 	}
 	defer rows.Close()
 
-	var drafts []types.Draft
+	drafts := []types.Draft{}
 	for rows.Next() {
-		var draft types.Draft
-		var updatedAt sql.NullTime
-
-		err := rows.Scan(
-			&draft.ID,
-			&draft.TypeID,
-			&draft.ObjectID,
-			&draft.StatusID,
-			&draft.Version,
-			&draft.Data,
-			&draft.Diff,
-			&draft.UserID,
-			&draft.ApproverID,
-			&draft.ApprovalComment,
-			&draft.PublishError,
-			&draft.CreatedAt,
-			&updatedAt,
-			&draft.ExpiresAt,
-		)
+		draft, err := scanDraft(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan draft row: %w", err)
 		}
-
-		// Handle nullable updated_at field
-		if updatedAt.Valid {
-			draft.UpdatedAt = updatedAt.Time
-		}
-
 		drafts = append(drafts, draft)
 	}
 
@@ -241,49 +286,50 @@ func (r *DraftRepository) GetDraftsByTypeAndObject(ctx context.Context, typeID,
 	return drafts, nil
 }
 
-// UpdateRequirementAndDeleteDraft atomically updates a requirement and deletes the associated draft
-func (r *DraftRepository) UpdateRequirementAndDeleteDraft(ctx context.Context, requirement types.Requirement, draft types.Draft) (types.Requirement, error) {
-	// Start transaction
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return types.Requirement{}, fmt.Errorf("failed to start transaction: %w", err)
-	}
-	defer tx.Rollback() // Safe to call even after commit
-
-	// 1. Update requirement in transaction
-	updateQuery := `
-		UPDATE requirement 
-		SET standard_id = ?, requirement_level_id = ?, parent_id = ?, 
-		    reference_code = ?, name = ?, description = ?
-		WHERE id = ?
-	`
-
-	_, err = tx.ExecContext(
-		ctx,
-		updateQuery,
-		requirement.StandardID,
-		requirement.LevelID,
-		requirement.ParentID,
-		requirement.ReferenceCode,
-		requirement.Name,
-		requirement.Description,
-		requirement.ID,
+func scanDraft(row rowScanner) (types.Draft, error) {
+	var (
+		draft                         types.Draft
+		objectID, approverID          sql.NullInt64
+		data, diff                    sql.NullString
+		approvalComment, publishError sql.NullString
+		publishedAt, expiresAt        sql.NullTime
+	)
+	err := row.Scan(
+		&draft.ID,
+		&draft.TypeID,
+		&objectID,
+		&draft.StatusID,
+		&draft.Version,
+		&data,
+		&diff,
+		&draft.UserID,
+		&approverID,
+		&approvalComment,
+		&publishedAt,
+		&publishError,
+		&draft.CreatedAt,
+		&draft.UpdatedAt,
+		&expiresAt,
 	)
 	if err != nil {
-		return types.Requirement{}, fmt.Errorf("failed to update requirement: %w", err)
+		return types.Draft{}, err
 	}
 
-	// 2. Delete draft in transaction
-	deleteQuery := `DELETE FROM drafts WHERE id = ?`
-	_, err = tx.ExecContext(ctx, deleteQuery, draft)
-	if err != nil {
-		return types.Requirement{}, fmt.Errorf("failed to delete draft: %w", err)
+	draft.ObjectID = int(objectID.Int64)
+	if data.Valid {
+		draft.Data = json.RawMessage(data.String)
 	}
-
-	// 3. Commit transaction
-	if err := tx.Commit(); err != nil {
-		return types.Requirement{}, fmt.Errorf("failed to commit transaction: %w", err)
+	if diff.Valid {
+		draft.Diff = json.RawMessage(diff.String)
 	}
-
-	return requirement, nil
+	draft.ApproverID = int(approverID.Int64)
+	draft.ApprovalComment = approvalComment.String
+	draft.PublishError = publishError.String
+	if publishedAt.Valid {
+		draft.PublishedAt = &publishedAt.Time
+	}
+	if expiresAt.Valid {
+		draft.ExpiresAt = expiresAt.Time
+	}
+	return draft, nil
 }
@@ -0,0 +1,165 @@
+package repositories
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// EvidenceAccessRepository is the concrete implementation
+type EvidenceAccessRepository struct {
+	db *sql.DB
+}
+
+// Ensure EvidenceAccessRepository implements EvidenceAccessRepositoryInterface
+var _ EvidenceAccessRepositoryInterface = (*EvidenceAccessRepository)(nil)
+
+func NewEvidenceAccessRepository(db *sql.DB) (EvidenceAccessRepositoryInterface, error) {
+	return &EvidenceAccessRepository{db: db}, nil
+}
+
+// GetUserRoleID returns the role reference value ID of an active user
+func (r *EvidenceAccessRepository) GetUserRoleID(ctx context.Context, userID int) (int, error) {
+	var roleID int
+	query := "SELECT role_id FROM users WHERE id = ? AND is_active = TRUE AND deleted_at IS NULL;"
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&roleID)
+	if err == sql.ErrNoRows {
+		return 0, custom_errors.NotFound(ctx, "User")
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to query user role: %w", err)
+	}
+	return roleID, nil
+}
+
+// GetByEvidenceProvidedIDEvidenceAccessGrants returns who may see provided evidence, oldest grant first
+func (r *EvidenceAccessRepository) GetByEvidenceProvidedIDEvidenceAccessGrants(ctx context.Context, providedID int) ([]types.EvidenceAccessGrant, error) {
+	query := `
+	SELECT g.evidence_provided_id, g.user_id, u.name, g.granted_by, g.created_at
+	FROM evidence_access_grants AS g
+	INNER JOIN users AS u ON u.id = g.user_id
+	WHERE g.evidence_provided_id = ?
+	ORDER BY g.created_at, g.id;
+	`
+	rows, err := r.db.QueryContext(ctx, query, providedID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query evidence access grants: %w", err)
+	}
+	defer rows.Close()
+
+	grants := []types.EvidenceAccessGrant{}
+	for rows.Next() {
+		var grant types.EvidenceAccessGrant
+		if err := rows.Scan(&grant.EvidenceProvidedID, &grant.UserID, &grant.User, &grant.GrantedBy, &grant.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan evidence access grant row: %w", err)
+		}
+		grants = append(grants, grant)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over evidence access grant rows: %w", err)
+	}
+
+	return grants, nil
+}
+
+// GetGrantedIDsEvidenceAccessGrants returns which of the provided evidence IDs a user was granted
+// access to
+func (r *EvidenceAccessRepository) GetGrantedIDsEvidenceAccessGrants(ctx context.Context, userID int, providedIDs []int) (map[int]bool, error) {
+	granted := make(map[int]bool)
+	if len(providedIDs) == 0 {
+		return granted, nil
+	}
+
+	query := `
+	SELECT evidence_provided_id
+	FROM evidence_access_grants
+	WHERE user_id = ? AND evidence_provided_id IN (` + placeholders(len(providedIDs)) + `);
+	`
+	rows, err := r.db.QueryContext(ctx, query, append([]any{userID}, intArgs(providedIDs)...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query evidence access grants: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan evidence access grant row: %w", err)
+		}
+		granted[id] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over evidence access grant rows: %w", err)
+	}
+
+	return granted, nil
+}
+
+// CreateEvidenceAccessGrant lets an active user see provided evidence. A user who was already
+// granted access is reported as a CONFLICT error.
+func (r *EvidenceAccessRepository) CreateEvidenceAccessGrant(ctx context.Context, grant types.EvidenceAccessGrant) error {
+	var granted int
+	query := "SELECT COUNT(*) FROM evidence_access_grants WHERE evidence_provided_id = ? AND user_id = ?;"
+	if err := r.db.QueryRowContext(ctx, query, grant.EvidenceProvidedID, grant.UserID).Scan(&granted); err != nil {
+		return fmt.Errorf("failed to check evidence access grant: %w", err)
+	}
+	if granted > 0 {
+		return custom_errors.Conflict(ctx, "User", "was already granted access to the provided evidence")
+	}
+
+	query = "INSERT INTO evidence_access_grants (evidence_provided_id, user_id, granted_by) VALUES (?, ?, ?);"
+	_, err := createTracked(ctx, r.db, events.EntityEvidenceGrant, func(tx *sql.Tx) (int, error) {
+		result, err := tx.ExecContext(ctx, query, grant.EvidenceProvidedID, grant.UserID, grant.GrantedBy)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create evidence access grant: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+		return int(id), nil
+	})
+	return err
+}
+
+// DeleteEvidenceAccessGrant revokes the access of a user to provided evidence
+func (r *EvidenceAccessRepository) DeleteEvidenceAccessGrant(ctx context.Context, providedID, userID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() // Safe to call even after commit
+
+	var id int
+	query := "SELECT id FROM evidence_access_grants WHERE evidence_provided_id = ? AND user_id = ? FOR UPDATE;"
+	err = tx.QueryRowContext(ctx, query, providedID, userID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return custom_errors.NotFound(ctx, "Evidence access grant")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock evidence access grant: %w", err)
+	}
+
+	before, err := snapshotRow(ctx, tx, events.EntityEvidenceGrant, id)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM evidence_access_grants WHERE id = ?;", id); err != nil {
+		return fmt.Errorf("failed to delete evidence access grant: %w", err)
+	}
+	if err := recordActivity(ctx, tx, events.ChangeDeleted, events.EntityEvidenceGrant, id, before); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
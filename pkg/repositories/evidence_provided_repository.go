@@ -0,0 +1,375 @@
+package repositories
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// EvidenceProvidedRepository is the concrete implementation
+type EvidenceProvidedRepository struct {
+	db *sql.DB
+}
+
+// Ensure EvidenceProvidedRepository implements EvidenceProvidedRepositoryInterface
+var _ EvidenceProvidedRepositoryInterface = (*EvidenceProvidedRepository)(nil)
+
+func NewEvidenceProvidedRepository(db *sql.DB) (EvidenceProvidedRepositoryInterface, error) {
+	return &EvidenceProvidedRepository{db: db}, nil
+}
+
+const evidenceProvidedColumns = `
+	ep.id, ep.evidence_id, ep.audit_question_id, ep.user_id, ep.evidence, ep.type_id,
+	ep.confidentiality_id, ep.status_id, ep.review_reason, ep.reviewed_by, ep.reviewed_at,
+	ep.superseded_by, ep.retention_days, ep.file_key, ep.file_name, ep.file_size,
+	ep.file_sha256, ep.file_mime_type, ep.created_at, ep.updated_at`
+
+// GetByIDEvidenceProvided returns provided evidence that is not deleted. Only the IDs of its
+// reference values are set.
+func (r *EvidenceProvidedRepository) GetByIDEvidenceProvided(ctx context.Context, provided types.EvidenceProvided) (types.EvidenceProvided, error) {
+	query := `
+	SELECT` + evidenceProvidedColumns + `
+	FROM evidence_provided AS ep
+	WHERE ep.id = ? AND ep.deleted_at IS NULL;
+	`
+	result, err := scanEvidenceProvided(r.db.QueryRowContext(ctx, query, provided.ID))
+	if err == sql.ErrNoRows {
+		return types.EvidenceProvided{}, custom_errors.NotFound(ctx, "Provided evidence")
+	}
+	if err != nil {
+		return types.EvidenceProvided{}, fmt.Errorf("failed to scan provided evidence: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *EvidenceProvidedRepository) CreateEvidenceProvided(ctx context.Context, provided types.EvidenceProvided) (types.EvidenceProvided, error) {
+	query := `
+	INSERT INTO evidence_provided (evidence_id, audit_question_id, user_id, evidence, type_id, confidentiality_id, status_id, retention_days)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+	`
+	id, err := createTracked(ctx, r.db, events.EntityEvidenceProvided, func(tx *sql.Tx) (int, error) {
+		result, err := tx.ExecContext(ctx, query,
+			provided.EvidenceID, provided.AuditQuestionID, provided.UserID, provided.Provided,
+			provided.TypeVal.ID, provided.ConfidentialityVal.ID, provided.StatusVal.ID, provided.RetentionDays,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create provided evidence: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+		return int(id), nil
+	})
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	provided.ID = id
+	return r.GetByIDEvidenceProvided(ctx, provided)
+}
+
+// UpdateEvidenceProvided changes what was provided, never the audit question or the status
+func (r *EvidenceProvidedRepository) UpdateEvidenceProvided(ctx context.Context, provided types.EvidenceProvided) (types.EvidenceProvided, error) {
+	query := `
+	UPDATE evidence_provided
+	SET evidence_id = ?, evidence = ?, type_id = ?, confidentiality_id = ?, retention_days = ?
+	WHERE id = ? AND deleted_at IS NULL;
+	`
+	err := changeTracked(ctx, r.db, events.ChangeUpdated, events.EntityEvidenceProvided, provided.ID, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query,
+			provided.EvidenceID, provided.Provided, provided.TypeVal.ID,
+			provided.ConfidentialityVal.ID, provided.RetentionDays, provided.ID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update provided evidence: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	return r.GetByIDEvidenceProvided(ctx, provided)
+}
+
+// ReviewEvidenceProvided records the decision of a reviewer. The update only applies while the
+// evidence is still in fromStatusID, a concurrent review is reported as a CONFLICT error.
+func (r *EvidenceProvidedRepository) ReviewEvidenceProvided(ctx context.Context, id, fromStatusID, toStatusID int, review types.EvidenceReview) error {
+	query := `
+	UPDATE evidence_provided
+	SET status_id = ?, review_reason = ?, reviewed_by = ?, reviewed_at = CURRENT_TIMESTAMP
+	WHERE id = ? AND status_id = ? AND deleted_at IS NULL;
+	`
+	return changeTracked(ctx, r.db, events.ChangeUpdated, events.EntityEvidenceProvided, id, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, toStatusID, review.Reason, review.ReviewedBy, id, fromStatusID)
+		if err != nil {
+			return fmt.Errorf("failed to review provided evidence: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return custom_errors.Conflict(ctx, "Provided evidence", "was reviewed or changed by another request")
+		}
+		return nil
+	})
+}
+
+// SupersedeEvidenceProvided stores replacement and marks the provided evidence it replaces as
+// supersededStatusID with a link to it, in one transaction. The replaced evidence must still be in
+// the status it was loaded with and not replaced yet, otherwise a CONFLICT error is returned.
+func (r *EvidenceProvidedRepository) SupersedeEvidenceProvided(ctx context.Context, replaced, replacement types.EvidenceProvided, supersededStatusID int) (types.EvidenceProvided, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return types.EvidenceProvided{}, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() // Safe to call even after commit
+
+	before, err := snapshotRow(ctx, tx, events.EntityEvidenceProvided, replaced.ID)
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	query := `
+	INSERT INTO evidence_provided (evidence_id, audit_question_id, user_id, evidence, type_id, confidentiality_id, status_id, retention_days)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+	`
+	result, err := tx.ExecContext(ctx, query,
+		replacement.EvidenceID, replacement.AuditQuestionID, replacement.UserID, replacement.Provided,
+		replacement.TypeVal.ID, replacement.ConfidentialityVal.ID, replacement.StatusVal.ID, replacement.RetentionDays,
+	)
+	if err != nil {
+		return types.EvidenceProvided{}, fmt.Errorf("failed to create replacement provided evidence: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return types.EvidenceProvided{}, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	query = `
+	UPDATE evidence_provided
+	SET status_id = ?, superseded_by = ?
+	WHERE id = ? AND status_id = ? AND superseded_by IS NULL AND deleted_at IS NULL;
+	`
+	result, err = tx.ExecContext(ctx, query, supersededStatusID, id, replaced.ID, replaced.StatusVal.ID)
+	if err != nil {
+		return types.EvidenceProvided{}, fmt.Errorf("failed to supersede provided evidence: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return types.EvidenceProvided{}, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return types.EvidenceProvided{}, custom_errors.Conflict(ctx, "Provided evidence", "was replaced or changed by another request")
+	}
+
+	if err := recordActivity(ctx, tx, events.ChangeCreated, events.EntityEvidenceProvided, int(id), nil); err != nil {
+		return types.EvidenceProvided{}, err
+	}
+	if err := recordActivity(ctx, tx, events.ChangeUpdated, events.EntityEvidenceProvided, replaced.ID, before); err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return types.EvidenceProvided{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	replacement.ID = int(id)
+	return r.GetByIDEvidenceProvided(ctx, replacement)
+}
+
+// UpdateEvidenceProvidedFile records the file uploaded for provided evidence, replacing any file
+// recorded before
+func (r *EvidenceProvidedRepository) UpdateEvidenceProvidedFile(ctx context.Context, id int, file types.EvidenceFile) error {
+	query := `
+	UPDATE evidence_provided
+	SET file_key = ?, file_name = ?, file_size = ?, file_sha256 = ?, file_mime_type = ?
+	WHERE id = ? AND deleted_at IS NULL;
+	`
+	return changeTracked(ctx, r.db, events.ChangeUpdated, events.EntityEvidenceProvided, id, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, file.Key, file.Name, file.Size, file.SHA256, file.MIMEType, id)
+		if err != nil {
+			return fmt.Errorf("failed to update provided evidence file: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return custom_errors.NotFound(ctx, "Provided evidence")
+		}
+		return nil
+	})
+}
+
+// GetExpiredEvidenceProvided returns the provided evidence that is not deleted and whose retention
+// period ended at or before asOf, oldest first
+func (r *EvidenceProvidedRepository) GetExpiredEvidenceProvided(ctx context.Context, asOf time.Time) ([]types.EvidenceProvided, error) {
+	query := `
+	SELECT` + evidenceProvidedColumns + `
+	FROM evidence_provided AS ep
+	WHERE ep.deleted_at IS NULL AND DATE_ADD(ep.created_at, INTERVAL ep.retention_days DAY) <= ?
+	ORDER BY ep.created_at, ep.id;
+	`
+	rows, err := r.db.QueryContext(ctx, query, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expired provided evidence: %w", err)
+	}
+	defer rows.Close()
+
+	expired := []types.EvidenceProvided{}
+	for rows.Next() {
+		provided, err := scanEvidenceProvided(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan provided evidence row: %w", err)
+		}
+		expired = append(expired, provided)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over provided evidence rows: %w", err)
+	}
+
+	return expired, nil
+}
+
+// UpdateEvidenceProvidedStatus moves provided evidence from one status to another. The update only
+// applies while it is still in fromStatusID, a concurrent change is reported as a CONFLICT error.
+func (r *EvidenceProvidedRepository) UpdateEvidenceProvidedStatus(ctx context.Context, id, fromStatusID, toStatusID int) error {
+	query := `
+	UPDATE evidence_provided
+	SET status_id = ?
+	WHERE id = ? AND status_id = ? AND deleted_at IS NULL;
+	`
+	return changeTracked(ctx, r.db, events.ChangeUpdated, events.EntityEvidenceProvided, id, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, toStatusID, id, fromStatusID)
+		if err != nil {
+			return fmt.Errorf("failed to update provided evidence status: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return custom_errors.Conflict(ctx, "Provided evidence", "status was changed by another request")
+		}
+		return nil
+	})
+}
+
+// PurgeEvidenceProvided soft deletes provided evidence whose file was removed from the blob store
+// and clears the blob key. Name, size and hash of the file are left in the row and in its
+// activity_log entry for the records. The reads of this repository skip deleted rows, so none of
+// them return purged evidence.
+func (r *EvidenceProvidedRepository) PurgeEvidenceProvided(ctx context.Context, id int) error {
+	query := `
+	UPDATE evidence_provided
+	SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP, file_key = NULL
+	WHERE id = ? AND deleted_at IS NULL;
+	`
+	return changeTracked(ctx, r.db, events.ChangeDeleted, events.EntityEvidenceProvided, id, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return fmt.Errorf("failed to purge provided evidence: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return custom_errors.NotFound(ctx, "Provided evidence")
+		}
+		return nil
+	})
+}
+
+// DeleteEvidenceProvided soft deletes provided evidence so the audit trail keeps it
+func (r *EvidenceProvidedRepository) DeleteEvidenceProvided(ctx context.Context, id int) error {
+	query := `
+	UPDATE evidence_provided
+	SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP
+	WHERE id = ? AND deleted_at IS NULL;
+	`
+	return changeTracked(ctx, r.db, events.ChangeDeleted, events.EntityEvidenceProvided, id, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete provided evidence: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return custom_errors.NotFound(ctx, "Provided evidence")
+		}
+		return nil
+	})
+}
+
+func scanEvidenceProvided(row rowScanner) (types.EvidenceProvided, error) {
+	var (
+		provided                                              types.EvidenceProvided
+		auditQuestionID, reviewedBy, supersededBy, fileSize   sql.NullInt64
+		reviewReason, fileKey, fileName, fileSHA256, fileMIME sql.NullString
+		reviewedAt                                            sql.NullTime
+	)
+
+	err := row.Scan(
+		&provided.ID,
+		&provided.EvidenceID,
+		&auditQuestionID,
+		&provided.UserID,
+		&provided.Provided,
+		&provided.TypeVal.ID,
+		&provided.ConfidentialityVal.ID,
+		&provided.StatusVal.ID,
+		&reviewReason,
+		&reviewedBy,
+		&reviewedAt,
+		&supersededBy,
+		&provided.RetentionDays,
+		&fileKey,
+		&fileName,
+		&fileSize,
+		&fileSHA256,
+		&fileMIME,
+		&provided.CreatedAt,
+		&provided.UpdatedAt,
+	)
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	// Rows recorded before evidence was tied to audits have no audit question
+	provided.AuditQuestionID = int(auditQuestionID.Int64)
+	provided.ExpiresAt = provided.CreatedAt.AddDate(0, 0, provided.RetentionDays)
+	provided.SupersededBy = int(supersededBy.Int64)
+	if reviewedAt.Valid {
+		provided.Review = &types.EvidenceReview{Reason: reviewReason.String, ReviewedBy: int(reviewedBy.Int64), ReviewedAt: reviewedAt.Time}
+	}
+	if fileKey.Valid {
+		provided.File = &types.EvidenceFile{
+			Key:      fileKey.String,
+			Name:     fileName.String,
+			Size:     fileSize.Int64,
+			SHA256:   fileSHA256.String,
+			MIMEType: fileMIME.String,
+		}
+	}
+	return provided, nil
+}
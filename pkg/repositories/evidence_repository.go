@@ -1,9 +1,12 @@
 package repositories
 
 import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
 	"ISO_Auditing_Tool/pkg/types"
 	"context"
 	"database/sql"
+	"fmt"
 )
 
 // EvidenceRepository is the concrete implementation
@@ -14,10 +17,171 @@ type EvidenceRepository struct {
 // Ensure EvidenceRepository implements EvidenceRepositoryInterface
 var _ EvidenceRepositoryInterface = (*EvidenceRepository)(nil)
 
+// evidenceTypeReference is the reference_types.name holding the valid expected evidence types
+const evidenceTypeReference = "evidence.type_id"
+
 func NewEvidenceRepository(db *sql.DB) (EvidenceRepositoryInterface, error) {
 	return &EvidenceRepository{db: db}, nil
 }
 
 func (r *EvidenceRepository) GetByIDEvidence(ctx context.Context, evidence types.Evidence) (types.Evidence, error) {
-	return types.Evidence{}, nil
+	query := `
+	SELECT e.id, e.question_id, e.expected, e.created_at, e.updated_at, e.sort_order,
+				rv.id, rv.type_id, rv.code, rv.name, rv.description, rv.is_active, rv.created_at, rv.updated_at
+	FROM evidence AS e
+	INNER JOIN reference_values AS rv ON rv.id = e.type_id
+	WHERE e.id = ?;
+	`
+	row := r.db.QueryRowContext(ctx, query, evidence.ID)
+
+	result, err := scanEvidence(row)
+	if err == sql.ErrNoRows {
+		return types.Evidence{}, custom_errors.NotFound(ctx, "Evidence")
+	}
+	if err != nil {
+		return types.Evidence{}, fmt.Errorf("failed to scan evidence: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetEvidenceType returns the active evidence type with the given reference value ID. IDs that
+// belong to another reference type, or to no type at all, are reported as INVALID_DATA.
+func (r *EvidenceRepository) GetEvidenceType(ctx context.Context, typeID int) (types.ReferenceValue, error) {
+	query := `
+	SELECT rv.id, rv.type_id, rv.code, rv.name, rv.description, rv.is_active, rv.created_at, rv.updated_at
+	FROM reference_values AS rv
+	INNER JOIN reference_types AS rt ON rt.id = rv.type_id
+	WHERE rv.id = ? AND rt.name = ? AND rv.is_active = TRUE AND rv.deleted_at IS NULL;
+	`
+	value, err := scanReferenceValue(r.db.QueryRowContext(ctx, query, typeID, evidenceTypeReference))
+	if err == sql.ErrNoRows {
+		return types.ReferenceValue{}, custom_errors.InvalidData(ctx, "type_id is not a valid evidence type")
+	}
+	if err != nil {
+		return types.ReferenceValue{}, fmt.Errorf("failed to get evidence type: %w", err)
+	}
+
+	return value, nil
+}
+
+// CreateEvidence inserts expected evidence as the last one of its question
+func (r *EvidenceRepository) CreateEvidence(ctx context.Context, evidence types.Evidence) (types.Evidence, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return types.Evidence{}, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() // Safe to call even after commit
+
+	evidence.SortOrder, err = nextSortOrder(ctx, tx, "evidence", "question_id", evidence.QuestionID)
+	if err != nil {
+		return types.Evidence{}, err
+	}
+
+	query := `
+	INSERT INTO evidence (question_id, type_id, expected, sort_order)
+	VALUES (?, ?, ?, ?);
+	`
+	result, err := tx.ExecContext(ctx, query, evidence.QuestionID, evidence.TypeVal.ID, evidence.Expected, evidence.SortOrder)
+	if err != nil {
+		return types.Evidence{}, fmt.Errorf("failed to create evidence: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return types.Evidence{}, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+	if err := recordActivity(ctx, tx, events.ChangeCreated, events.EntityEvidence, int(id), nil); err != nil {
+		return types.Evidence{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return types.Evidence{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	evidence.ID = int(id)
+	return r.GetByIDEvidence(ctx, evidence)
+}
+
+func (r *EvidenceRepository) UpdateEvidence(ctx context.Context, evidence types.Evidence) (types.Evidence, error) {
+	query := `
+	UPDATE evidence
+	SET type_id = ?, expected = ?
+	WHERE id = ?;
+	`
+	err := changeTracked(ctx, r.db, events.ChangeUpdated, events.EntityEvidence, evidence.ID, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query, evidence.TypeVal.ID, evidence.Expected, evidence.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update evidence: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return types.Evidence{}, err
+	}
+
+	return r.GetByIDEvidence(ctx, evidence)
+}
+
+// DeleteEvidence removes expected evidence. Evidence that auditees already provided material for
+// is rejected with a CONFLICT error.
+func (r *EvidenceRepository) DeleteEvidence(ctx context.Context, id int) error {
+	var provided int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM evidence_provided WHERE evidence_id = ?;", id).Scan(&provided); err != nil {
+		return fmt.Errorf("failed to count provided evidence: %w", err)
+	}
+	if provided > 0 {
+		return custom_errors.Conflict(ctx, "Evidence", "has provided evidence")
+	}
+
+	return changeTracked(ctx, r.db, events.ChangeDeleted, events.EntityEvidence, id, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, "DELETE FROM evidence WHERE id = ?;", id)
+		if err != nil {
+			return fmt.Errorf("failed to delete evidence: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return custom_errors.NotFound(ctx, "Evidence")
+		}
+		return nil
+	})
+}
+
+// ReorderEvidence sets the order of the expected evidence of a question. The given IDs must be
+// exactly the current evidence, otherwise an INVALID_DATA error is returned and nothing changes.
+func (r *EvidenceRepository) ReorderEvidence(ctx context.Context, questionID int, evidenceIDs []int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() // Safe to call even after commit
+
+	current, err := lockChildIDs(ctx, tx, "evidence", "question_id", questionID)
+	if err != nil {
+		return err
+	}
+
+	if !sameIDs(current, evidenceIDs) {
+		return custom_errors.InvalidData(ctx, "evidence_ids must list every evidence of the question exactly once")
+	}
+
+	if err := updateSortOrder(ctx, tx, "evidence", evidenceIDs); err != nil {
+		return err
+	}
+
+	before := idsSnapshot(map[string][]int{"evidence_ids": current})
+	after := idsSnapshot(map[string][]int{"evidence_ids": evidenceIDs})
+	if err := appendActivity(ctx, tx, events.ChangeUpdated, events.EntityQuestion, questionID, before, after); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
 }
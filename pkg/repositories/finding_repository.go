@@ -0,0 +1,294 @@
+package repositories
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// FindingRepository is the concrete implementation
+type FindingRepository struct {
+	db *sql.DB
+}
+
+// Ensure FindingRepository implements FindingRepositoryInterface
+var _ FindingRepositoryInterface = (*FindingRepository)(nil)
+
+func NewFindingRepository(db *sql.DB) (FindingRepositoryInterface, error) {
+	return &FindingRepository{db: db}, nil
+}
+
+const findingColumns = `
+	f.id, f.audit_id, aqf.audit_question_id, f.question_id, r.reference_code, f.finding_type_id,
+	f.severity_id, f.description, f.due_date, f.responsible_user_id, u.name, f.status_id,
+	f.status_reason, f.needs_owner, f.created_by, f.created_at, f.updated_at`
+
+const findingJoins = `
+	FROM findings AS f
+	INNER JOIN audit_question_findings AS aqf ON aqf.finding_id = f.id
+	INNER JOIN questions AS q ON q.id = f.question_id
+	INNER JOIN requirement AS r ON r.id = q.requirement_id
+	INNER JOIN users AS u ON u.id = f.responsible_user_id`
+
+// GetAllFindings returns the findings that are not deleted, ordered by due date. The filter
+// expects severity and status as reference value IDs, zero values are ignored.
+func (r *FindingRepository) GetAllFindings(ctx context.Context, filter types.FindingFilter) ([]types.Finding, error) {
+	conditions := []string{"f.deleted_at IS NULL"}
+	args := []any{}
+
+	if filter.AuditID != 0 {
+		conditions = append(conditions, "f.audit_id = ?")
+		args = append(args, filter.AuditID)
+	}
+	if filter.AuditorID != 0 {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM audit_plans AS ap WHERE ap.id = f.audit_id AND (ap.lead_auditor_id = ? OR EXISTS (SELECT 1 FROM audit_support_auditors AS asa WHERE asa.audit_id = ap.id AND asa.user_id = ?)))")
+		args = append(args, filter.AuditorID, filter.AuditorID)
+	}
+	if filter.ResponsibleUserID != 0 {
+		conditions = append(conditions, "f.responsible_user_id = ?")
+		args = append(args, filter.ResponsibleUserID)
+	}
+	if filter.FollowUpUserID != 0 {
+		conditions = append(conditions, "(f.responsible_user_id = ? OR EXISTS (SELECT 1 FROM finding_corrective_actions AS fca WHERE fca.finding_id = f.id AND fca.owner_id = ?))")
+		args = append(args, filter.FollowUpUserID, filter.FollowUpUserID)
+	}
+	if filter.SeverityID != 0 {
+		conditions = append(conditions, "f.severity_id = ?")
+		args = append(args, filter.SeverityID)
+	}
+	if filter.StatusID != 0 {
+		conditions = append(conditions, "f.status_id = ?")
+		args = append(args, filter.StatusID)
+	}
+	if len(filter.StatusIDs) > 0 {
+		conditions = append(conditions, "f.status_id IN ("+placeholders(len(filter.StatusIDs))+")")
+		args = append(args, intArgs(filter.StatusIDs)...)
+	}
+	if !filter.DueFrom.IsZero() {
+		conditions = append(conditions, "f.due_date >= ?")
+		args = append(args, filter.DueFrom)
+	}
+	if !filter.DueTo.IsZero() {
+		conditions = append(conditions, "f.due_date < ?")
+		args = append(args, filter.DueTo)
+	}
+	if filter.NeedsOwner {
+		conditions = append(conditions, "f.needs_owner = TRUE")
+	}
+
+	query := `
+	SELECT` + findingColumns + findingJoins + `
+	WHERE ` + strings.Join(conditions, " AND ") + `
+	ORDER BY f.due_date, f.id;
+	`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query findings: %w", err)
+	}
+	defer rows.Close()
+
+	findings := []types.Finding{}
+	for rows.Next() {
+		finding, err := scanFinding(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan finding row: %w", err)
+		}
+		findings = append(findings, finding)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over finding rows: %w", err)
+	}
+
+	return findings, nil
+}
+
+// GetByIDFinding returns a finding that is not deleted. Only the IDs of its reference values are set.
+func (r *FindingRepository) GetByIDFinding(ctx context.Context, finding types.Finding) (types.Finding, error) {
+	query := `
+	SELECT` + findingColumns + findingJoins + `
+	WHERE f.id = ? AND f.deleted_at IS NULL;
+	`
+	result, err := scanFinding(r.db.QueryRowContext(ctx, query, finding.ID))
+	if err == sql.ErrNoRows {
+		return types.Finding{}, custom_errors.NotFound(ctx, "Finding")
+	}
+	if err != nil {
+		return types.Finding{}, fmt.Errorf("failed to scan finding: %w", err)
+	}
+
+	return result, nil
+}
+
+// CreateFinding stores a finding and links it to its audit question in one transaction
+func (r *FindingRepository) CreateFinding(ctx context.Context, finding types.Finding) (types.Finding, error) {
+	if err := r.checkResponsibleUser(ctx, finding.ResponsibleUserID); err != nil {
+		return types.Finding{}, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return types.Finding{}, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() // Safe to call even after commit
+
+	query := `
+	INSERT INTO findings (audit_id, question_id, finding_type_id, severity_id, description, due_date, responsible_user_id, status_id, created_by)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`
+	result, err := tx.ExecContext(ctx, query,
+		finding.AuditID, finding.QuestionID, finding.TypeVal.ID, finding.SeverityVal.ID, finding.Description,
+		finding.DueDate, finding.ResponsibleUserID, finding.StatusVal.ID, finding.CreatedBy,
+	)
+	if err != nil {
+		return types.Finding{}, fmt.Errorf("failed to create finding: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return types.Finding{}, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	query = "INSERT INTO audit_question_findings (audit_question_id, finding_id) VALUES (?, ?);"
+	if _, err := tx.ExecContext(ctx, query, finding.AuditQuestionID, id); err != nil {
+		return types.Finding{}, fmt.Errorf("failed to link finding to audit question: %w", err)
+	}
+	if err := recordActivity(ctx, tx, events.ChangeCreated, events.EntityFinding, int(id), nil); err != nil {
+		return types.Finding{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return types.Finding{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	finding.ID = int(id)
+	return r.GetByIDFinding(ctx, finding)
+}
+
+// UpdateFinding changes the classification, description, due date and owner of a finding. The
+// audit question and status stay as they are. The owner is active, so the finding no longer
+// needs a new one.
+func (r *FindingRepository) UpdateFinding(ctx context.Context, finding types.Finding) (types.Finding, error) {
+	if err := r.checkResponsibleUser(ctx, finding.ResponsibleUserID); err != nil {
+		return types.Finding{}, err
+	}
+
+	query := `
+	UPDATE findings
+	SET finding_type_id = ?, severity_id = ?, description = ?, due_date = ?, responsible_user_id = ?, needs_owner = FALSE
+	WHERE id = ? AND deleted_at IS NULL;
+	`
+	err := changeTracked(ctx, r.db, events.ChangeUpdated, events.EntityFinding, finding.ID, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query,
+			finding.TypeVal.ID, finding.SeverityVal.ID, finding.Description,
+			finding.DueDate, finding.ResponsibleUserID, finding.ID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update finding: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return types.Finding{}, err
+	}
+
+	return r.GetByIDFinding(ctx, finding)
+}
+
+// UpdateFindingStatus moves a finding from one status to another and stores the reason given for
+// it. The update only applies while the finding is still in fromStatusID, so a concurrent change is
+// reported as a CONFLICT error.
+func (r *FindingRepository) UpdateFindingStatus(ctx context.Context, id, fromStatusID, toStatusID int, reason string) error {
+	query := `
+	UPDATE findings
+	SET status_id = ?, status_reason = ?
+	WHERE id = ? AND status_id = ? AND deleted_at IS NULL;
+	`
+	return changeTracked(ctx, r.db, events.ChangeUpdated, events.EntityFinding, id, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, toStatusID, nullString(reason), id, fromStatusID)
+		if err != nil {
+			return fmt.Errorf("failed to update finding status: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return custom_errors.Conflict(ctx, "Finding", "status was changed by another request")
+		}
+		return nil
+	})
+}
+
+// DeleteFinding soft deletes a finding, the link to its audit question stays for the records
+func (r *FindingRepository) DeleteFinding(ctx context.Context, id int) error {
+	query := `
+	UPDATE findings
+	SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP
+	WHERE id = ? AND deleted_at IS NULL;
+	`
+	return changeTracked(ctx, r.db, events.ChangeDeleted, events.EntityFinding, id, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete finding: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return custom_errors.NotFound(ctx, "Finding")
+		}
+		return nil
+	})
+}
+
+// checkResponsibleUser reports an owner that is not an active user as INVALID_DATA
+func (r *FindingRepository) checkResponsibleUser(ctx context.Context, userID int) error {
+	var count int
+	query := "SELECT COUNT(*) FROM users WHERE id = ? AND is_active = TRUE AND deleted_at IS NULL;"
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return fmt.Errorf("failed to check responsible user: %w", err)
+	}
+	if count == 0 {
+		return custom_errors.InvalidData(ctx, "responsible_user_id is not an active user")
+	}
+	return nil
+}
+
+func scanFinding(row rowScanner) (types.Finding, error) {
+	var finding types.Finding
+	var statusReason sql.NullString
+
+	err := row.Scan(
+		&finding.ID,
+		&finding.AuditID,
+		&finding.AuditQuestionID,
+		&finding.QuestionID,
+		&finding.ReferenceCode,
+		&finding.TypeVal.ID,
+		&finding.SeverityVal.ID,
+		&finding.Description,
+		&finding.DueDate,
+		&finding.ResponsibleUserID,
+		&finding.ResponsibleUser,
+		&finding.StatusVal.ID,
+		&statusReason,
+		&finding.NeedsOwner,
+		&finding.CreatedBy,
+		&finding.CreatedAt,
+		&finding.UpdatedAt,
+	)
+	if err != nil {
+		return types.Finding{}, err
+	}
+
+	finding.StatusReason = statusReason.String
+	return finding, nil
+}
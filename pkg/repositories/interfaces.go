@@ -5,6 +5,7 @@ package repositories
 import (
 	"ISO_Auditing_Tool/pkg/types"
 	"context"
+	"time"
 )
 
 // Repository interface defines the methods for interacting with the database
@@ -12,10 +13,11 @@ type DraftRepositoryInterface interface {
 	GetAllDrafts(ctx context.Context) ([]types.Draft, error)
 	CreateDraft(ctx context.Context, draft types.Draft) (types.Draft, error)
 	GetDraftByID(ctx context.Context, draft types.Draft) (types.Draft, error)
-	UpdateDraft(ctx context.Context, draft types.Draft) (types.Draft, error)
+	UpdateDraft(ctx context.Context, draft types.Draft, fromStatusID int) (types.Draft, error)
 	DeleteDraft(ctx context.Context, draft types.Draft) (types.Draft, error)
 	GetDraftsByTypeAndObject(ctx context.Context, typeID, objectID int) ([]types.Draft, error)
-	UpdateRequirementAndDeleteDraft(ctx context.Context, requirement types.Requirement, draft types.Draft) (types.Requirement, error)
+	GetDraftTransitions(ctx context.Context, draftID int) ([]types.DraftTransition, error)
+	TransitionDraft(ctx context.Context, current, next types.Draft, transition types.DraftTransition) error
 	// Add methods for REST, filtering, searching, etc..
 }
 
@@ -35,10 +37,172 @@ type MaterializedHTMLQueryRepositoryInterface interface {
 	// Add methods for filtering, searching, etc...
 }
 
+type ReferenceDataRepositoryInterface interface {
+	GetAllReferenceTypes(ctx context.Context) ([]types.ReferenceType, error)
+	GetAllReferenceValues(ctx context.Context) ([]types.ReferenceValue, error)
+	GetByIDReferenceValue(ctx context.Context, value types.ReferenceValue) (types.ReferenceValue, error)
+	CreateReferenceValue(ctx context.Context, value types.ReferenceValue) (types.ReferenceValue, error)
+	UpdateReferenceValue(ctx context.Context, value types.ReferenceValue) (types.ReferenceValue, error)
+	DeleteReferenceValue(ctx context.Context, id int) error
+
+	// Add methods for filtering, searching, etc...
+}
+
 type StandardRepositoryInterface interface {
 	GetAllStandards(ctx context.Context) ([]types.Standard, error)
 	GetByIDStandard(ctx context.Context, standard types.Standard) (types.Standard, error)
 	GetByIDWithFullHierarchyStandard(ctx context.Context, standard types.Standard) (types.Standard, error)
+	CreateStandard(ctx context.Context, standard types.Standard) (types.Standard, error)
+	UpdateStandard(ctx context.Context, standard types.Standard) (types.Standard, error)
+	DeleteStandard(ctx context.Context, id int) error
+
+	// Add methods for filtering, searching, etc...
+}
+
+type AuditPlanRepositoryInterface interface {
+	GetAllAuditPlans(ctx context.Context, filter types.AuditPlanFilter) ([]types.AuditPlan, error)
+	GetByIDAuditPlan(ctx context.Context, plan types.AuditPlan) (types.AuditPlan, error)
+	CreateAuditPlan(ctx context.Context, plan types.AuditPlan) (types.AuditPlan, error)
+	UpdateAuditPlan(ctx context.Context, plan types.AuditPlan) (types.AuditPlan, error)
+	UpdateAuditPlanStatus(ctx context.Context, id, fromStatusID, toStatusID int) error
+	DeleteAuditPlan(ctx context.Context, id int) error
+
+	// Add methods for filtering, searching, etc...
+}
+
+type AuditAssignmentRepositoryInterface interface {
+	GetByAuditPlanIDAuditAssignments(ctx context.Context, auditPlanID int) ([]types.AuditAssignment, error)
+	GetByUserIDAuditAssignments(ctx context.Context, userID int) ([]types.AuditAssignment, error)
+	CreateAuditAssignment(ctx context.Context, auditPlanID, userID int) error
+	DeleteAuditAssignment(ctx context.Context, auditPlanID, userID int) error
+	IsAuditTeamMember(ctx context.Context, auditPlanID, userID int) (bool, error)
+	IsAuditFollowUpOwner(ctx context.Context, auditPlanID, userID int) (bool, error)
+
+	// Add methods for filtering, searching, etc...
+}
+
+type AuditChecklistRepositoryInterface interface {
+	GetAuditChecklist(ctx context.Context, auditPlanID int) (types.AuditChecklist, error)
+	SyncAuditChecklist(ctx context.Context, auditPlanID int, scopeIDs, requirementIDs []int) (types.AuditChecklistDiff, error)
+
+	// Add methods for filtering, searching, etc...
+}
+
+type AuditQuestionRepositoryInterface interface {
+	GetByAuditIDAuditQuestions(ctx context.Context, auditID int) ([]types.AuditQuestion, error)
+	GetByIDAuditQuestion(ctx context.Context, question types.AuditQuestion) (types.AuditQuestion, error)
+	GetAuditProgress(ctx context.Context, auditID, acceptedStatusID int) (types.AuditProgress, error)
+
+	// Add methods for filtering, searching, etc...
+}
+
+type CommentRepositoryInterface interface {
+	GetByAuditQuestionIDComments(ctx context.Context, auditQuestionID int) ([]types.Comment, error)
+	GetByIDComment(ctx context.Context, comment types.Comment) (types.Comment, error)
+	CreateAuditQuestionComment(ctx context.Context, auditQuestionID int, comment types.Comment, mentionIDs []int) (types.Comment, error)
+	UpdateComment(ctx context.Context, comment types.Comment, editedBy int, mentionIDs []int) (types.Comment, error)
+	DeleteComment(ctx context.Context, id int) error
+	GetRevisionsComment(ctx context.Context, commentID int) ([]types.CommentRevision, error)
+	GetMentionedUserIDs(ctx context.Context, handles []string) (map[string][]int, error)
+
+	// Add methods for filtering, searching, etc...
+}
+
+type UserRepositoryInterface interface {
+	GetAllUsers(ctx context.Context, filter types.UserFilter) ([]types.User, error)
+	GetByIDUser(ctx context.Context, user types.User) (types.User, error)
+	GetCredentialsUser(ctx context.Context, email string) (types.User, string, error)
+	CreateUser(ctx context.Context, user types.User) (types.User, error)
+	UpdateUserPassword(ctx context.Context, id int, passwordHash string) error
+	UpdateUserLastLogin(ctx context.Context, id int) error
+	UpdateUserRole(ctx context.Context, id, roleID int) error
+	ReactivateUser(ctx context.Context, id int) error
+	DeactivateUser(ctx context.Context, id, reassignTo int, openStatusIDs []int) (types.UserDeactivation, error)
+
+	// Add methods for filtering, searching, etc...
+}
+
+type APITokenRepositoryInterface interface {
+	GetByUserIDAPITokens(ctx context.Context, userID int) ([]types.APIToken, error)
+	GetByIDAPIToken(ctx context.Context, id int) (types.APIToken, error)
+	GetUserByAPIToken(ctx context.Context, tokenHash string, asOf time.Time) (types.User, types.APIToken, error)
+	CreateAPIToken(ctx context.Context, token types.APIToken, tokenHash string) (types.APIToken, error)
+	RevokeAPIToken(ctx context.Context, id int) error
+	UpdateAPITokenLastUsed(ctx context.Context, id int, usedAt time.Time) error
+
+	// Add methods for filtering, searching, etc...
+}
+
+type ActivityLogRepositoryInterface interface {
+	GetActivityEntries(ctx context.Context, filter types.ActivityFilter) ([]types.ActivityEntry, error)
+
+	// Add methods for filtering, searching, etc...
+}
+
+type SessionRepositoryInterface interface {
+	GetUserBySessionToken(ctx context.Context, tokenHash string, asOf time.Time) (types.User, error)
+	CreateSession(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error
+	DeleteSession(ctx context.Context, tokenHash string) error
+	DeleteByUserIDSessions(ctx context.Context, userID int) error
+	DeleteExpiredSessions(ctx context.Context, asOf time.Time) error
+
+	// Add methods for filtering, searching, etc...
+}
+
+type EvidenceProvidedRepositoryInterface interface {
+	GetByIDEvidenceProvided(ctx context.Context, provided types.EvidenceProvided) (types.EvidenceProvided, error)
+	CreateEvidenceProvided(ctx context.Context, provided types.EvidenceProvided) (types.EvidenceProvided, error)
+	UpdateEvidenceProvided(ctx context.Context, provided types.EvidenceProvided) (types.EvidenceProvided, error)
+	UpdateEvidenceProvidedFile(ctx context.Context, id int, file types.EvidenceFile) error
+	ReviewEvidenceProvided(ctx context.Context, id, fromStatusID, toStatusID int, review types.EvidenceReview) error
+	SupersedeEvidenceProvided(ctx context.Context, replaced, replacement types.EvidenceProvided, supersededStatusID int) (types.EvidenceProvided, error)
+	GetExpiredEvidenceProvided(ctx context.Context, asOf time.Time) ([]types.EvidenceProvided, error)
+	UpdateEvidenceProvidedStatus(ctx context.Context, id, fromStatusID, toStatusID int) error
+	PurgeEvidenceProvided(ctx context.Context, id int) error
+	DeleteEvidenceProvided(ctx context.Context, id int) error
+
+	// Add methods for filtering, searching, etc...
+}
+
+type EvidenceAccessRepositoryInterface interface {
+	GetUserRoleID(ctx context.Context, userID int) (int, error)
+	GetByEvidenceProvidedIDEvidenceAccessGrants(ctx context.Context, providedID int) ([]types.EvidenceAccessGrant, error)
+	GetGrantedIDsEvidenceAccessGrants(ctx context.Context, userID int, providedIDs []int) (map[int]bool, error)
+	CreateEvidenceAccessGrant(ctx context.Context, grant types.EvidenceAccessGrant) error
+	DeleteEvidenceAccessGrant(ctx context.Context, providedID, userID int) error
+
+	// Add methods for filtering, searching, etc...
+}
+
+type FindingRepositoryInterface interface {
+	GetAllFindings(ctx context.Context, filter types.FindingFilter) ([]types.Finding, error)
+	GetByIDFinding(ctx context.Context, finding types.Finding) (types.Finding, error)
+	CreateFinding(ctx context.Context, finding types.Finding) (types.Finding, error)
+	UpdateFinding(ctx context.Context, finding types.Finding) (types.Finding, error)
+	UpdateFindingStatus(ctx context.Context, id, fromStatusID, toStatusID int, reason string) error
+	DeleteFinding(ctx context.Context, id int) error
+
+	// Add methods for filtering, searching, etc...
+}
+
+type CorrectiveActionRepositoryInterface interface {
+	GetByFindingIDCorrectiveActions(ctx context.Context, findingID int) ([]types.CorrectiveAction, error)
+	GetByIDCorrectiveAction(ctx context.Context, action types.CorrectiveAction) (types.CorrectiveAction, error)
+	CreateCorrectiveAction(ctx context.Context, action types.CorrectiveAction) (types.CorrectiveAction, error)
+	UpdateCorrectiveAction(ctx context.Context, action types.CorrectiveAction) (types.CorrectiveAction, error)
+	CompleteCorrectiveAction(ctx context.Context, id int, note string) error
+	VerifyCorrectiveAction(ctx context.Context, id, userID int) error
+
+	// Add methods for filtering, searching, etc...
+}
+
+type RequirementLevelRepositoryInterface interface {
+	GetByIDRequirementLevel(ctx context.Context, level types.RequirementLevel) (types.RequirementLevel, error)
+	GetByStandardIDRequirementLevels(ctx context.Context, standardID int) ([]types.RequirementLevel, error)
+	CreateRequirementLevel(ctx context.Context, level types.RequirementLevel) (types.RequirementLevel, error)
+	UpdateRequirementLevel(ctx context.Context, level types.RequirementLevel) (types.RequirementLevel, error)
+	DeleteRequirementLevel(ctx context.Context, id int) error
+	CountRequirementsByLevel(ctx context.Context, levelID int) (int, error)
 
 	// Add methods for filtering, searching, etc...
 }
@@ -46,20 +210,34 @@ type StandardRepositoryInterface interface {
 type RequirementRepositoryInterface interface {
 	GetByIDRequirement(ctx context.Context, requirement types.Requirement) (types.Requirement, error)
 	GetByIDWithQuestionsRequirement(ctx context.Context, requirement types.Requirement) (types.Requirement, error)
+	GetByStandardIDRequirements(ctx context.Context, standardID int) ([]types.Requirement, error)
+	CreateRequirement(ctx context.Context, requirement types.Requirement) (types.Requirement, error)
 	UpdateRequirement(ctx context.Context, requirement types.Requirement) (types.Requirement, error)
-	UpdateRequirementAndDeleteDraft(ctx context.Context, requirement types.Requirement, draft types.Draft) (types.Requirement, error)
+	MoveRequirement(ctx context.Context, requirement types.Requirement, position int) (types.Requirement, error)
+	ReorderRequirements(ctx context.Context, standardID, parentID int, requirementIDs []int) error
+	UpdateRequirementDescriptionAndPublishDraft(ctx context.Context, requirementID int, from, to string, current, next types.Draft, transition types.DraftTransition) (types.Requirement, error)
 	// Add methods for filtering, searching, etc...
 }
 
 type QuestionRepositoryInterface interface {
 	GetByIDQuestion(ctx context.Context, question types.Question) (types.Question, error)
 	GetByIDWithEvidenceQuestion(ctx context.Context, question types.Question) (types.Question, error)
+	GetByRequirementIDQuestions(ctx context.Context, requirementID int) ([]types.Question, error)
+	CreateQuestion(ctx context.Context, question types.Question) (types.Question, error)
+	UpdateQuestion(ctx context.Context, question types.Question) (types.Question, error)
+	DeleteQuestion(ctx context.Context, id int) error
+	ReorderQuestions(ctx context.Context, requirementID int, questionIDs []int) error
 
 	// Add methods for filtering, searching, etc...
 }
 
 type EvidenceRepositoryInterface interface {
 	GetByIDEvidence(ctx context.Context, evidence types.Evidence) (types.Evidence, error)
+	GetEvidenceType(ctx context.Context, typeID int) (types.ReferenceValue, error)
+	CreateEvidence(ctx context.Context, evidence types.Evidence) (types.Evidence, error)
+	UpdateEvidence(ctx context.Context, evidence types.Evidence) (types.Evidence, error)
+	DeleteEvidence(ctx context.Context, id int) error
+	ReorderEvidence(ctx context.Context, questionID int, evidenceIDs []int) error
 
 	// Add methods for filtering, searching, etc...
 }
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Helpers shared by the repositories whose rows carry a sort_order among their siblings.
+// Table and column names are always constants from this package, never user input.
+
+// lockChildIDs returns the IDs of the rows under parentID in their current order, locking them
+// until the transaction ends
+func lockChildIDs(ctx context.Context, tx *sql.Tx, table, parentColumn string, parentID int) ([]int, error) {
+	query := fmt.Sprintf(`
+	SELECT id
+	FROM %s
+	WHERE %s = ?
+	ORDER BY sort_order, id
+	FOR UPDATE;
+	`, table, parentColumn)
+	rows, err := tx.QueryContext(ctx, query, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s order: %w", table, err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan %s id: %w", table, err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over %s ids: %w", table, err)
+	}
+
+	return ids, nil
+}
+
+// nextSortOrder returns the position after the last row under parentID
+func nextSortOrder(ctx context.Context, tx *sql.Tx, table, parentColumn string, parentID int) (int, error) {
+	query := fmt.Sprintf(`
+	SELECT COALESCE(MAX(sort_order), 0)
+	FROM %s
+	WHERE %s = ?
+	FOR UPDATE;
+	`, table, parentColumn)
+
+	var last int
+	if err := tx.QueryRowContext(ctx, query, parentID).Scan(&last); err != nil {
+		return 0, fmt.Errorf("failed to get %s position: %w", table, err)
+	}
+	return last + 1, nil
+}
+
+// updateSortOrder numbers the given rows 1..n in slice order
+func updateSortOrder(ctx context.Context, tx *sql.Tx, table string, orderedIDs []int) error {
+	query := fmt.Sprintf("UPDATE %s SET sort_order = ? WHERE id = ?;", table)
+	for i, id := range orderedIDs {
+		if _, err := tx.ExecContext(ctx, query, i+1, id); err != nil {
+			return fmt.Errorf("failed to update %s order: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// sameIDs reports whether both slices hold the same IDs, each exactly once
+func sameIDs(current, requested []int) bool {
+	if len(current) != len(requested) {
+		return false
+	}
+
+	seen := make(map[int]bool, len(current))
+	for _, id := range current {
+		seen[id] = true
+	}
+	for _, id := range requested {
+		if !seen[id] {
+			return false
+		}
+		delete(seen, id)
+	}
+	return true
+}
@@ -1,17 +1,21 @@
 package repositories
 
 import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
 	"ISO_Auditing_Tool/pkg/types"
 	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
 )
 
-// DraftRepository is the concrete implementation
+// QuestionRepository is the concrete implementation
 type QuestionRepository struct {
 	db *sql.DB
 }
 
-// Ensure DraftRepository implements DraftRepositoryInterface
+// Ensure QuestionRepository implements QuestionRepositoryInterface
 var _ QuestionRepositoryInterface = (*QuestionRepository)(nil)
 
 func NewQuestionRepository(db *sql.DB) (QuestionRepositoryInterface, error) {
@@ -19,9 +23,250 @@ func NewQuestionRepository(db *sql.DB) (QuestionRepositoryInterface, error) {
 }
 
 func (r *QuestionRepository) GetByIDQuestion(ctx context.Context, question types.Question) (types.Question, error) {
-	return types.Question{}, nil
+	query := `
+	SELECT id, requirement_id, question, guidance, created_at, updated_at, sort_order
+	FROM questions
+	WHERE id = ?;
+	`
+	row := r.db.QueryRowContext(ctx, query, question.ID)
+
+	result, err := scanQuestion(row)
+	if err == sql.ErrNoRows {
+		return types.Question{}, custom_errors.NotFound(ctx, "Question")
+	}
+	if err != nil {
+		return types.Question{}, fmt.Errorf("failed to scan question: %w", err)
+	}
+
+	return result, nil
 }
 
+// GetByIDWithEvidenceQuestion loads a question with its expected evidence
 func (r *QuestionRepository) GetByIDWithEvidenceQuestion(ctx context.Context, question types.Question) (types.Question, error) {
-	return types.Question{}, nil
+	result, err := r.GetByIDQuestion(ctx, question)
+	if err != nil {
+		return types.Question{}, err
+	}
+
+	query := `
+	SELECT e.id, e.question_id, e.expected, e.created_at, e.updated_at, e.sort_order,
+				rv.id, rv.type_id, rv.code, rv.name, rv.description, rv.is_active, rv.created_at, rv.updated_at
+	FROM evidence AS e
+	INNER JOIN reference_values AS rv ON rv.id = e.type_id
+	WHERE e.question_id = ?
+	ORDER BY e.sort_order, e.id;
+	`
+	rows, err := r.db.QueryContext(ctx, query, result.ID)
+	if err != nil {
+		return types.Question{}, fmt.Errorf("failed to query evidence: %w", err)
+	}
+	defer rows.Close()
+
+	result.Evidence = []types.Evidence{}
+	for rows.Next() {
+		evidence, err := scanEvidence(rows)
+		if err != nil {
+			return types.Question{}, fmt.Errorf("failed to scan evidence row: %w", err)
+		}
+		result.Evidence = append(result.Evidence, evidence)
+	}
+
+	if err = rows.Err(); err != nil {
+		return types.Question{}, fmt.Errorf("error iterating over evidence rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetByRequirementIDQuestions returns the questions of a requirement ordered by position
+func (r *QuestionRepository) GetByRequirementIDQuestions(ctx context.Context, requirementID int) ([]types.Question, error) {
+	query := `
+	SELECT id, requirement_id, question, guidance, created_at, updated_at, sort_order
+	FROM questions
+	WHERE requirement_id = ?
+	ORDER BY sort_order, id;
+	`
+	rows, err := r.db.QueryContext(ctx, query, requirementID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query questions: %w", err)
+	}
+	defer rows.Close()
+
+	questions := []types.Question{}
+	for rows.Next() {
+		question, err := scanQuestion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan question row: %w", err)
+		}
+		questions = append(questions, question)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over question rows: %w", err)
+	}
+
+	return questions, nil
+}
+
+// CreateQuestion inserts a question as the last one of its requirement
+func (r *QuestionRepository) CreateQuestion(ctx context.Context, question types.Question) (types.Question, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return types.Question{}, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() // Safe to call even after commit
+
+	question.SortOrder, err = nextSortOrder(ctx, tx, "questions", "requirement_id", question.RequirementID)
+	if err != nil {
+		return types.Question{}, err
+	}
+
+	query := `
+	INSERT INTO questions (requirement_id, question, guidance, sort_order)
+	VALUES (?, ?, ?, ?);
+	`
+	result, err := tx.ExecContext(ctx, query, question.RequirementID, question.Question, nullString(question.Guidance), question.SortOrder)
+	if err != nil {
+		return types.Question{}, fmt.Errorf("failed to create question: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return types.Question{}, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+	if err := recordActivity(ctx, tx, events.ChangeCreated, events.EntityQuestion, int(id), nil); err != nil {
+		return types.Question{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return types.Question{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	question.ID = int(id)
+	return r.GetByIDQuestion(ctx, question)
+}
+
+func (r *QuestionRepository) UpdateQuestion(ctx context.Context, question types.Question) (types.Question, error) {
+	query := `
+	UPDATE questions
+	SET question = ?, guidance = ?
+	WHERE id = ?;
+	`
+	err := changeTracked(ctx, r.db, events.ChangeUpdated, events.EntityQuestion, question.ID, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query, question.Question, nullString(question.Guidance), question.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update question: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return types.Question{}, err
+	}
+
+	return r.GetByIDQuestion(ctx, question)
+}
+
+// DeleteQuestion removes a question and its expected evidence. Questions already copied into an
+// audit are rejected with a CONFLICT error.
+func (r *QuestionRepository) DeleteQuestion(ctx context.Context, id int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() // Safe to call even after commit
+
+	var audits, provided int
+	countQuery := `
+	SELECT
+		(SELECT COUNT(*) FROM audit_questions WHERE question_id = ?),
+		(SELECT COUNT(*) FROM evidence_provided AS ep INNER JOIN evidence AS e ON e.id = ep.evidence_id WHERE e.question_id = ?);
+	`
+	if err := tx.QueryRowContext(ctx, countQuery, id, id).Scan(&audits, &provided); err != nil {
+		return fmt.Errorf("failed to count question dependencies: %w", err)
+	}
+	if audits > 0 || provided > 0 {
+		return custom_errors.Conflict(ctx, "Question", "is used by audits")
+	}
+
+	evidenceIDs, err := lockChildIDs(ctx, tx, "evidence", "question_id", id)
+	if err != nil {
+		return err
+	}
+	evidenceBefore := make([]json.RawMessage, len(evidenceIDs))
+	for i, evidenceID := range evidenceIDs {
+		if evidenceBefore[i], err = snapshotRow(ctx, tx, events.EntityEvidence, evidenceID); err != nil {
+			return err
+		}
+	}
+	before, err := snapshotRow(ctx, tx, events.EntityQuestion, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM evidence WHERE question_id = ?;", id); err != nil {
+		return fmt.Errorf("failed to delete question evidence: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM questions WHERE id = ?;", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete question: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return custom_errors.NotFound(ctx, "Question")
+	}
+
+	for i, evidenceID := range evidenceIDs {
+		if err := appendActivity(ctx, tx, events.ChangeDeleted, events.EntityEvidence, evidenceID, evidenceBefore[i], nil); err != nil {
+			return err
+		}
+	}
+	if err := appendActivity(ctx, tx, events.ChangeDeleted, events.EntityQuestion, id, before, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ReorderQuestions sets the order of the questions of a requirement. The given IDs must be exactly
+// the current questions, otherwise an INVALID_DATA error is returned and nothing changes.
+func (r *QuestionRepository) ReorderQuestions(ctx context.Context, requirementID int, questionIDs []int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() // Safe to call even after commit
+
+	current, err := lockChildIDs(ctx, tx, "questions", "requirement_id", requirementID)
+	if err != nil {
+		return err
+	}
+
+	if !sameIDs(current, questionIDs) {
+		return custom_errors.InvalidData(ctx, "question_ids must list every question of the requirement exactly once")
+	}
+
+	if err := updateSortOrder(ctx, tx, "questions", questionIDs); err != nil {
+		return err
+	}
+
+	before := idsSnapshot(map[string][]int{"question_ids": current})
+	after := idsSnapshot(map[string][]int{"question_ids": questionIDs})
+	if err := appendActivity(ctx, tx, events.ChangeUpdated, events.EntityRequirement, requirementID, before, after); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
 }
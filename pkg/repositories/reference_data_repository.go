@@ -0,0 +1,193 @@
+package repositories
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// ReferenceDataRepository is the concrete implementation
+type ReferenceDataRepository struct {
+	db *sql.DB
+}
+
+// Ensure ReferenceDataRepository implements ReferenceDataRepositoryInterface
+var _ ReferenceDataRepositoryInterface = (*ReferenceDataRepository)(nil)
+
+func NewReferenceDataRepository(db *sql.DB) (ReferenceDataRepositoryInterface, error) {
+	return &ReferenceDataRepository{db: db}, nil
+}
+
+func (r *ReferenceDataRepository) GetAllReferenceTypes(ctx context.Context) ([]types.ReferenceType, error) {
+	query := `
+	SELECT id, name, description, created_at, updated_at
+	FROM reference_types
+	ORDER BY id;
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reference types: %w", err)
+	}
+	defer rows.Close()
+
+	referenceTypes := []types.ReferenceType{}
+	for rows.Next() {
+		var (
+			referenceType types.ReferenceType
+			description   sql.NullString
+		)
+		if err := rows.Scan(&referenceType.ID, &referenceType.Name, &description, &referenceType.CreatedAt, &referenceType.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan reference type row: %w", err)
+		}
+		referenceType.Description = description.String
+		referenceTypes = append(referenceTypes, referenceType)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over reference type rows: %w", err)
+	}
+
+	return referenceTypes, nil
+}
+
+// GetAllReferenceValues returns every value that is not soft deleted, inactive ones included
+func (r *ReferenceDataRepository) GetAllReferenceValues(ctx context.Context) ([]types.ReferenceValue, error) {
+	query := `
+	SELECT id, type_id, code, name, description, is_active, created_at, updated_at
+	FROM reference_values
+	WHERE deleted_at IS NULL
+	ORDER BY type_id, id;
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reference values: %w", err)
+	}
+	defer rows.Close()
+
+	values := []types.ReferenceValue{}
+	for rows.Next() {
+		value, err := scanReferenceValue(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reference value row: %w", err)
+		}
+		values = append(values, value)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over reference value rows: %w", err)
+	}
+
+	return values, nil
+}
+
+func (r *ReferenceDataRepository) GetByIDReferenceValue(ctx context.Context, value types.ReferenceValue) (types.ReferenceValue, error) {
+	query := `
+	SELECT id, type_id, code, name, description, is_active, created_at, updated_at
+	FROM reference_values
+	WHERE id = ? AND deleted_at IS NULL;
+	`
+	result, err := scanReferenceValue(r.db.QueryRowContext(ctx, query, value.ID))
+	if err == sql.ErrNoRows {
+		return types.ReferenceValue{}, custom_errors.NotFound(ctx, "Reference value")
+	}
+	if err != nil {
+		return types.ReferenceValue{}, fmt.Errorf("failed to scan reference value: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *ReferenceDataRepository) CreateReferenceValue(ctx context.Context, value types.ReferenceValue) (types.ReferenceValue, error) {
+	query := `
+	INSERT INTO reference_values (type_id, code, name, description, is_active)
+	VALUES (?, ?, ?, ?, ?);
+	`
+	id, err := createTracked(ctx, r.db, events.EntityReferenceValue, func(tx *sql.Tx) (int, error) {
+		result, err := tx.ExecContext(ctx, query, value.TypeID, value.Code, value.Name, nullString(value.Description), value.IsActive)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create reference value: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+		return int(id), nil
+	})
+	if err != nil {
+		return types.ReferenceValue{}, err
+	}
+
+	value.ID = id
+	return r.GetByIDReferenceValue(ctx, value)
+}
+
+func (r *ReferenceDataRepository) UpdateReferenceValue(ctx context.Context, value types.ReferenceValue) (types.ReferenceValue, error) {
+	query := `
+	UPDATE reference_values
+	SET code = ?, name = ?, description = ?, is_active = ?
+	WHERE id = ? AND deleted_at IS NULL;
+	`
+	err := changeTracked(ctx, r.db, events.ChangeUpdated, events.EntityReferenceValue, value.ID, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query, value.Code, value.Name, nullString(value.Description), value.IsActive, value.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update reference value: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return types.ReferenceValue{}, err
+	}
+
+	return r.GetByIDReferenceValue(ctx, value)
+}
+
+// DeleteReferenceValue soft deletes a value so rows that still point at it keep a valid foreign key
+func (r *ReferenceDataRepository) DeleteReferenceValue(ctx context.Context, id int) error {
+	query := `
+	UPDATE reference_values
+	SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP
+	WHERE id = ? AND deleted_at IS NULL;
+	`
+	return changeTracked(ctx, r.db, events.ChangeDeleted, events.EntityReferenceValue, id, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete reference value: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return custom_errors.NotFound(ctx, "Reference value")
+		}
+		return nil
+	})
+}
+
+func scanReferenceValue(row rowScanner) (types.ReferenceValue, error) {
+	var (
+		value       types.ReferenceValue
+		description sql.NullString
+	)
+	err := row.Scan(
+		&value.ID,
+		&value.TypeID,
+		&value.Code,
+		&value.Name,
+		&description,
+		&value.IsActive,
+		&value.CreatedAt,
+		&value.UpdatedAt,
+	)
+	if err != nil {
+		return types.ReferenceValue{}, err
+	}
+
+	value.Description = description.String
+	return value, nil
+}
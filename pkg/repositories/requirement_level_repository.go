@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// RequirementLevelRepository is the concrete implementation
+type RequirementLevelRepository struct {
+	db *sql.DB
+}
+
+// Ensure RequirementLevelRepository implements RequirementLevelRepositoryInterface
+var _ RequirementLevelRepositoryInterface = (*RequirementLevelRepository)(nil)
+
+func NewRequirementLevelRepository(db *sql.DB) (RequirementLevelRepositoryInterface, error) {
+	return &RequirementLevelRepository{db: db}, nil
+}
+
+func (r *RequirementLevelRepository) GetByIDRequirementLevel(ctx context.Context, level types.RequirementLevel) (types.RequirementLevel, error) {
+	query := `
+	SELECT id, standard_id, level_order, level_name
+	FROM requirement_level
+	WHERE id = ?;
+	`
+	var result types.RequirementLevel
+	err := r.db.QueryRowContext(ctx, query, level.ID).Scan(&result.ID, &result.StandardID, &result.LevelOrder, &result.LevelName)
+	if err == sql.ErrNoRows {
+		return types.RequirementLevel{}, custom_errors.NotFound(ctx, "Requirement level")
+	}
+	if err != nil {
+		return types.RequirementLevel{}, fmt.Errorf("failed to scan requirement level: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetByStandardIDRequirementLevels returns the levels of a standard from the top level down
+func (r *RequirementLevelRepository) GetByStandardIDRequirementLevels(ctx context.Context, standardID int) ([]types.RequirementLevel, error) {
+	query := `
+	SELECT id, standard_id, level_order, level_name
+	FROM requirement_level
+	WHERE standard_id = ?
+	ORDER BY level_order;
+	`
+	rows, err := r.db.QueryContext(ctx, query, standardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query requirement levels: %w", err)
+	}
+	defer rows.Close()
+
+	levels := []types.RequirementLevel{}
+	for rows.Next() {
+		var level types.RequirementLevel
+		if err := rows.Scan(&level.ID, &level.StandardID, &level.LevelOrder, &level.LevelName); err != nil {
+			return nil, fmt.Errorf("failed to scan requirement level row: %w", err)
+		}
+		levels = append(levels, level)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over requirement level rows: %w", err)
+	}
+
+	return levels, nil
+}
+
+func (r *RequirementLevelRepository) CreateRequirementLevel(ctx context.Context, level types.RequirementLevel) (types.RequirementLevel, error) {
+	query := `
+	INSERT INTO requirement_level (standard_id, level_order, level_name)
+	VALUES (?, ?, ?);
+	`
+	id, err := createTracked(ctx, r.db, events.EntityRequirementLevel, func(tx *sql.Tx) (int, error) {
+		result, err := tx.ExecContext(ctx, query, level.StandardID, level.LevelOrder, level.LevelName)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create requirement level: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+		return int(id), nil
+	})
+	if err != nil {
+		return types.RequirementLevel{}, err
+	}
+
+	level.ID = id
+	return level, nil
+}
+
+func (r *RequirementLevelRepository) UpdateRequirementLevel(ctx context.Context, level types.RequirementLevel) (types.RequirementLevel, error) {
+	query := `
+	UPDATE requirement_level
+	SET level_order = ?, level_name = ?
+	WHERE id = ?;
+	`
+	err := changeTracked(ctx, r.db, events.ChangeUpdated, events.EntityRequirementLevel, level.ID, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query, level.LevelOrder, level.LevelName, level.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update requirement level: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return types.RequirementLevel{}, err
+	}
+
+	return r.GetByIDRequirementLevel(ctx, level)
+}
+
+// DeleteRequirementLevel removes a level. Levels still used by requirements are rejected with a
+// CONFLICT error instead of failing on the foreign key.
+func (r *RequirementLevelRepository) DeleteRequirementLevel(ctx context.Context, id int) error {
+	used, err := r.CountRequirementsByLevel(ctx, id)
+	if err != nil {
+		return err
+	}
+	if used > 0 {
+		return custom_errors.Conflict(ctx, "Requirement level", "is used by requirements")
+	}
+
+	return changeTracked(ctx, r.db, events.ChangeDeleted, events.EntityRequirementLevel, id, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, "DELETE FROM requirement_level WHERE id = ?;", id)
+		if err != nil {
+			return fmt.Errorf("failed to delete requirement level: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return custom_errors.NotFound(ctx, "Requirement level")
+		}
+		return nil
+	})
+}
+
+// CountRequirementsByLevel returns how many requirements are assigned to the level
+func (r *RequirementLevelRepository) CountRequirementsByLevel(ctx context.Context, levelID int) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM requirement WHERE requirement_level_id = ?;", levelID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count level requirements: %w", err)
+	}
+	return count, nil
+}
@@ -1,17 +1,20 @@
 package repositories
 
 import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
 	"ISO_Auditing_Tool/pkg/types"
 	"context"
 	"database/sql"
+	"fmt"
 )
 
-// DraftRepository is the concrete implementation
+// RequirementRepository is the concrete implementation
 type RequirementRepository struct {
 	db *sql.DB
 }
 
-// Ensure DraftRepository implements DraftRepositoryInterface
+// Ensure RequirementRepository implements RequirementRepositoryInterface
 var _ RequirementRepositoryInterface = (*RequirementRepository)(nil)
 
 func NewRequirementRepository(db *sql.DB) (RequirementRepositoryInterface, error) {
@@ -19,17 +22,384 @@ func NewRequirementRepository(db *sql.DB) (RequirementRepositoryInterface, error
 }
 
 func (r *RequirementRepository) GetByIDRequirement(ctx context.Context, requirement types.Requirement) (types.Requirement, error) {
-	return types.Requirement{}, nil
+	query := `
+	SELECT id, standard_id, requirement_level_id, parent_id, reference_code, name, description, sort_order
+	FROM requirement
+	WHERE id = ?;
+	`
+	row := r.db.QueryRowContext(ctx, query, requirement.ID)
+
+	result, err := scanRequirement(row)
+	if err == sql.ErrNoRows {
+		return types.Requirement{}, custom_errors.NotFound(ctx, "Requirement")
+	}
+	if err != nil {
+		return types.Requirement{}, fmt.Errorf("failed to scan requirement: %w", err)
+	}
+
+	return result, nil
 }
 
+// GetByIDWithQuestionsRequirement loads a requirement with its questions and their expected evidence
 func (r *RequirementRepository) GetByIDWithQuestionsRequirement(ctx context.Context, requirement types.Requirement) (types.Requirement, error) {
-	return types.Requirement{}, nil
+	result, err := r.GetByIDRequirement(ctx, requirement)
+	if err != nil {
+		return types.Requirement{}, err
+	}
+
+	questionQuery := `
+	SELECT id, requirement_id, question, guidance, created_at, updated_at, sort_order
+	FROM questions
+	WHERE requirement_id = ?
+	ORDER BY sort_order, id;
+	`
+	rows, err := r.db.QueryContext(ctx, questionQuery, result.ID)
+	if err != nil {
+		return types.Requirement{}, fmt.Errorf("failed to query questions: %w", err)
+	}
+	defer rows.Close()
+
+	result.Questions = []types.Question{}
+	questionIndex := make(map[int]int)
+	for rows.Next() {
+		question, err := scanQuestion(rows)
+		if err != nil {
+			return types.Requirement{}, fmt.Errorf("failed to scan question row: %w", err)
+		}
+		question.Evidence = []types.Evidence{}
+		questionIndex[question.ID] = len(result.Questions)
+		result.Questions = append(result.Questions, question)
+	}
+	if err = rows.Err(); err != nil {
+		return types.Requirement{}, fmt.Errorf("error iterating over question rows: %w", err)
+	}
+
+	evidenceQuery := `
+	SELECT e.id, e.question_id, e.expected, e.created_at, e.updated_at, e.sort_order,
+				rv.id, rv.type_id, rv.code, rv.name, rv.description, rv.is_active, rv.created_at, rv.updated_at
+	FROM evidence AS e
+	INNER JOIN questions AS q ON q.id = e.question_id
+	INNER JOIN reference_values AS rv ON rv.id = e.type_id
+	WHERE q.requirement_id = ?
+	ORDER BY e.sort_order, e.id;
+	`
+	evidenceRows, err := r.db.QueryContext(ctx, evidenceQuery, result.ID)
+	if err != nil {
+		return types.Requirement{}, fmt.Errorf("failed to query evidence: %w", err)
+	}
+	defer evidenceRows.Close()
+
+	for evidenceRows.Next() {
+		evidence, err := scanEvidence(evidenceRows)
+		if err != nil {
+			return types.Requirement{}, fmt.Errorf("failed to scan evidence row: %w", err)
+		}
+		if i, ok := questionIndex[evidence.QuestionID]; ok {
+			result.Questions[i].Evidence = append(result.Questions[i].Evidence, evidence)
+		}
+	}
+	if err = evidenceRows.Err(); err != nil {
+		return types.Requirement{}, fmt.Errorf("error iterating over evidence rows: %w", err)
+	}
+
+	return result, nil
 }
 
+// GetByStandardIDRequirements returns every requirement of a standard ordered by position
+func (r *RequirementRepository) GetByStandardIDRequirements(ctx context.Context, standardID int) ([]types.Requirement, error) {
+	query := `
+	SELECT id, standard_id, requirement_level_id, parent_id, reference_code, name, description, sort_order
+	FROM requirement
+	WHERE standard_id = ?
+	ORDER BY sort_order, id;
+	`
+	rows, err := r.db.QueryContext(ctx, query, standardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query requirements: %w", err)
+	}
+	defer rows.Close()
+
+	requirements := []types.Requirement{}
+	for rows.Next() {
+		requirement, err := scanRequirement(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan requirement row: %w", err)
+		}
+		requirements = append(requirements, requirement)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over requirement rows: %w", err)
+	}
+
+	return requirements, nil
+}
+
+// CreateRequirement inserts a requirement as the last child of its parent
+func (r *RequirementRepository) CreateRequirement(ctx context.Context, requirement types.Requirement) (types.Requirement, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return types.Requirement{}, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() // Safe to call even after commit
+
+	positionQuery := `
+	SELECT COALESCE(MAX(sort_order), 0)
+	FROM requirement
+	WHERE standard_id = ? AND parent_id <=> ?
+	FOR UPDATE;
+	`
+	var lastPosition int
+	if err := tx.QueryRowContext(ctx, positionQuery, requirement.StandardID, nullInt(requirement.ParentID)).Scan(&lastPosition); err != nil {
+		return types.Requirement{}, fmt.Errorf("failed to get sibling position: %w", err)
+	}
+	requirement.SortOrder = lastPosition + 1
+
+	insertQuery := `
+	INSERT INTO requirement (standard_id, requirement_level_id, parent_id, reference_code, name, description, sort_order)
+	VALUES (?, ?, ?, ?, ?, ?, ?);
+	`
+	result, err := tx.ExecContext(
+		ctx,
+		insertQuery,
+		requirement.StandardID,
+		requirement.LevelID,
+		nullInt(requirement.ParentID),
+		requirement.ReferenceCode,
+		requirement.Name,
+		nullString(requirement.Description),
+		requirement.SortOrder,
+	)
+	if err != nil {
+		return types.Requirement{}, fmt.Errorf("failed to create requirement: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return types.Requirement{}, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+	if err := recordActivity(ctx, tx, events.ChangeCreated, events.EntityRequirement, int(id), nil); err != nil {
+		return types.Requirement{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return types.Requirement{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	requirement.ID = int(id)
+	return requirement, nil
+}
+
+// UpdateRequirement changes the content of a requirement. Its position in the tree is changed
+// through MoveRequirement and ReorderRequirements only.
 func (r *RequirementRepository) UpdateRequirement(ctx context.Context, requirement types.Requirement) (types.Requirement, error) {
-	return types.Requirement{}, nil
+	query := `
+	UPDATE requirement
+	SET requirement_level_id = ?, reference_code = ?, name = ?, description = ?
+	WHERE id = ?;
+	`
+	err := changeTracked(ctx, r.db, events.ChangeUpdated, events.EntityRequirement, requirement.ID, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(
+			ctx,
+			query,
+			requirement.LevelID,
+			requirement.ReferenceCode,
+			requirement.Name,
+			nullString(requirement.Description),
+			requirement.ID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update requirement: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return types.Requirement{}, err
+	}
+
+	return r.GetByIDRequirement(ctx, requirement)
 }
 
-func (r *RequirementRepository) UpdateRequirementAndDeleteDraft(ctx context.Context, requirement types.Requirement, draft types.Draft) (types.Requirement, error) {
-	return types.Requirement{}, nil
+// MoveRequirement places a requirement under parentID at the given sibling index and renumbers the
+// new siblings. Callers are expected to have rejected cycles and cross-standard moves.
+func (r *RequirementRepository) MoveRequirement(ctx context.Context, requirement types.Requirement, position int) (types.Requirement, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return types.Requirement{}, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() // Safe to call even after commit
+
+	before, err := snapshotRow(ctx, tx, events.EntityRequirement, requirement.ID)
+	if err != nil {
+		return types.Requirement{}, err
+	}
+
+	siblings, err := lockSiblingIDs(ctx, tx, requirement.StandardID, requirement.ParentID)
+	if err != nil {
+		return types.Requirement{}, err
+	}
+
+	ordered := make([]int, 0, len(siblings)+1)
+	for _, id := range siblings {
+		if id != requirement.ID {
+			ordered = append(ordered, id)
+		}
+	}
+	if position < 0 || position > len(ordered) {
+		position = len(ordered)
+	}
+	ordered = append(ordered[:position], append([]int{requirement.ID}, ordered[position:]...)...)
+
+	if _, err := tx.ExecContext(ctx, "UPDATE requirement SET parent_id = ? WHERE id = ?;", nullInt(requirement.ParentID), requirement.ID); err != nil {
+		return types.Requirement{}, fmt.Errorf("failed to move requirement: %w", err)
+	}
+
+	if err := updateSortOrder(ctx, tx, "requirement", ordered); err != nil {
+		return types.Requirement{}, err
+	}
+	if err := recordActivity(ctx, tx, events.ChangeUpdated, events.EntityRequirement, requirement.ID, before); err != nil {
+		return types.Requirement{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return types.Requirement{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetByIDRequirement(ctx, requirement)
+}
+
+// ReorderRequirements sets the order of all children of parentID. The given IDs must be exactly the
+// current children, otherwise an INVALID_DATA error is returned and nothing changes.
+func (r *RequirementRepository) ReorderRequirements(ctx context.Context, standardID, parentID int, requirementIDs []int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() // Safe to call even after commit
+
+	siblings, err := lockSiblingIDs(ctx, tx, standardID, parentID)
+	if err != nil {
+		return err
+	}
+
+	if !sameIDs(siblings, requirementIDs) {
+		return custom_errors.InvalidData(ctx, "requirement_ids must list every child of the parent exactly once")
+	}
+
+	if err := updateSortOrder(ctx, tx, "requirement", requirementIDs); err != nil {
+		return err
+	}
+
+	// Top level requirements are ordered under their standard
+	entity, entityID := events.EntityRequirement, parentID
+	if parentID == 0 {
+		entity, entityID = events.EntityStandard, standardID
+	}
+	before := idsSnapshot(map[string][]int{"requirement_ids": siblings})
+	after := idsSnapshot(map[string][]int{"requirement_ids": requirementIDs})
+	if err := appendActivity(ctx, tx, events.ChangeUpdated, entity, entityID, before, after); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateRequirementDescriptionAndPublishDraft atomically changes the description of a requirement
+// from from to to and publishes the draft that proposed the change, moving it from current to next
+// and recording the transition. The draft row is kept so its approval history stays with it. Other
+// fields of the requirement are left as they are. A requirement whose description is no longer
+// from is reported as a CONFLICT error.
+func (r *RequirementRepository) UpdateRequirementDescriptionAndPublishDraft(ctx context.Context, requirementID int, from, to string, current, next types.Draft, transition types.DraftTransition) (types.Requirement, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return types.Requirement{}, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() // Safe to call even after commit
+
+	requirementBefore, err := snapshotRow(ctx, tx, events.EntityRequirement, requirementID)
+	if err != nil {
+		return types.Requirement{}, err
+	}
+	draftBefore, err := snapshotRow(ctx, tx, events.EntityDraft, current.ID)
+	if err != nil {
+		return types.Requirement{}, err
+	}
+
+	// 1. Check the description against the one the draft was made from
+	query := `
+	SELECT id, standard_id, requirement_level_id, parent_id, reference_code, name, description, sort_order
+	FROM requirement
+	WHERE id = ?
+	FOR UPDATE;
+	`
+	requirement, err := scanRequirement(tx.QueryRowContext(ctx, query, requirementID))
+	if err == sql.ErrNoRows {
+		return types.Requirement{}, custom_errors.NotFound(ctx, "Requirement")
+	}
+	if err != nil {
+		return types.Requirement{}, fmt.Errorf("failed to scan requirement: %w", err)
+	}
+	if requirement.Description != from {
+		return types.Requirement{}, custom_errors.Conflict(ctx, "Requirement", "its description was changed after the draft was made")
+	}
+
+	// 2. Apply the change and publish the draft
+	if _, err := tx.ExecContext(ctx, "UPDATE requirement SET description = ? WHERE id = ?;", nullString(to), requirementID); err != nil {
+		return types.Requirement{}, fmt.Errorf("failed to update requirement: %w", err)
+	}
+	requirement.Description = to
+
+	if err := transitionDraft(ctx, tx, current, next, transition); err != nil {
+		return types.Requirement{}, err
+	}
+
+	// 3. Record both in the activity log
+	if err := recordActivity(ctx, tx, events.ChangeUpdated, events.EntityRequirement, requirementID, requirementBefore); err != nil {
+		return types.Requirement{}, err
+	}
+	if err := recordActivity(ctx, tx, events.ChangeUpdated, events.EntityDraft, current.ID, draftBefore); err != nil {
+		return types.Requirement{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return types.Requirement{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return requirement, nil
+}
+
+// lockSiblingIDs returns the children of parentID in their current order, locking the rows
+// until the transaction ends
+func lockSiblingIDs(ctx context.Context, tx *sql.Tx, standardID, parentID int) ([]int, error) {
+	query := `
+	SELECT id
+	FROM requirement
+	WHERE standard_id = ? AND parent_id <=> ?
+	ORDER BY sort_order, id
+	FOR UPDATE;
+	`
+	rows, err := tx.QueryContext(ctx, query, standardID, nullInt(parentID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sibling requirements: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan sibling requirement: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over sibling requirements: %w", err)
+	}
+
+	return ids, nil
 }
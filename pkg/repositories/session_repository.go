@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// SessionRepository is the concrete implementation
+type SessionRepository struct {
+	db *sql.DB
+}
+
+// Ensure SessionRepository implements SessionRepositoryInterface
+var _ SessionRepositoryInterface = (*SessionRepository)(nil)
+
+func NewSessionRepository(db *sql.DB) (SessionRepositoryInterface, error) {
+	return &SessionRepository{db: db}, nil
+}
+
+// GetUserBySessionToken returns the user of a session that has not expired at asOf. Sessions of
+// users that were deactivated or deleted since are not found. Only the ID of the role is set.
+func (r *SessionRepository) GetUserBySessionToken(ctx context.Context, tokenHash string, asOf time.Time) (types.User, error) {
+	query := `
+	SELECT u.id, u.email, u.name, u.role_id, u.is_active, u.last_login_at, u.created_at, u.updated_at
+	FROM user_sessions AS s
+	INNER JOIN users AS u ON u.id = s.user_id
+	WHERE s.token_hash = ? AND s.expires_at > ? AND u.is_active = TRUE AND u.deleted_at IS NULL;
+	`
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, tokenHash, asOf))
+	if err == sql.ErrNoRows {
+		return types.User{}, custom_errors.NotFound(ctx, "Session")
+	}
+	if err != nil {
+		return types.User{}, fmt.Errorf("failed to scan session user: %w", err)
+	}
+	return user, nil
+}
+
+func (r *SessionRepository) CreateSession(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	query := "INSERT INTO user_sessions (user_id, token_hash, expires_at) VALUES (?, ?, ?);"
+	if _, err := r.db.ExecContext(ctx, query, userID, tokenHash, expiresAt); err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	return nil
+}
+
+// DeleteSession ends a session, ending one that does not exist is not an error
+func (r *SessionRepository) DeleteSession(ctx context.Context, tokenHash string) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM user_sessions WHERE token_hash = ?;", tokenHash); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+// DeleteByUserIDSessions signs a user out everywhere
+func (r *SessionRepository) DeleteByUserIDSessions(ctx context.Context, userID int) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM user_sessions WHERE user_id = ?;", userID); err != nil {
+		return fmt.Errorf("failed to delete user sessions: %w", err)
+	}
+	return nil
+}
+
+func (r *SessionRepository) DeleteExpiredSessions(ctx context.Context, asOf time.Time) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM user_sessions WHERE expires_at <= ?;", asOf); err != nil {
+		return fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+	return nil
+}
@@ -1,18 +1,21 @@
 package repositories
 
 import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
 	"ISO_Auditing_Tool/pkg/types"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 )
 
-// DraftRepository is the concrete implementation
+// StandardRepository is the concrete implementation
 type StandardRepository struct {
 	db *sql.DB
 }
 
-// Ensure DraftRepository implements DraftRepositoryInterface
+// Ensure StandardRepository implements StandardRepositoryInterface
 var _ StandardRepositoryInterface = (*StandardRepository)(nil)
 
 func NewStandardRepository(db *sql.DB) (StandardRepositoryInterface, error) {
@@ -20,35 +23,446 @@ func NewStandardRepository(db *sql.DB) (StandardRepositoryInterface, error) {
 }
 
 func (r *StandardRepository) GetAllStandards(ctx context.Context) ([]types.Standard, error) {
-	return []types.Standard{}, nil
+	query := `
+	SELECT id, name, description, version
+	FROM standards
+	ORDER BY id;
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query standards: %w", err)
+	}
+	defer rows.Close()
+
+	standards := []types.Standard{}
+	for rows.Next() {
+		standard, err := scanStandard(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan standard row: %w", err)
+		}
+		standards = append(standards, standard)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over standard rows: %w", err)
+	}
+
+	return standards, nil
 }
 
+func (r *StandardRepository) GetByIDStandard(ctx context.Context, standard types.Standard) (types.Standard, error) {
+	query := `
+	SELECT id, name, description, version
+	FROM standards
+	WHERE id = ?;
+	`
+	row := r.db.QueryRowContext(ctx, query, standard.ID)
+
+	result, err := scanStandard(row)
+	if err == sql.ErrNoRows {
+		return types.Standard{}, custom_errors.NotFound(ctx, "Standard")
+	}
+	if err != nil {
+		return types.Standard{}, fmt.Errorf("failed to scan standard: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetByIDWithFullHierarchyStandard loads a standard with its requirements, their questions and the
+// expected evidence of each question. It mirrors the shape produced by the materialized CTE in
+// internal/materialized_queries but assembles the tree in Go from one query per level.
 func (r *StandardRepository) GetByIDWithFullHierarchyStandard(ctx context.Context, standard types.Standard) (types.Standard, error) {
-	return types.Standard{}, nil
+	result, err := r.GetByIDStandard(ctx, standard)
+	if err != nil {
+		return types.Standard{}, err
+	}
+
+	requirements, err := r.getRequirementsByStandard(ctx, result.ID)
+	if err != nil {
+		return types.Standard{}, err
+	}
+
+	questions, err := r.getQuestionsByStandard(ctx, result.ID)
+	if err != nil {
+		return types.Standard{}, err
+	}
+
+	evidence, err := r.getEvidenceByStandard(ctx, result.ID)
+	if err != nil {
+		return types.Standard{}, err
+	}
+
+	// Attach evidence to questions and questions to requirements, keeping the query order
+	evidenceByQuestion := make(map[int][]types.Evidence)
+	for _, e := range evidence {
+		evidenceByQuestion[e.QuestionID] = append(evidenceByQuestion[e.QuestionID], e)
+	}
+
+	questionsByRequirement := make(map[int][]types.Question)
+	for _, q := range questions {
+		q.Evidence = evidenceByQuestion[q.ID]
+		if q.Evidence == nil {
+			q.Evidence = []types.Evidence{}
+		}
+		questionsByRequirement[q.RequirementID] = append(questionsByRequirement[q.RequirementID], q)
+	}
+
+	for i := range requirements {
+		requirements[i].Questions = questionsByRequirement[requirements[i].ID]
+		if requirements[i].Questions == nil {
+			requirements[i].Questions = []types.Question{}
+		}
+	}
+
+	result.Requirements = requirements
+	return result, nil
 }
 
-func (r *StandardRepository) GetByIDStandard(ctx context.Context, standard types.Standard) (types.Standard, error) {
+func (r *StandardRepository) CreateStandard(ctx context.Context, standard types.Standard) (types.Standard, error) {
 	query := `
-  INSERT INTO drafts (
-    type_id, object_id, status_id, version, data, diff,
-    user_id, approver_id, approval_comment, publish_error
-  ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
-  `
-
-	result, err := r.db.ExecContext(
-		ctx,
-		query,
-		standard.ID,
-	)
+	INSERT INTO standards (name, description, version)
+	VALUES (?, ?, ?);
+	`
+	id, err := createTracked(ctx, r.db, events.EntityStandard, func(tx *sql.Tx) (int, error) {
+		result, err := tx.ExecContext(ctx, query, standard.Name, nullString(standard.Description), standard.Version)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create standard: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+		return int(id), nil
+	})
 	if err != nil {
-		return types.Standard{}, fmt.Errorf("failed to create draft: %w", err)
+		return types.Standard{}, err
 	}
 
-	id, err := result.LastInsertId()
+	standard.ID = id
+	return standard, nil
+}
+
+func (r *StandardRepository) UpdateStandard(ctx context.Context, standard types.Standard) (types.Standard, error) {
+	// Check existence first, MySQL reports zero affected rows when nothing changed
+	if _, err := r.GetByIDStandard(ctx, standard); err != nil {
+		return types.Standard{}, err
+	}
+
+	query := `
+	UPDATE standards
+	SET name = ?, description = ?, version = ?
+	WHERE id = ?;
+	`
+	err := changeTracked(ctx, r.db, events.ChangeUpdated, events.EntityStandard, standard.ID, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query, standard.Name, nullString(standard.Description), standard.Version, standard.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update standard: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return types.Standard{}, fmt.Errorf("failed to get last insert ID: %w", err)
+		return types.Standard{}, err
 	}
 
-	standard.ID = int(id)
 	return standard, nil
 }
+
+// DeleteStandard removes a standard together with its requirement levels. Standards that still have
+// requirements or audit plans are rejected with a CONFLICT error instead of failing on the foreign keys.
+func (r *StandardRepository) DeleteStandard(ctx context.Context, id int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() // Safe to call even after commit
+
+	var requirements, auditPlans int
+	countQuery := `
+	SELECT
+		(SELECT COUNT(*) FROM requirement WHERE standard_id = ?),
+		(SELECT COUNT(*) FROM audit_plans WHERE standard_id = ?);
+	`
+	if err := tx.QueryRowContext(ctx, countQuery, id, id).Scan(&requirements, &auditPlans); err != nil {
+		return fmt.Errorf("failed to count standard dependencies: %w", err)
+	}
+	if requirements > 0 {
+		return custom_errors.Conflict(ctx, "Standard", "still has requirements")
+	}
+	if auditPlans > 0 {
+		return custom_errors.Conflict(ctx, "Standard", "is used by audit plans")
+	}
+
+	levelIDs, err := lockLevelIDs(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	levelsBefore := make([]json.RawMessage, len(levelIDs))
+	for i, levelID := range levelIDs {
+		if levelsBefore[i], err = snapshotRow(ctx, tx, events.EntityRequirementLevel, levelID); err != nil {
+			return err
+		}
+	}
+	before, err := snapshotRow(ctx, tx, events.EntityStandard, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM requirement_level WHERE standard_id = ?;", id); err != nil {
+		return fmt.Errorf("failed to delete requirement levels: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM standards WHERE id = ?;", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete standard: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return custom_errors.NotFound(ctx, "Standard")
+	}
+
+	for i, levelID := range levelIDs {
+		if err := appendActivity(ctx, tx, events.ChangeDeleted, events.EntityRequirementLevel, levelID, levelsBefore[i], nil); err != nil {
+			return err
+		}
+	}
+	if err := appendActivity(ctx, tx, events.ChangeDeleted, events.EntityStandard, id, before, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// lockLevelIDs returns the requirement levels of a standard, locking them until the transaction ends
+func lockLevelIDs(ctx context.Context, tx *sql.Tx, standardID int) ([]int, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT id FROM requirement_level WHERE standard_id = ? ORDER BY level_order, id FOR UPDATE;", standardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query requirement levels: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan requirement level id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over requirement level ids: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (r *StandardRepository) getRequirementsByStandard(ctx context.Context, standardID int) ([]types.Requirement, error) {
+	query := `
+	SELECT id, standard_id, requirement_level_id, parent_id, reference_code, name, description, sort_order
+	FROM requirement
+	WHERE standard_id = ?
+	ORDER BY sort_order, id;
+	`
+	rows, err := r.db.QueryContext(ctx, query, standardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query requirements: %w", err)
+	}
+	defer rows.Close()
+
+	requirements := []types.Requirement{}
+	for rows.Next() {
+		requirement, err := scanRequirement(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan requirement row: %w", err)
+		}
+		requirements = append(requirements, requirement)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over requirement rows: %w", err)
+	}
+
+	return requirements, nil
+}
+
+func (r *StandardRepository) getQuestionsByStandard(ctx context.Context, standardID int) ([]types.Question, error) {
+	query := `
+	SELECT q.id, q.requirement_id, q.question, q.guidance, q.created_at, q.updated_at, q.sort_order
+	FROM questions AS q
+	INNER JOIN requirement AS r ON r.id = q.requirement_id
+	WHERE r.standard_id = ?
+	ORDER BY q.sort_order, q.id;
+	`
+	rows, err := r.db.QueryContext(ctx, query, standardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query questions: %w", err)
+	}
+	defer rows.Close()
+
+	questions := []types.Question{}
+	for rows.Next() {
+		question, err := scanQuestion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan question row: %w", err)
+		}
+		questions = append(questions, question)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over question rows: %w", err)
+	}
+
+	return questions, nil
+}
+
+func (r *StandardRepository) getEvidenceByStandard(ctx context.Context, standardID int) ([]types.Evidence, error) {
+	query := `
+	SELECT e.id, e.question_id, e.expected, e.created_at, e.updated_at, e.sort_order,
+				rv.id, rv.type_id, rv.code, rv.name, rv.description, rv.is_active, rv.created_at, rv.updated_at
+	FROM evidence AS e
+	INNER JOIN questions AS q ON q.id = e.question_id
+	INNER JOIN requirement AS r ON r.id = q.requirement_id
+	INNER JOIN reference_values AS rv ON rv.id = e.type_id
+	WHERE r.standard_id = ?
+	ORDER BY e.sort_order, e.id;
+	`
+	rows, err := r.db.QueryContext(ctx, query, standardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query evidence: %w", err)
+	}
+	defer rows.Close()
+
+	evidence := []types.Evidence{}
+	for rows.Next() {
+		e, err := scanEvidence(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan evidence row: %w", err)
+		}
+		evidence = append(evidence, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over evidence rows: %w", err)
+	}
+
+	return evidence, nil
+}
+
+// nullString stores empty optional text columns as NULL
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+// nullInt stores a zero foreign key as NULL, e.g. the parent of a top level requirement
+func nullInt(value int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(value), Valid: value != 0}
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanStandard(row rowScanner) (types.Standard, error) {
+	var (
+		standard    types.Standard
+		description sql.NullString
+	)
+
+	if err := row.Scan(&standard.ID, &standard.Name, &description, &standard.Version); err != nil {
+		return types.Standard{}, err
+	}
+
+	standard.Description = description.String
+	return standard, nil
+}
+
+func scanRequirement(row rowScanner) (types.Requirement, error) {
+	var (
+		requirement types.Requirement
+		parentID    sql.NullInt64
+		description sql.NullString
+	)
+
+	err := row.Scan(
+		&requirement.ID,
+		&requirement.StandardID,
+		&requirement.LevelID,
+		&parentID,
+		&requirement.ReferenceCode,
+		&requirement.Name,
+		&description,
+		&requirement.SortOrder,
+	)
+	if err != nil {
+		return types.Requirement{}, err
+	}
+
+	// Top level requirements have no parent and are exposed with a zero ParentID
+	requirement.ParentID = int(parentID.Int64)
+	requirement.Description = description.String
+	return requirement, nil
+}
+
+func scanQuestion(row rowScanner) (types.Question, error) {
+	var (
+		question types.Question
+		guidance sql.NullString
+	)
+
+	err := row.Scan(
+		&question.ID,
+		&question.RequirementID,
+		&question.Question,
+		&guidance,
+		&question.CreatedAt,
+		&question.UpdatedAt,
+		&question.SortOrder,
+	)
+	if err != nil {
+		return types.Question{}, err
+	}
+
+	question.Guidance = guidance.String
+	return question, nil
+}
+
+func scanEvidence(row rowScanner) (types.Evidence, error) {
+	var (
+		evidence        types.Evidence
+		typeDescription sql.NullString
+	)
+
+	err := row.Scan(
+		&evidence.ID,
+		&evidence.QuestionID,
+		&evidence.Expected,
+		&evidence.CreatedAt,
+		&evidence.UpdatedAt,
+		&evidence.SortOrder,
+		&evidence.TypeVal.ID,
+		&evidence.TypeVal.TypeID,
+		&evidence.TypeVal.Code,
+		&evidence.TypeVal.Name,
+		&typeDescription,
+		&evidence.TypeVal.IsActive,
+		&evidence.TypeVal.CreatedAt,
+		&evidence.TypeVal.UpdatedAt,
+	)
+	if err != nil {
+		return types.Evidence{}, err
+	}
+
+	evidence.TypeVal.Description = typeDescription.String
+	return evidence, nil
+}
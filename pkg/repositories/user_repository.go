@@ -0,0 +1,368 @@
+package repositories
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// UserRepository is the concrete implementation
+type UserRepository struct {
+	db *sql.DB
+}
+
+// Ensure UserRepository implements UserRepositoryInterface
+var _ UserRepositoryInterface = (*UserRepository)(nil)
+
+func NewUserRepository(db *sql.DB) (UserRepositoryInterface, error) {
+	return &UserRepository{db: db}, nil
+}
+
+const userColumns = `
+	id, email, name, role_id, is_active, last_login_at, created_at, updated_at`
+
+// GetAllUsers returns the users that are not deleted, ordered by name. Inactive users are left out
+// unless the filter includes them. Only the ID of the role is set.
+func (r *UserRepository) GetAllUsers(ctx context.Context, filter types.UserFilter) ([]types.User, error) {
+	conditions := []string{"deleted_at IS NULL"}
+	args := []any{}
+
+	if !filter.IncludeInactive {
+		conditions = append(conditions, "is_active = TRUE")
+	}
+	if filter.RoleID != 0 {
+		conditions = append(conditions, "role_id = ?")
+		args = append(args, filter.RoleID)
+	}
+
+	query := `
+	SELECT` + userColumns + `
+	FROM users
+	WHERE ` + strings.Join(conditions, " AND ") + `
+	ORDER BY name, id;
+	`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []types.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user row: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over user rows: %w", err)
+	}
+
+	return users, nil
+}
+
+// GetByIDUser returns a user that is not deleted, active or not. Only the ID of the role is set.
+func (r *UserRepository) GetByIDUser(ctx context.Context, user types.User) (types.User, error) {
+	query := `
+	SELECT` + userColumns + `
+	FROM users
+	WHERE id = ? AND deleted_at IS NULL;
+	`
+	result, err := scanUser(r.db.QueryRowContext(ctx, query, user.ID))
+	if err == sql.ErrNoRows {
+		return types.User{}, custom_errors.NotFound(ctx, "User")
+	}
+	if err != nil {
+		return types.User{}, fmt.Errorf("failed to scan user: %w", err)
+	}
+	return result, nil
+}
+
+// CreateUser adds an active user. Emails are unique regardless of case, deleted users keep theirs.
+func (r *UserRepository) CreateUser(ctx context.Context, user types.User) (types.User, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM users WHERE LOWER(email) = LOWER(?);"
+	if err := r.db.QueryRowContext(ctx, query, user.Email).Scan(&count); err != nil {
+		return types.User{}, fmt.Errorf("failed to check user email: %w", err)
+	}
+	if count > 0 {
+		return types.User{}, custom_errors.Conflict(ctx, "User", fmt.Sprintf("with email %s already exists", user.Email))
+	}
+
+	query = "INSERT INTO users (email, name, role_id) VALUES (?, ?, ?);"
+	id, err := createTracked(ctx, r.db, events.EntityUser, func(tx *sql.Tx) (int, error) {
+		result, err := tx.ExecContext(ctx, query, user.Email, user.Name, user.RoleVal.ID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create user: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+		return int(id), nil
+	})
+	if err != nil {
+		return types.User{}, err
+	}
+
+	return r.GetByIDUser(ctx, types.User{ID: id})
+}
+
+// GetCredentialsUser returns the active user with the given email, regardless of case, and their
+// password hash. The hash is empty when no password was set.
+func (r *UserRepository) GetCredentialsUser(ctx context.Context, email string) (types.User, string, error) {
+	query := `
+	SELECT` + userColumns + `, password_hash
+	FROM users
+	WHERE LOWER(email) = LOWER(?) AND is_active = TRUE AND deleted_at IS NULL;
+	`
+	var (
+		user         types.User
+		lastLoginAt  sql.NullTime
+		passwordHash sql.NullString
+	)
+	err := r.db.QueryRowContext(ctx, query, email).Scan(
+		&user.ID,
+		&user.Email,
+		&user.Name,
+		&user.RoleVal.ID,
+		&user.IsActive,
+		&lastLoginAt,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&passwordHash,
+	)
+	if err == sql.ErrNoRows {
+		return types.User{}, "", custom_errors.NotFound(ctx, "User")
+	}
+	if err != nil {
+		return types.User{}, "", fmt.Errorf("failed to scan user: %w", err)
+	}
+
+	if lastLoginAt.Valid {
+		user.LastLoginAt = &lastLoginAt.Time
+	}
+	return user, passwordHash.String, nil
+}
+
+// UpdateUserPassword replaces the password hash of a user that is not deleted
+func (r *UserRepository) UpdateUserPassword(ctx context.Context, id int, passwordHash string) error {
+	query := "UPDATE users SET password_hash = ? WHERE id = ? AND deleted_at IS NULL;"
+	return changeTracked(ctx, r.db, events.ChangeUpdated, events.EntityUser, id, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, passwordHash, id)
+		if err != nil {
+			return fmt.Errorf("failed to update user password: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return custom_errors.NotFound(ctx, "User")
+		}
+		return nil
+	})
+}
+
+// UpdateUserLastLogin records that a user signed in just now
+func (r *UserRepository) UpdateUserLastLogin(ctx context.Context, id int) error {
+	query := "UPDATE users SET last_login_at = CURRENT_TIMESTAMP WHERE id = ?;"
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to update last login: %w", err)
+	}
+	return nil
+}
+
+// UpdateUserRole gives a user that is not deleted another role
+func (r *UserRepository) UpdateUserRole(ctx context.Context, id, roleID int) error {
+	query := "UPDATE users SET role_id = ? WHERE id = ? AND deleted_at IS NULL;"
+	return changeTracked(ctx, r.db, events.ChangeUpdated, events.EntityUser, id, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, roleID, id)
+		if err != nil {
+			return fmt.Errorf("failed to update user role: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return custom_errors.NotFound(ctx, "User")
+		}
+		return nil
+	})
+}
+
+// ReactivateUser lets an inactive user in again. A user that is already active is reported as a
+// CONFLICT error.
+func (r *UserRepository) ReactivateUser(ctx context.Context, id int) error {
+	query := "UPDATE users SET is_active = TRUE WHERE id = ? AND is_active = FALSE AND deleted_at IS NULL;"
+	return changeTracked(ctx, r.db, events.ChangeUpdated, events.EntityUser, id, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return fmt.Errorf("failed to reactivate user: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return custom_errors.Conflict(ctx, "User", "is already active")
+		}
+		return nil
+	})
+}
+
+// DeactivateUser marks a user inactive and hands over the findings they are responsible for whose
+// status is one of openStatusIDs, in one transaction. The findings move to reassignTo when it is
+// set, otherwise they are flagged as needing a new owner.
+func (r *UserRepository) DeactivateUser(ctx context.Context, id, reassignTo int, openStatusIDs []int) (types.UserDeactivation, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return types.UserDeactivation{}, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() // Safe to call even after commit
+
+	var active bool
+	err = tx.QueryRowContext(ctx, "SELECT is_active FROM users WHERE id = ? AND deleted_at IS NULL FOR UPDATE;", id).Scan(&active)
+	if err == sql.ErrNoRows {
+		return types.UserDeactivation{}, custom_errors.NotFound(ctx, "User")
+	}
+	if err != nil {
+		return types.UserDeactivation{}, fmt.Errorf("failed to lock user: %w", err)
+	}
+	if !active {
+		return types.UserDeactivation{}, custom_errors.Conflict(ctx, "User", "is already inactive")
+	}
+
+	if reassignTo != 0 {
+		var count int
+		query := "SELECT COUNT(*) FROM users WHERE id = ? AND is_active = TRUE AND deleted_at IS NULL FOR UPDATE;"
+		if err := tx.QueryRowContext(ctx, query, reassignTo).Scan(&count); err != nil {
+			return types.UserDeactivation{}, fmt.Errorf("failed to check reassigned user: %w", err)
+		}
+		if count == 0 {
+			return types.UserDeactivation{}, custom_errors.InvalidData(ctx, "reassign_to is not an active user")
+		}
+	}
+
+	before, err := snapshotRow(ctx, tx, events.EntityUser, id)
+	if err != nil {
+		return types.UserDeactivation{}, err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE users SET is_active = FALSE WHERE id = ?;", id); err != nil {
+		return types.UserDeactivation{}, fmt.Errorf("failed to deactivate user: %w", err)
+	}
+	if err := recordActivity(ctx, tx, events.ChangeUpdated, events.EntityUser, id, before); err != nil {
+		return types.UserDeactivation{}, err
+	}
+
+	findingIDs, err := openFindingIDs(ctx, tx, id, openStatusIDs)
+	if err != nil {
+		return types.UserDeactivation{}, err
+	}
+	findingsBefore := make([]json.RawMessage, len(findingIDs))
+	for i, findingID := range findingIDs {
+		if findingsBefore[i], err = snapshotRow(ctx, tx, events.EntityFinding, findingID); err != nil {
+			return types.UserDeactivation{}, err
+		}
+	}
+
+	report := types.UserDeactivation{ReassignTo: reassignTo, Reassigned: []int{}, Flagged: []int{}}
+	if len(findingIDs) > 0 {
+		if reassignTo != 0 {
+			query := "UPDATE findings SET responsible_user_id = ?, needs_owner = FALSE WHERE id IN (" + placeholders(len(findingIDs)) + ");"
+			if _, err := tx.ExecContext(ctx, query, append([]any{reassignTo}, intArgs(findingIDs)...)...); err != nil {
+				return types.UserDeactivation{}, fmt.Errorf("failed to reassign findings: %w", err)
+			}
+			report.Reassigned = findingIDs
+		} else {
+			query := "UPDATE findings SET needs_owner = TRUE WHERE id IN (" + placeholders(len(findingIDs)) + ");"
+			if _, err := tx.ExecContext(ctx, query, intArgs(findingIDs)...); err != nil {
+				return types.UserDeactivation{}, fmt.Errorf("failed to flag findings: %w", err)
+			}
+			report.Flagged = findingIDs
+		}
+	}
+	for i, findingID := range findingIDs {
+		if err := recordActivity(ctx, tx, events.ChangeUpdated, events.EntityFinding, findingID, findingsBefore[i]); err != nil {
+			return types.UserDeactivation{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return types.UserDeactivation{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return report, nil
+}
+
+// openFindingIDs locks the findings a user is responsible for whose status is one of statusIDs
+func openFindingIDs(ctx context.Context, tx *sql.Tx, userID int, statusIDs []int) ([]int, error) {
+	if len(statusIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+	SELECT id
+	FROM findings
+	WHERE responsible_user_id = ? AND status_id IN (` + placeholders(len(statusIDs)) + `) AND deleted_at IS NULL
+	ORDER BY id
+	FOR UPDATE;
+	`
+	rows, err := tx.QueryContext(ctx, query, append([]any{userID}, intArgs(statusIDs)...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open findings: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan finding row: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over finding rows: %w", err)
+	}
+
+	return ids, nil
+}
+
+func scanUser(row rowScanner) (types.User, error) {
+	var (
+		user        types.User
+		lastLoginAt sql.NullTime
+	)
+	err := row.Scan(
+		&user.ID,
+		&user.Email,
+		&user.Name,
+		&user.RoleVal.ID,
+		&user.IsActive,
+		&lastLoginAt,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		return types.User{}, err
+	}
+
+	if lastLoginAt.Valid {
+		user.LastLoginAt = &lastLoginAt.Time
+	}
+	return user, nil
+}
@@ -0,0 +1,32 @@
+// Carries who made a request and its ID through the request context
+// Kept apart from services so that repositories and errors can read them without import cycles
+package requestctx
+
+import "context"
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx recording the ID of the user the request acts for
+func WithActor(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, actorKey{}, userID)
+}
+
+// Actor returns the ID of the user the request acts for, false for anonymous requests and
+// background jobs
+func Actor(ctx context.Context) (int, bool) {
+	userID, ok := ctx.Value(actorKey{}).(int)
+	return userID, ok
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the ID of the request
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the ID of the request, empty outside of requests
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
@@ -0,0 +1,48 @@
+// Contains activity log business logic
+// Checks the filters of activity log queries, the entries themselves are written by the repositories
+package services
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"fmt"
+)
+
+const (
+	// defaultActivityLimit is the page size when the filter sets none
+	defaultActivityLimit = 100
+	// maxActivityLimit is the largest page a filter may ask for
+	maxActivityLimit = 500
+)
+
+type ActivityLogService struct {
+	Repo repositories.ActivityLogRepositoryInterface
+}
+
+// ensure ActivityLogService implements ActivityLogServiceInterface
+var _ ActivityLogServiceInterface = (*ActivityLogService)(nil)
+
+func NewActivityLogService(repo repositories.ActivityLogRepositoryInterface) *ActivityLogService {
+	return &ActivityLogService{Repo: repo}
+}
+
+// GetAll returns the entries matching the filter, newest first. A page holds 100 entries unless
+// the filter asks for another size, at most 500.
+func (s *ActivityLogService) GetAll(ctx context.Context, filter types.ActivityFilter) ([]types.ActivityEntry, error) {
+	if filter.EntityID != 0 && filter.EntityType == "" {
+		return nil, custom_errors.InvalidData(ctx, "entity_id needs an entity_type")
+	}
+	if filter.Limit < 0 || filter.Limit > maxActivityLimit {
+		return nil, custom_errors.InvalidData(ctx, fmt.Sprintf("limit must be between 1 and %d", maxActivityLimit))
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.To.After(filter.From) {
+		return nil, custom_errors.InvalidData(ctx, "to must be after from")
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultActivityLimit
+	}
+	return s.Repo.GetActivityEntries(ctx, filter)
+}
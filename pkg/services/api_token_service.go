@@ -0,0 +1,187 @@
+// Contains personal API token business logic
+// Mints named, scoped and expiring tokens, resolves a Bearer token to its user and revokes tokens
+package services
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+)
+
+// API token scopes. Scopes are independent, a script that reads and writes needs both.
+const (
+	APITokenScopeRead  = "read"  // GET and HEAD requests
+	APITokenScopeWrite = "write" // every other request
+)
+
+const (
+	// apiTokenPrefix marks tokens so secret scanners and people can recognise them
+	apiTokenPrefix = "iat_"
+	// maxAPITokenDays is the longest lifetime of a token
+	maxAPITokenDays = 365
+)
+
+type APITokenService struct {
+	Repo          repositories.APITokenRepositoryInterface
+	Users         repositories.UserRepositoryInterface
+	ReferenceData ReferenceDataServiceInterface
+	Now           Clock
+	EventBus      *events.EventBus
+}
+
+// ensure APITokenService implements APITokenServiceInterface
+var _ APITokenServiceInterface = (*APITokenService)(nil)
+
+func NewAPITokenService(
+	repo repositories.APITokenRepositoryInterface,
+	users repositories.UserRepositoryInterface,
+	referenceData ReferenceDataServiceInterface,
+	now Clock,
+	eventBus *events.EventBus,
+) *APITokenService {
+	if now == nil {
+		now = time.Now
+	}
+	return &APITokenService{Repo: repo, Users: users, ReferenceData: referenceData, Now: now, EventBus: eventBus}
+}
+
+// GetByUserID returns the tokens of a user, revoked and expired ones included
+func (s *APITokenService) GetByUserID(ctx context.Context, userID int) ([]types.APIToken, error) {
+	if _, err := s.Users.GetByIDUser(ctx, types.User{ID: userID}); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetByUserIDAPITokens(ctx, userID)
+}
+
+// Create mints a token for a user. The returned token carries its secret, which cannot be read
+// again. Requests made with an API token cannot mint further tokens.
+func (s *APITokenService) Create(ctx context.Context, userID int, form types.APITokenForm) (types.APIToken, error) {
+	if _, ok := CurrentAPIToken(ctx); ok {
+		return types.APIToken{}, custom_errors.Forbidden(ctx, "API tokens cannot be created with an API token")
+	}
+
+	name := strings.TrimSpace(form.Name)
+	if name == "" {
+		return types.APIToken{}, custom_errors.EmptyField(ctx, "string", "Name")
+	}
+	if form.ExpiresInDays < 1 || form.ExpiresInDays > maxAPITokenDays {
+		return types.APIToken{}, custom_errors.InvalidData(ctx, fmt.Sprintf("expires_in_days must be between 1 and %d", maxAPITokenDays))
+	}
+
+	scopes := []string{}
+	for _, scope := range form.Scopes {
+		if scope != APITokenScopeRead && scope != APITokenScopeWrite {
+			return types.APIToken{}, custom_errors.InvalidData(ctx, fmt.Sprintf("scope %q is not one of read, write", scope))
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return types.APIToken{}, custom_errors.EmptyField(ctx, "list", "Scopes")
+	}
+
+	secret, err := newSessionToken()
+	if err != nil {
+		return types.APIToken{}, err
+	}
+	secret = apiTokenPrefix + secret
+
+	token := types.APIToken{
+		UserID:    userID,
+		Name:      name,
+		Scopes:    scopes,
+		ExpiresAt: s.Now().Add(time.Duration(form.ExpiresInDays) * 24 * time.Hour),
+	}
+	created, err := s.Repo.CreateAPIToken(ctx, token, hashSessionToken(secret))
+	if err != nil {
+		return types.APIToken{}, err
+	}
+
+	s.publish(ctx, created, events.ChangeCreated)
+	created.Token = secret
+	return created, nil
+}
+
+// Revoke stops a token of userID from working. Tokens of other users are not found.
+func (s *APITokenService) Revoke(ctx context.Context, userID, id int) (types.APIToken, error) {
+	token, err := s.Repo.GetByIDAPIToken(ctx, id)
+	if err != nil {
+		return types.APIToken{}, err
+	}
+	if token.UserID != userID {
+		return types.APIToken{}, custom_errors.NotFound(ctx, "API token")
+	}
+
+	if err := s.Repo.RevokeAPIToken(ctx, token.ID); err != nil {
+		return types.APIToken{}, err
+	}
+
+	now := s.Now()
+	token.RevokedAt = &now
+	s.publish(ctx, token, events.ChangeUpdated)
+	return token, nil
+}
+
+// Authenticate returns the active user of a token that is neither revoked nor expired, and
+// records that the token was used
+func (s *APITokenService) Authenticate(ctx context.Context, secret string) (types.User, types.APIToken, error) {
+	if !strings.HasPrefix(secret, apiTokenPrefix) {
+		return types.User{}, types.APIToken{}, custom_errors.Unauthorized(ctx, "invalid API token")
+	}
+
+	now := s.Now()
+	user, token, err := s.Repo.GetUserByAPIToken(ctx, hashSessionToken(secret), now)
+	if custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound) {
+		return types.User{}, types.APIToken{}, custom_errors.Unauthorized(ctx, "the API token is invalid, revoked or expired")
+	}
+	if err != nil {
+		return types.User{}, types.APIToken{}, err
+	}
+
+	// A failed timestamp should not fail the request
+	if err := s.Repo.UpdateAPITokenLastUsed(ctx, token.ID, now); err != nil {
+		log.Printf("Failed to record use of API token %d: %v", token.ID, err)
+	} else {
+		token.LastUsedAt = &now
+	}
+
+	role, err := s.ReferenceData.GetByID(ctx, user.RoleVal.ID)
+	if err != nil {
+		return types.User{}, types.APIToken{}, err
+	}
+	user.RoleVal = role
+	return user, token, nil
+}
+
+// CheckAPITokenScope lets session requests through and reports a request made with an API token
+// whose scopes do not cover the HTTP method as FORBIDDEN
+func CheckAPITokenScope(ctx context.Context, method string) error {
+	token, ok := CurrentAPIToken(ctx)
+	if !ok {
+		return nil
+	}
+
+	scope := APITokenScopeWrite
+	if method == "GET" || method == "HEAD" {
+		scope = APITokenScopeRead
+	}
+	if !slices.Contains(token.Scopes, scope) {
+		return custom_errors.Forbidden(ctx, fmt.Sprintf("the API token does not have the %s scope", scope))
+	}
+	return nil
+}
+
+func (s *APITokenService) publish(ctx context.Context, token types.APIToken, changeType events.ChangeType) {
+	if s.EventBus == nil {
+		return
+	}
+	s.EventBus.AsyncPublish(ctx, events.NewAPITokenEvent(token.ID, changeType, token.UserID, "", token))
+}
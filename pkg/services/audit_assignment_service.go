@@ -0,0 +1,139 @@
+// Contains audit team business logic
+// Manages the support auditors of audit plans and answers who may work on an audit
+package services
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"fmt"
+)
+
+type AuditAssignmentService struct {
+	Repo          repositories.AuditAssignmentRepositoryInterface
+	AuditPlans    AuditPlanServiceInterface
+	ReferenceData ReferenceDataServiceInterface
+	EventBus      *events.EventBus
+}
+
+// ensure AuditAssignmentService implements AuditAssignmentServiceInterface
+var _ AuditAssignmentServiceInterface = (*AuditAssignmentService)(nil)
+
+func NewAuditAssignmentService(
+	repo repositories.AuditAssignmentRepositoryInterface,
+	auditPlans AuditPlanServiceInterface,
+	referenceData ReferenceDataServiceInterface,
+	eventBus *events.EventBus,
+) *AuditAssignmentService {
+	return &AuditAssignmentService{Repo: repo, AuditPlans: auditPlans, ReferenceData: referenceData, EventBus: eventBus}
+}
+
+// GetByAuditPlanID returns the team of a plan, its lead auditor followed by the support auditors
+func (s *AuditAssignmentService) GetByAuditPlanID(ctx context.Context, auditPlanID int) ([]types.AuditAssignment, error) {
+	plan, err := s.AuditPlans.GetByID(ctx, types.AuditPlan{ID: auditPlanID})
+	if err != nil {
+		return nil, err
+	}
+
+	assignments, err := s.Repo.GetByAuditPlanIDAuditAssignments(ctx, plan.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range assignments {
+		assignments[i].StatusVal = plan.StatusVal
+	}
+	return assignments, nil
+}
+
+// GetByUserID returns the plans a user leads or supports, ordered by scheduled date
+func (s *AuditAssignmentService) GetByUserID(ctx context.Context, userID int) ([]types.AuditAssignment, error) {
+	assignments, err := s.Repo.GetByUserIDAuditAssignments(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range assignments {
+		status, err := s.ReferenceData.GetByID(ctx, assignments[i].StatusVal.ID)
+		if err != nil {
+			return nil, err
+		}
+		assignments[i].StatusVal = status
+	}
+	return assignments, nil
+}
+
+// Assign adds a support auditor to a plan that is not closed yet and returns the new team
+func (s *AuditAssignmentService) Assign(ctx context.Context, auditPlanID, userID int) ([]types.AuditAssignment, error) {
+	plan, err := s.openAuditPlan(ctx, auditPlanID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Repo.CreateAuditAssignment(ctx, plan.ID, userID); err != nil {
+		return nil, err
+	}
+
+	team, err := s.GetByAuditPlanID(ctx, plan.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publish(ctx, plan, team)
+	return team, nil
+}
+
+// Unassign removes a support auditor from a plan that is not closed yet
+func (s *AuditAssignmentService) Unassign(ctx context.Context, auditPlanID, userID int) error {
+	plan, err := s.openAuditPlan(ctx, auditPlanID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Repo.DeleteAuditAssignment(ctx, plan.ID, userID); err != nil {
+		return err
+	}
+
+	team, err := s.Repo.GetByAuditPlanIDAuditAssignments(ctx, plan.ID)
+	if err != nil {
+		return err
+	}
+
+	s.publish(ctx, plan, team)
+	return nil
+}
+
+// CheckTeamMember reports a user who is neither the lead auditor nor a support auditor of a plan
+// as FORBIDDEN. Services call it before recording anything on an audit.
+func (s *AuditAssignmentService) CheckTeamMember(ctx context.Context, auditPlanID, userID int) error {
+	member, err := s.Repo.IsAuditTeamMember(ctx, auditPlanID, userID)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return custom_errors.Forbidden(ctx, fmt.Sprintf("user %d is not on the team of audit plan %d", userID, auditPlanID))
+	}
+	return nil
+}
+
+// openAuditPlan loads a plan whose team may still change
+func (s *AuditAssignmentService) openAuditPlan(ctx context.Context, auditPlanID int) (types.AuditPlan, error) {
+	plan, err := s.AuditPlans.GetByID(ctx, types.AuditPlan{ID: auditPlanID})
+	if err != nil {
+		return types.AuditPlan{}, err
+	}
+
+	if isClosedAuditPlan(plan.StatusVal.Code) {
+		return types.AuditPlan{}, custom_errors.Conflict(ctx, "Audit plan", fmt.Sprintf("is %s and its team can no longer be changed", plan.StatusVal.Code))
+	}
+	return plan, nil
+}
+
+func (s *AuditAssignmentService) publish(ctx context.Context, plan types.AuditPlan, team []types.AuditAssignment) {
+	if s.EventBus == nil {
+		return
+	}
+	s.EventBus.AsyncPublish(ctx, events.NewAuditPlanEvent(plan.ID, events.ChangeUpdated, plan.StandardID, "", team))
+}
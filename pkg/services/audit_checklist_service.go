@@ -0,0 +1,150 @@
+// Contains audit checklist business logic
+// Expands the requirement subtrees picked for an audit plan into its audit questions
+package services
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"fmt"
+	"sort"
+)
+
+type AuditChecklistService struct {
+	Repo            repositories.AuditChecklistRepositoryInterface
+	RequirementRepo repositories.RequirementRepositoryInterface
+	AuditPlans      AuditPlanServiceInterface
+	EventBus        *events.EventBus
+}
+
+// ensure AuditChecklistService implements AuditChecklistServiceInterface
+var _ AuditChecklistServiceInterface = (*AuditChecklistService)(nil)
+
+func NewAuditChecklistService(
+	repo repositories.AuditChecklistRepositoryInterface,
+	requirementRepo repositories.RequirementRepositoryInterface,
+	auditPlans AuditPlanServiceInterface,
+	eventBus *events.EventBus,
+) *AuditChecklistService {
+	return &AuditChecklistService{Repo: repo, RequirementRepo: requirementRepo, AuditPlans: auditPlans, EventBus: eventBus}
+}
+
+// GetByAuditPlanID returns the checklist of a plan in the order of the requirement tree
+func (s *AuditChecklistService) GetByAuditPlanID(ctx context.Context, auditPlanID int) (types.AuditChecklist, error) {
+	plan, err := s.AuditPlans.GetByID(ctx, types.AuditPlan{ID: auditPlanID})
+	if err != nil {
+		return types.AuditChecklist{}, err
+	}
+
+	requirements, err := s.RequirementRepo.GetByStandardIDRequirements(ctx, plan.StandardID)
+	if err != nil {
+		return types.AuditChecklist{}, err
+	}
+
+	return s.load(ctx, plan.ID, flattenRequirementTree(buildRequirementTree(requirements)))
+}
+
+// Generate sets the scope of a plan to the requirement subtrees rooted at requirementIDs and
+// creates an audit question for every question in them. When the scope changes later only the
+// difference is applied, audit questions that stay in scope keep their answers.
+func (s *AuditChecklistService) Generate(ctx context.Context, auditPlanID int, requirementIDs []int) (types.AuditChecklist, error) {
+	plan, err := s.AuditPlans.GetByID(ctx, types.AuditPlan{ID: auditPlanID})
+	if err != nil {
+		return types.AuditChecklist{}, err
+	}
+
+	if isClosedAuditPlan(plan.StatusVal.Code) {
+		return types.AuditChecklist{}, custom_errors.Conflict(ctx, "Audit plan", fmt.Sprintf("is %s and its scope can no longer be changed", plan.StatusVal.Code))
+	}
+
+	requirements, err := s.RequirementRepo.GetByStandardIDRequirements(ctx, plan.StandardID)
+	if err != nil {
+		return types.AuditChecklist{}, err
+	}
+	ordered := flattenRequirementTree(buildRequirementTree(requirements))
+
+	known := make(map[int]bool, len(ordered))
+	for _, requirement := range ordered {
+		known[requirement.ID] = true
+	}
+
+	scope := []int{}
+	selected := make(map[int]bool, len(requirementIDs))
+	for _, id := range requirementIDs {
+		if !known[id] {
+			return types.AuditChecklist{}, custom_errors.InvalidData(ctx, fmt.Sprintf("requirement %d does not belong to the standard of the audit plan", id))
+		}
+		if !selected[id] {
+			selected[id] = true
+			scope = append(scope, id)
+		}
+	}
+
+	diff, err := s.Repo.SyncAuditChecklist(ctx, plan.ID, scope, requirementSubtreeIDs(ordered, selected))
+	if err != nil {
+		return types.AuditChecklist{}, err
+	}
+
+	checklist, err := s.load(ctx, plan.ID, ordered)
+	if err != nil {
+		return types.AuditChecklist{}, err
+	}
+	checklist.Changes = &diff
+
+	if s.EventBus != nil {
+		s.EventBus.AsyncPublish(ctx, events.NewAuditPlanEvent(plan.ID, events.ChangeUpdated, plan.StandardID, "", diff))
+	}
+	return checklist, nil
+}
+
+// load reads the checklist and sorts its questions in the order of the requirement tree
+func (s *AuditChecklistService) load(ctx context.Context, auditPlanID int, ordered []types.Requirement) (types.AuditChecklist, error) {
+	checklist, err := s.Repo.GetAuditChecklist(ctx, auditPlanID)
+	if err != nil {
+		return types.AuditChecklist{}, err
+	}
+
+	sortByRequirementTree(checklist.Questions, ordered)
+	return checklist, nil
+}
+
+// sortByRequirementTree orders audit questions by the position of their requirement in ordered.
+// The repositories already order questions within a requirement, a stable sort keeps that.
+func sortByRequirementTree(questions []types.AuditQuestion, ordered []types.Requirement) {
+	position := make(map[int]int, len(ordered))
+	for i, requirement := range ordered {
+		position[requirement.ID] = i
+	}
+
+	sort.SliceStable(questions, func(i, j int) bool {
+		return position[questions[i].RequirementID] < position[questions[j].RequirementID]
+	})
+}
+
+// flattenRequirementTree lists a nested tree depth first, parents before their children
+func flattenRequirementTree(tree []types.Requirement) []types.Requirement {
+	flat := []types.Requirement{}
+	for _, requirement := range tree {
+		children := requirement.Children
+		requirement.Children = nil
+		flat = append(flat, requirement)
+		flat = append(flat, flattenRequirementTree(children)...)
+	}
+	return flat
+}
+
+// requirementSubtreeIDs returns the selected requirements and all their descendants. ordered must
+// list parents before their children, as flattenRequirementTree does.
+func requirementSubtreeIDs(ordered []types.Requirement, selected map[int]bool) []int {
+	included := make(map[int]bool, len(ordered))
+	ids := []int{}
+	for _, requirement := range ordered {
+		if selected[requirement.ID] || included[requirement.ParentID] {
+			included[requirement.ID] = true
+			ids = append(ids, requirement.ID)
+		}
+	}
+	return ids
+}
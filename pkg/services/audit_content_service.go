@@ -2,30 +2,34 @@
 package services
 
 import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
 	"ISO_Auditing_Tool/pkg/events"
 	"ISO_Auditing_Tool/pkg/repositories"
 	"ISO_Auditing_Tool/pkg/types"
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 )
 
 // AuditContentService manages modifications to audit content (requirements, questions, evidence)
 type AuditContentService struct {
-	DraftService    *DraftService
+	DraftService    DraftServiceInterface
 	RequirementRepo repositories.RequirementRepositoryInterface
 	QuestionRepo    repositories.QuestionRepositoryInterface
 	EvidenceRepo    repositories.EvidenceRepositoryInterface
+	ReferenceData   ReferenceDataServiceInterface
 	EventBus        *events.EventBus
 }
 
 // NewAuditContentService creates a new AuditContentService
 func NewAuditContentService(
-	draftService *DraftService,
+	draftService DraftServiceInterface,
 	requirementRepo repositories.RequirementRepositoryInterface,
 	questionRepo repositories.QuestionRepositoryInterface,
 	evidenceRepo repositories.EvidenceRepositoryInterface,
+	referenceData ReferenceDataServiceInterface,
 	eventBus *events.EventBus,
 ) *AuditContentService {
 	return &AuditContentService{
@@ -33,12 +37,22 @@ func NewAuditContentService(
 		RequirementRepo: requirementRepo,
 		QuestionRepo:    questionRepo,
 		EvidenceRepo:    evidenceRepo,
+		ReferenceData:   referenceData,
 		EventBus:        eventBus,
 	}
 }
 
-// ModifyRequirementDescription modifies the description of a requirement
+// ensure AuditContentService publishes AUDIT_CONTENT drafts
+var _ DraftPublisher = (*AuditContentService)(nil)
+
+// ModifyRequirementDescription proposes a new description for a requirement as an AUDIT_CONTENT
+// draft and submits it for approval with reason as comment. The requirement only changes once
+// another user approves and publishes the draft.
 func (s *AuditContentService) ModifyRequirementDescription(ctx context.Context, requirementID int, newDescription, reason string, userID int) error {
+	if strings.TrimSpace(reason) == "" {
+		return custom_errors.InvalidData(ctx, "a reason is required to change a requirement")
+	}
+
 	// 1. Get the original requirement
 	originalReq, err := s.RequirementRepo.GetByIDRequirement(ctx, types.Requirement{ID: requirementID})
 	if err != nil {
@@ -81,30 +95,33 @@ func (s *AuditContentService) ModifyRequirementDescription(ctx context.Context,
 	modification.ModifiedContent = modifiedContent
 	modification.OriginalContent = originalContent
 
-	// 5. Create draft for immediate publishing
-	draft := types.Draft{
-		TypeID:   11, // audit_content type (from reference_values)
-		ObjectID: requirementID,
-		StatusID: 12, // draft status
-		Version:  1,
-		UserID:   userID,
+	// 5. Create the draft with the modification as its data
+	draftTypeID, err := s.ReferenceData.ResolveID(ctx, RefDraftType, DraftTypeAuditContent)
+	if err != nil {
+		return fmt.Errorf("failed to resolve draft type: %w", err)
 	}
 
-	// 6. Serialize the modification as draft data
 	draftData, err := json.Marshal(modification)
 	if err != nil {
 		return fmt.Errorf("failed to marshal draft data: %w", err)
 	}
-	draft.Data = draftData
 
-	// 7. Create the draft
-	createdDraft, err := s.DraftService.Create(ctx, draft)
+	draft, err := s.DraftService.Create(ctx, types.Draft{
+		TypeID:   draftTypeID,
+		ObjectID: requirementID,
+		Version:  1,
+		Data:     draftData,
+		UserID:   userID,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to create draft: %w", err)
 	}
 
-	// 8. Immediately publish the change (admin has immediate control)
-	return s.PublishRequirementChange(ctx, createdDraft)
+	// 6. Submit it for approval by another user
+	if _, err := s.DraftService.Submit(ctx, draft.ID, types.DraftTransitionForm{Comment: reason, UserID: userID}); err != nil {
+		return fmt.Errorf("failed to submit draft: %w", err)
+	}
+	return nil
 }
 
 // GetRequirement gets a requirement (always returns current published version)
@@ -112,43 +129,35 @@ func (s *AuditContentService) GetRequirement(ctx context.Context, requirementID
 	return s.RequirementRepo.GetByIDRequirement(ctx, types.Requirement{ID: requirementID})
 }
 
-// PublishRequirementChange publishes a draft requirement change
-func (s *AuditContentService) PublishRequirementChange(ctx context.Context, draft types.Draft) error {
-	// 1. Get the draft
-	draft, err := s.DraftService.GetByID(ctx, draft)
-	if err != nil {
-		return fmt.Errorf("failed to get draft: %w", err)
-	}
-
-	// 2. Parse the modification from draft data
+// PublishDraft applies the requirement change of a published AUDIT_CONTENT draft. Only the
+// description the draft proposes is written, in the transaction that moves the draft from current
+// to next. A requirement whose description changed since the draft was made is reported as a
+// CONFLICT error, the draft then stays approved and a new one has to be made.
+func (s *AuditContentService) PublishDraft(ctx context.Context, current, next types.Draft, transition types.DraftTransition) error {
+	// 1. Parse the modification from draft data
 	var modification types.AuditContentModification
-	if err := json.Unmarshal(draft.Data, &modification); err != nil {
+	if err := json.Unmarshal(current.Data, &modification); err != nil {
 		return fmt.Errorf("failed to parse draft data: %w", err)
 	}
 
-	// 3. Parse the modified requirement
+	if modification.ContentType != "requirement" {
+		return custom_errors.InvalidData(ctx, fmt.Sprintf("changes to %s content cannot be published yet", modification.ContentType))
+	}
+
+	// 2. Parse the modified requirement
 	var modifiedReq types.RequirementModification
 	if err := json.Unmarshal(modification.ModifiedContent, &modifiedReq); err != nil {
 		return fmt.Errorf("failed to parse modified requirement: %w", err)
 	}
 
-	// 4. Atomically update requirement and delete draft
-	updatedReq := types.Requirement{
-		ID:            modifiedReq.ID,
-		StandardID:    modifiedReq.StandardID,
-		LevelID:       modifiedReq.LevelID,
-		ParentID:      modifiedReq.ParentID,
-		ReferenceCode: modifiedReq.ReferenceCode,
-		Name:          modifiedReq.Name,
-		Description:   modifiedReq.Description,
-	}
-
-	finalReq, err := s.RequirementRepo.UpdateRequirementAndDeleteDraft(ctx, updatedReq, draft)
+	// 3. Atomically change the description and publish the draft
+	finalReq, err := s.RequirementRepo.UpdateRequirementDescriptionAndPublishDraft(
+		ctx, modifiedReq.ID, modifiedReq.OriginalDescription, modifiedReq.Description, current, next, transition)
 	if err != nil {
-		return fmt.Errorf("failed to update requirement and delete draft: %w", err)
+		return err
 	}
 
-	// 5. Publish event asynchronously (after successful atomic operation)
+	// 4. Publish event asynchronously (after successful atomic operation)
 	event := events.NewEntityChangeEvent(
 		events.EntityRequirement,
 		finalReq.ID,
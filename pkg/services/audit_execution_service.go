@@ -0,0 +1,529 @@
+// Contains audit execution business logic
+// Serves the checklist of a running audit and records the evidence and comments gathered for it
+package services
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"fmt"
+	"strings"
+)
+
+type AuditExecutionService struct {
+	Repo            repositories.AuditQuestionRepositoryInterface
+	ProvidedRepo    repositories.EvidenceProvidedRepositoryInterface
+	RequirementRepo repositories.RequirementRepositoryInterface
+	AuditPlans      AuditPlanServiceInterface
+	Assignments     AuditAssignmentServiceInterface
+	Access          EvidenceAccessServiceInterface
+	ReferenceData   ReferenceDataServiceInterface
+	EventBus        *events.EventBus
+}
+
+// ensure AuditExecutionService implements AuditExecutionServiceInterface
+var _ AuditExecutionServiceInterface = (*AuditExecutionService)(nil)
+
+func NewAuditExecutionService(
+	repo repositories.AuditQuestionRepositoryInterface,
+	providedRepo repositories.EvidenceProvidedRepositoryInterface,
+	requirementRepo repositories.RequirementRepositoryInterface,
+	auditPlans AuditPlanServiceInterface,
+	assignments AuditAssignmentServiceInterface,
+	access EvidenceAccessServiceInterface,
+	referenceData ReferenceDataServiceInterface,
+	eventBus *events.EventBus,
+) *AuditExecutionService {
+	return &AuditExecutionService{
+		Repo:            repo,
+		ProvidedRepo:    providedRepo,
+		RequirementRepo: requirementRepo,
+		AuditPlans:      auditPlans,
+		Assignments:     assignments,
+		Access:          access,
+		ReferenceData:   referenceData,
+		EventBus:        eventBus,
+	}
+}
+
+// GetQuestions returns the questions of an audit in the order of the requirement tree, each with
+// its expected evidence, provided evidence and comments. Provided evidence userID may not see is
+// redacted.
+func (s *AuditExecutionService) GetQuestions(ctx context.Context, auditPlanID, userID int) ([]types.AuditQuestion, error) {
+	plan, err := s.AuditPlans.GetByID(ctx, types.AuditPlan{ID: auditPlanID})
+	if err != nil {
+		return nil, err
+	}
+
+	questions, err := s.Repo.GetByAuditIDAuditQuestions(ctx, plan.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range questions {
+		if err := s.hydrateAll(ctx, questions[i].EvidenceProvided); err != nil {
+			return nil, err
+		}
+		if err := s.Access.Redact(ctx, userID, plan.ID, questions[i].EvidenceProvided); err != nil {
+			return nil, err
+		}
+		questions[i].Satisfied = isSatisfied(questions[i])
+	}
+
+	requirements, err := s.RequirementRepo.GetByStandardIDRequirements(ctx, plan.StandardID)
+	if err != nil {
+		return nil, err
+	}
+	sortByRequirementTree(questions, flattenRequirementTree(buildRequirementTree(requirements)))
+
+	return questions, nil
+}
+
+// GetQuestion returns an audit question with everything recorded for it. Provided evidence userID
+// may not see is redacted.
+func (s *AuditExecutionService) GetQuestion(ctx context.Context, question types.AuditQuestion, userID int) (types.AuditQuestion, error) {
+	result, err := s.Repo.GetByIDAuditQuestion(ctx, question)
+	if err != nil {
+		return types.AuditQuestion{}, err
+	}
+
+	if err := s.hydrateAll(ctx, result.EvidenceProvided); err != nil {
+		return types.AuditQuestion{}, err
+	}
+	if err := s.Access.Redact(ctx, userID, result.AuditID, result.EvidenceProvided); err != nil {
+		return types.AuditQuestion{}, err
+	}
+	result.Satisfied = isSatisfied(result)
+	return result, nil
+}
+
+func (s *AuditExecutionService) GetProgress(ctx context.Context, auditPlanID int) (types.AuditProgress, error) {
+	if _, err := s.AuditPlans.GetByID(ctx, types.AuditPlan{ID: auditPlanID}); err != nil {
+		return types.AuditProgress{}, err
+	}
+
+	acceptedID, err := s.ReferenceData.ResolveID(ctx, RefEvidenceProvidedStatus, EvidenceProvidedAccepted)
+	if err != nil {
+		return types.AuditProgress{}, err
+	}
+	return s.Repo.GetAuditProgress(ctx, auditPlanID, acceptedID)
+}
+
+// ProvideEvidence records evidence for one of the expected evidence of an audit question. The
+// audit must be IN_PROGRESS, the user on its team, and new evidence always starts as PENDING review.
+func (s *AuditExecutionService) ProvideEvidence(ctx context.Context, provided types.EvidenceProvided) (types.EvidenceProvided, error) {
+	question, err := s.Repo.GetByIDAuditQuestion(ctx, types.AuditQuestion{ID: provided.AuditQuestionID})
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	if err := s.checkInProgress(ctx, question.AuditID); err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	if err := s.Assignments.CheckTeamMember(ctx, question.AuditID, provided.UserID); err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	if err := s.validateProvided(ctx, question, &provided); err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	pending, err := s.ReferenceData.Resolve(ctx, RefEvidenceProvidedStatus, EvidenceProvidedPending)
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+	provided.StatusVal = pending
+
+	created, err := s.ProvidedRepo.CreateEvidenceProvided(ctx, provided)
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	if err := s.hydrate(ctx, &created); err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	s.publish(ctx, events.NewEvidenceProvidedEvent(created.ID, events.ChangeCreated, created.AuditQuestionID, "", created))
+	return created, nil
+}
+
+// UpdateEvidence changes PENDING provided evidence of an audit that is still IN_PROGRESS. The user
+// making the change must be on the team of the audit. Reviewed evidence is replaced instead. The
+// result is redacted when the user may not see its new confidentiality.
+func (s *AuditExecutionService) UpdateEvidence(ctx context.Context, provided types.EvidenceProvided) (types.EvidenceProvided, error) {
+	existing, err := s.ProvidedRepo.GetByIDEvidenceProvided(ctx, provided)
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	status, err := s.ReferenceData.GetByID(ctx, existing.StatusVal.ID)
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+	if status.Code != EvidenceProvidedPending {
+		return types.EvidenceProvided{}, custom_errors.Conflict(ctx, "Provided evidence", fmt.Sprintf("is %s and can only be replaced", status.Code))
+	}
+
+	question, err := s.Repo.GetByIDAuditQuestion(ctx, types.AuditQuestion{ID: existing.AuditQuestionID})
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	if err := s.checkInProgress(ctx, question.AuditID); err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	if err := s.Assignments.CheckTeamMember(ctx, question.AuditID, provided.UserID); err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	if err := s.validateProvided(ctx, question, &provided); err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	existing.EvidenceID = provided.EvidenceID
+	existing.Provided = provided.Provided
+	existing.TypeVal = provided.TypeVal
+	existing.ConfidentialityVal = provided.ConfidentialityVal
+	existing.RetentionDays = provided.RetentionDays
+
+	updated, err := s.ProvidedRepo.UpdateEvidenceProvided(ctx, existing)
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	if err := s.hydrate(ctx, &updated); err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	s.publish(ctx, events.NewEvidenceProvidedEvent(updated.ID, events.ChangeUpdated, updated.AuditQuestionID, "", updated))
+	if err := s.redact(ctx, provided.UserID, question.AuditID, &updated); err != nil {
+		return types.EvidenceProvided{}, err
+	}
+	return updated, nil
+}
+
+// DeleteEvidence soft deletes provided evidence of an audit that is still IN_PROGRESS. The user
+// deleting it must be on the team of the audit.
+func (s *AuditExecutionService) DeleteEvidence(ctx context.Context, provided types.EvidenceProvided) error {
+	existing, err := s.ProvidedRepo.GetByIDEvidenceProvided(ctx, provided)
+	if err != nil {
+		return err
+	}
+
+	question, err := s.Repo.GetByIDAuditQuestion(ctx, types.AuditQuestion{ID: existing.AuditQuestionID})
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkInProgress(ctx, question.AuditID); err != nil {
+		return err
+	}
+
+	if err := s.Assignments.CheckTeamMember(ctx, question.AuditID, provided.UserID); err != nil {
+		return err
+	}
+
+	if err := s.ProvidedRepo.DeleteEvidenceProvided(ctx, existing.ID); err != nil {
+		return err
+	}
+
+	s.publish(ctx, events.NewEvidenceProvidedEvent(existing.ID, events.ChangeDeleted, existing.AuditQuestionID, "", nil))
+	return nil
+}
+
+// ReviewEvidence accepts or rejects PENDING provided evidence with the reason given. The reviewer
+// must be on the team of an audit that is not closed, able to see the evidence, so CLASSIFIED
+// evidence needs a grant, and cannot review evidence they provided.
+func (s *AuditExecutionService) ReviewEvidence(ctx context.Context, providedID int, review types.EvidenceReviewForm) (types.EvidenceProvided, error) {
+	if !isReviewDecision(review.Status) {
+		return types.EvidenceProvided{}, custom_errors.InvalidData(ctx, fmt.Sprintf("status must be %s, %s or %s", EvidenceProvidedAccepted, EvidenceProvidedPartiallyAccepted, EvidenceProvidedRejected))
+	}
+	if strings.TrimSpace(review.Reason) == "" {
+		return types.EvidenceProvided{}, custom_errors.InvalidData(ctx, "reason is required to review provided evidence")
+	}
+
+	existing, err := s.ProvidedRepo.GetByIDEvidenceProvided(ctx, types.EvidenceProvided{ID: providedID})
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	question, err := s.Repo.GetByIDAuditQuestion(ctx, types.AuditQuestion{ID: existing.AuditQuestionID})
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	plan, err := s.AuditPlans.GetByID(ctx, types.AuditPlan{ID: question.AuditID})
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+	if isClosedAuditPlan(plan.StatusVal.Code) {
+		return types.EvidenceProvided{}, custom_errors.Conflict(ctx, "Audit plan", fmt.Sprintf("is %s and its evidence can no longer be reviewed", plan.StatusVal.Code))
+	}
+
+	if err := s.Assignments.CheckTeamMember(ctx, question.AuditID, review.ReviewedBy); err != nil {
+		return types.EvidenceProvided{}, err
+	}
+	if review.ReviewedBy == existing.UserID {
+		return types.EvidenceProvided{}, custom_errors.Forbidden(ctx, fmt.Sprintf("user %d cannot review evidence they provided", review.ReviewedBy))
+	}
+	if err := s.Access.CheckAccess(ctx, review.ReviewedBy, question.AuditID, existing, "review"); err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	status, err := s.ReferenceData.GetByID(ctx, existing.StatusVal.ID)
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+	if status.Code != EvidenceProvidedPending {
+		return types.EvidenceProvided{}, custom_errors.Conflict(ctx, "Provided evidence", fmt.Sprintf("is %s and was already reviewed", status.Code))
+	}
+
+	target, err := s.ReferenceData.ResolveID(ctx, RefEvidenceProvidedStatus, review.Status)
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	decision := types.EvidenceReview{Reason: review.Reason, ReviewedBy: review.ReviewedBy}
+	if err := s.ProvidedRepo.ReviewEvidenceProvided(ctx, existing.ID, existing.StatusVal.ID, target, decision); err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	reviewed, err := s.ProvidedRepo.GetByIDEvidenceProvided(ctx, existing)
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+	if err := s.hydrate(ctx, &reviewed); err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	s.publish(ctx, events.NewEvidenceProvidedEvent(reviewed.ID, events.ChangeUpdated, reviewed.AuditQuestionID, "", reviewed))
+	return reviewed, nil
+}
+
+// ReplaceEvidence records replacement for the same expected evidence as the provided evidence it
+// replaces, which becomes SUPERSEDED and links to it. The replacement starts as PENDING review, the
+// rules of ProvideEvidence apply to it. The result is redacted when the user may not see it.
+func (s *AuditExecutionService) ReplaceEvidence(ctx context.Context, providedID int, replacement types.EvidenceProvided) (types.EvidenceProvided, error) {
+	existing, err := s.ProvidedRepo.GetByIDEvidenceProvided(ctx, types.EvidenceProvided{ID: providedID})
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+	if replacement.EvidenceID != existing.EvidenceID {
+		return types.EvidenceProvided{}, custom_errors.InvalidData(ctx, "a replacement must answer the same expected evidence")
+	}
+
+	question, err := s.Repo.GetByIDAuditQuestion(ctx, types.AuditQuestion{ID: existing.AuditQuestionID})
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	if err := s.checkInProgress(ctx, question.AuditID); err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	if err := s.Assignments.CheckTeamMember(ctx, question.AuditID, replacement.UserID); err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	status, err := s.ReferenceData.GetByID(ctx, existing.StatusVal.ID)
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+	if status.Code == EvidenceProvidedSuperseded || status.Code == EvidenceProvidedExpired {
+		return types.EvidenceProvided{}, custom_errors.Conflict(ctx, "Provided evidence", fmt.Sprintf("is %s and can no longer be replaced", status.Code))
+	}
+
+	if err := s.validateProvided(ctx, question, &replacement); err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	pending, err := s.ReferenceData.Resolve(ctx, RefEvidenceProvidedStatus, EvidenceProvidedPending)
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+	replacement.StatusVal = pending
+
+	supersededID, err := s.ReferenceData.ResolveID(ctx, RefEvidenceProvidedStatus, EvidenceProvidedSuperseded)
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	created, err := s.ProvidedRepo.SupersedeEvidenceProvided(ctx, existing, replacement, supersededID)
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+	if err := s.hydrate(ctx, &created); err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	s.publish(ctx, events.NewEvidenceProvidedEvent(existing.ID, events.ChangeUpdated, existing.AuditQuestionID, "", nil))
+	s.publish(ctx, events.NewEvidenceProvidedEvent(created.ID, events.ChangeCreated, created.AuditQuestionID, "", created))
+	if err := s.redact(ctx, replacement.UserID, question.AuditID, &created); err != nil {
+		return types.EvidenceProvided{}, err
+	}
+	return created, nil
+}
+
+// GetEvidenceChain returns the provided evidence that replaced one another, from the first
+// submission to the current one. Evidence userID may not see is redacted.
+func (s *AuditExecutionService) GetEvidenceChain(ctx context.Context, providedID, userID int) ([]types.EvidenceProvided, error) {
+	existing, err := s.ProvidedRepo.GetByIDEvidenceProvided(ctx, types.EvidenceProvided{ID: providedID})
+	if err != nil {
+		return nil, err
+	}
+
+	question, err := s.Repo.GetByIDAuditQuestion(ctx, types.AuditQuestion{ID: existing.AuditQuestionID})
+	if err != nil {
+		return nil, err
+	}
+
+	chain := supersessionChain(question.EvidenceProvided, existing.ID)
+	if err := s.hydrateAll(ctx, chain); err != nil {
+		return nil, err
+	}
+	if err := s.Access.Redact(ctx, userID, question.AuditID, chain); err != nil {
+		return nil, err
+	}
+	return chain, nil
+}
+
+// checkInProgress rejects recording evidence for an audit that is not being carried out
+func (s *AuditExecutionService) checkInProgress(ctx context.Context, auditPlanID int) error {
+	plan, err := s.AuditPlans.GetByID(ctx, types.AuditPlan{ID: auditPlanID})
+	if err != nil {
+		return err
+	}
+	if plan.StatusVal.Code != AuditPlanInProgress {
+		return custom_errors.Conflict(ctx, "Audit plan", fmt.Sprintf("is %s, evidence can only be recorded while it is IN_PROGRESS", plan.StatusVal.Code))
+	}
+	return nil
+}
+
+// validateProvided checks that the evidence answers the audit question and that its type and
+// confidentiality are active values of their reference types, then sets them on provided
+func (s *AuditExecutionService) validateProvided(ctx context.Context, question types.AuditQuestion, provided *types.EvidenceProvided) error {
+	expected := false
+	for _, evidence := range question.Evidence {
+		if evidence.ID == provided.EvidenceID {
+			expected = true
+			break
+		}
+	}
+	if !expected {
+		return custom_errors.InvalidData(ctx, "evidence_id is not an expected evidence of the audit question")
+	}
+
+	if provided.RetentionDays <= 0 {
+		return custom_errors.InvalidData(ctx, "retention_days must be positive")
+	}
+
+	evidenceType, err := s.ReferenceData.Validate(ctx, RefEvidenceProvidedType, provided.TypeVal.ID)
+	if err != nil {
+		return err
+	}
+	confidentiality, err := s.ReferenceData.Validate(ctx, RefEvidenceProvidedConfidentiality, provided.ConfidentialityVal.ID)
+	if err != nil {
+		return err
+	}
+
+	provided.AuditQuestionID = question.ID
+	provided.TypeVal = evidenceType
+	provided.ConfidentialityVal = confidentiality
+	return nil
+}
+
+// redact withholds the content of one provided evidence userID may not see, like the lists
+// returned by GetQuestion
+func (s *AuditExecutionService) redact(ctx context.Context, userID, auditPlanID int, provided *types.EvidenceProvided) error {
+	redacted := []types.EvidenceProvided{*provided}
+	if err := s.Access.Redact(ctx, userID, auditPlanID, redacted); err != nil {
+		return err
+	}
+	*provided = redacted[0]
+	return nil
+}
+
+func (s *AuditExecutionService) hydrateAll(ctx context.Context, provided []types.EvidenceProvided) error {
+	for i := range provided {
+		if err := s.hydrate(ctx, &provided[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hydrate replaces the reference IDs loaded by the repository with their reference values
+func (s *AuditExecutionService) hydrate(ctx context.Context, provided *types.EvidenceProvided) error {
+	for _, value := range []*types.ReferenceValue{&provided.TypeVal, &provided.ConfidentialityVal, &provided.StatusVal} {
+		resolved, err := s.ReferenceData.GetByID(ctx, value.ID)
+		if err != nil {
+			return err
+		}
+		*value = resolved
+	}
+	return nil
+}
+
+// isSatisfied reports whether every expected evidence of a hydrated question has an ACCEPTED
+// submission. Questions without expected evidence have nothing to accept and are never satisfied.
+func isSatisfied(question types.AuditQuestion) bool {
+	if len(question.Evidence) == 0 {
+		return false
+	}
+
+	accepted := make(map[int]bool)
+	for _, provided := range question.EvidenceProvided {
+		if provided.StatusVal.Code == EvidenceProvidedAccepted {
+			accepted[provided.EvidenceID] = true
+		}
+	}
+	for _, evidence := range question.Evidence {
+		if !accepted[evidence.ID] {
+			return false
+		}
+	}
+	return true
+}
+
+func isReviewDecision(statusCode string) bool {
+	return statusCode == EvidenceProvidedAccepted || statusCode == EvidenceProvidedPartiallyAccepted || statusCode == EvidenceProvidedRejected
+}
+
+// supersessionChain follows the superseded_by links through the provided evidence of a question
+// from the first submission to the last, passing through providedID
+func supersessionChain(provided []types.EvidenceProvided, providedID int) []types.EvidenceProvided {
+	byID := make(map[int]types.EvidenceProvided, len(provided))
+	replaces := make(map[int]int, len(provided))
+	for _, item := range provided {
+		byID[item.ID] = item
+		if item.SupersededBy != 0 {
+			replaces[item.SupersededBy] = item.ID
+		}
+	}
+
+	first := providedID
+	for seen := 0; replaces[first] != 0 && seen < len(provided); seen++ {
+		first = replaces[first]
+	}
+
+	chain := []types.EvidenceProvided{}
+	for id := first; id != 0 && len(chain) <= len(provided); id = byID[id].SupersededBy {
+		item, ok := byID[id]
+		if !ok {
+			break
+		}
+		chain = append(chain, item)
+	}
+	return chain
+}
+
+func (s *AuditExecutionService) publish(ctx context.Context, event events.Event) {
+	if s.EventBus == nil {
+		return
+	}
+	s.EventBus.AsyncPublish(ctx, event)
+}
@@ -0,0 +1,244 @@
+// Contains audit plan business logic
+// Validates plans against the standards and reference data and enforces the status lifecycle
+package services
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"fmt"
+)
+
+type AuditPlanService struct {
+	Repo          repositories.AuditPlanRepositoryInterface
+	StandardRepo  repositories.StandardRepositoryInterface
+	ReferenceData ReferenceDataServiceInterface
+	EventBus      *events.EventBus
+}
+
+// ensure AuditPlanService implements AuditPlanServiceInterface
+var _ AuditPlanServiceInterface = (*AuditPlanService)(nil)
+
+// auditPlanTransitions lists the statuses a plan may move to from each status of the seeded
+// lifecycle DRAFT -> SCHEDULED -> IN_PROGRESS -> REVIEW -> COMPLETED. Any open plan can be
+// cancelled, COMPLETED and CANCELLED are final.
+var auditPlanTransitions = map[string][]string{
+	AuditPlanDraft:      {AuditPlanScheduled, AuditPlanCancelled},
+	AuditPlanScheduled:  {AuditPlanInProgress, AuditPlanCancelled},
+	AuditPlanInProgress: {AuditPlanReview, AuditPlanCancelled},
+	AuditPlanReview:     {AuditPlanCompleted, AuditPlanCancelled},
+}
+
+func NewAuditPlanService(
+	repo repositories.AuditPlanRepositoryInterface,
+	standardRepo repositories.StandardRepositoryInterface,
+	referenceData ReferenceDataServiceInterface,
+	eventBus *events.EventBus,
+) *AuditPlanService {
+	return &AuditPlanService{Repo: repo, StandardRepo: standardRepo, ReferenceData: referenceData, EventBus: eventBus}
+}
+
+// GetAll returns the plans matching the filter. Status and type codes are resolved to their
+// reference values first, unknown codes are reported as INVALID_DATA.
+func (s *AuditPlanService) GetAll(ctx context.Context, filter types.AuditPlanFilter) ([]types.AuditPlan, error) {
+	var err error
+	if filter.Status != "" {
+		if filter.StatusID, err = resolveCode(ctx, s.ReferenceData, RefAuditPlanStatus, filter.Status); err != nil {
+			return nil, err
+		}
+	}
+	if filter.Type != "" {
+		if filter.TypeID, err = resolveCode(ctx, s.ReferenceData, RefAuditPlanType, filter.Type); err != nil {
+			return nil, err
+		}
+	}
+
+	plans, err := s.Repo.GetAllAuditPlans(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range plans {
+		if err := s.hydrate(ctx, &plans[i]); err != nil {
+			return nil, err
+		}
+	}
+	return plans, nil
+}
+
+func (s *AuditPlanService) GetByID(ctx context.Context, plan types.AuditPlan) (types.AuditPlan, error) {
+	result, err := s.Repo.GetByIDAuditPlan(ctx, plan)
+	if err != nil {
+		return types.AuditPlan{}, err
+	}
+
+	if err := s.hydrate(ctx, &result); err != nil {
+		return types.AuditPlan{}, err
+	}
+	return result, nil
+}
+
+// Create adds a plan in the DRAFT status
+func (s *AuditPlanService) Create(ctx context.Context, plan types.AuditPlan) (types.AuditPlan, error) {
+	if err := s.validate(ctx, plan); err != nil {
+		return types.AuditPlan{}, err
+	}
+
+	draft, err := s.ReferenceData.Resolve(ctx, RefAuditPlanStatus, AuditPlanDraft)
+	if err != nil {
+		return types.AuditPlan{}, err
+	}
+	plan.StatusVal = draft
+
+	created, err := s.Repo.CreateAuditPlan(ctx, plan)
+	if err != nil {
+		return types.AuditPlan{}, err
+	}
+
+	if err := s.hydrate(ctx, &created); err != nil {
+		return types.AuditPlan{}, err
+	}
+
+	s.publish(ctx, created.ID, created.StandardID, events.ChangeCreated, created)
+	return created, nil
+}
+
+// Update changes the details of a plan that is not closed yet, the status is left untouched
+func (s *AuditPlanService) Update(ctx context.Context, plan types.AuditPlan) (types.AuditPlan, error) {
+	existing, err := s.GetByID(ctx, plan)
+	if err != nil {
+		return types.AuditPlan{}, err
+	}
+
+	if isClosedAuditPlan(existing.StatusVal.Code) {
+		return types.AuditPlan{}, custom_errors.Conflict(ctx, "Audit plan", fmt.Sprintf("is %s and can no longer be changed", existing.StatusVal.Code))
+	}
+
+	if err := s.validate(ctx, plan); err != nil {
+		return types.AuditPlan{}, err
+	}
+
+	existing.StandardID = plan.StandardID
+	existing.LeadAuditorID = plan.LeadAuditorID
+	existing.Name = plan.Name
+	existing.ScheduledDate = plan.ScheduledDate
+	existing.Team = plan.Team
+	existing.Scope = plan.Scope
+	existing.TypeVal = plan.TypeVal
+
+	updated, err := s.Repo.UpdateAuditPlan(ctx, existing)
+	if err != nil {
+		return types.AuditPlan{}, err
+	}
+
+	if err := s.hydrate(ctx, &updated); err != nil {
+		return types.AuditPlan{}, err
+	}
+
+	s.publish(ctx, updated.ID, updated.StandardID, events.ChangeUpdated, updated)
+	return updated, nil
+}
+
+// ChangeStatus moves a plan to the status with the given code. Transitions outside the lifecycle
+// are rejected with a CONFLICT error.
+func (s *AuditPlanService) ChangeStatus(ctx context.Context, plan types.AuditPlan, statusCode string) (types.AuditPlan, error) {
+	existing, err := s.GetByID(ctx, plan)
+	if err != nil {
+		return types.AuditPlan{}, err
+	}
+
+	target, err := s.ReferenceData.Resolve(ctx, RefAuditPlanStatus, statusCode)
+	if custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound) {
+		return types.AuditPlan{}, custom_errors.InvalidData(ctx, fmt.Sprintf("%s is not a valid %s", statusCode, RefAuditPlanStatus))
+	}
+	if err != nil {
+		return types.AuditPlan{}, err
+	}
+
+	if !canTransitionAuditPlan(existing.StatusVal.Code, target.Code) {
+		return types.AuditPlan{}, custom_errors.Conflict(ctx, "Audit plan", fmt.Sprintf("cannot move from %s to %s", existing.StatusVal.Code, target.Code))
+	}
+
+	if err := s.Repo.UpdateAuditPlanStatus(ctx, existing.ID, existing.StatusVal.ID, target.ID); err != nil {
+		return types.AuditPlan{}, err
+	}
+
+	updated, err := s.GetByID(ctx, existing)
+	if err != nil {
+		return types.AuditPlan{}, err
+	}
+
+	s.publish(ctx, updated.ID, updated.StandardID, events.ChangeUpdated, updated)
+	return updated, nil
+}
+
+// Delete soft deletes a plan. Only plans that never started, DRAFT or CANCELLED, can be deleted.
+func (s *AuditPlanService) Delete(ctx context.Context, plan types.AuditPlan) error {
+	existing, err := s.GetByID(ctx, plan)
+	if err != nil {
+		return err
+	}
+
+	if existing.StatusVal.Code != AuditPlanDraft && existing.StatusVal.Code != AuditPlanCancelled {
+		return custom_errors.Conflict(ctx, "Audit plan", fmt.Sprintf("is %s, only DRAFT or CANCELLED plans can be deleted", existing.StatusVal.Code))
+	}
+
+	if err := s.Repo.DeleteAuditPlan(ctx, existing.ID); err != nil {
+		return err
+	}
+
+	s.publish(ctx, existing.ID, existing.StandardID, events.ChangeDeleted, nil)
+	return nil
+}
+
+// validate checks the references of a plan coming from a request body
+func (s *AuditPlanService) validate(ctx context.Context, plan types.AuditPlan) error {
+	_, err := s.StandardRepo.GetByIDStandard(ctx, types.Standard{ID: plan.StandardID})
+	if custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound) {
+		return custom_errors.InvalidData(ctx, "standard_id is not an existing standard")
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = s.ReferenceData.Validate(ctx, RefAuditPlanType, plan.TypeVal.ID)
+	return err
+}
+
+// hydrate replaces the status and type IDs loaded by the repository with their reference values
+func (s *AuditPlanService) hydrate(ctx context.Context, plan *types.AuditPlan) error {
+	status, err := s.ReferenceData.GetByID(ctx, plan.StatusVal.ID)
+	if err != nil {
+		return err
+	}
+	auditType, err := s.ReferenceData.GetByID(ctx, plan.TypeVal.ID)
+	if err != nil {
+		return err
+	}
+
+	plan.StatusVal = status
+	plan.TypeVal = auditType
+	return nil
+}
+
+func (s *AuditPlanService) publish(ctx context.Context, planID, standardID int, changeType events.ChangeType, data any) {
+	if s.EventBus == nil {
+		return
+	}
+	s.EventBus.AsyncPublish(ctx, events.NewAuditPlanEvent(planID, changeType, standardID, "", data))
+}
+
+func canTransitionAuditPlan(from, to string) bool {
+	for _, allowed := range auditPlanTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+func isClosedAuditPlan(statusCode string) bool {
+	return statusCode == AuditPlanCompleted || statusCode == AuditPlanCancelled
+}
@@ -0,0 +1,214 @@
+// Contains local password authentication business logic
+// Checks bcrypt passwords, opens and ends cookie sessions and resolves a session back to its user
+package services
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// dummyPasswordHash is compared against when the email is unknown, so a failed login takes as long
+// whether the account exists or not
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("not the password of anyone"), bcrypt.DefaultCost)
+
+type AuthService struct {
+	Users         repositories.UserRepositoryInterface
+	Sessions      repositories.SessionRepositoryInterface
+	ReferenceData ReferenceDataServiceInterface
+	SessionTTL    time.Duration
+	Now           Clock
+	EventBus      *events.EventBus
+}
+
+// ensure AuthService implements AuthServiceInterface
+var _ AuthServiceInterface = (*AuthService)(nil)
+
+func NewAuthService(
+	users repositories.UserRepositoryInterface,
+	sessions repositories.SessionRepositoryInterface,
+	referenceData ReferenceDataServiceInterface,
+	sessionTTL time.Duration,
+	now Clock,
+	eventBus *events.EventBus,
+) *AuthService {
+	if now == nil {
+		now = time.Now
+	}
+	return &AuthService{
+		Users:         users,
+		Sessions:      sessions,
+		ReferenceData: referenceData,
+		SessionTTL:    sessionTTL,
+		Now:           now,
+		EventBus:      eventBus,
+	}
+}
+
+// Login checks the password of an active user and opens a session for them. Unknown emails,
+// inactive users, users without a password and wrong passwords all fail the same way.
+func (s *AuthService) Login(ctx context.Context, email, password string) (types.Session, error) {
+	user, hash, err := s.Users.GetCredentialsUser(ctx, strings.TrimSpace(email))
+	if err != nil && !custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound) {
+		return types.Session{}, err
+	}
+	if err != nil || hash == "" {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return types.Session{}, custom_errors.Unauthorized(ctx, "invalid email or password")
+	}
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		return types.Session{}, custom_errors.Unauthorized(ctx, "invalid email or password")
+	}
+
+	return s.StartSession(ctx, user)
+}
+
+// StartSession opens a session for an active user whose identity was already checked, by their
+// password or by the single sign-on provider
+func (s *AuthService) StartSession(ctx context.Context, user types.User) (types.Session, error) {
+	now := s.Now()
+	if err := s.Sessions.DeleteExpiredSessions(ctx, now); err != nil {
+		return types.Session{}, err
+	}
+
+	token, err := newSessionToken()
+	if err != nil {
+		return types.Session{}, err
+	}
+	expiresAt := now.Add(s.SessionTTL)
+	if err := s.Sessions.CreateSession(ctx, user.ID, hashSessionToken(token), expiresAt); err != nil {
+		return types.Session{}, err
+	}
+
+	if err := s.Users.UpdateUserLastLogin(ctx, user.ID); err != nil {
+		return types.Session{}, err
+	}
+	user.LastLoginAt = &now
+
+	if err := s.hydrate(ctx, &user); err != nil {
+		return types.Session{}, err
+	}
+
+	s.publish(ctx, user, user)
+	return types.Session{Token: token, User: user, ExpiresAt: expiresAt}, nil
+}
+
+// Authenticate returns the active user of a session that has not expired
+func (s *AuthService) Authenticate(ctx context.Context, token string) (types.User, error) {
+	if token == "" {
+		return types.User{}, custom_errors.Unauthorized(ctx, "sign in required")
+	}
+
+	user, err := s.Sessions.GetUserBySessionToken(ctx, hashSessionToken(token), s.Now())
+	if custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound) {
+		return types.User{}, custom_errors.Unauthorized(ctx, "the session has expired")
+	}
+	if err != nil {
+		return types.User{}, err
+	}
+
+	if err := s.hydrate(ctx, &user); err != nil {
+		return types.User{}, err
+	}
+	return user, nil
+}
+
+// Logout ends the session of token, ending a session that is already gone is not an error
+func (s *AuthService) Logout(ctx context.Context, token string) error {
+	if token == "" {
+		return nil
+	}
+	return s.Sessions.DeleteSession(ctx, hashSessionToken(token))
+}
+
+// SetPassword replaces the password of a user and ends all their sessions
+func (s *AuthService) SetPassword(ctx context.Context, userID int, password string) error {
+	hash, err := hashPassword(ctx, password)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Users.UpdateUserPassword(ctx, userID, hash); err != nil {
+		return err
+	}
+	return s.Sessions.DeleteByUserIDSessions(ctx, userID)
+}
+
+// Bootstrap lets an administrator sign in to a fresh installation. An ADMIN user with the given
+// email is created when there is no active user with it, and given the password unless they
+// already have one.
+func (s *AuthService) Bootstrap(ctx context.Context, email, password string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+	user, hash, err := s.Users.GetCredentialsUser(ctx, email)
+	if custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound) {
+		roleID, err := s.ReferenceData.ResolveID(ctx, RefUserRole, UserRoleAdmin)
+		if err != nil {
+			return err
+		}
+		name, _, _ := strings.Cut(email, "@")
+		user, err = s.Users.CreateUser(ctx, types.User{Email: email, Name: name, RoleVal: types.ReferenceValue{ID: roleID}})
+		if err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+	if hash != "" {
+		return nil
+	}
+	return s.SetPassword(ctx, user.ID, password)
+}
+
+// hydrate replaces the role ID set by the repository with the full reference value
+func (s *AuthService) hydrate(ctx context.Context, user *types.User) error {
+	role, err := s.ReferenceData.GetByID(ctx, user.RoleVal.ID)
+	if err != nil {
+		return err
+	}
+	user.RoleVal = role
+	return nil
+}
+
+func (s *AuthService) publish(ctx context.Context, user types.User, data any) {
+	if s.EventBus == nil {
+		return
+	}
+	s.EventBus.AsyncPublish(ctx, events.NewUserEvent(user.ID, events.ChangeUpdated, user.RoleVal.ID, "", data))
+}
+
+// hashPassword returns the bcrypt hash of a password. bcrypt rejects passwords over 72 bytes.
+func hashPassword(ctx context.Context, password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err == bcrypt.ErrPasswordTooLong {
+		return "", custom_errors.MaxFieldCharacters(ctx, "password", 72)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// newSessionToken returns 32 random bytes, URL safe so they fit in a cookie
+func newSessionToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("failed to generate session token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+func hashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
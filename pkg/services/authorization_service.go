@@ -0,0 +1,153 @@
+// Contains authorization business logic
+// Checks the users.role_id role of the signed in user and whether they work on a given audit
+package services
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"fmt"
+	"slices"
+)
+
+// RequireRole reports an anonymous caller as UNAUTHORIZED and a signed in user whose role is not
+// one of roles as FORBIDDEN
+func RequireRole(ctx context.Context, roles ...string) error {
+	user, ok := CurrentUser(ctx)
+	if !ok {
+		return custom_errors.Unauthorized(ctx, "sign in required")
+	}
+	if !slices.Contains(roles, user.RoleVal.Code) {
+		return custom_errors.Forbidden(ctx, fmt.Sprintf("the %s role cannot do this", user.RoleVal.Code))
+	}
+	return nil
+}
+
+type AuthorizationService struct {
+	Assignments    repositories.AuditAssignmentRepositoryInterface
+	AuditQuestions repositories.AuditQuestionRepositoryInterface
+	Comments       repositories.CommentRepositoryInterface
+	Findings       repositories.FindingRepositoryInterface
+	Actions        repositories.CorrectiveActionRepositoryInterface
+	Evidence       repositories.EvidenceProvidedRepositoryInterface
+}
+
+// ensure AuthorizationService implements AuthorizationServiceInterface
+var _ AuthorizationServiceInterface = (*AuthorizationService)(nil)
+
+func NewAuthorizationService(
+	assignments repositories.AuditAssignmentRepositoryInterface,
+	auditQuestions repositories.AuditQuestionRepositoryInterface,
+	comments repositories.CommentRepositoryInterface,
+	findings repositories.FindingRepositoryInterface,
+	actions repositories.CorrectiveActionRepositoryInterface,
+	evidence repositories.EvidenceProvidedRepositoryInterface,
+) *AuthorizationService {
+	return &AuthorizationService{
+		Assignments:    assignments,
+		AuditQuestions: auditQuestions,
+		Comments:       comments,
+		Findings:       findings,
+		Actions:        actions,
+		Evidence:       evidence,
+	}
+}
+
+// IsOnAudit reports whether the signed in user is the lead auditor or a support auditor of a plan
+func (s *AuthorizationService) IsOnAudit(ctx context.Context, auditPlanID int) (bool, error) {
+	user, ok := CurrentUser(ctx)
+	if !ok {
+		return false, nil
+	}
+	return s.Assignments.IsAuditTeamMember(ctx, auditPlanID, user.ID)
+}
+
+// CheckAuditAccess lets administrators and the team of a plan manage it. Anyone else is reported
+// as FORBIDDEN, anonymous callers as UNAUTHORIZED.
+func (s *AuthorizationService) CheckAuditAccess(ctx context.Context, auditPlanID int) error {
+	user, ok := CurrentUser(ctx)
+	if !ok {
+		return custom_errors.Unauthorized(ctx, "sign in required")
+	}
+	if user.RoleVal.Code == UserRoleAdmin {
+		return nil
+	}
+
+	member, err := s.IsOnAudit(ctx, auditPlanID)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return custom_errors.Forbidden(ctx, fmt.Sprintf("user %d is not on the team of audit plan %d", user.ID, auditPlanID))
+	}
+	return nil
+}
+
+// CheckAuditParticipation lets those who take part in the discussion and follow up of a plan
+// through: administrators, its team and clients responsible for one of its findings or corrective
+// actions. Anyone else is reported as FORBIDDEN, anonymous callers as UNAUTHORIZED.
+func (s *AuthorizationService) CheckAuditParticipation(ctx context.Context, auditPlanID int) error {
+	err := s.CheckAuditAccess(ctx, auditPlanID)
+	if !custom_errors.IsErrorCode(err, custom_errors.ErrCodeForbidden) {
+		return err
+	}
+
+	user, _ := CurrentUser(ctx)
+	if user.RoleVal.Code != UserRoleClient {
+		return err
+	}
+	owner, ownerErr := s.Assignments.IsAuditFollowUpOwner(ctx, auditPlanID, user.ID)
+	if ownerErr != nil {
+		return ownerErr
+	}
+	if !owner {
+		return custom_errors.Forbidden(ctx, fmt.Sprintf("user %d does not take part in audit plan %d", user.ID, auditPlanID))
+	}
+	return nil
+}
+
+// AuditPlanOfQuestion returns the audit plan an audit question belongs to
+func (s *AuthorizationService) AuditPlanOfQuestion(ctx context.Context, auditQuestionID int) (int, error) {
+	question, err := s.AuditQuestions.GetByIDAuditQuestion(ctx, types.AuditQuestion{ID: auditQuestionID})
+	if err != nil {
+		return 0, err
+	}
+	return question.AuditID, nil
+}
+
+// AuditPlanOfComment returns the audit plan of the audit question a comment was made on
+func (s *AuthorizationService) AuditPlanOfComment(ctx context.Context, commentID int) (int, error) {
+	comment, err := s.Comments.GetByIDComment(ctx, types.Comment{ID: commentID})
+	if err != nil {
+		return 0, err
+	}
+	return s.AuditPlanOfQuestion(ctx, comment.AuditQuestionID)
+}
+
+// AuditPlanOfFinding returns the audit plan a finding was raised in
+func (s *AuthorizationService) AuditPlanOfFinding(ctx context.Context, findingID int) (int, error) {
+	finding, err := s.Findings.GetByIDFinding(ctx, types.Finding{ID: findingID})
+	if err != nil {
+		return 0, err
+	}
+	return finding.AuditID, nil
+}
+
+// AuditPlanOfCorrectiveAction returns the audit plan of the finding a corrective action belongs to
+func (s *AuthorizationService) AuditPlanOfCorrectiveAction(ctx context.Context, actionID int) (int, error) {
+	action, err := s.Actions.GetByIDCorrectiveAction(ctx, types.CorrectiveAction{ID: actionID})
+	if err != nil {
+		return 0, err
+	}
+	return s.AuditPlanOfFinding(ctx, action.FindingID)
+}
+
+// AuditPlanOfEvidence returns the audit plan of the audit question evidence was provided for
+func (s *AuthorizationService) AuditPlanOfEvidence(ctx context.Context, evidenceProvidedID int) (int, error) {
+	provided, err := s.Evidence.GetByIDEvidenceProvided(ctx, types.EvidenceProvided{ID: evidenceProvidedID})
+	if err != nil {
+		return 0, err
+	}
+	return s.AuditPlanOfQuestion(ctx, provided.AuditQuestionID)
+}
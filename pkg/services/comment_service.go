@@ -0,0 +1,272 @@
+// Contains audit question comment business logic
+// Threads replies under the comment they answer, resolves @ mentions to users and keeps the text a
+// comment had before each edit
+package services
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// mentionPattern matches @handle where the handle is an email address or the part before its @.
+// The @ must not follow a word character, so email addresses in the text are not mentions.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w.@])@([A-Za-z0-9._%+-]+(?:@[A-Za-z0-9.-]+\.[A-Za-z]+)?)`)
+
+type CommentService struct {
+	Repo           repositories.CommentRepositoryInterface
+	AuditQuestions repositories.AuditQuestionRepositoryInterface
+	AuditPlans     AuditPlanServiceInterface
+	EventBus       *events.EventBus
+}
+
+// ensure CommentService implements CommentServiceInterface
+var _ CommentServiceInterface = (*CommentService)(nil)
+
+func NewCommentService(
+	repo repositories.CommentRepositoryInterface,
+	auditQuestions repositories.AuditQuestionRepositoryInterface,
+	auditPlans AuditPlanServiceInterface,
+	eventBus *events.EventBus,
+) *CommentService {
+	return &CommentService{
+		Repo:           repo,
+		AuditQuestions: auditQuestions,
+		AuditPlans:     auditPlans,
+		EventBus:       eventBus,
+	}
+}
+
+// GetThread returns the comments of an audit question that start a thread, oldest first, with
+// their replies nested below them
+func (s *CommentService) GetThread(ctx context.Context, auditQuestionID int) ([]types.Comment, error) {
+	if _, err := s.AuditQuestions.GetByIDAuditQuestion(ctx, types.AuditQuestion{ID: auditQuestionID}); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.Repo.GetByAuditQuestionIDComments(ctx, auditQuestionID)
+	if err != nil {
+		return nil, err
+	}
+	return buildThread(comments), nil
+}
+
+// Add attaches a comment to an audit question of an audit that is not closed yet. A reply must
+// answer a comment of the same audit question that is not deleted.
+func (s *CommentService) Add(ctx context.Context, auditQuestionID int, comment types.Comment) (types.Comment, error) {
+	question, err := s.AuditQuestions.GetByIDAuditQuestion(ctx, types.AuditQuestion{ID: auditQuestionID})
+	if err != nil {
+		return types.Comment{}, err
+	}
+
+	if err := s.checkOpen(ctx, question.AuditID); err != nil {
+		return types.Comment{}, err
+	}
+
+	if comment.ParentID != 0 {
+		parent, err := s.Repo.GetByIDComment(ctx, types.Comment{ID: comment.ParentID})
+		if custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound) {
+			return types.Comment{}, custom_errors.InvalidData(ctx, "parent_id is not a comment")
+		}
+		if err != nil {
+			return types.Comment{}, err
+		}
+		if parent.AuditQuestionID != question.ID || parent.Deleted {
+			return types.Comment{}, custom_errors.InvalidData(ctx, "parent_id must be a comment of the same audit question that is not deleted")
+		}
+	}
+
+	mentionIDs, err := s.mentions(ctx, comment.Text)
+	if err != nil {
+		return types.Comment{}, err
+	}
+
+	created, err := s.Repo.CreateAuditQuestionComment(ctx, question.ID, comment, mentionIDs)
+	if err != nil {
+		return types.Comment{}, err
+	}
+
+	s.publish(ctx, events.NewCommentEvent(created.ID, events.ChangeCreated, question.ID, "", created))
+	return created, nil
+}
+
+// Edit replaces the text of a comment on behalf of its author, the previous text is kept as a
+// revision and mentions are resolved again
+func (s *CommentService) Edit(ctx context.Context, commentID int, form types.CommentEditForm) (types.Comment, error) {
+	existing, err := s.editable(ctx, commentID, form.UserID)
+	if err != nil {
+		return types.Comment{}, err
+	}
+	if existing.Text == form.Text {
+		return existing, nil
+	}
+
+	mentionIDs, err := s.mentions(ctx, form.Text)
+	if err != nil {
+		return types.Comment{}, err
+	}
+
+	existing.Text = form.Text
+	updated, err := s.Repo.UpdateComment(ctx, existing, form.UserID, mentionIDs)
+	if err != nil {
+		return types.Comment{}, err
+	}
+
+	s.publish(ctx, events.NewCommentEvent(updated.ID, events.ChangeUpdated, updated.AuditQuestionID, "", updated))
+	return updated, nil
+}
+
+// Delete soft deletes a comment on behalf of its author and returns it as it is shown from now
+// on. Replies stay in the thread.
+func (s *CommentService) Delete(ctx context.Context, commentID, userID int) (types.Comment, error) {
+	existing, err := s.editable(ctx, commentID, userID)
+	if err != nil {
+		return types.Comment{}, err
+	}
+
+	if err := s.Repo.DeleteComment(ctx, existing.ID); err != nil {
+		return types.Comment{}, err
+	}
+
+	s.publish(ctx, events.NewCommentEvent(existing.ID, events.ChangeDeleted, existing.AuditQuestionID, "", nil))
+
+	existing.Text = ""
+	existing.Mentions = nil
+	existing.Deleted = true
+	return existing, nil
+}
+
+// GetRevisions returns the texts a comment had before it was edited, oldest first. The history of
+// a deleted comment is not shown.
+func (s *CommentService) GetRevisions(ctx context.Context, commentID int) ([]types.CommentRevision, error) {
+	comment, err := s.Repo.GetByIDComment(ctx, types.Comment{ID: commentID})
+	if err != nil {
+		return nil, err
+	}
+	if comment.Deleted {
+		return nil, custom_errors.NotFound(ctx, "Comment")
+	}
+	return s.Repo.GetRevisionsComment(ctx, comment.ID)
+}
+
+// editable loads a comment that userID may change: it is not deleted, userID wrote it and its
+// audit is not closed
+func (s *CommentService) editable(ctx context.Context, commentID, userID int) (types.Comment, error) {
+	comment, err := s.Repo.GetByIDComment(ctx, types.Comment{ID: commentID})
+	if err != nil {
+		return types.Comment{}, err
+	}
+	if comment.Deleted {
+		return types.Comment{}, custom_errors.NotFound(ctx, "Comment")
+	}
+	if comment.UserID != userID {
+		return types.Comment{}, custom_errors.Forbidden(ctx, fmt.Sprintf("user %d can only change their own comments", userID))
+	}
+
+	question, err := s.AuditQuestions.GetByIDAuditQuestion(ctx, types.AuditQuestion{ID: comment.AuditQuestionID})
+	if err != nil {
+		return types.Comment{}, err
+	}
+	if err := s.checkOpen(ctx, question.AuditID); err != nil {
+		return types.Comment{}, err
+	}
+	return comment, nil
+}
+
+// checkOpen rejects comments on an audit that is COMPLETED or CANCELLED
+func (s *CommentService) checkOpen(ctx context.Context, auditPlanID int) error {
+	plan, err := s.AuditPlans.GetByID(ctx, types.AuditPlan{ID: auditPlanID})
+	if err != nil {
+		return err
+	}
+	if isClosedAuditPlan(plan.StatusVal.Code) {
+		return custom_errors.Conflict(ctx, "Audit plan", fmt.Sprintf("is %s and can no longer be commented", plan.StatusVal.Code))
+	}
+	return nil
+}
+
+// mentions resolves the @ handles in text to active users. Handles matching no user, or more than
+// one, stay plain text.
+func (s *CommentService) mentions(ctx context.Context, text string) ([]int, error) {
+	handles := parseMentions(text)
+	if len(handles) == 0 {
+		return nil, nil
+	}
+
+	matches, err := s.Repo.GetMentionedUserIDs(ctx, handles)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int]bool)
+	userIDs := []int{}
+	for _, handle := range handles {
+		if len(matches[handle]) != 1 || seen[matches[handle][0]] {
+			continue
+		}
+		seen[matches[handle][0]] = true
+		userIDs = append(userIDs, matches[handle][0])
+	}
+	return userIDs, nil
+}
+
+// parseMentions returns the distinct @ handles of text in lower case, in the order they appear
+func parseMentions(text string) []string {
+	handles := []string{}
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		handle := strings.ToLower(strings.TrimRight(match[1], "."))
+		if handle == "" || seen[handle] {
+			continue
+		}
+		seen[handle] = true
+		handles = append(handles, handle)
+	}
+	return handles
+}
+
+// buildThread nests replies below the comment they answer. Comments are expected oldest first,
+// replies whose parent is missing are shown at the top level.
+func buildThread(comments []types.Comment) []types.Comment {
+	children := make(map[int][]int)
+	known := make(map[int]bool, len(comments))
+	for _, comment := range comments {
+		known[comment.ID] = true
+	}
+
+	roots := []int{}
+	for i, comment := range comments {
+		if comment.ParentID != 0 && known[comment.ParentID] {
+			children[comment.ParentID] = append(children[comment.ParentID], i)
+		} else {
+			roots = append(roots, i)
+		}
+	}
+
+	var nest func(index int) types.Comment
+	nest = func(index int) types.Comment {
+		comment := comments[index]
+		for _, child := range children[comment.ID] {
+			comment.Replies = append(comment.Replies, nest(child))
+		}
+		return comment
+	}
+
+	thread := make([]types.Comment, 0, len(roots))
+	for _, index := range roots {
+		thread = append(thread, nest(index))
+	}
+	return thread
+}
+
+func (s *CommentService) publish(ctx context.Context, event events.Event) {
+	if s.EventBus == nil {
+		return
+	}
+	s.EventBus.AsyncPublish(ctx, event)
+}
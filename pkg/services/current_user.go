@@ -0,0 +1,37 @@
+// Carries the signed in user through the request context
+// The authentication middleware stores the user and API token, controllers and services read them back
+package services
+
+import (
+	"ISO_Auditing_Tool/pkg/requestctx"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+)
+
+type currentUserKey struct{}
+
+// WithCurrentUser returns a copy of ctx carrying the signed in user, who is also recorded as the
+// actor of the writes made for the request
+func WithCurrentUser(ctx context.Context, user types.User) context.Context {
+	ctx = requestctx.WithActor(ctx, user.ID)
+	return context.WithValue(ctx, currentUserKey{}, user)
+}
+
+// CurrentUser returns the signed in user of ctx, false when the request is anonymous
+func CurrentUser(ctx context.Context) (types.User, bool) {
+	user, ok := ctx.Value(currentUserKey{}).(types.User)
+	return user, ok
+}
+
+type apiTokenKey struct{}
+
+// WithAPIToken returns a copy of ctx recording that the request was made with an API token
+func WithAPIToken(ctx context.Context, token types.APIToken) context.Context {
+	return context.WithValue(ctx, apiTokenKey{}, token)
+}
+
+// CurrentAPIToken returns the API token the request was made with, false for session requests
+func CurrentAPIToken(ctx context.Context) (types.APIToken, bool) {
+	token, ok := ctx.Value(apiTokenKey{}).(types.APIToken)
+	return token, ok
+}
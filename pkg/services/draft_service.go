@@ -1,42 +1,248 @@
 // Contains draft business logic
-// Calls the draft repository and applies transformations
+// Calls the draft repository and runs the approval workflow of drafts
 package services
 
 import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
 	"ISO_Auditing_Tool/pkg/repositories"
 	"ISO_Auditing_Tool/pkg/types"
 	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 )
 
+// Steps of the approval workflow, recorded with each transition of a draft
+const (
+	DraftActionSubmit  = "submit"
+	DraftActionApprove = "approve"
+	DraftActionReject  = "reject"
+	DraftActionPublish = "publish"
+)
+
+// draftSteps lists for each step of the approval workflow the statuses a draft may be in and the
+// status it moves to. The seeded lifecycle is DRAFT_DRAFT -> DRAFT_PENDING_APPROVAL ->
+// DRAFT_PUBLISHED, a rejected draft can be submitted again. Approval records the approver and
+// keeps the draft pending until it is published. DRAFT_PUBLISHED is final.
+var draftSteps = map[string]struct {
+	from []string
+	to   string
+}{
+	DraftActionSubmit:  {from: []string{DraftStatusDraft, DraftStatusRejected}, to: DraftStatusPendingApproval},
+	DraftActionApprove: {from: []string{DraftStatusPendingApproval}, to: DraftStatusPendingApproval},
+	DraftActionReject:  {from: []string{DraftStatusPendingApproval}, to: DraftStatusRejected},
+	DraftActionPublish: {from: []string{DraftStatusPendingApproval}, to: DraftStatusPublished},
+}
+
 type DraftService struct {
-	Repo repositories.DraftRepositoryInterface
+	Repo          repositories.DraftRepositoryInterface
+	ReferenceData ReferenceDataServiceInterface
+	Now           Clock
+	// Publishers apply published drafts, keyed by the code of their drafts.type_id
+	Publishers map[string]DraftPublisher
 }
 
 // ensure DraftService implements DraftServiceInterface
 var _ DraftServiceInterface = (*DraftService)(nil)
 
-func NewDraftService(repo repositories.DraftRepositoryInterface) DraftServiceInterface {
-	return &DraftService{Repo: repo}
+func NewDraftService(repo repositories.DraftRepositoryInterface, referenceData ReferenceDataServiceInterface, now Clock) *DraftService {
+	if now == nil {
+		now = time.Now
+	}
+	return &DraftService{Repo: repo, ReferenceData: referenceData, Now: now, Publishers: map[string]DraftPublisher{}}
+}
+
+// RegisterPublisher makes drafts of the type with the given code publishable, publisher applies
+// their data
+func (s *DraftService) RegisterPublisher(typeCode string, publisher DraftPublisher) {
+	if s.Publishers == nil {
+		s.Publishers = map[string]DraftPublisher{}
+	}
+	s.Publishers[typeCode] = publisher
 }
 
 func (s *DraftService) GetAll(ctx context.Context) ([]types.Draft, error) {
 	return s.Repo.GetAllDrafts(ctx)
 }
 
+// Create saves a new draft as DRAFT_DRAFT, a status or approval in draft is ignored. Drafts only
+// move on through the approval workflow.
 func (s *DraftService) Create(ctx context.Context, draft types.Draft) (types.Draft, error) {
+	statusID, err := s.ReferenceData.ResolveID(ctx, RefDraftStatus, DraftStatusDraft)
+	if err != nil {
+		return types.Draft{}, err
+	}
+
+	draft.StatusID = statusID
+	draft.ApproverID = 0
+	draft.ApprovalComment = ""
+	draft.PublishedAt = nil
 	return s.Repo.CreateDraft(ctx, draft)
 }
 
+// Update changes the data of a draft. Only its author edits a draft and only while it is
+// DRAFT_DRAFT or DRAFT_REJECTED, what was submitted is what gets reviewed. A draft submitted while
+// it is being edited is reported as a CONFLICT error.
 func (s *DraftService) Update(ctx context.Context, draft types.Draft) (types.Draft, error) {
-	return s.Repo.UpdateDraft(ctx, draft)
+	existing, err := s.Repo.GetDraftByID(ctx, draft)
+	if err != nil {
+		return types.Draft{}, err
+	}
+	if existing.UserID != draft.UserID {
+		return types.Draft{}, custom_errors.Forbidden(ctx, fmt.Sprintf("user %d cannot edit a draft of another user", draft.UserID))
+	}
+
+	status, err := s.ReferenceData.GetByID(ctx, existing.StatusID)
+	if err != nil {
+		return types.Draft{}, err
+	}
+	if status.Code != DraftStatusDraft && status.Code != DraftStatusRejected {
+		return types.Draft{}, custom_errors.Conflict(ctx, "Draft", fmt.Sprintf("is %s and can no longer be edited", status.Code))
+	}
+
+	return s.Repo.UpdateDraft(ctx, draft, existing.StatusID)
 }
 
+// GetByID returns a draft with the steps of its approval workflow
 func (s *DraftService) GetByID(ctx context.Context, draft types.Draft) (types.Draft, error) {
-	return s.Repo.GetDraftByID(ctx, draft)
+	found, err := s.Repo.GetDraftByID(ctx, draft)
+	if err != nil {
+		return types.Draft{}, err
+	}
+
+	found.Transitions, err = s.Repo.GetDraftTransitions(ctx, found.ID)
+	if err != nil {
+		return types.Draft{}, err
+	}
+	return found, nil
 }
 
+// Delete removes a draft that never entered the approval workflow. Once submitted a draft is kept
+// together with its history.
 func (s *DraftService) Delete(ctx context.Context, draft types.Draft) (types.Draft, error) {
+	transitions, err := s.Repo.GetDraftTransitions(ctx, draft.ID)
+	if err != nil {
+		return types.Draft{}, err
+	}
+	if len(transitions) > 0 {
+		return types.Draft{}, custom_errors.Conflict(ctx, "Draft", "was submitted for approval and is kept with its history")
+	}
 	return s.Repo.DeleteDraft(ctx, draft)
 }
 
-// List
+// Submit asks for approval of a draft. Only its author submits it, a rejected draft is submitted
+// again once reworked and needs a new approval.
+func (s *DraftService) Submit(ctx context.Context, id int, form types.DraftTransitionForm) (types.Draft, error) {
+	return s.transition(ctx, id, DraftActionSubmit, form, func(current types.Draft, next *types.Draft) error {
+		if form.UserID != current.UserID {
+			return custom_errors.Forbidden(ctx, fmt.Sprintf("user %d cannot submit a draft of another user", form.UserID))
+		}
+		next.ApproverID = 0
+		next.ApprovalComment = ""
+		return nil
+	})
+}
+
+// Approve records the approval of a pending draft, which can then be published. Authors cannot
+// approve their own drafts.
+func (s *DraftService) Approve(ctx context.Context, id int, form types.DraftTransitionForm) (types.Draft, error) {
+	return s.transition(ctx, id, DraftActionApprove, form, func(current types.Draft, next *types.Draft) error {
+		if form.UserID == current.UserID {
+			return custom_errors.Forbidden(ctx, fmt.Sprintf("user %d cannot approve a draft they wrote", form.UserID))
+		}
+		if current.ApproverID != 0 {
+			return custom_errors.Conflict(ctx, "Draft", fmt.Sprintf("was already approved by user %d", current.ApproverID))
+		}
+		next.ApproverID = form.UserID
+		next.ApprovalComment = strings.TrimSpace(form.Comment)
+		return nil
+	})
+}
+
+// Reject sends a pending draft back to its author, approved or not. Authors cannot reject their
+// own drafts either.
+func (s *DraftService) Reject(ctx context.Context, id int, form types.DraftTransitionForm) (types.Draft, error) {
+	return s.transition(ctx, id, DraftActionReject, form, func(current types.Draft, next *types.Draft) error {
+		if form.UserID == current.UserID {
+			return custom_errors.Forbidden(ctx, fmt.Sprintf("user %d cannot reject a draft they wrote", form.UserID))
+		}
+		next.ApproverID = form.UserID
+		next.ApprovalComment = strings.TrimSpace(form.Comment)
+		return nil
+	})
+}
+
+// Publish applies an approved draft through the publisher registered for its type and marks it
+// DRAFT_PUBLISHED in the same transaction. Drafts of a type without publisher cannot be published.
+func (s *DraftService) Publish(ctx context.Context, id int, form types.DraftTransitionForm) (types.Draft, error) {
+	return s.transition(ctx, id, DraftActionPublish, form, func(current types.Draft, next *types.Draft) error {
+		if current.ApproverID == 0 {
+			return custom_errors.Conflict(ctx, "Draft", "must be approved before it is published")
+		}
+		publishedAt := s.Now()
+		next.PublishedAt = &publishedAt
+		return nil
+	})
+}
+
+// transition runs a step of the approval workflow on the draft with the given ID. apply checks
+// the step against the current draft and sets the approval fields of the next one.
+func (s *DraftService) transition(ctx context.Context, id int, action string, form types.DraftTransitionForm, apply func(current types.Draft, next *types.Draft) error) (types.Draft, error) {
+	comment := strings.TrimSpace(form.Comment)
+	if comment == "" {
+		return types.Draft{}, custom_errors.InvalidData(ctx, fmt.Sprintf("a comment is required to %s a draft", action))
+	}
+
+	current, err := s.Repo.GetDraftByID(ctx, types.Draft{ID: id})
+	if err != nil {
+		return types.Draft{}, err
+	}
+
+	status, err := s.ReferenceData.GetByID(ctx, current.StatusID)
+	if err != nil {
+		return types.Draft{}, err
+	}
+	step := draftSteps[action]
+	if !slices.Contains(step.from, status.Code) {
+		return types.Draft{}, custom_errors.Conflict(ctx, "Draft", fmt.Sprintf("is %s and cannot be moved by %s", status.Code, action))
+	}
+
+	next := current
+	next.StatusID, err = s.ReferenceData.ResolveID(ctx, RefDraftStatus, step.to)
+	if err != nil {
+		return types.Draft{}, err
+	}
+	if err := apply(current, &next); err != nil {
+		return types.Draft{}, err
+	}
+
+	save := s.Repo.TransitionDraft
+	if action == DraftActionPublish {
+		publisher, err := s.publisher(ctx, current)
+		if err != nil {
+			return types.Draft{}, err
+		}
+		save = publisher.PublishDraft
+	}
+
+	transition := types.DraftTransition{Action: action, UserID: form.UserID, Comment: comment}
+	if err := save(ctx, current, next, transition); err != nil {
+		return types.Draft{}, err
+	}
+
+	return s.GetByID(ctx, current)
+}
+
+// publisher returns the publisher registered for the type of draft
+func (s *DraftService) publisher(ctx context.Context, draft types.Draft) (DraftPublisher, error) {
+	draftType, err := s.ReferenceData.GetByID(ctx, draft.TypeID)
+	if err != nil {
+		return nil, err
+	}
+	publisher, ok := s.Publishers[draftType.Code]
+	if !ok {
+		return nil, custom_errors.Conflict(ctx, "Draft", fmt.Sprintf("of type %s cannot be published", draftType.Code))
+	}
+	return publisher, nil
+}
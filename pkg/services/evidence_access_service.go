@@ -0,0 +1,311 @@
+// Contains provided evidence access business logic
+// Decides from the confidentiality of provided evidence, the role of the caller and their audit
+// membership what may be shown, and manages the grants CLASSIFIED evidence needs
+package services
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"fmt"
+	"log"
+)
+
+// confidentialityRank orders the evidence_provided.confidentiality_id codes from least to most
+// sensitive. CLASSIFIED is not ranked, it always needs a grant.
+var confidentialityRank = map[string]int{
+	ConfidentialityPublic:       0,
+	ConfidentialityInternal:     1,
+	ConfidentialityConfidential: 2,
+	ConfidentialityRestricted:   3,
+}
+
+type EvidenceAccessService struct {
+	Repo           repositories.EvidenceAccessRepositoryInterface
+	ProvidedRepo   repositories.EvidenceProvidedRepositoryInterface
+	AuditQuestions repositories.AuditQuestionRepositoryInterface
+	Assignments    repositories.AuditAssignmentRepositoryInterface
+	ReferenceData  ReferenceDataServiceInterface
+	EventBus       *events.EventBus
+}
+
+// ensure EvidenceAccessService implements EvidenceAccessServiceInterface
+var _ EvidenceAccessServiceInterface = (*EvidenceAccessService)(nil)
+
+func NewEvidenceAccessService(
+	repo repositories.EvidenceAccessRepositoryInterface,
+	providedRepo repositories.EvidenceProvidedRepositoryInterface,
+	auditQuestions repositories.AuditQuestionRepositoryInterface,
+	assignments repositories.AuditAssignmentRepositoryInterface,
+	referenceData ReferenceDataServiceInterface,
+	eventBus *events.EventBus,
+) *EvidenceAccessService {
+	return &EvidenceAccessService{
+		Repo:           repo,
+		ProvidedRepo:   providedRepo,
+		AuditQuestions: auditQuestions,
+		Assignments:    assignments,
+		ReferenceData:  referenceData,
+		EventBus:       eventBus,
+	}
+}
+
+// evidenceViewer is the caller evidence is shown to. Callers who did not identify themselves, or
+// are not active users, have no role.
+type evidenceViewer struct {
+	UserID int
+	Role   string
+	Member bool // lead or support auditor of the audit
+}
+
+// clearance is the most sensitive ranked confidentiality the viewer may see. Administrators and
+// the audit team see up to RESTRICTED, other auditors and viewers INTERNAL, clients up to
+// CONFIDENTIAL and anybody else only PUBLIC evidence.
+func (v evidenceViewer) clearance() string {
+	switch {
+	case v.Role == UserRoleClient:
+		return ConfidentialityConfidential
+	case v.Role == UserRoleAdmin, v.Member:
+		return ConfidentialityRestricted
+	case v.Role == UserRoleLead, v.Role == UserRoleSupport, v.Role == UserRoleViewer:
+		return ConfidentialityInternal
+	default:
+		return ConfidentialityPublic
+	}
+}
+
+// canSee reports whether the viewer may see evidence of the given confidentiality. CLASSIFIED
+// evidence needs a grant, which is never honoured for clients.
+func (v evidenceViewer) canSee(confidentiality string, granted bool) bool {
+	if confidentiality == ConfidentialityClassified {
+		return granted && v.Role != UserRoleClient
+	}
+	rank, ok := confidentialityRank[confidentiality]
+	return ok && rank <= confidentialityRank[v.clearance()]
+}
+
+// Redact withholds the content and file of the provided evidence of an audit that userID may not
+// see. Only IDs, classification and dates stay, so counts still add up. Each redaction is logged.
+func (s *EvidenceAccessService) Redact(ctx context.Context, userID, auditPlanID int, provided []types.EvidenceProvided) error {
+	if len(provided) == 0 {
+		return nil
+	}
+
+	viewer, err := s.viewer(ctx, userID, auditPlanID)
+	if err != nil {
+		return err
+	}
+
+	codes := make([]string, len(provided))
+	classified := []int{}
+	for i := range provided {
+		value, err := s.ReferenceData.GetByID(ctx, provided[i].ConfidentialityVal.ID)
+		if err != nil {
+			return err
+		}
+		codes[i] = value.Code
+		if value.Code == ConfidentialityClassified {
+			classified = append(classified, provided[i].ID)
+		}
+	}
+
+	granted, err := s.granted(ctx, viewer, classified)
+	if err != nil {
+		return err
+	}
+
+	for i := range provided {
+		if viewer.canSee(codes[i], granted[provided[i].ID]) {
+			continue
+		}
+		provided[i].Provided = ""
+		provided[i].File = nil
+		provided[i].Redacted = true
+		s.denied(ctx, viewer, provided[i], codes[i], "read")
+	}
+	return nil
+}
+
+// CheckAccess reports provided evidence of an audit that userID may not see as FORBIDDEN, and
+// logs the denial
+func (s *EvidenceAccessService) CheckAccess(ctx context.Context, userID, auditPlanID int, provided types.EvidenceProvided, action string) error {
+	viewer, err := s.viewer(ctx, userID, auditPlanID)
+	if err != nil {
+		return err
+	}
+
+	confidentiality, err := s.ReferenceData.GetByID(ctx, provided.ConfidentialityVal.ID)
+	if err != nil {
+		return err
+	}
+
+	granted := map[int]bool{}
+	if confidentiality.Code == ConfidentialityClassified {
+		if granted, err = s.granted(ctx, viewer, []int{provided.ID}); err != nil {
+			return err
+		}
+	}
+
+	if !viewer.canSee(confidentiality.Code, granted[provided.ID]) {
+		s.denied(ctx, viewer, provided, confidentiality.Code, action)
+		return custom_errors.Forbidden(ctx, fmt.Sprintf("%s evidence is not accessible to user %d", confidentiality.Code, userID))
+	}
+	return nil
+}
+
+// GetGrants returns who was granted access to provided evidence. Only users who can see
+// RESTRICTED evidence of the audit may list them.
+func (s *EvidenceAccessService) GetGrants(ctx context.Context, providedID, userID int) ([]types.EvidenceAccessGrant, error) {
+	provided, err := s.checkGrantManager(ctx, providedID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.Repo.GetByEvidenceProvidedIDEvidenceAccessGrants(ctx, provided.ID)
+}
+
+// Grant lets form.UserID see provided evidence and returns the grants. The granting user must be
+// able to see RESTRICTED evidence of the audit, clients cannot be granted access.
+func (s *EvidenceAccessService) Grant(ctx context.Context, providedID int, form types.EvidenceAccessGrantForm) ([]types.EvidenceAccessGrant, error) {
+	provided, err := s.checkGrantManager(ctx, providedID, form.GrantedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.role(ctx, form.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		return nil, custom_errors.InvalidData(ctx, "user_id is not an active user")
+	}
+	if role == UserRoleClient {
+		return nil, custom_errors.InvalidData(ctx, "CLIENT users cannot be granted access to provided evidence")
+	}
+
+	grant := types.EvidenceAccessGrant{EvidenceProvidedID: provided.ID, UserID: form.UserID, GrantedBy: form.GrantedBy}
+	if err := s.Repo.CreateEvidenceAccessGrant(ctx, grant); err != nil {
+		return nil, err
+	}
+
+	grants, err := s.Repo.GetByEvidenceProvidedIDEvidenceAccessGrants(ctx, provided.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("User %d granted user %d access to provided evidence %d", form.GrantedBy, form.UserID, provided.ID)
+	s.publish(ctx, provided, grants)
+	return grants, nil
+}
+
+// Revoke removes the access of userID to provided evidence on behalf of revokedBy
+func (s *EvidenceAccessService) Revoke(ctx context.Context, providedID, userID, revokedBy int) error {
+	provided, err := s.checkGrantManager(ctx, providedID, revokedBy)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Repo.DeleteEvidenceAccessGrant(ctx, provided.ID, userID); err != nil {
+		return err
+	}
+
+	grants, err := s.Repo.GetByEvidenceProvidedIDEvidenceAccessGrants(ctx, provided.ID)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("User %d revoked the access of user %d to provided evidence %d", revokedBy, userID, provided.ID)
+	s.publish(ctx, provided, grants)
+	return nil
+}
+
+// checkGrantManager loads provided evidence and reports a user who cannot see RESTRICTED evidence
+// of its audit as FORBIDDEN
+func (s *EvidenceAccessService) checkGrantManager(ctx context.Context, providedID, userID int) (types.EvidenceProvided, error) {
+	provided, err := s.ProvidedRepo.GetByIDEvidenceProvided(ctx, types.EvidenceProvided{ID: providedID})
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	question, err := s.AuditQuestions.GetByIDAuditQuestion(ctx, types.AuditQuestion{ID: provided.AuditQuestionID})
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	viewer, err := s.viewer(ctx, userID, question.AuditID)
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+	if !viewer.canSee(ConfidentialityRestricted, false) {
+		return types.EvidenceProvided{}, custom_errors.Forbidden(ctx, fmt.Sprintf("user %d cannot manage access to provided evidence %d", userID, provided.ID))
+	}
+	return provided, nil
+}
+
+// viewer looks up the role of userID and whether they are on the team of the audit
+func (s *EvidenceAccessService) viewer(ctx context.Context, userID, auditPlanID int) (evidenceViewer, error) {
+	viewer := evidenceViewer{UserID: userID}
+	if userID == 0 {
+		return viewer, nil
+	}
+
+	role, err := s.role(ctx, userID)
+	if err != nil {
+		return evidenceViewer{}, err
+	}
+	viewer.Role = role
+
+	if viewer.Member, err = s.Assignments.IsAuditTeamMember(ctx, auditPlanID, userID); err != nil {
+		return evidenceViewer{}, err
+	}
+	return viewer, nil
+}
+
+// role returns the role code of an active user, or "" for anybody else
+func (s *EvidenceAccessService) role(ctx context.Context, userID int) (string, error) {
+	roleID, err := s.Repo.GetUserRoleID(ctx, userID)
+	if custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	role, err := s.ReferenceData.GetByID(ctx, roleID)
+	if err != nil {
+		return "", err
+	}
+	return role.Code, nil
+}
+
+// granted returns which of the CLASSIFIED provided evidence IDs the viewer was granted access to
+func (s *EvidenceAccessService) granted(ctx context.Context, viewer evidenceViewer, classified []int) (map[int]bool, error) {
+	if len(classified) == 0 || viewer.UserID == 0 {
+		return map[int]bool{}, nil
+	}
+	return s.Repo.GetGrantedIDsEvidenceAccessGrants(ctx, viewer.UserID, classified)
+}
+
+// denied logs that provided evidence was withheld and publishes EvidenceAccessDenied
+func (s *EvidenceAccessService) denied(ctx context.Context, viewer evidenceViewer, provided types.EvidenceProvided, confidentiality, action string) {
+	log.Printf("Denied %s of %s provided evidence %d to user %d (role %q)", action, confidentiality, provided.ID, viewer.UserID, viewer.Role)
+	if s.EventBus == nil {
+		return
+	}
+	s.EventBus.AsyncPublish(ctx, events.NewEvidenceAccessDeniedEvent(events.EvidenceAccessPayload{
+		EvidenceProvidedID: provided.ID,
+		AuditQuestionID:    provided.AuditQuestionID,
+		UserID:             viewer.UserID,
+		Role:               viewer.Role,
+		Confidentiality:    confidentiality,
+		Action:             action,
+	}))
+}
+
+func (s *EvidenceAccessService) publish(ctx context.Context, provided types.EvidenceProvided, grants []types.EvidenceAccessGrant) {
+	if s.EventBus == nil {
+		return
+	}
+	s.EventBus.AsyncPublish(ctx, events.NewEvidenceProvidedEvent(provided.ID, events.ChangeUpdated, provided.AuditQuestionID, "", grants))
+}
@@ -0,0 +1,216 @@
+// Contains evidence file business logic
+// Streams the files of provided evidence into the blob store and back, recording their hash,
+// size and MIME type
+package services
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/storage"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+)
+
+type EvidenceFileService struct {
+	ProvidedRepo   repositories.EvidenceProvidedRepositoryInterface
+	AuditQuestions repositories.AuditQuestionRepositoryInterface
+	AuditPlans     AuditPlanServiceInterface
+	Assignments    AuditAssignmentServiceInterface
+	Access         EvidenceAccessServiceInterface
+	ReferenceData  ReferenceDataServiceInterface
+	Blobs          storage.BlobStore
+	MaxSize        int64
+	EventBus       *events.EventBus
+}
+
+// ensure EvidenceFileService implements EvidenceFileServiceInterface
+var _ EvidenceFileServiceInterface = (*EvidenceFileService)(nil)
+
+func NewEvidenceFileService(
+	providedRepo repositories.EvidenceProvidedRepositoryInterface,
+	auditQuestions repositories.AuditQuestionRepositoryInterface,
+	auditPlans AuditPlanServiceInterface,
+	assignments AuditAssignmentServiceInterface,
+	access EvidenceAccessServiceInterface,
+	referenceData ReferenceDataServiceInterface,
+	blobs storage.BlobStore,
+	maxSize int64,
+	eventBus *events.EventBus,
+) *EvidenceFileService {
+	return &EvidenceFileService{
+		ProvidedRepo:   providedRepo,
+		AuditQuestions: auditQuestions,
+		AuditPlans:     auditPlans,
+		Assignments:    assignments,
+		Access:         access,
+		ReferenceData:  referenceData,
+		Blobs:          blobs,
+		MaxSize:        maxSize,
+		EventBus:       eventBus,
+	}
+}
+
+// Upload streams content into the blob store as the file of provided evidence of a FILE, IMAGE or
+// VIDEO type. IMAGE and VIDEO evidence must sniff as such. The audit must be IN_PROGRESS and
+// provided.UserID on its team. A file uploaded before is replaced. The result is redacted when
+// provided.UserID may not see the evidence.
+func (s *EvidenceFileService) Upload(ctx context.Context, provided types.EvidenceProvided, name string, content io.Reader) (types.EvidenceProvided, error) {
+	existing, err := s.ProvidedRepo.GetByIDEvidenceProvided(ctx, provided)
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	question, err := s.AuditQuestions.GetByIDAuditQuestion(ctx, types.AuditQuestion{ID: existing.AuditQuestionID})
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	plan, err := s.AuditPlans.GetByID(ctx, types.AuditPlan{ID: question.AuditID})
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+	if plan.StatusVal.Code != AuditPlanInProgress {
+		return types.EvidenceProvided{}, custom_errors.Conflict(ctx, "Audit plan", fmt.Sprintf("is %s, evidence can only be recorded while it is IN_PROGRESS", plan.StatusVal.Code))
+	}
+
+	if err := s.Assignments.CheckTeamMember(ctx, question.AuditID, provided.UserID); err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	evidenceType, err := s.ReferenceData.GetByID(ctx, existing.TypeVal.ID)
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+	if evidenceType.Code != EvidenceTypeFile && evidenceType.Code != EvidenceTypeImage && evidenceType.Code != EvidenceTypeVideo {
+		return types.EvidenceProvided{}, custom_errors.InvalidData(ctx, fmt.Sprintf("%s evidence does not take a file", evidenceType.Code))
+	}
+
+	key, err := newBlobKey(existing.ID)
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	digest, err := storage.PutWithDigest(ctx, s.Blobs, key, content, s.MaxSize)
+	if errors.Is(err, storage.ErrBlobTooLarge) {
+		return types.EvidenceProvided{}, custom_errors.InvalidData(ctx, fmt.Sprintf("file exceeds the maximum size of %d bytes", s.MaxSize))
+	}
+	if err != nil {
+		return types.EvidenceProvided{}, fmt.Errorf("failed to store evidence file: %w", err)
+	}
+
+	if err := checkMIMEType(ctx, evidenceType.Code, digest.MIMEType); err != nil {
+		s.deleteBlob(ctx, key)
+		return types.EvidenceProvided{}, err
+	}
+
+	file := types.EvidenceFile{Key: key, Name: name, Size: digest.Size, SHA256: digest.SHA256, MIMEType: digest.MIMEType}
+	if err := s.ProvidedRepo.UpdateEvidenceProvidedFile(ctx, existing.ID, file); err != nil {
+		s.deleteBlob(ctx, key)
+		return types.EvidenceProvided{}, err
+	}
+	if existing.File != nil {
+		s.deleteBlob(ctx, existing.File.Key)
+	}
+
+	updated, err := s.ProvidedRepo.GetByIDEvidenceProvided(ctx, existing)
+	if err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	if err := s.hydrate(ctx, &updated); err != nil {
+		return types.EvidenceProvided{}, err
+	}
+
+	s.publish(ctx, updated)
+
+	redacted := []types.EvidenceProvided{updated}
+	if err := s.Access.Redact(ctx, provided.UserID, question.AuditID, redacted); err != nil {
+		return types.EvidenceProvided{}, err
+	}
+	return redacted[0], nil
+}
+
+// Download returns the file of provided evidence and a reader for its content, the caller closes
+// it. Files provided.UserID may not see are FORBIDDEN.
+func (s *EvidenceFileService) Download(ctx context.Context, provided types.EvidenceProvided) (types.EvidenceFile, io.ReadCloser, error) {
+	existing, err := s.ProvidedRepo.GetByIDEvidenceProvided(ctx, provided)
+	if err != nil {
+		return types.EvidenceFile{}, nil, err
+	}
+
+	question, err := s.AuditQuestions.GetByIDAuditQuestion(ctx, types.AuditQuestion{ID: existing.AuditQuestionID})
+	if err != nil {
+		return types.EvidenceFile{}, nil, err
+	}
+	if err := s.Access.CheckAccess(ctx, provided.UserID, question.AuditID, existing, "download"); err != nil {
+		return types.EvidenceFile{}, nil, err
+	}
+	if existing.File == nil {
+		return types.EvidenceFile{}, nil, custom_errors.NotFound(ctx, "Evidence file")
+	}
+
+	content, err := s.Blobs.Get(ctx, existing.File.Key)
+	if errors.Is(err, storage.ErrBlobNotFound) {
+		return types.EvidenceFile{}, nil, custom_errors.NotFound(ctx, "Evidence file")
+	}
+	if err != nil {
+		return types.EvidenceFile{}, nil, fmt.Errorf("failed to read evidence file: %w", err)
+	}
+
+	return *existing.File, content, nil
+}
+
+// hydrate replaces the reference IDs loaded by the repository with their reference values
+func (s *EvidenceFileService) hydrate(ctx context.Context, provided *types.EvidenceProvided) error {
+	for _, value := range []*types.ReferenceValue{&provided.TypeVal, &provided.ConfidentialityVal, &provided.StatusVal} {
+		resolved, err := s.ReferenceData.GetByID(ctx, value.ID)
+		if err != nil {
+			return err
+		}
+		*value = resolved
+	}
+	return nil
+}
+
+// deleteBlob removes a blob that is no longer referenced. A failure only leaves an orphaned blob,
+// so it is logged rather than returned.
+func (s *EvidenceFileService) deleteBlob(ctx context.Context, key string) {
+	if err := s.Blobs.Delete(ctx, key); err != nil {
+		log.Printf("Failed to delete evidence blob %s: %v", key, err)
+	}
+}
+
+func (s *EvidenceFileService) publish(ctx context.Context, provided types.EvidenceProvided) {
+	if s.EventBus == nil {
+		return
+	}
+	s.EventBus.AsyncPublish(ctx, events.NewEvidenceProvidedEvent(provided.ID, events.ChangeUpdated, provided.AuditQuestionID, "", provided))
+}
+
+// newBlobKey returns a fresh key below the provided evidence, every upload gets its own so a
+// replaced file can be removed after the new one is recorded
+func newBlobKey(providedID int) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate blob key: %w", err)
+	}
+	return fmt.Sprintf("evidence/%d/%s", providedID, hex.EncodeToString(random)), nil
+}
+
+// checkMIMEType reports IMAGE or VIDEO evidence whose content sniffs as something else as
+// INVALID_DATA, FILE evidence takes any content
+func checkMIMEType(ctx context.Context, evidenceType, mimeType string) error {
+	prefix := map[string]string{EvidenceTypeImage: "image/", EvidenceTypeVideo: "video/"}[evidenceType]
+	if prefix != "" && !strings.HasPrefix(mimeType, prefix) {
+		return custom_errors.InvalidData(ctx, fmt.Sprintf("%s evidence cannot be a %s file", evidenceType, mimeType))
+	}
+	return nil
+}
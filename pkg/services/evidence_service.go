@@ -0,0 +1,114 @@
+// Contains expected evidence business logic
+// Calls the evidence and question repositories and publishes content changes
+package services
+
+import (
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+)
+
+type EvidenceService struct {
+	Repo         repositories.EvidenceRepositoryInterface
+	QuestionRepo repositories.QuestionRepositoryInterface
+	EventBus     *events.EventBus
+}
+
+// ensure EvidenceService implements EvidenceServiceInterface
+var _ EvidenceServiceInterface = (*EvidenceService)(nil)
+
+func NewEvidenceService(
+	repo repositories.EvidenceRepositoryInterface,
+	questionRepo repositories.QuestionRepositoryInterface,
+	eventBus *events.EventBus,
+) *EvidenceService {
+	return &EvidenceService{Repo: repo, QuestionRepo: questionRepo, EventBus: eventBus}
+}
+
+func (s *EvidenceService) GetByID(ctx context.Context, evidence types.Evidence) (types.Evidence, error) {
+	return s.Repo.GetByIDEvidence(ctx, evidence)
+}
+
+// Create adds expected evidence to a question. TypeVal.ID must be an active evidence type.
+func (s *EvidenceService) Create(ctx context.Context, evidence types.Evidence) (types.Evidence, error) {
+	if _, err := s.QuestionRepo.GetByIDQuestion(ctx, types.Question{ID: evidence.QuestionID}); err != nil {
+		return types.Evidence{}, err
+	}
+
+	evidenceType, err := s.Repo.GetEvidenceType(ctx, evidence.TypeVal.ID)
+	if err != nil {
+		return types.Evidence{}, err
+	}
+	evidence.TypeVal = evidenceType
+
+	created, err := s.Repo.CreateEvidence(ctx, evidence)
+	if err != nil {
+		return types.Evidence{}, err
+	}
+
+	s.publish(ctx, created.ID, created.QuestionID, events.ChangeCreated, created)
+	return created, nil
+}
+
+func (s *EvidenceService) Update(ctx context.Context, evidence types.Evidence) (types.Evidence, error) {
+	existing, err := s.Repo.GetByIDEvidence(ctx, evidence)
+	if err != nil {
+		return types.Evidence{}, err
+	}
+
+	evidenceType, err := s.Repo.GetEvidenceType(ctx, evidence.TypeVal.ID)
+	if err != nil {
+		return types.Evidence{}, err
+	}
+	existing.TypeVal = evidenceType
+	existing.Expected = evidence.Expected
+
+	updated, err := s.Repo.UpdateEvidence(ctx, existing)
+	if err != nil {
+		return types.Evidence{}, err
+	}
+
+	s.publish(ctx, updated.ID, updated.QuestionID, events.ChangeUpdated, updated)
+	return updated, nil
+}
+
+func (s *EvidenceService) Delete(ctx context.Context, evidence types.Evidence) error {
+	existing, err := s.Repo.GetByIDEvidence(ctx, evidence)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Repo.DeleteEvidence(ctx, existing.ID); err != nil {
+		return err
+	}
+
+	s.publish(ctx, existing.ID, existing.QuestionID, events.ChangeDeleted, nil)
+	return nil
+}
+
+// Reorder sets the order of every expected evidence of a question
+func (s *EvidenceService) Reorder(ctx context.Context, questionID int, evidenceIDs []int) error {
+	question, err := s.QuestionRepo.GetByIDQuestion(ctx, types.Question{ID: questionID})
+	if err != nil {
+		return err
+	}
+
+	if err := s.Repo.ReorderEvidence(ctx, questionID, evidenceIDs); err != nil {
+		return err
+	}
+
+	// The order belongs to the question, so refresh it rather than every evidence
+	if s.EventBus != nil {
+		s.EventBus.AsyncPublish(ctx, events.NewQuestionEvent(question.ID, events.ChangeUpdated, question.RequirementID, "", nil))
+	}
+	return nil
+}
+
+// publish notifies the materialized caches, which rebuild the question and everything above it
+func (s *EvidenceService) publish(ctx context.Context, evidenceID, questionID int, changeType events.ChangeType, data any) {
+	if s.EventBus == nil {
+		return
+	}
+	s.EventBus.AsyncPublish(ctx, events.NewEvidenceEvent(evidenceID, changeType, questionID, "", data))
+}
@@ -0,0 +1,518 @@
+// Contains findings business logic
+// Raises findings against audit questions and validates them against the findings reference data
+package services
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type FindingService struct {
+	Repo           repositories.FindingRepositoryInterface
+	Actions        repositories.CorrectiveActionRepositoryInterface
+	AuditQuestions repositories.AuditQuestionRepositoryInterface
+	AuditPlans     AuditPlanServiceInterface
+	Assignments    AuditAssignmentServiceInterface
+	ReferenceData  ReferenceDataServiceInterface
+	EventBus       *events.EventBus
+}
+
+// ensure FindingService implements FindingServiceInterface
+var _ FindingServiceInterface = (*FindingService)(nil)
+
+// findingTransitions lists the statuses a finding may move to from each status of the seeded
+// lifecycle OPEN -> IN_PROGRESS -> PENDING_REVIEW -> CLOSED. A finding under review can be sent
+// back to IN_PROGRESS, any open finding can be waived or transferred. CLOSED, WAIVED and
+// TRANSFERRED are final.
+var findingTransitions = map[string][]string{
+	FindingOpen:          {FindingInProgress, FindingWaived, FindingTransferred},
+	FindingInProgress:    {FindingPendingReview, FindingWaived, FindingTransferred},
+	FindingPendingReview: {FindingClosed, FindingInProgress, FindingWaived, FindingTransferred},
+}
+
+func NewFindingService(
+	repo repositories.FindingRepositoryInterface,
+	actions repositories.CorrectiveActionRepositoryInterface,
+	auditQuestions repositories.AuditQuestionRepositoryInterface,
+	auditPlans AuditPlanServiceInterface,
+	assignments AuditAssignmentServiceInterface,
+	referenceData ReferenceDataServiceInterface,
+	eventBus *events.EventBus,
+) *FindingService {
+	return &FindingService{
+		Repo:           repo,
+		Actions:        actions,
+		AuditQuestions: auditQuestions,
+		AuditPlans:     auditPlans,
+		Assignments:    assignments,
+		ReferenceData:  referenceData,
+		EventBus:       eventBus,
+	}
+}
+
+// GetAll returns the findings matching the filter. Severity and status codes are resolved to
+// their reference values first, unknown codes are reported as INVALID_DATA. A signed in client
+// only gets the findings they are responsible for or own a corrective action of, other users who
+// are not administrators the findings of audits they are on the team of. Asking for the audits of
+// another auditor is FORBIDDEN unless the caller is an administrator.
+func (s *FindingService) GetAll(ctx context.Context, filter types.FindingFilter) ([]types.Finding, error) {
+	if user, ok := CurrentUser(ctx); ok && user.RoleVal.Code != UserRoleAdmin {
+		if filter.AuditorID != 0 && filter.AuditorID != user.ID {
+			return nil, custom_errors.Forbidden(ctx, fmt.Sprintf("user %d cannot list the findings of auditor %d", user.ID, filter.AuditorID))
+		}
+		if user.RoleVal.Code == UserRoleClient {
+			filter.FollowUpUserID = user.ID
+		} else {
+			filter.AuditorID = user.ID
+		}
+	}
+
+	var err error
+	if filter.Severity != "" {
+		if filter.SeverityID, err = resolveCode(ctx, s.ReferenceData, RefFindingSeverity, filter.Severity); err != nil {
+			return nil, err
+		}
+	}
+	if filter.Status != "" {
+		if filter.StatusID, err = resolveCode(ctx, s.ReferenceData, RefFindingStatus, filter.Status); err != nil {
+			return nil, err
+		}
+	}
+
+	findings, err := s.Repo.GetAllFindings(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range findings {
+		if err := s.hydrate(ctx, &findings[i]); err != nil {
+			return nil, err
+		}
+	}
+	return findings, nil
+}
+
+// GetOverdue reports the findings that are not closed yet and were due before the day of asOf, or
+// are due within warningDays of it. Due dates are compared by calendar day in the location of asOf.
+func (s *FindingService) GetOverdue(ctx context.Context, asOf time.Time, warningDays int) (types.OverdueFindingReport, error) {
+	statusIDs, err := openFindingStatusIDs(ctx, s.ReferenceData)
+	if err != nil {
+		return types.OverdueFindingReport{}, err
+	}
+
+	// Due dates are DATE columns, so the day of asOf is compared as a UTC date
+	today := time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)
+	findings, err := s.GetAll(ctx, types.FindingFilter{StatusIDs: statusIDs, DueTo: today.AddDate(0, 0, warningDays+1)})
+	if err != nil {
+		return types.OverdueFindingReport{}, err
+	}
+
+	report := types.OverdueFindingReport{
+		AsOf:        asOf,
+		WarningDays: warningDays,
+		Overdue:     []types.Finding{},
+		DueSoon:     []types.Finding{},
+	}
+	for _, finding := range findings {
+		if daysBetween(asOf, finding.DueDate) < 0 {
+			report.Overdue = append(report.Overdue, finding)
+		} else {
+			report.DueSoon = append(report.DueSoon, finding)
+		}
+	}
+	return report, nil
+}
+
+// GetByID returns a finding together with its corrective actions
+func (s *FindingService) GetByID(ctx context.Context, finding types.Finding) (types.Finding, error) {
+	result, err := s.Repo.GetByIDFinding(ctx, finding)
+	if err != nil {
+		return types.Finding{}, err
+	}
+
+	if err := s.hydrate(ctx, &result); err != nil {
+		return types.Finding{}, err
+	}
+
+	if result.CorrectiveActions, err = s.Actions.GetByFindingIDCorrectiveActions(ctx, result.ID); err != nil {
+		return types.Finding{}, err
+	}
+	return result, nil
+}
+
+// Create raises an OPEN finding against an audit question. The audit must be IN_PROGRESS or in
+// REVIEW and the creator on its team.
+func (s *FindingService) Create(ctx context.Context, finding types.Finding) (types.Finding, error) {
+	question, err := s.AuditQuestions.GetByIDAuditQuestion(ctx, types.AuditQuestion{ID: finding.AuditQuestionID})
+	if err != nil {
+		return types.Finding{}, err
+	}
+
+	plan, err := s.AuditPlans.GetByID(ctx, types.AuditPlan{ID: question.AuditID})
+	if err != nil {
+		return types.Finding{}, err
+	}
+	if plan.StatusVal.Code != AuditPlanInProgress && plan.StatusVal.Code != AuditPlanReview {
+		return types.Finding{}, custom_errors.Conflict(ctx, "Audit plan", fmt.Sprintf("is %s, findings can only be raised while it is IN_PROGRESS or in REVIEW", plan.StatusVal.Code))
+	}
+
+	if err := s.Assignments.CheckTeamMember(ctx, plan.ID, finding.CreatedBy); err != nil {
+		return types.Finding{}, err
+	}
+
+	if err := s.validate(ctx, &finding); err != nil {
+		return types.Finding{}, err
+	}
+
+	open, err := s.ReferenceData.Resolve(ctx, RefFindingStatus, FindingOpen)
+	if err != nil {
+		return types.Finding{}, err
+	}
+
+	finding.AuditID = question.AuditID
+	finding.QuestionID = question.QuestionID
+	finding.StatusVal = open
+
+	created, err := s.Repo.CreateFinding(ctx, finding)
+	if err != nil {
+		return types.Finding{}, err
+	}
+
+	if err := s.hydrate(ctx, &created); err != nil {
+		return types.Finding{}, err
+	}
+
+	s.publish(ctx, created, events.ChangeCreated, created)
+	return created, nil
+}
+
+// Update changes the classification, description, due date and owner of a finding. CreatedBy is
+// the user making the change, who must be on the team of the audit.
+func (s *FindingService) Update(ctx context.Context, finding types.Finding) (types.Finding, error) {
+	existing, err := s.GetByID(ctx, finding)
+	if err != nil {
+		return types.Finding{}, err
+	}
+
+	if isClosedFinding(existing.StatusVal.Code) {
+		return types.Finding{}, custom_errors.Conflict(ctx, "Finding", fmt.Sprintf("is %s and can no longer be changed", existing.StatusVal.Code))
+	}
+
+	if err := s.Assignments.CheckTeamMember(ctx, existing.AuditID, finding.CreatedBy); err != nil {
+		return types.Finding{}, err
+	}
+
+	if err := s.validate(ctx, &finding); err != nil {
+		return types.Finding{}, err
+	}
+
+	existing.TypeVal = finding.TypeVal
+	existing.SeverityVal = finding.SeverityVal
+	existing.Description = finding.Description
+	existing.DueDate = finding.DueDate
+	existing.ResponsibleUserID = finding.ResponsibleUserID
+
+	updated, err := s.Repo.UpdateFinding(ctx, existing)
+	if err != nil {
+		return types.Finding{}, err
+	}
+
+	if err := s.hydrate(ctx, &updated); err != nil {
+		return types.Finding{}, err
+	}
+
+	s.publish(ctx, updated, events.ChangeUpdated, updated)
+	return updated, nil
+}
+
+// ChangeStatus moves a finding to the status with the given code on behalf of userID. Transitions
+// outside the lifecycle are rejected with a CONFLICT error. The responsible user or the audit team
+// may start work and submit it for review, every other transition is an auditor decision. Review
+// needs all corrective actions completed, closing needs all of them verified and waiving needs a
+// reason in StatusReason.
+func (s *FindingService) ChangeStatus(ctx context.Context, finding types.Finding, statusCode string, userID int) (types.Finding, error) {
+	existing, err := s.GetByID(ctx, finding)
+	if err != nil {
+		return types.Finding{}, err
+	}
+
+	target, err := s.ReferenceData.Resolve(ctx, RefFindingStatus, statusCode)
+	if custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound) {
+		return types.Finding{}, custom_errors.InvalidData(ctx, fmt.Sprintf("%s is not a valid %s", statusCode, RefFindingStatus))
+	}
+	if err != nil {
+		return types.Finding{}, err
+	}
+
+	if !canTransitionFinding(existing.StatusVal.Code, target.Code) {
+		return types.Finding{}, custom_errors.Conflict(ctx, "Finding", fmt.Sprintf("cannot move from %s to %s", existing.StatusVal.Code, target.Code))
+	}
+
+	if isAuditorDecision(existing.StatusVal.Code, target.Code) || userID != existing.ResponsibleUserID {
+		if err := s.Assignments.CheckTeamMember(ctx, existing.AuditID, userID); err != nil {
+			return types.Finding{}, err
+		}
+	}
+
+	reason := strings.TrimSpace(finding.StatusReason)
+	if target.Code == FindingWaived && reason == "" {
+		return types.Finding{}, custom_errors.InvalidData(ctx, "a reason is required to waive a finding")
+	}
+
+	switch target.Code {
+	case FindingPendingReview:
+		if err := checkCorrectiveActions(ctx, existing, "completed", func(a types.CorrectiveAction) bool { return a.CompletedAt != nil }); err != nil {
+			return types.Finding{}, err
+		}
+	case FindingClosed:
+		if err := checkCorrectiveActions(ctx, existing, "verified", func(a types.CorrectiveAction) bool { return a.VerifiedAt != nil }); err != nil {
+			return types.Finding{}, err
+		}
+	}
+
+	if err := s.Repo.UpdateFindingStatus(ctx, existing.ID, existing.StatusVal.ID, target.ID, reason); err != nil {
+		return types.Finding{}, err
+	}
+
+	updated, err := s.GetByID(ctx, existing)
+	if err != nil {
+		return types.Finding{}, err
+	}
+
+	s.publish(ctx, updated, events.ChangeUpdated, updated)
+	return updated, nil
+}
+
+// Delete soft deletes a finding that is still OPEN, anything further along is part of the record
+func (s *FindingService) Delete(ctx context.Context, finding types.Finding) error {
+	existing, err := s.GetByID(ctx, finding)
+	if err != nil {
+		return err
+	}
+
+	if existing.StatusVal.Code != FindingOpen {
+		return custom_errors.Conflict(ctx, "Finding", fmt.Sprintf("is %s, only OPEN findings can be deleted", existing.StatusVal.Code))
+	}
+
+	if err := s.Repo.DeleteFinding(ctx, existing.ID); err != nil {
+		return err
+	}
+
+	s.publish(ctx, existing, events.ChangeDeleted, nil)
+	return nil
+}
+
+// AddCorrectiveAction plans a corrective action for a finding that is not closed yet
+func (s *FindingService) AddCorrectiveAction(ctx context.Context, action types.CorrectiveAction) (types.CorrectiveAction, error) {
+	finding, err := s.openFinding(ctx, action.FindingID)
+	if err != nil {
+		return types.CorrectiveAction{}, err
+	}
+
+	created, err := s.Actions.CreateCorrectiveAction(ctx, action)
+	if err != nil {
+		return types.CorrectiveAction{}, err
+	}
+
+	s.publishAction(ctx, created, events.ChangeCreated)
+	s.publish(ctx, finding, events.ChangeUpdated, nil)
+	return created, nil
+}
+
+// UpdateCorrectiveAction changes the action, owner and target date of an action that is not
+// verified yet
+func (s *FindingService) UpdateCorrectiveAction(ctx context.Context, action types.CorrectiveAction) (types.CorrectiveAction, error) {
+	existing, err := s.Actions.GetByIDCorrectiveAction(ctx, action)
+	if err != nil {
+		return types.CorrectiveAction{}, err
+	}
+
+	if _, err := s.openFinding(ctx, existing.FindingID); err != nil {
+		return types.CorrectiveAction{}, err
+	}
+
+	existing.Action = action.Action
+	existing.OwnerID = action.OwnerID
+	existing.TargetDate = action.TargetDate
+
+	updated, err := s.Actions.UpdateCorrectiveAction(ctx, existing)
+	if err != nil {
+		return types.CorrectiveAction{}, err
+	}
+
+	s.publishAction(ctx, updated, events.ChangeUpdated)
+	return updated, nil
+}
+
+// CompleteCorrectiveAction records the completion note of an action that is not verified yet
+func (s *FindingService) CompleteCorrectiveAction(ctx context.Context, action types.CorrectiveAction) (types.CorrectiveAction, error) {
+	existing, err := s.Actions.GetByIDCorrectiveAction(ctx, action)
+	if err != nil {
+		return types.CorrectiveAction{}, err
+	}
+
+	if _, err := s.openFinding(ctx, existing.FindingID); err != nil {
+		return types.CorrectiveAction{}, err
+	}
+
+	if err := s.Actions.CompleteCorrectiveAction(ctx, existing.ID, action.CompletionNote); err != nil {
+		return types.CorrectiveAction{}, err
+	}
+
+	completed, err := s.Actions.GetByIDCorrectiveAction(ctx, existing)
+	if err != nil {
+		return types.CorrectiveAction{}, err
+	}
+
+	s.publishAction(ctx, completed, events.ChangeUpdated)
+	return completed, nil
+}
+
+// VerifyCorrectiveAction marks a completed action as verified. userID must be on the team of the
+// audit the finding was raised in.
+func (s *FindingService) VerifyCorrectiveAction(ctx context.Context, action types.CorrectiveAction, userID int) (types.CorrectiveAction, error) {
+	existing, err := s.Actions.GetByIDCorrectiveAction(ctx, action)
+	if err != nil {
+		return types.CorrectiveAction{}, err
+	}
+
+	finding, err := s.openFinding(ctx, existing.FindingID)
+	if err != nil {
+		return types.CorrectiveAction{}, err
+	}
+
+	if err := s.Assignments.CheckTeamMember(ctx, finding.AuditID, userID); err != nil {
+		return types.CorrectiveAction{}, err
+	}
+
+	if err := s.Actions.VerifyCorrectiveAction(ctx, existing.ID, userID); err != nil {
+		return types.CorrectiveAction{}, err
+	}
+
+	verified, err := s.Actions.GetByIDCorrectiveAction(ctx, existing)
+	if err != nil {
+		return types.CorrectiveAction{}, err
+	}
+
+	s.publishAction(ctx, verified, events.ChangeUpdated)
+	return verified, nil
+}
+
+// openFinding loads a finding whose corrective actions may still change
+func (s *FindingService) openFinding(ctx context.Context, findingID int) (types.Finding, error) {
+	finding, err := s.Repo.GetByIDFinding(ctx, types.Finding{ID: findingID})
+	if err != nil {
+		return types.Finding{}, err
+	}
+
+	status, err := s.ReferenceData.GetByID(ctx, finding.StatusVal.ID)
+	if err != nil {
+		return types.Finding{}, err
+	}
+	if isClosedFinding(status.Code) {
+		return types.Finding{}, custom_errors.Conflict(ctx, "Finding", fmt.Sprintf("is %s and its corrective actions can no longer be changed", status.Code))
+	}
+	return finding, nil
+}
+
+// validate checks that type and severity are active values of their reference types and sets
+// them on finding
+func (s *FindingService) validate(ctx context.Context, finding *types.Finding) error {
+	findingType, err := s.ReferenceData.Validate(ctx, RefFindingType, finding.TypeVal.ID)
+	if err != nil {
+		return err
+	}
+	severity, err := s.ReferenceData.Validate(ctx, RefFindingSeverity, finding.SeverityVal.ID)
+	if err != nil {
+		return err
+	}
+
+	finding.TypeVal = findingType
+	finding.SeverityVal = severity
+	return nil
+}
+
+// hydrate replaces the reference IDs loaded by the repository with their reference values
+func (s *FindingService) hydrate(ctx context.Context, finding *types.Finding) error {
+	for _, value := range []*types.ReferenceValue{&finding.TypeVal, &finding.SeverityVal, &finding.StatusVal} {
+		resolved, err := s.ReferenceData.GetByID(ctx, value.ID)
+		if err != nil {
+			return err
+		}
+		*value = resolved
+	}
+	return nil
+}
+
+// openFindingStatusIDs returns the IDs of the finding statuses that still need work from the owner
+func openFindingStatusIDs(ctx context.Context, referenceData ReferenceDataServiceInterface) ([]int, error) {
+	statusIDs := []int{}
+	for _, code := range []string{FindingOpen, FindingInProgress, FindingPendingReview} {
+		id, err := referenceData.ResolveID(ctx, RefFindingStatus, code)
+		if err != nil {
+			return nil, err
+		}
+		statusIDs = append(statusIDs, id)
+	}
+	return statusIDs, nil
+}
+
+func (s *FindingService) publish(ctx context.Context, finding types.Finding, changeType events.ChangeType, data any) {
+	if s.EventBus == nil {
+		return
+	}
+	s.EventBus.AsyncPublish(ctx, events.NewFindingEvent(finding.ID, changeType, finding.AuditQuestionID, "", data))
+}
+
+func (s *FindingService) publishAction(ctx context.Context, action types.CorrectiveAction, changeType events.ChangeType) {
+	if s.EventBus == nil {
+		return
+	}
+	s.EventBus.AsyncPublish(ctx, events.NewCorrectiveActionEvent(action.ID, changeType, action.FindingID, "", action))
+}
+
+// checkCorrectiveActions reports a finding without corrective actions, or with an action that is
+// not done yet, as a CONFLICT error
+func checkCorrectiveActions(ctx context.Context, finding types.Finding, state string, done func(types.CorrectiveAction) bool) error {
+	if len(finding.CorrectiveActions) == 0 {
+		return custom_errors.Conflict(ctx, "Finding", "has no corrective actions")
+	}
+	for _, action := range finding.CorrectiveActions {
+		if !done(action) {
+			return custom_errors.Conflict(ctx, "Finding", fmt.Sprintf("corrective action %d is not %s", action.ID, state))
+		}
+	}
+	return nil
+}
+
+// daysBetween counts calendar days from one date to another, ignoring time of day and location
+func daysBetween(from, to time.Time) int {
+	fromDay := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toDay := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(toDay.Sub(fromDay).Hours() / 24)
+}
+
+func canTransitionFinding(from, to string) bool {
+	for _, allowed := range findingTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// isAuditorDecision reports transitions only the audit team may make: settling a finding and
+// anything that follows a review
+func isAuditorDecision(from, to string) bool {
+	return isClosedFinding(to) || from == FindingPendingReview
+}
+
+func isClosedFinding(statusCode string) bool {
+	return statusCode == FindingClosed || statusCode == FindingWaived || statusCode == FindingTransferred
+}
@@ -3,6 +3,8 @@ package services
 import (
 	"ISO_Auditing_Tool/pkg/types"
 	"context"
+	"io"
+	"time"
 )
 
 type DraftServiceInterface interface {
@@ -10,4 +12,196 @@ type DraftServiceInterface interface {
 	GetByID(ctx context.Context, draft types.Draft) (types.Draft, error)
 	Update(ctx context.Context, draft types.Draft) (types.Draft, error)
 	GetAll(ctx context.Context) ([]types.Draft, error)
+	Submit(ctx context.Context, id int, form types.DraftTransitionForm) (types.Draft, error)
+	Approve(ctx context.Context, id int, form types.DraftTransitionForm) (types.Draft, error)
+	Reject(ctx context.Context, id int, form types.DraftTransitionForm) (types.Draft, error)
+	Publish(ctx context.Context, id int, form types.DraftTransitionForm) (types.Draft, error)
+}
+
+// DraftPublisher applies the data of published drafts of one type. The change, the status of the
+// draft and the transition are stored in one transaction.
+type DraftPublisher interface {
+	PublishDraft(ctx context.Context, current, next types.Draft, transition types.DraftTransition) error
+}
+
+type StandardServiceInterface interface {
+	GetAll(ctx context.Context) ([]types.Standard, error)
+	GetByID(ctx context.Context, standard types.Standard) (types.Standard, error)
+	GetByIDWithFullHierarchy(ctx context.Context, standard types.Standard) (types.Standard, error)
+	Create(ctx context.Context, standard types.Standard) (types.Standard, error)
+	Update(ctx context.Context, standard types.Standard) (types.Standard, error)
+	Delete(ctx context.Context, standard types.Standard) error
+}
+
+type RequirementServiceInterface interface {
+	GetTree(ctx context.Context, standardID int) ([]types.Requirement, error)
+	GetByID(ctx context.Context, requirement types.Requirement) (types.Requirement, error)
+	Create(ctx context.Context, requirement types.Requirement) (types.Requirement, error)
+	Update(ctx context.Context, requirement types.Requirement) (types.Requirement, error)
+	Move(ctx context.Context, requirement types.Requirement, parentID, position int) (types.Requirement, error)
+	Reorder(ctx context.Context, standardID, parentID int, requirementIDs []int) error
+}
+
+type QuestionServiceInterface interface {
+	GetByRequirementID(ctx context.Context, requirementID int) ([]types.Question, error)
+	GetByID(ctx context.Context, question types.Question) (types.Question, error)
+	Create(ctx context.Context, question types.Question) (types.Question, error)
+	Update(ctx context.Context, question types.Question) (types.Question, error)
+	Delete(ctx context.Context, question types.Question) error
+	Reorder(ctx context.Context, requirementID int, questionIDs []int) error
+}
+
+type EvidenceServiceInterface interface {
+	GetByID(ctx context.Context, evidence types.Evidence) (types.Evidence, error)
+	Create(ctx context.Context, evidence types.Evidence) (types.Evidence, error)
+	Update(ctx context.Context, evidence types.Evidence) (types.Evidence, error)
+	Delete(ctx context.Context, evidence types.Evidence) error
+	Reorder(ctx context.Context, questionID int, evidenceIDs []int) error
+}
+
+type RequirementLevelServiceInterface interface {
+	GetByStandardID(ctx context.Context, standardID int) ([]types.RequirementLevel, error)
+	GetByID(ctx context.Context, level types.RequirementLevel) (types.RequirementLevel, error)
+	Create(ctx context.Context, level types.RequirementLevel) (types.RequirementLevel, error)
+	Update(ctx context.Context, level types.RequirementLevel) (types.RequirementLevel, error)
+	Delete(ctx context.Context, level types.RequirementLevel) error
+}
+
+type ReferenceDataServiceInterface interface {
+	GetTypes(ctx context.Context) ([]types.ReferenceType, error)
+	GetType(ctx context.Context, typeName string) (types.ReferenceType, error)
+	GetByID(ctx context.Context, id int) (types.ReferenceValue, error)
+	Resolve(ctx context.Context, typeName, code string) (types.ReferenceValue, error)
+	ResolveID(ctx context.Context, typeName, code string) (int, error)
+	Validate(ctx context.Context, typeName string, id int) (types.ReferenceValue, error)
+	Create(ctx context.Context, typeName string, value types.ReferenceValue) (types.ReferenceValue, error)
+	Update(ctx context.Context, value types.ReferenceValue) (types.ReferenceValue, error)
+	Delete(ctx context.Context, value types.ReferenceValue) error
+	Invalidate()
+}
+
+type AuditPlanServiceInterface interface {
+	GetAll(ctx context.Context, filter types.AuditPlanFilter) ([]types.AuditPlan, error)
+	GetByID(ctx context.Context, plan types.AuditPlan) (types.AuditPlan, error)
+	Create(ctx context.Context, plan types.AuditPlan) (types.AuditPlan, error)
+	Update(ctx context.Context, plan types.AuditPlan) (types.AuditPlan, error)
+	ChangeStatus(ctx context.Context, plan types.AuditPlan, statusCode string) (types.AuditPlan, error)
+	Delete(ctx context.Context, plan types.AuditPlan) error
+}
+
+type AuditAssignmentServiceInterface interface {
+	GetByAuditPlanID(ctx context.Context, auditPlanID int) ([]types.AuditAssignment, error)
+	GetByUserID(ctx context.Context, userID int) ([]types.AuditAssignment, error)
+	Assign(ctx context.Context, auditPlanID, userID int) ([]types.AuditAssignment, error)
+	Unassign(ctx context.Context, auditPlanID, userID int) error
+	CheckTeamMember(ctx context.Context, auditPlanID, userID int) error
+}
+
+type AuditChecklistServiceInterface interface {
+	GetByAuditPlanID(ctx context.Context, auditPlanID int) (types.AuditChecklist, error)
+	Generate(ctx context.Context, auditPlanID int, requirementIDs []int) (types.AuditChecklist, error)
+}
+
+type AuditExecutionServiceInterface interface {
+	GetQuestions(ctx context.Context, auditPlanID, userID int) ([]types.AuditQuestion, error)
+	GetQuestion(ctx context.Context, question types.AuditQuestion, userID int) (types.AuditQuestion, error)
+	GetProgress(ctx context.Context, auditPlanID int) (types.AuditProgress, error)
+	ProvideEvidence(ctx context.Context, provided types.EvidenceProvided) (types.EvidenceProvided, error)
+	UpdateEvidence(ctx context.Context, provided types.EvidenceProvided) (types.EvidenceProvided, error)
+	DeleteEvidence(ctx context.Context, provided types.EvidenceProvided) error
+	ReviewEvidence(ctx context.Context, providedID int, review types.EvidenceReviewForm) (types.EvidenceProvided, error)
+	ReplaceEvidence(ctx context.Context, providedID int, replacement types.EvidenceProvided) (types.EvidenceProvided, error)
+	GetEvidenceChain(ctx context.Context, providedID, userID int) ([]types.EvidenceProvided, error)
+}
+
+type CommentServiceInterface interface {
+	GetThread(ctx context.Context, auditQuestionID int) ([]types.Comment, error)
+	Add(ctx context.Context, auditQuestionID int, comment types.Comment) (types.Comment, error)
+	Edit(ctx context.Context, commentID int, form types.CommentEditForm) (types.Comment, error)
+	Delete(ctx context.Context, commentID, userID int) (types.Comment, error)
+	GetRevisions(ctx context.Context, commentID int) ([]types.CommentRevision, error)
+}
+
+type UserServiceInterface interface {
+	GetAll(ctx context.Context, filter types.UserFilter) ([]types.User, error)
+	GetByID(ctx context.Context, id int) (types.User, error)
+	Create(ctx context.Context, form types.UserForm) (types.User, error)
+	ChangeRole(ctx context.Context, id, roleID int) (types.User, error)
+	Deactivate(ctx context.Context, id int, form types.UserDeactivationForm) (types.UserDeactivation, error)
+	Reactivate(ctx context.Context, id int) (types.User, error)
+}
+
+type AuthorizationServiceInterface interface {
+	IsOnAudit(ctx context.Context, auditPlanID int) (bool, error)
+	CheckAuditAccess(ctx context.Context, auditPlanID int) error
+	CheckAuditParticipation(ctx context.Context, auditPlanID int) error
+	AuditPlanOfQuestion(ctx context.Context, auditQuestionID int) (int, error)
+	AuditPlanOfComment(ctx context.Context, commentID int) (int, error)
+	AuditPlanOfFinding(ctx context.Context, findingID int) (int, error)
+	AuditPlanOfCorrectiveAction(ctx context.Context, actionID int) (int, error)
+	AuditPlanOfEvidence(ctx context.Context, evidenceProvidedID int) (int, error)
+}
+
+type AuthServiceInterface interface {
+	Login(ctx context.Context, email, password string) (types.Session, error)
+	StartSession(ctx context.Context, user types.User) (types.Session, error)
+	Authenticate(ctx context.Context, token string) (types.User, error)
+	Logout(ctx context.Context, token string) error
+	SetPassword(ctx context.Context, userID int, password string) error
+	Bootstrap(ctx context.Context, email, password string) error
+}
+
+type SSOServiceInterface interface {
+	Begin(ctx context.Context) (types.SSOAttempt, error)
+	Complete(ctx context.Context, attempt types.SSOAttempt, state, code string) (types.Session, error)
+}
+
+type APITokenServiceInterface interface {
+	GetByUserID(ctx context.Context, userID int) ([]types.APIToken, error)
+	Create(ctx context.Context, userID int, form types.APITokenForm) (types.APIToken, error)
+	Revoke(ctx context.Context, userID, id int) (types.APIToken, error)
+	Authenticate(ctx context.Context, secret string) (types.User, types.APIToken, error)
+}
+
+type ActivityLogServiceInterface interface {
+	GetAll(ctx context.Context, filter types.ActivityFilter) ([]types.ActivityEntry, error)
+}
+
+type EvidenceAccessServiceInterface interface {
+	Redact(ctx context.Context, userID, auditPlanID int, provided []types.EvidenceProvided) error
+	CheckAccess(ctx context.Context, userID, auditPlanID int, provided types.EvidenceProvided, action string) error
+	GetGrants(ctx context.Context, providedID, userID int) ([]types.EvidenceAccessGrant, error)
+	Grant(ctx context.Context, providedID int, form types.EvidenceAccessGrantForm) ([]types.EvidenceAccessGrant, error)
+	Revoke(ctx context.Context, providedID, userID, revokedBy int) error
+}
+
+type EvidenceFileServiceInterface interface {
+	Upload(ctx context.Context, provided types.EvidenceProvided, name string, content io.Reader) (types.EvidenceProvided, error)
+	Download(ctx context.Context, provided types.EvidenceProvided) (types.EvidenceFile, io.ReadCloser, error)
+}
+
+type FindingServiceInterface interface {
+	GetAll(ctx context.Context, filter types.FindingFilter) ([]types.Finding, error)
+	GetOverdue(ctx context.Context, asOf time.Time, warningDays int) (types.OverdueFindingReport, error)
+	GetByID(ctx context.Context, finding types.Finding) (types.Finding, error)
+	Create(ctx context.Context, finding types.Finding) (types.Finding, error)
+	Update(ctx context.Context, finding types.Finding) (types.Finding, error)
+	ChangeStatus(ctx context.Context, finding types.Finding, statusCode string, userID int) (types.Finding, error)
+	Delete(ctx context.Context, finding types.Finding) error
+	AddCorrectiveAction(ctx context.Context, action types.CorrectiveAction) (types.CorrectiveAction, error)
+	UpdateCorrectiveAction(ctx context.Context, action types.CorrectiveAction) (types.CorrectiveAction, error)
+	CompleteCorrectiveAction(ctx context.Context, action types.CorrectiveAction) (types.CorrectiveAction, error)
+	VerifyCorrectiveAction(ctx context.Context, action types.CorrectiveAction, userID int) (types.CorrectiveAction, error)
+}
+
+type RetentionServiceInterface interface {
+	Report(ctx context.Context) (types.RetentionReport, error)
+	Sweep(ctx context.Context, approvedBy int, purgeIDs []int) (types.RetentionReport, error)
+	Run(ctx context.Context)
+}
+
+type OverdueFindingJobInterface interface {
+	Run(ctx context.Context)
+	Check(ctx context.Context) (types.OverdueFindingReport, error)
+	Report(ctx context.Context) (types.OverdueFindingReport, error)
 }
@@ -0,0 +1,30 @@
+// Contains helpers shared by the background jobs started with the server
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Clock returns the current time. Jobs take one so tests can control what "now" is.
+type Clock func() time.Time
+
+// runEvery calls run once right away and then every interval until ctx is cancelled. Failed runs
+// are logged under name and retried on the next tick.
+func runEvery(ctx context.Context, interval time.Duration, name string, run func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := run(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("%s failed: %v", name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -63,16 +63,30 @@ func (s *MaterializedJSONService) HandleEntityChange(ctx context.Context, payloa
 		return fmt.Errorf("expected entity ID to be an integer, got %T", payload.EntityID)
 	}
 
+	// Only the audit content hierarchy is materialized
+	switch entityType {
+	case events.EntityStandard, events.EntityRequirement, events.EntityQuestion, events.EntityEvidence:
+	default:
+		return nil
+	}
+
+	// A deleted standard has no hierarchy left to rebuild
+	if entityType == events.EntityStandard && payload.ChangeType == events.ChangeDeleted {
+		return nil
+	}
+
 	// Debounce the update to avoid rapid successive updates
 	updateKey := fmt.Sprintf("%s_%d", entityType, entityID)
 	s.debounceUpdate(updateKey, func() {
 		// Use a background context for the debounced function
 		bgCtx := context.Background()
 
-		// Update the specific entity
-		if err := s.updateEntity(bgCtx, entityType, entityID, payload.Data); err != nil {
-			// Log the error
-			fmt.Printf("Error updating %s %d: %v\n", entityType, entityID, err)
+		// Update the specific entity, a deleted entity has nothing left to materialize
+		if payload.ChangeType != events.ChangeDeleted {
+			if err := s.updateEntity(bgCtx, entityType, entityID, payload.Data); err != nil {
+				// Log the error
+				fmt.Printf("Error updating %s %d: %v\n", entityType, entityID, err)
+			}
 		}
 
 		// Update any parent entities if needed
@@ -301,7 +315,7 @@ func (s *MaterializedJSONService) updateParentEntities(ctx context.Context, enti
 		if parentType == events.EntityQuestion && parentID != nil {
 			questionID, ok := parentID.(int)
 			if ok {
-				return s.updateQuestion(ctx, questionID, nil)
+				return s.refreshQuestionChain(ctx, questionID)
 			}
 		}
 
@@ -311,14 +325,14 @@ func (s *MaterializedJSONService) updateParentEntities(ctx context.Context, enti
 		if err != nil {
 			return err
 		}
-		return s.updateQuestion(ctx, fetchedEvidence.QuestionID, nil)
+		return s.refreshQuestionChain(ctx, fetchedEvidence.QuestionID)
 
 	case events.EntityQuestion:
 		// If we already know the parent requirement ID, use it
 		if parentType == events.EntityRequirement && parentID != nil {
 			requirementID, ok := parentID.(int)
 			if ok {
-				return s.updateRequirement(ctx, requirementID, nil)
+				return s.refreshRequirementChain(ctx, requirementID)
 			}
 		}
 
@@ -328,7 +342,7 @@ func (s *MaterializedJSONService) updateParentEntities(ctx context.Context, enti
 		if err != nil {
 			return err
 		}
-		return s.updateRequirement(ctx, fetchedQuestion.RequirementID, nil)
+		return s.refreshRequirementChain(ctx, fetchedQuestion.RequirementID)
 
 	case events.EntityRequirement:
 		// If we already know the parent standard ID, use it
@@ -355,6 +369,32 @@ func (s *MaterializedJSONService) updateParentEntities(ctx context.Context, enti
 	return nil
 }
 
+// refreshQuestionChain rebuilds a question and everything above it up to the full standard
+func (s *MaterializedJSONService) refreshQuestionChain(ctx context.Context, questionID int) error {
+	question, err := s.QuestionRepo.GetByIDWithEvidenceQuestion(ctx, types.Question{ID: questionID})
+	if err != nil {
+		return err
+	}
+
+	if err := s.updateQuestion(ctx, questionID, question); err != nil {
+		return err
+	}
+	return s.refreshRequirementChain(ctx, question.RequirementID)
+}
+
+// refreshRequirementChain rebuilds a requirement and the full hierarchy of its standard
+func (s *MaterializedJSONService) refreshRequirementChain(ctx context.Context, requirementID int) error {
+	requirement, err := s.RequirementRepo.GetByIDWithQuestionsRequirement(ctx, types.Requirement{ID: requirementID})
+	if err != nil {
+		return err
+	}
+
+	if err := s.updateRequirement(ctx, requirementID, requirement); err != nil {
+		return err
+	}
+	return s.updateStandardFull(ctx, requirement.StandardID)
+}
+
 func (s *MaterializedJSONService) updateStandardFull(ctx context.Context, standardID int) error {
 	// This builds the complete hierarchy for a standard
 	standard := types.Standard{ID: standardID}
@@ -0,0 +1,113 @@
+// Contains the overdue finding job
+// Periodically looks for open findings past or close to their due date and publishes an event
+// when a finding enters the warning window or becomes overdue
+package services
+
+import (
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"sync"
+	"time"
+)
+
+type OverdueFindingJob struct {
+	Findings    FindingServiceInterface
+	EventBus    *events.EventBus
+	Interval    time.Duration
+	WarningDays int
+	Now         Clock
+
+	mu       sync.Mutex
+	reported map[int]events.EventType // Last event published per finding, so each is sent once
+}
+
+// ensure OverdueFindingJob implements OverdueFindingJobInterface
+var _ OverdueFindingJobInterface = (*OverdueFindingJob)(nil)
+
+// NewOverdueFindingJob creates a job checking every interval, a nil clock uses time.Now
+func NewOverdueFindingJob(
+	findings FindingServiceInterface,
+	eventBus *events.EventBus,
+	interval time.Duration,
+	warningDays int,
+	now Clock,
+) *OverdueFindingJob {
+	if now == nil {
+		now = time.Now
+	}
+	return &OverdueFindingJob{
+		Findings:    findings,
+		EventBus:    eventBus,
+		Interval:    interval,
+		WarningDays: warningDays,
+		Now:         now,
+		reported:    make(map[int]events.EventType),
+	}
+}
+
+// Run checks once right away and then every Interval until ctx is cancelled. Failed checks are
+// logged and retried on the next tick.
+func (j *OverdueFindingJob) Run(ctx context.Context) {
+	runEvery(ctx, j.Interval, "Overdue finding check", func(ctx context.Context) error {
+		_, err := j.Check(ctx)
+		return err
+	})
+}
+
+// Check builds the current report and publishes FindingDueSoon or FindingOverdue for findings
+// whose state changed since the previous check. Events are published synchronously so none are
+// left running once Run returns.
+func (j *OverdueFindingJob) Check(ctx context.Context) (types.OverdueFindingReport, error) {
+	report, err := j.Report(ctx)
+	if err != nil {
+		return types.OverdueFindingReport{}, err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	current := make(map[int]events.EventType, len(report.Overdue)+len(report.DueSoon))
+	check := func(eventType events.EventType, findings []types.Finding) {
+		for _, finding := range findings {
+			current[finding.ID] = eventType
+			if j.reported[finding.ID] != eventType {
+				j.publish(ctx, eventType, finding, report.AsOf)
+			}
+		}
+	}
+	check(events.FindingOverdue, report.Overdue)
+	check(events.FindingDueSoon, report.DueSoon)
+	j.reported = current
+
+	return report, nil
+}
+
+// Report returns the overdue findings as of now without publishing anything
+func (j *OverdueFindingJob) Report(ctx context.Context) (types.OverdueFindingReport, error) {
+	return j.Findings.GetOverdue(ctx, j.Now(), j.WarningDays)
+}
+
+func (j *OverdueFindingJob) publish(ctx context.Context, eventType events.EventType, finding types.Finding, asOf time.Time) {
+	if j.EventBus == nil {
+		return
+	}
+
+	payload := events.FindingDuePayload{
+		FindingID:         finding.ID,
+		AuditID:           finding.AuditID,
+		AuditQuestionID:   finding.AuditQuestionID,
+		ResponsibleUserID: finding.ResponsibleUserID,
+		DueDate:           finding.DueDate,
+		DaysLeft:          daysBetween(asOf, finding.DueDate),
+	}
+
+	event := events.NewFindingDueSoonEvent(payload)
+	if eventType == events.FindingOverdue {
+		event = events.NewFindingOverdueEvent(payload)
+	}
+
+	if err := j.EventBus.Publish(ctx, event); err != nil {
+		events.DefaultErrorCallback(event.Type, err)
+	}
+}
@@ -0,0 +1,112 @@
+// Contains audit question business logic
+// Calls the question and requirement repositories and publishes content changes
+package services
+
+import (
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+)
+
+type QuestionService struct {
+	Repo            repositories.QuestionRepositoryInterface
+	RequirementRepo repositories.RequirementRepositoryInterface
+	EventBus        *events.EventBus
+}
+
+// ensure QuestionService implements QuestionServiceInterface
+var _ QuestionServiceInterface = (*QuestionService)(nil)
+
+func NewQuestionService(
+	repo repositories.QuestionRepositoryInterface,
+	requirementRepo repositories.RequirementRepositoryInterface,
+	eventBus *events.EventBus,
+) *QuestionService {
+	return &QuestionService{Repo: repo, RequirementRepo: requirementRepo, EventBus: eventBus}
+}
+
+func (s *QuestionService) GetByRequirementID(ctx context.Context, requirementID int) ([]types.Question, error) {
+	if _, err := s.RequirementRepo.GetByIDRequirement(ctx, types.Requirement{ID: requirementID}); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetByRequirementIDQuestions(ctx, requirementID)
+}
+
+// GetByID returns a question with its expected evidence
+func (s *QuestionService) GetByID(ctx context.Context, question types.Question) (types.Question, error) {
+	return s.Repo.GetByIDWithEvidenceQuestion(ctx, question)
+}
+
+func (s *QuestionService) Create(ctx context.Context, question types.Question) (types.Question, error) {
+	if _, err := s.RequirementRepo.GetByIDRequirement(ctx, types.Requirement{ID: question.RequirementID}); err != nil {
+		return types.Question{}, err
+	}
+
+	created, err := s.Repo.CreateQuestion(ctx, question)
+	if err != nil {
+		return types.Question{}, err
+	}
+
+	s.publish(ctx, created.ID, created.RequirementID, events.ChangeCreated, created)
+	return created, nil
+}
+
+func (s *QuestionService) Update(ctx context.Context, question types.Question) (types.Question, error) {
+	existing, err := s.Repo.GetByIDQuestion(ctx, question)
+	if err != nil {
+		return types.Question{}, err
+	}
+
+	existing.Question = question.Question
+	existing.Guidance = question.Guidance
+
+	updated, err := s.Repo.UpdateQuestion(ctx, existing)
+	if err != nil {
+		return types.Question{}, err
+	}
+
+	s.publish(ctx, updated.ID, updated.RequirementID, events.ChangeUpdated, updated)
+	return updated, nil
+}
+
+// Delete removes a question together with its expected evidence
+func (s *QuestionService) Delete(ctx context.Context, question types.Question) error {
+	existing, err := s.Repo.GetByIDQuestion(ctx, question)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Repo.DeleteQuestion(ctx, existing.ID); err != nil {
+		return err
+	}
+
+	s.publish(ctx, existing.ID, existing.RequirementID, events.ChangeDeleted, nil)
+	return nil
+}
+
+// Reorder sets the order of every question of a requirement
+func (s *QuestionService) Reorder(ctx context.Context, requirementID int, questionIDs []int) error {
+	requirement, err := s.RequirementRepo.GetByIDRequirement(ctx, types.Requirement{ID: requirementID})
+	if err != nil {
+		return err
+	}
+
+	if err := s.Repo.ReorderQuestions(ctx, requirementID, questionIDs); err != nil {
+		return err
+	}
+
+	// The order belongs to the requirement, so refresh it rather than every question
+	if s.EventBus != nil {
+		s.EventBus.AsyncPublish(ctx, events.NewRequirementEvent(requirement.ID, events.ChangeUpdated, requirement.StandardID, "", nil))
+	}
+	return nil
+}
+
+// publish notifies the materialized caches, which rebuild the question, its requirement and the standard
+func (s *QuestionService) publish(ctx context.Context, questionID, requirementID int, changeType events.ChangeType, data any) {
+	if s.EventBus == nil {
+		return
+	}
+	s.EventBus.AsyncPublish(ctx, events.NewQuestionEvent(questionID, changeType, requirementID, "", data))
+}
@@ -0,0 +1,351 @@
+// Contains reference data business logic
+// Keeps reference_types and reference_values in memory and resolves them by (type name, code)
+package services
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Reference type names, they follow the "<table>.<column>" they classify
+const (
+	RefDraftType       = "drafts.type_id"
+	RefDraftStatus     = "drafts.status_id"
+	RefAuditPlanStatus = "audit_plans.status_id"
+	RefAuditPlanType   = "audit_plans.type_id"
+
+	RefEvidenceProvidedType            = "evidence_provided.type_id"
+	RefEvidenceProvidedConfidentiality = "evidence_provided.confidentiality_id"
+	RefEvidenceProvidedStatus          = "evidence_provided.status_id"
+
+	RefFindingType     = "findings.type_id"
+	RefFindingSeverity = "findings.severity_id"
+	RefFindingStatus   = "findings.status_id"
+
+	RefUserRole = "users.role_id"
+)
+
+// Reference value codes used by the services
+const (
+	DraftTypeAuditContent      = "AUDIT_CONTENT"
+	DraftStatusDraft           = "DRAFT_DRAFT"
+	DraftStatusPendingApproval = "DRAFT_PENDING_APPROVAL"
+	DraftStatusRejected        = "DRAFT_REJECTED"
+	DraftStatusPublished       = "DRAFT_PUBLISHED"
+
+	AuditPlanDraft      = "DRAFT"
+	AuditPlanScheduled  = "SCHEDULED"
+	AuditPlanInProgress = "IN_PROGRESS"
+	AuditPlanReview     = "REVIEW"
+	AuditPlanCompleted  = "COMPLETED"
+	AuditPlanCancelled  = "CANCELLED"
+
+	EvidenceProvidedPending           = "PENDING"
+	EvidenceProvidedAccepted          = "ACCEPTED"
+	EvidenceProvidedRejected          = "REJECTED"
+	EvidenceProvidedPartiallyAccepted = "PARTIALLY_ACCEPTED"
+	EvidenceProvidedExpired           = "EXPIRED"
+	EvidenceProvidedSuperseded        = "SUPERSEDED"
+
+	EvidenceTypeFile  = "FILE"
+	EvidenceTypeImage = "IMAGE"
+	EvidenceTypeVideo = "VIDEO"
+
+	FindingOpen          = "OPEN"
+	FindingInProgress    = "IN_PROGRESS"
+	FindingPendingReview = "PENDING_REVIEW"
+	FindingClosed        = "CLOSED"
+	FindingWaived        = "WAIVED"
+	FindingTransferred   = "TRANSFERRED"
+
+	ConfidentialityPublic       = "PUBLIC"
+	ConfidentialityInternal     = "INTERNAL"
+	ConfidentialityConfidential = "CONFIDENTIAL"
+	ConfidentialityRestricted   = "RESTRICTED"
+	ConfidentialityClassified   = "CLASSIFIED"
+
+	UserRoleAdmin   = "ADMIN"
+	UserRoleLead    = "LEAD"
+	UserRoleSupport = "SUPPORT"
+	UserRoleViewer  = "VIEWER"
+	UserRoleClient  = "CLIENT"
+)
+
+type ReferenceDataService struct {
+	Repo     repositories.ReferenceDataRepositoryInterface
+	EventBus *events.EventBus
+	mutex    sync.RWMutex
+	cache    *referenceCache
+}
+
+// referenceCache is an immutable snapshot of the reference tables, replaced as a whole on reload
+type referenceCache struct {
+	types       []types.ReferenceType
+	typesByName map[string]int
+	typesByID   map[int]int
+	values      map[int]types.ReferenceValue
+	codes       map[referenceKey]int
+}
+
+type referenceKey struct {
+	typeName string
+	code     string
+}
+
+// ensure ReferenceDataService implements ReferenceDataServiceInterface
+var _ ReferenceDataServiceInterface = (*ReferenceDataService)(nil)
+
+// NewReferenceDataService creates the service and drops its cache whenever reference data changes
+func NewReferenceDataService(repo repositories.ReferenceDataRepositoryInterface, eventBus *events.EventBus) *ReferenceDataService {
+	service := &ReferenceDataService{Repo: repo, EventBus: eventBus}
+
+	if eventBus != nil {
+		eventBus.Subscribe(events.EntityChanged, service.handleEntityChange)
+	}
+
+	return service
+}
+
+// GetTypes returns every reference type with its values
+func (s *ReferenceDataService) GetTypes(ctx context.Context) ([]types.ReferenceType, error) {
+	cache, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cache.types, nil
+}
+
+// GetType returns a reference type with its values
+func (s *ReferenceDataService) GetType(ctx context.Context, typeName string) (types.ReferenceType, error) {
+	cache, err := s.load(ctx)
+	if err != nil {
+		return types.ReferenceType{}, err
+	}
+
+	index, ok := cache.typesByName[typeName]
+	if !ok {
+		return types.ReferenceType{}, custom_errors.NotFound(ctx, "Reference type")
+	}
+	return cache.types[index], nil
+}
+
+func (s *ReferenceDataService) GetByID(ctx context.Context, id int) (types.ReferenceValue, error) {
+	cache, err := s.load(ctx)
+	if err != nil {
+		return types.ReferenceValue{}, err
+	}
+
+	value, ok := cache.values[id]
+	if !ok {
+		return types.ReferenceValue{}, custom_errors.NotFound(ctx, "Reference value")
+	}
+	return value, nil
+}
+
+// Resolve returns the value with the given code, e.g. ("drafts.status_id", "DRAFT_PENDING_APPROVAL")
+func (s *ReferenceDataService) Resolve(ctx context.Context, typeName, code string) (types.ReferenceValue, error) {
+	cache, err := s.load(ctx)
+	if err != nil {
+		return types.ReferenceValue{}, err
+	}
+
+	id, ok := cache.codes[referenceKey{typeName: typeName, code: code}]
+	if !ok {
+		return types.ReferenceValue{}, custom_errors.NotFound(ctx, fmt.Sprintf("Reference value %s %s", typeName, code))
+	}
+	return cache.values[id], nil
+}
+
+// ResolveID is Resolve for callers that only store the foreign key
+func (s *ReferenceDataService) ResolveID(ctx context.Context, typeName, code string) (int, error) {
+	value, err := s.Resolve(ctx, typeName, code)
+	if err != nil {
+		return 0, err
+	}
+	return value.ID, nil
+}
+
+// Validate checks that id is an active value of the reference type. Anything else, such as an ID
+// from another type, is reported as INVALID_DATA since it usually comes from a request body.
+func (s *ReferenceDataService) Validate(ctx context.Context, typeName string, id int) (types.ReferenceValue, error) {
+	value, err := s.GetByID(ctx, id)
+	if custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound) {
+		return types.ReferenceValue{}, custom_errors.InvalidData(ctx, fmt.Sprintf("%d is not a valid %s", id, typeName))
+	}
+	if err != nil {
+		return types.ReferenceValue{}, err
+	}
+
+	cache, err := s.load(ctx)
+	if err != nil {
+		return types.ReferenceValue{}, err
+	}
+	if index, ok := cache.typesByID[value.TypeID]; !ok || cache.types[index].Name != typeName || !value.IsActive {
+		return types.ReferenceValue{}, custom_errors.InvalidData(ctx, fmt.Sprintf("%d is not a valid %s", id, typeName))
+	}
+	return value, nil
+}
+
+// Create adds a value to the reference type. Codes are unique within a type.
+func (s *ReferenceDataService) Create(ctx context.Context, typeName string, value types.ReferenceValue) (types.ReferenceValue, error) {
+	referenceType, err := s.GetType(ctx, typeName)
+	if err != nil {
+		return types.ReferenceValue{}, err
+	}
+
+	if _, err := s.Resolve(ctx, typeName, value.Code); err == nil {
+		return types.ReferenceValue{}, custom_errors.Conflict(ctx, "Reference value", fmt.Sprintf("%s already exists in %s", value.Code, typeName))
+	}
+
+	value.TypeID = referenceType.ID
+	created, err := s.Repo.CreateReferenceValue(ctx, value)
+	if err != nil {
+		return types.ReferenceValue{}, err
+	}
+
+	s.changed(ctx, created.ID, created.TypeID, events.ChangeCreated, created)
+	return created, nil
+}
+
+// Update changes the code, name, description or active flag of a value, never its type
+func (s *ReferenceDataService) Update(ctx context.Context, value types.ReferenceValue) (types.ReferenceValue, error) {
+	existing, err := s.Repo.GetByIDReferenceValue(ctx, value)
+	if err != nil {
+		return types.ReferenceValue{}, err
+	}
+
+	if value.Code != existing.Code {
+		cache, err := s.load(ctx)
+		if err != nil {
+			return types.ReferenceValue{}, err
+		}
+		typeName := cache.types[cache.typesByID[existing.TypeID]].Name
+		if _, err := s.Resolve(ctx, typeName, value.Code); err == nil {
+			return types.ReferenceValue{}, custom_errors.Conflict(ctx, "Reference value", fmt.Sprintf("%s already exists in %s", value.Code, typeName))
+		}
+	}
+
+	existing.Code = value.Code
+	existing.Name = value.Name
+	existing.Description = value.Description
+	existing.IsActive = value.IsActive
+
+	updated, err := s.Repo.UpdateReferenceValue(ctx, existing)
+	if err != nil {
+		return types.ReferenceValue{}, err
+	}
+
+	s.changed(ctx, updated.ID, updated.TypeID, events.ChangeUpdated, updated)
+	return updated, nil
+}
+
+// Delete soft deletes a value, rows that already use it keep pointing at it
+func (s *ReferenceDataService) Delete(ctx context.Context, value types.ReferenceValue) error {
+	existing, err := s.Repo.GetByIDReferenceValue(ctx, value)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Repo.DeleteReferenceValue(ctx, existing.ID); err != nil {
+		return err
+	}
+
+	s.changed(ctx, existing.ID, existing.TypeID, events.ChangeDeleted, nil)
+	return nil
+}
+
+// Invalidate drops the cache, the next read reloads it from the database
+func (s *ReferenceDataService) Invalidate() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.cache = nil
+}
+
+// changed drops the local cache right away so the caller reads its own write, and tells the rest
+// of the application through the event bus
+func (s *ReferenceDataService) changed(ctx context.Context, valueID, typeID int, changeType events.ChangeType, data any) {
+	s.Invalidate()
+	if s.EventBus == nil {
+		return
+	}
+	s.EventBus.AsyncPublish(ctx, events.NewReferenceValueEvent(valueID, changeType, typeID, "", data))
+}
+
+func (s *ReferenceDataService) handleEntityChange(ctx context.Context, event events.Event) error {
+	payload, err := events.GetEntityChangePayload(event)
+	if err != nil {
+		return err
+	}
+
+	if payload.EntityType == events.EntityReferenceValue || payload.EntityType == events.EntityReferenceType {
+		s.Invalidate()
+	}
+	return nil
+}
+
+// load returns the cached snapshot, reading both tables on the first call after an invalidation
+func (s *ReferenceDataService) load(ctx context.Context) (*referenceCache, error) {
+	s.mutex.RLock()
+	cache := s.cache
+	s.mutex.RUnlock()
+	if cache != nil {
+		return cache, nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Another caller may have loaded it while we waited for the lock
+	if s.cache != nil {
+		return s.cache, nil
+	}
+
+	referenceTypes, err := s.Repo.GetAllReferenceTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	values, err := s.Repo.GetAllReferenceValues(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cache = &referenceCache{
+		types:       referenceTypes,
+		typesByName: make(map[string]int, len(referenceTypes)),
+		typesByID:   make(map[int]int, len(referenceTypes)),
+		values:      make(map[int]types.ReferenceValue, len(values)),
+		codes:       make(map[referenceKey]int, len(values)),
+	}
+	for i, referenceType := range referenceTypes {
+		cache.typesByName[referenceType.Name] = i
+		cache.typesByID[referenceType.ID] = i
+		cache.types[i].Values = []types.ReferenceValue{}
+	}
+	for _, value := range values {
+		index, ok := cache.typesByID[value.TypeID]
+		if !ok {
+			continue
+		}
+		cache.types[index].Values = append(cache.types[index].Values, value)
+		cache.values[value.ID] = value
+		cache.codes[referenceKey{typeName: cache.types[index].Name, code: value.Code}] = value.ID
+	}
+
+	s.cache = cache
+	return cache, nil
+}
+
+// resolveCode turns a reference code from a request, e.g. a list filter, into its value ID.
+// Unknown codes are reported as INVALID_DATA rather than NOT_FOUND.
+func resolveCode(ctx context.Context, referenceData ReferenceDataServiceInterface, typeName, code string) (int, error) {
+	id, err := referenceData.ResolveID(ctx, typeName, code)
+	if custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound) {
+		return 0, custom_errors.InvalidData(ctx, fmt.Sprintf("%s is not a valid %s", code, typeName))
+	}
+	return id, err
+}
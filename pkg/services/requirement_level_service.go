@@ -0,0 +1,100 @@
+// Contains requirement level business logic
+// Calls the requirement level and standard repositories
+package services
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"fmt"
+)
+
+type RequirementLevelService struct {
+	Repo         repositories.RequirementLevelRepositoryInterface
+	StandardRepo repositories.StandardRepositoryInterface
+}
+
+// ensure RequirementLevelService implements RequirementLevelServiceInterface
+var _ RequirementLevelServiceInterface = (*RequirementLevelService)(nil)
+
+func NewRequirementLevelService(
+	repo repositories.RequirementLevelRepositoryInterface,
+	standardRepo repositories.StandardRepositoryInterface,
+) *RequirementLevelService {
+	return &RequirementLevelService{Repo: repo, StandardRepo: standardRepo}
+}
+
+func (s *RequirementLevelService) GetByStandardID(ctx context.Context, standardID int) ([]types.RequirementLevel, error) {
+	if _, err := s.StandardRepo.GetByIDStandard(ctx, types.Standard{ID: standardID}); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetByStandardIDRequirementLevels(ctx, standardID)
+}
+
+func (s *RequirementLevelService) GetByID(ctx context.Context, level types.RequirementLevel) (types.RequirementLevel, error) {
+	return s.Repo.GetByIDRequirementLevel(ctx, level)
+}
+
+// Create adds a level to a standard. Each level_order can only be used once per standard.
+func (s *RequirementLevelService) Create(ctx context.Context, level types.RequirementLevel) (types.RequirementLevel, error) {
+	if _, err := s.StandardRepo.GetByIDStandard(ctx, types.Standard{ID: level.StandardID}); err != nil {
+		return types.RequirementLevel{}, err
+	}
+
+	if err := s.checkOrderAvailable(ctx, level); err != nil {
+		return types.RequirementLevel{}, err
+	}
+
+	return s.Repo.CreateRequirementLevel(ctx, level)
+}
+
+// Update renames a level. The level_order can only change while no requirement uses the level,
+// otherwise those requirements would no longer match their depth.
+func (s *RequirementLevelService) Update(ctx context.Context, level types.RequirementLevel) (types.RequirementLevel, error) {
+	existing, err := s.Repo.GetByIDRequirementLevel(ctx, level)
+	if err != nil {
+		return types.RequirementLevel{}, err
+	}
+
+	if level.LevelOrder != existing.LevelOrder {
+		used, err := s.Repo.CountRequirementsByLevel(ctx, existing.ID)
+		if err != nil {
+			return types.RequirementLevel{}, err
+		}
+		if used > 0 {
+			return types.RequirementLevel{}, custom_errors.Conflict(ctx, "Requirement level", "is used by requirements, its level_order cannot change")
+		}
+
+		existing.LevelOrder = level.LevelOrder
+		if err := s.checkOrderAvailable(ctx, existing); err != nil {
+			return types.RequirementLevel{}, err
+		}
+	}
+
+	existing.LevelName = level.LevelName
+	return s.Repo.UpdateRequirementLevel(ctx, existing)
+}
+
+func (s *RequirementLevelService) Delete(ctx context.Context, level types.RequirementLevel) error {
+	existing, err := s.Repo.GetByIDRequirementLevel(ctx, level)
+	if err != nil {
+		return err
+	}
+	return s.Repo.DeleteRequirementLevel(ctx, existing.ID)
+}
+
+// checkOrderAvailable makes sure no other level of the standard already uses the level_order
+func (s *RequirementLevelService) checkOrderAvailable(ctx context.Context, level types.RequirementLevel) error {
+	levels, err := s.Repo.GetByStandardIDRequirementLevels(ctx, level.StandardID)
+	if err != nil {
+		return err
+	}
+
+	for _, other := range levels {
+		if other.ID != level.ID && other.LevelOrder == level.LevelOrder {
+			return custom_errors.Conflict(ctx, "Requirement level", fmt.Sprintf("with level_order %d already exists (%s)", level.LevelOrder, other.LevelName))
+		}
+	}
+	return nil
+}
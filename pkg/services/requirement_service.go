@@ -0,0 +1,292 @@
+// Contains requirement tree business logic
+// Calls the requirement and standard repositories and keeps the tree consistent
+package services
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"fmt"
+)
+
+type RequirementService struct {
+	Repo         repositories.RequirementRepositoryInterface
+	StandardRepo repositories.StandardRepositoryInterface
+	LevelRepo    repositories.RequirementLevelRepositoryInterface
+	EventBus     *events.EventBus
+}
+
+// ensure RequirementService implements RequirementServiceInterface
+var _ RequirementServiceInterface = (*RequirementService)(nil)
+
+func NewRequirementService(
+	repo repositories.RequirementRepositoryInterface,
+	standardRepo repositories.StandardRepositoryInterface,
+	levelRepo repositories.RequirementLevelRepositoryInterface,
+	eventBus *events.EventBus,
+) *RequirementService {
+	return &RequirementService{Repo: repo, StandardRepo: standardRepo, LevelRepo: levelRepo, EventBus: eventBus}
+}
+
+// GetTree returns the top level requirements of a standard with their descendants nested in Children
+func (s *RequirementService) GetTree(ctx context.Context, standardID int) ([]types.Requirement, error) {
+	if _, err := s.StandardRepo.GetByIDStandard(ctx, types.Standard{ID: standardID}); err != nil {
+		return nil, err
+	}
+
+	requirements, err := s.Repo.GetByStandardIDRequirements(ctx, standardID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildRequirementTree(requirements), nil
+}
+
+func (s *RequirementService) GetByID(ctx context.Context, requirement types.Requirement) (types.Requirement, error) {
+	return s.Repo.GetByIDRequirement(ctx, requirement)
+}
+
+// Create adds a requirement to a standard, as the last child of ParentID when it is set.
+// The level must be the one the standard defines for that depth.
+func (s *RequirementService) Create(ctx context.Context, requirement types.Requirement) (types.Requirement, error) {
+	if _, err := s.StandardRepo.GetByIDStandard(ctx, types.Standard{ID: requirement.StandardID}); err != nil {
+		return types.Requirement{}, err
+	}
+
+	depth := 1
+	if requirement.ParentID != 0 {
+		if _, err := s.getParent(ctx, requirement.StandardID, requirement.ParentID); err != nil {
+			return types.Requirement{}, err
+		}
+
+		requirements, err := s.Repo.GetByStandardIDRequirements(ctx, requirement.StandardID)
+		if err != nil {
+			return types.Requirement{}, err
+		}
+		depth = requirementDepth(requirements, requirement.ParentID) + 1
+	}
+
+	if err := s.checkLevel(ctx, requirement.StandardID, requirement.LevelID, depth); err != nil {
+		return types.Requirement{}, err
+	}
+
+	created, err := s.Repo.CreateRequirement(ctx, requirement)
+	if err != nil {
+		return types.Requirement{}, err
+	}
+
+	s.publish(ctx, created, events.ChangeCreated)
+	return created, nil
+}
+
+// Update changes the content of a requirement, its position in the tree is left untouched
+func (s *RequirementService) Update(ctx context.Context, requirement types.Requirement) (types.Requirement, error) {
+	existing, err := s.Repo.GetByIDRequirement(ctx, requirement)
+	if err != nil {
+		return types.Requirement{}, err
+	}
+
+	if requirement.LevelID != existing.LevelID {
+		requirements, err := s.Repo.GetByStandardIDRequirements(ctx, existing.StandardID)
+		if err != nil {
+			return types.Requirement{}, err
+		}
+		if err := s.checkLevel(ctx, existing.StandardID, requirement.LevelID, requirementDepth(requirements, existing.ID)); err != nil {
+			return types.Requirement{}, err
+		}
+	}
+
+	existing.LevelID = requirement.LevelID
+	existing.ReferenceCode = requirement.ReferenceCode
+	existing.Name = requirement.Name
+	existing.Description = requirement.Description
+
+	updated, err := s.Repo.UpdateRequirement(ctx, existing)
+	if err != nil {
+		return types.Requirement{}, err
+	}
+
+	s.publish(ctx, updated, events.ChangeUpdated)
+	return updated, nil
+}
+
+// Move reparents a requirement and its subtree. A parentID of 0 makes it a top level requirement.
+// Moves under the requirement itself, one of its descendants or another standard are rejected, as
+// are moves to a depth that does not match the requirement's level.
+func (s *RequirementService) Move(ctx context.Context, requirement types.Requirement, parentID, position int) (types.Requirement, error) {
+	existing, err := s.Repo.GetByIDRequirement(ctx, requirement)
+	if err != nil {
+		return types.Requirement{}, err
+	}
+
+	depth := 1
+	if parentID != 0 {
+		if parentID == existing.ID {
+			return types.Requirement{}, custom_errors.Conflict(ctx, "Requirement", "cannot be its own parent")
+		}
+
+		if _, err := s.getParent(ctx, existing.StandardID, parentID); err != nil {
+			return types.Requirement{}, err
+		}
+
+		requirements, err := s.Repo.GetByStandardIDRequirements(ctx, existing.StandardID)
+		if err != nil {
+			return types.Requirement{}, err
+		}
+		if isDescendant(requirements, existing.ID, parentID) {
+			return types.Requirement{}, custom_errors.Conflict(ctx, "Requirement", "cannot be moved under one of its descendants")
+		}
+		depth = requirementDepth(requirements, parentID) + 1
+	}
+
+	if err := s.checkLevel(ctx, existing.StandardID, existing.LevelID, depth); err != nil {
+		return types.Requirement{}, err
+	}
+
+	existing.ParentID = parentID
+	moved, err := s.Repo.MoveRequirement(ctx, existing, position)
+	if err != nil {
+		return types.Requirement{}, err
+	}
+
+	s.publish(ctx, moved, events.ChangeUpdated)
+	return moved, nil
+}
+
+// Reorder sets the order of the children of parentID (0 for the top level of the standard)
+func (s *RequirementService) Reorder(ctx context.Context, standardID, parentID int, requirementIDs []int) error {
+	if _, err := s.StandardRepo.GetByIDStandard(ctx, types.Standard{ID: standardID}); err != nil {
+		return err
+	}
+
+	if parentID != 0 {
+		if _, err := s.getParent(ctx, standardID, parentID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.Repo.ReorderRequirements(ctx, standardID, parentID, requirementIDs); err != nil {
+		return err
+	}
+
+	for i, id := range requirementIDs {
+		s.publish(ctx, types.Requirement{ID: id, StandardID: standardID, ParentID: parentID, SortOrder: i + 1}, events.ChangeUpdated)
+	}
+	return nil
+}
+
+// getParent loads a prospective parent and makes sure it belongs to the same standard
+func (s *RequirementService) getParent(ctx context.Context, standardID, parentID int) (types.Requirement, error) {
+	parent, err := s.Repo.GetByIDRequirement(ctx, types.Requirement{ID: parentID})
+	if custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound) {
+		return types.Requirement{}, custom_errors.InvalidData(ctx, "parent requirement does not exist")
+	}
+	if err != nil {
+		return types.Requirement{}, err
+	}
+
+	if parent.StandardID != standardID {
+		return types.Requirement{}, custom_errors.Conflict(ctx, "Requirement", "cannot be moved to another standard")
+	}
+	return parent, nil
+}
+
+// checkLevel makes sure levelID is a level of the standard whose level_order equals depth
+func (s *RequirementService) checkLevel(ctx context.Context, standardID, levelID, depth int) error {
+	level, err := s.LevelRepo.GetByIDRequirementLevel(ctx, types.RequirementLevel{ID: levelID})
+	if custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound) {
+		return custom_errors.InvalidData(ctx, "requirement level does not exist")
+	}
+	if err != nil {
+		return err
+	}
+
+	if level.StandardID != standardID {
+		return custom_errors.InvalidData(ctx, "requirement level belongs to another standard")
+	}
+	if level.LevelOrder != depth {
+		return custom_errors.InvalidData(ctx, fmt.Sprintf("level %s has level_order %d but the requirement would be at depth %d", level.LevelName, level.LevelOrder, depth))
+	}
+	return nil
+}
+
+// publish notifies the materialized caches, the standard is the parent of every requirement
+func (s *RequirementService) publish(ctx context.Context, requirement types.Requirement, changeType events.ChangeType) {
+	if s.EventBus == nil {
+		return
+	}
+	s.EventBus.AsyncPublish(ctx, events.NewRequirementEvent(requirement.ID, changeType, requirement.StandardID, "", requirement))
+}
+
+// isDescendant reports whether candidateID sits somewhere below ancestorID
+func isDescendant(requirements []types.Requirement, ancestorID, candidateID int) bool {
+	parents := make(map[int]int, len(requirements))
+	for _, requirement := range requirements {
+		parents[requirement.ID] = requirement.ParentID
+	}
+
+	// The visited set guards against cycles already present in the data
+	visited := make(map[int]bool)
+	for current := parents[candidateID]; current != 0 && !visited[current]; current = parents[current] {
+		if current == ancestorID {
+			return true
+		}
+		visited[current] = true
+	}
+	return false
+}
+
+// requirementDepth returns how deep requirementID sits in the tree, top level requirements being 1
+func requirementDepth(requirements []types.Requirement, requirementID int) int {
+	parents := make(map[int]int, len(requirements))
+	for _, requirement := range requirements {
+		parents[requirement.ID] = requirement.ParentID
+	}
+
+	depth := 1
+	visited := map[int]bool{requirementID: true}
+	for current := parents[requirementID]; current != 0 && !visited[current]; current = parents[current] {
+		depth++
+		visited[current] = true
+	}
+	return depth
+}
+
+// buildRequirementTree nests a flat, ordered requirement list. Requirements whose parent is missing
+// from the list are treated as top level so nothing is dropped.
+func buildRequirementTree(requirements []types.Requirement) []types.Requirement {
+	known := make(map[int]bool, len(requirements))
+	for _, requirement := range requirements {
+		known[requirement.ID] = true
+	}
+
+	children := make(map[int][]types.Requirement)
+	for _, requirement := range requirements {
+		parentID := requirement.ParentID
+		if !known[parentID] {
+			parentID = 0
+		}
+		children[parentID] = append(children[parentID], requirement)
+	}
+
+	var attach func(parentID int, depth int) []types.Requirement
+	attach = func(parentID int, depth int) []types.Requirement {
+		nodes := children[parentID]
+		// A cycle in the stored data can never be deeper than the number of requirements
+		if depth > len(requirements) {
+			return nil
+		}
+		for i := range nodes {
+			nodes[i].Children = attach(nodes[i].ID, depth+1)
+		}
+		return nodes
+	}
+
+	tree := attach(0, 0)
+	if tree == nil {
+		return []types.Requirement{}
+	}
+	return tree
+}
@@ -0,0 +1,195 @@
+// Contains evidence retention business logic
+// Marks provided evidence EXPIRED once its retention period ends and, after a grace period and
+// only with approval, soft deletes it and purges its file from the blob store
+package services
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/storage"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+type RetentionService struct {
+	ProvidedRepo  repositories.EvidenceProvidedRepositoryInterface
+	ReferenceData ReferenceDataServiceInterface
+	Blobs         storage.BlobStore
+	GraceDays     int
+	Interval      time.Duration
+	Now           Clock
+	EventBus      *events.EventBus
+}
+
+// ensure RetentionService implements RetentionServiceInterface
+var _ RetentionServiceInterface = (*RetentionService)(nil)
+
+// NewRetentionService creates a service sweeping every interval, a nil clock uses time.Now
+func NewRetentionService(
+	providedRepo repositories.EvidenceProvidedRepositoryInterface,
+	referenceData ReferenceDataServiceInterface,
+	blobs storage.BlobStore,
+	graceDays int,
+	interval time.Duration,
+	now Clock,
+	eventBus *events.EventBus,
+) *RetentionService {
+	if now == nil {
+		now = time.Now
+	}
+	return &RetentionService{
+		ProvidedRepo:  providedRepo,
+		ReferenceData: referenceData,
+		Blobs:         blobs,
+		GraceDays:     graceDays,
+		Interval:      interval,
+		Now:           now,
+		EventBus:      eventBus,
+	}
+}
+
+// Report is the dry run of a sweep. It lists the provided evidence that would be marked EXPIRED
+// and everything past its grace period that may be approved for purging, without changing anything.
+func (s *RetentionService) Report(ctx context.Context) (types.RetentionReport, error) {
+	report, _, _, err := s.plan(ctx)
+	if err != nil {
+		return types.RetentionReport{}, err
+	}
+	report.DryRun = true
+	return report, nil
+}
+
+// Sweep marks all provided evidence past its retention period EXPIRED and purges the listed IDs
+// that are past their grace period. Purging needs the approving user, IDs that are not eligible
+// are skipped. Purge failures are reported per item and retried by the next approved sweep.
+func (s *RetentionService) Sweep(ctx context.Context, approvedBy int, purgeIDs []int) (types.RetentionReport, error) {
+	if len(purgeIDs) > 0 && approvedBy == 0 {
+		return types.RetentionReport{}, custom_errors.InvalidData(ctx, "purging provided evidence requires approved_by")
+	}
+
+	report, expire, expiredStatusID, err := s.plan(ctx)
+	if err != nil {
+		return types.RetentionReport{}, err
+	}
+
+	for i, provided := range expire {
+		if err := s.ProvidedRepo.UpdateEvidenceProvidedStatus(ctx, provided.ID, provided.StatusVal.ID, expiredStatusID); err != nil {
+			// Changed since it was loaded, the next sweep picks it up again
+			if custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict) {
+				report.Expire[i].Error = err.Error()
+				continue
+			}
+			return types.RetentionReport{}, err
+		}
+		provided.StatusVal.ID = expiredStatusID
+		s.publish(ctx, provided, events.ChangeUpdated)
+	}
+
+	approved := make(map[int]bool, len(purgeIDs))
+	for _, id := range purgeIDs {
+		approved[id] = true
+	}
+
+	purged := []types.RetentionItem{}
+	for _, item := range report.Purge {
+		if !approved[item.EvidenceProvidedID] {
+			continue
+		}
+		if err := s.purge(ctx, item); err != nil {
+			item.Error = err.Error()
+		} else {
+			log.Printf("Purged provided evidence %d, approved by user %d", item.EvidenceProvidedID, approvedBy)
+		}
+		purged = append(purged, item)
+	}
+
+	report.ApprovedBy = approvedBy
+	report.Purge = purged
+	return report, nil
+}
+
+// Run sweeps once right away and then every Interval until ctx is cancelled. Unattended sweeps
+// only mark evidence EXPIRED, purging always goes through an approved Sweep.
+func (s *RetentionService) Run(ctx context.Context) {
+	runEvery(ctx, s.Interval, "Evidence retention sweep", func(ctx context.Context) error {
+		_, err := s.Sweep(ctx, 0, nil)
+		return err
+	})
+}
+
+// plan loads the provided evidence past its retention period and splits it into what still has
+// to be marked EXPIRED and what is past its grace period. The records to expire are returned
+// alongside the report together with the ID of the EXPIRED status.
+func (s *RetentionService) plan(ctx context.Context) (types.RetentionReport, []types.EvidenceProvided, int, error) {
+	expiredStatusID, err := s.ReferenceData.ResolveID(ctx, RefEvidenceProvidedStatus, EvidenceProvidedExpired)
+	if err != nil {
+		return types.RetentionReport{}, nil, 0, err
+	}
+
+	asOf := s.Now()
+	due, err := s.ProvidedRepo.GetExpiredEvidenceProvided(ctx, asOf)
+	if err != nil {
+		return types.RetentionReport{}, nil, 0, err
+	}
+
+	report := types.RetentionReport{
+		AsOf:      asOf,
+		GraceDays: s.GraceDays,
+		Expire:    []types.RetentionItem{},
+		Purge:     []types.RetentionItem{},
+	}
+	expire := []types.EvidenceProvided{}
+	for _, provided := range due {
+		item := types.RetentionItem{
+			EvidenceProvidedID: provided.ID,
+			AuditQuestionID:    provided.AuditQuestionID,
+			ExpiresAt:          provided.ExpiresAt,
+			PurgeAfter:         provided.ExpiresAt.AddDate(0, 0, s.GraceDays),
+			HasFile:            provided.File != nil && provided.File.Key != "",
+		}
+		if provided.StatusVal.ID != expiredStatusID {
+			report.Expire = append(report.Expire, item)
+			expire = append(expire, provided)
+		}
+		if !asOf.Before(item.PurgeAfter) {
+			report.Purge = append(report.Purge, item)
+		}
+	}
+
+	return report, expire, expiredStatusID, nil
+}
+
+// purge removes the file before the record, so a failed blob delete leaves the record in place to
+// be purged again rather than an orphaned blob nobody knows about
+func (s *RetentionService) purge(ctx context.Context, item types.RetentionItem) error {
+	provided, err := s.ProvidedRepo.GetByIDEvidenceProvided(ctx, types.EvidenceProvided{ID: item.EvidenceProvidedID})
+	if err != nil {
+		return err
+	}
+
+	if provided.File != nil && provided.File.Key != "" {
+		err := s.Blobs.Delete(ctx, provided.File.Key)
+		if err != nil && !errors.Is(err, storage.ErrBlobNotFound) {
+			return fmt.Errorf("failed to purge evidence file: %w", err)
+		}
+	}
+
+	if err := s.ProvidedRepo.PurgeEvidenceProvided(ctx, provided.ID); err != nil {
+		return err
+	}
+
+	s.publish(ctx, provided, events.ChangeDeleted)
+	return nil
+}
+
+func (s *RetentionService) publish(ctx context.Context, provided types.EvidenceProvided, change events.ChangeType) {
+	if s.EventBus == nil {
+		return
+	}
+	s.EventBus.AsyncPublish(ctx, events.NewEvidenceProvidedEvent(provided.ID, change, provided.AuditQuestionID, "", provided))
+}
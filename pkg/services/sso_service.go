@@ -0,0 +1,188 @@
+// Contains OpenID Connect single sign-on business logic
+// Matches the verified email of the identity provider to a user, provisions unknown users when
+// allowed and keeps roles in step with the groups the provider reports
+package services
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/oidc"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"crypto/subtle"
+	"log"
+	"strings"
+)
+
+// ssoRoleRank orders the roles a set of groups can map to, the first one held wins
+var ssoRoleRank = []string{UserRoleAdmin, UserRoleLead, UserRoleSupport, UserRoleClient, UserRoleViewer}
+
+// OIDCClient is the identity provider side of single sign-on, implemented by oidc.Client
+type OIDCClient interface {
+	AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error)
+	Exchange(ctx context.Context, code, verifier, nonce string) (oidc.Claims, error)
+}
+
+type SSOService struct {
+	Client         OIDCClient
+	GroupRoles     map[string]string
+	ProvisionUsers bool
+	Users          repositories.UserRepositoryInterface
+	ReferenceData  ReferenceDataServiceInterface
+	Auth           AuthServiceInterface
+	EventBus       *events.EventBus
+}
+
+// ensure SSOService implements SSOServiceInterface
+var _ SSOServiceInterface = (*SSOService)(nil)
+
+func NewSSOService(
+	client OIDCClient,
+	groupRoles map[string]string,
+	provisionUsers bool,
+	users repositories.UserRepositoryInterface,
+	referenceData ReferenceDataServiceInterface,
+	auth AuthServiceInterface,
+	eventBus *events.EventBus,
+) *SSOService {
+	return &SSOService{
+		Client:         client,
+		GroupRoles:     groupRoles,
+		ProvisionUsers: provisionUsers,
+		Users:          users,
+		ReferenceData:  referenceData,
+		Auth:           auth,
+		EventBus:       eventBus,
+	}
+}
+
+// Begin starts a sign in with the identity provider. The attempt has to be kept by the browser
+// and handed to Complete when the provider sends the user back.
+func (s *SSOService) Begin(ctx context.Context) (types.SSOAttempt, error) {
+	var attempt types.SSOAttempt
+	var err error
+	if attempt.State, err = oidc.NewState(); err != nil {
+		return types.SSOAttempt{}, err
+	}
+	if attempt.Nonce, err = oidc.NewState(); err != nil {
+		return types.SSOAttempt{}, err
+	}
+	if attempt.Verifier, err = oidc.NewVerifier(); err != nil {
+		return types.SSOAttempt{}, err
+	}
+
+	if attempt.URL, err = s.Client.AuthCodeURL(ctx, attempt.State, attempt.Nonce, attempt.Verifier); err != nil {
+		return types.SSOAttempt{}, err
+	}
+	return attempt, nil
+}
+
+// Complete redeems the code the provider sent the user back with and opens a session for the
+// user with the verified email of the ID token. Unknown emails are provisioned as VIEWER, or as
+// the role their groups map to, when provisioning is on and turned away otherwise.
+func (s *SSOService) Complete(ctx context.Context, attempt types.SSOAttempt, state, code string) (types.Session, error) {
+	if attempt.State == "" || subtle.ConstantTimeCompare([]byte(attempt.State), []byte(state)) != 1 {
+		return types.Session{}, custom_errors.Unauthorized(ctx, "the sign in attempt has expired, please try again")
+	}
+
+	claims, err := s.Client.Exchange(ctx, code, attempt.Verifier, attempt.Nonce)
+	if err != nil {
+		log.Printf("Single sign-on failed: %v", err)
+		return types.Session{}, custom_errors.Unauthorized(ctx, "the identity provider did not confirm the sign in")
+	}
+
+	email := strings.ToLower(strings.TrimSpace(claims.Email))
+	if email == "" || claims.EmailVerified == nil || !*claims.EmailVerified {
+		return types.Session{}, custom_errors.Unauthorized(ctx, "the identity provider did not confirm an email address")
+	}
+
+	roleCode := s.groupRole(claims.Groups)
+	user, _, err := s.Users.GetCredentialsUser(ctx, email)
+	switch {
+	case custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound):
+		user, err = s.provision(ctx, email, claims.Name, roleCode)
+		if err != nil {
+			return types.Session{}, err
+		}
+	case err != nil:
+		return types.Session{}, err
+	case roleCode != "":
+		if err := s.syncRole(ctx, &user, roleCode); err != nil {
+			return types.Session{}, err
+		}
+	}
+
+	return s.Auth.StartSession(ctx, user)
+}
+
+// groupRole returns the highest ranked role the groups map to, empty when none is mapped
+func (s *SSOService) groupRole(groups []string) string {
+	held := map[string]bool{}
+	for _, group := range groups {
+		if role, ok := s.GroupRoles[group]; ok {
+			held[role] = true
+		}
+	}
+	for _, role := range ssoRoleRank {
+		if held[role] {
+			return role
+		}
+	}
+	return ""
+}
+
+// provision creates a user for an email seen for the first time. An inactive user keeping the
+// email is turned away like an unknown one.
+func (s *SSOService) provision(ctx context.Context, email, name, roleCode string) (types.User, error) {
+	if !s.ProvisionUsers {
+		return types.User{}, custom_errors.Unauthorized(ctx, "there is no active account for "+email)
+	}
+	if roleCode == "" {
+		roleCode = UserRoleViewer
+	}
+	roleID, err := s.ReferenceData.ResolveID(ctx, RefUserRole, roleCode)
+	if err != nil {
+		return types.User{}, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name, _, _ = strings.Cut(email, "@")
+	}
+	user, err := s.Users.CreateUser(ctx, types.User{Email: email, Name: name, RoleVal: types.ReferenceValue{ID: roleID}})
+	if custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict) {
+		return types.User{}, custom_errors.Unauthorized(ctx, "there is no active account for "+email)
+	}
+	if err != nil {
+		return types.User{}, err
+	}
+
+	s.publish(ctx, user, events.ChangeCreated)
+	return user, nil
+}
+
+// syncRole gives an existing user the role their groups map to
+func (s *SSOService) syncRole(ctx context.Context, user *types.User, roleCode string) error {
+	roleID, err := s.ReferenceData.ResolveID(ctx, RefUserRole, roleCode)
+	if err != nil {
+		return err
+	}
+	if user.RoleVal.ID == roleID {
+		return nil
+	}
+
+	if err := s.Users.UpdateUserRole(ctx, user.ID, roleID); err != nil {
+		return err
+	}
+	user.RoleVal = types.ReferenceValue{ID: roleID}
+	s.publish(ctx, *user, events.ChangeUpdated)
+	return nil
+}
+
+func (s *SSOService) publish(ctx context.Context, user types.User, changeType events.ChangeType) {
+	if s.EventBus == nil {
+		return
+	}
+	s.EventBus.AsyncPublish(ctx, events.NewUserEvent(user.ID, changeType, user.RoleVal.ID, "", user))
+}
@@ -3,19 +3,69 @@
 package services
 
 import (
+	"ISO_Auditing_Tool/pkg/events"
 	"ISO_Auditing_Tool/pkg/repositories"
 	"ISO_Auditing_Tool/pkg/types"
 	"context"
 )
 
 type StandardService struct {
-	Repo repositories.StandardRepositoryInterface
+	Repo     repositories.StandardRepositoryInterface
+	EventBus *events.EventBus
 }
 
-func NewStandardService(repo repositories.StandardRepositoryInterface) *StandardService {
-	return &StandardService{Repo: repo}
+// ensure StandardService implements StandardServiceInterface
+var _ StandardServiceInterface = (*StandardService)(nil)
+
+func NewStandardService(repo repositories.StandardRepositoryInterface, eventBus *events.EventBus) *StandardService {
+	return &StandardService{Repo: repo, EventBus: eventBus}
+}
+
+func (s *StandardService) GetAll(ctx context.Context) ([]types.Standard, error) {
+	return s.Repo.GetAllStandards(ctx)
 }
 
 func (s *StandardService) GetByID(ctx context.Context, standard types.Standard) (types.Standard, error) {
 	return s.Repo.GetByIDStandard(ctx, standard)
 }
+
+func (s *StandardService) GetByIDWithFullHierarchy(ctx context.Context, standard types.Standard) (types.Standard, error) {
+	return s.Repo.GetByIDWithFullHierarchyStandard(ctx, standard)
+}
+
+func (s *StandardService) Create(ctx context.Context, standard types.Standard) (types.Standard, error) {
+	created, err := s.Repo.CreateStandard(ctx, standard)
+	if err != nil {
+		return types.Standard{}, err
+	}
+
+	s.publish(ctx, created.ID, events.ChangeCreated, created)
+	return created, nil
+}
+
+func (s *StandardService) Update(ctx context.Context, standard types.Standard) (types.Standard, error) {
+	updated, err := s.Repo.UpdateStandard(ctx, standard)
+	if err != nil {
+		return types.Standard{}, err
+	}
+
+	s.publish(ctx, updated.ID, events.ChangeUpdated, updated)
+	return updated, nil
+}
+
+func (s *StandardService) Delete(ctx context.Context, standard types.Standard) error {
+	if err := s.Repo.DeleteStandard(ctx, standard.ID); err != nil {
+		return err
+	}
+
+	s.publish(ctx, standard.ID, events.ChangeDeleted, nil)
+	return nil
+}
+
+// publish notifies the materialized caches that a standard changed
+func (s *StandardService) publish(ctx context.Context, standardID int, changeType events.ChangeType, data any) {
+	if s.EventBus == nil {
+		return
+	}
+	s.EventBus.AsyncPublish(ctx, events.NewStandardEvent(standardID, changeType, "", data))
+}
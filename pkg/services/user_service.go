@@ -0,0 +1,195 @@
+// Contains user directory business logic
+// Creates users with a users.role_id role and hands over the open findings of deactivated users
+package services
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"strings"
+)
+
+type UserService struct {
+	Repo          repositories.UserRepositoryInterface
+	ReferenceData ReferenceDataServiceInterface
+	EventBus      *events.EventBus
+}
+
+// ensure UserService implements UserServiceInterface
+var _ UserServiceInterface = (*UserService)(nil)
+
+func NewUserService(
+	repo repositories.UserRepositoryInterface,
+	referenceData ReferenceDataServiceInterface,
+	eventBus *events.EventBus,
+) *UserService {
+	return &UserService{Repo: repo, ReferenceData: referenceData, EventBus: eventBus}
+}
+
+// GetAll returns the users of the directory ordered by name, active users only unless the filter
+// includes inactive ones
+func (s *UserService) GetAll(ctx context.Context, filter types.UserFilter) ([]types.User, error) {
+	users, err := s.Repo.GetAllUsers(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range users {
+		if err := s.hydrate(ctx, &users[i]); err != nil {
+			return nil, err
+		}
+	}
+	return users, nil
+}
+
+// GetByID returns a user with their role, active or not
+func (s *UserService) GetByID(ctx context.Context, id int) (types.User, error) {
+	user, err := s.Repo.GetByIDUser(ctx, types.User{ID: id})
+	if err != nil {
+		return types.User{}, err
+	}
+
+	if err := s.hydrate(ctx, &user); err != nil {
+		return types.User{}, err
+	}
+	return user, nil
+}
+
+// Create adds an active user. The email is stored trimmed and in lower case, so it stays unique
+// however it is typed. Without a password the user cannot sign in until one is set.
+func (s *UserService) Create(ctx context.Context, form types.UserForm) (types.User, error) {
+	role, err := s.ReferenceData.Validate(ctx, RefUserRole, form.RoleID)
+	if err != nil {
+		return types.User{}, err
+	}
+
+	var passwordHash string
+	if form.Password != "" {
+		if passwordHash, err = hashPassword(ctx, form.Password); err != nil {
+			return types.User{}, err
+		}
+	}
+
+	user := types.User{
+		Email:   strings.ToLower(strings.TrimSpace(form.Email)),
+		Name:    strings.TrimSpace(form.Name),
+		RoleVal: role,
+	}
+	created, err := s.Repo.CreateUser(ctx, user)
+	if err != nil {
+		return types.User{}, err
+	}
+	if passwordHash != "" {
+		if err := s.Repo.UpdateUserPassword(ctx, created.ID, passwordHash); err != nil {
+			return types.User{}, err
+		}
+	}
+
+	created.RoleVal = role
+	s.publish(ctx, created, events.ChangeCreated, created)
+	return created, nil
+}
+
+// ChangeRole gives a user another users.role_id role. Keeping the current role changes nothing.
+func (s *UserService) ChangeRole(ctx context.Context, id, roleID int) (types.User, error) {
+	user, err := s.GetByID(ctx, id)
+	if err != nil {
+		return types.User{}, err
+	}
+
+	role, err := s.ReferenceData.Validate(ctx, RefUserRole, roleID)
+	if err != nil {
+		return types.User{}, err
+	}
+	if user.RoleVal.ID == role.ID {
+		return user, nil
+	}
+
+	if err := s.Repo.UpdateUserRole(ctx, user.ID, role.ID); err != nil {
+		return types.User{}, err
+	}
+
+	user.RoleVal = role
+	s.publish(ctx, user, events.ChangeUpdated, user)
+	return user, nil
+}
+
+// Deactivate marks a user inactive. Their findings that are OPEN, IN_PROGRESS or PENDING_REVIEW
+// move to form.ReassignTo, an active user other than them, or are flagged as needing a new owner
+// when no one is given.
+func (s *UserService) Deactivate(ctx context.Context, id int, form types.UserDeactivationForm) (types.UserDeactivation, error) {
+	user, err := s.GetByID(ctx, id)
+	if err != nil {
+		return types.UserDeactivation{}, err
+	}
+	if !user.IsActive {
+		return types.UserDeactivation{}, custom_errors.Conflict(ctx, "User", "is already inactive")
+	}
+
+	if form.ReassignTo != 0 {
+		if form.ReassignTo == user.ID {
+			return types.UserDeactivation{}, custom_errors.InvalidData(ctx, "reassign_to must be another user than the one deactivated")
+		}
+		target, err := s.Repo.GetByIDUser(ctx, types.User{ID: form.ReassignTo})
+		if custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound) {
+			return types.UserDeactivation{}, custom_errors.InvalidData(ctx, "reassign_to is not a user")
+		}
+		if err != nil {
+			return types.UserDeactivation{}, err
+		}
+		if !target.IsActive {
+			return types.UserDeactivation{}, custom_errors.InvalidData(ctx, "reassign_to is not an active user")
+		}
+	}
+
+	statusIDs, err := openFindingStatusIDs(ctx, s.ReferenceData)
+	if err != nil {
+		return types.UserDeactivation{}, err
+	}
+
+	report, err := s.Repo.DeactivateUser(ctx, user.ID, form.ReassignTo, statusIDs)
+	if err != nil {
+		return types.UserDeactivation{}, err
+	}
+
+	user.IsActive = false
+	report.User = user
+	s.publish(ctx, user, events.ChangeUpdated, report)
+	return report, nil
+}
+
+// Reactivate lets an inactive user in again with the role they had. Findings handed over when
+// they were deactivated stay with their new owner.
+func (s *UserService) Reactivate(ctx context.Context, id int) (types.User, error) {
+	user, err := s.GetByID(ctx, id)
+	if err != nil {
+		return types.User{}, err
+	}
+
+	if err := s.Repo.ReactivateUser(ctx, user.ID); err != nil {
+		return types.User{}, err
+	}
+
+	user.IsActive = true
+	s.publish(ctx, user, events.ChangeUpdated, user)
+	return user, nil
+}
+
+// hydrate replaces the role ID set by the repository with the full reference value
+func (s *UserService) hydrate(ctx context.Context, user *types.User) error {
+	role, err := s.ReferenceData.GetByID(ctx, user.RoleVal.ID)
+	if err != nil {
+		return err
+	}
+	user.RoleVal = role
+	return nil
+}
+
+func (s *UserService) publish(ctx context.Context, user types.User, changeType events.ChangeType, data any) {
+	if s.EventBus == nil {
+		return
+	}
+	s.EventBus.AsyncPublish(ctx, events.NewUserEvent(user.ID, changeType, user.RoleVal.ID, "", data))
+}
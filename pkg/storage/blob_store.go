@@ -0,0 +1,140 @@
+// Package storage keeps the bytes of uploaded evidence files outside the database
+package storage
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// ErrBlobNotFound is returned by Get for a key that holds no blob
+var ErrBlobNotFound = errors.New("blob not found")
+
+// ErrBlobTooLarge is returned by PutWithDigest when the content exceeds the size limit
+var ErrBlobTooLarge = errors.New("blob exceeds the maximum size")
+
+// BlobStore stores opaque blobs under keys such as "evidence/12/5f2c...". Keys use forward
+// slashes and never start with one. Put reads r until EOF without holding it in memory.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Config holds blob store configuration. Backend is "local" or "s3".
+type Config struct {
+	Backend  string
+	LocalDir string
+	MaxSize  int64
+	S3       S3Config
+}
+
+// LoadConfigFromEnv loads blob store configuration from environment variables
+func LoadConfigFromEnv() *Config {
+	backend := os.Getenv("BLOB_STORE")
+	if backend == "" {
+		backend = "local"
+	}
+
+	localDir := os.Getenv("BLOB_LOCAL_DIR")
+	if localDir == "" {
+		localDir = "storage"
+	}
+
+	maxSize := int64(100 << 20) // Default: 100 MiB
+	maxSizeStr := os.Getenv("BLOB_MAX_SIZE_BYTES")
+	if maxSizeStr != "" {
+		if val, err := strconv.ParseInt(maxSizeStr, 10, 64); err == nil && val > 0 {
+			maxSize = val
+		}
+	}
+
+	s3Timeout := defaultS3Timeout
+	s3TimeoutStr := os.Getenv("S3_TIMEOUT_SECONDS")
+	if s3TimeoutStr != "" {
+		if val, err := strconv.Atoi(s3TimeoutStr); err == nil && val > 0 {
+			s3Timeout = time.Duration(val) * time.Second
+		}
+	}
+
+	return &Config{
+		Backend:  backend,
+		LocalDir: localDir,
+		MaxSize:  maxSize,
+		S3: S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          os.Getenv("S3_REGION"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			Timeout:         s3Timeout,
+		},
+	}
+}
+
+// New creates the blob store selected by config
+func New(config *Config) (BlobStore, error) {
+	switch config.Backend {
+	case "local":
+		return NewLocalBlobStore(config.LocalDir)
+	case "s3":
+		return NewS3BlobStore(config.S3)
+	default:
+		return nil, fmt.Errorf("unknown blob store backend %q", config.Backend)
+	}
+}
+
+// Digest describes stored content
+type Digest struct {
+	SHA256   string
+	Size     int64
+	MIMEType string
+}
+
+// PutWithDigest streams r into store under key while hashing it and sniffing its MIME type from
+// the first 512 bytes. Content larger than maxSize is rejected with ErrBlobTooLarge and nothing is
+// left in the store.
+func PutWithDigest(ctx context.Context, store BlobStore, key string, r io.Reader, maxSize int64) (Digest, error) {
+	buffered := bufio.NewReaderSize(r, 512)
+	head, err := buffered.Peek(512)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return Digest{}, fmt.Errorf("failed to read content: %w", err)
+	}
+	mimeType := http.DetectContentType(head)
+
+	hash := sha256.New()
+	counter := &limitCounter{max: maxSize}
+	content := io.TeeReader(buffered, io.MultiWriter(hash, counter))
+
+	if err := store.Put(ctx, key, content); err != nil {
+		if errors.Is(err, ErrBlobTooLarge) {
+			return Digest{}, ErrBlobTooLarge
+		}
+		return Digest{}, err
+	}
+
+	return Digest{SHA256: hex.EncodeToString(hash.Sum(nil)), Size: counter.n, MIMEType: mimeType}, nil
+}
+
+// limitCounter counts the bytes written to it and fails once more than max were written, which
+// aborts the TeeReader feeding it
+type limitCounter struct {
+	n   int64
+	max int64
+}
+
+func (c *limitCounter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	if c.max > 0 && c.n > c.max {
+		return 0, ErrBlobTooLarge
+	}
+	return len(p), nil
+}
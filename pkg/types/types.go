@@ -81,15 +81,13 @@ func DecodeForm(values url.Values, dst any) error {
 	return nil
 }
 
+// CommentForm adds a comment on behalf of the signed in user
 type CommentForm struct {
-	UserID string `json:"user_id" form:"user_id" binding:"required"`
-	Text   string `json:"text" form:"text" binding:"required"`
+	Text     string `json:"text" form:"text" binding:"required" validate:"required,max=65535"`
+	ParentID int    `json:"parent_id" form:"parent_id"` // Comment replied to, zero starts a thread
 }
 
 func (f *CommentForm) Validate() error {
-	if f.UserID == "" {
-		return fmt.Errorf("user_id: %w", ErrRequired)
-	}
 	if f.Text == "" {
 		return fmt.Errorf("text: %w", ErrRequired)
 	}
@@ -104,15 +102,25 @@ type Standard struct {
 	Requirements []Requirement `json:"requirements"`
 }
 
+// RequirementLevel names one depth of a standard's requirement tree, LevelOrder 1 being the top level
+type RequirementLevel struct {
+	ID         int    `json:"id"`
+	StandardID int    `json:"standard_id"`
+	LevelOrder int    `json:"level_order"`
+	LevelName  string `json:"level_name"`
+}
+
 type Requirement struct {
-	ID            int        `json:"id"`
-	StandardID    int        `json:"standard_id"`
-	LevelID       int        `json:"level_id"`
-	ParentID      int        `json:"parent_id"`
-	ReferenceCode string     `json:"reference_code"`
-	Name          string     `json:"name"`
-	Description   string     `json:"description"`
-	Questions     []Question `json:"questions"`
+	ID            int           `json:"id"`
+	StandardID    int           `json:"standard_id"`
+	LevelID       int           `json:"level_id"`
+	ParentID      int           `json:"parent_id"`
+	ReferenceCode string        `json:"reference_code"`
+	Name          string        `json:"name"`
+	Description   string        `json:"description"`
+	SortOrder     int           `json:"sort_order"`
+	Questions     []Question    `json:"questions"`
+	Children      []Requirement `json:"children,omitempty"`
 }
 
 type Question struct {
@@ -120,6 +128,7 @@ type Question struct {
 	RequirementID int        `json:"requirement_id"`
 	Question      string     `json:"question"`
 	Guidance      string     `json:"guidance"`
+	SortOrder     int        `json:"sort_order"`
 	CreatedAt     time.Time  `json:"created_at"`
 	UpdatedAt     time.Time  `json:"updated_at"`
 	Evidence      []Evidence `json:"evidence"`
@@ -130,10 +139,21 @@ type Evidence struct {
 	QuestionID int            `json:"question_id"`
 	TypeVal    ReferenceValue `json:"type"`
 	Expected   string         `json:"expected"`
+	SortOrder  int            `json:"sort_order"`
 	CreatedAt  time.Time      `json:"created_at"`
 	UpdatedAt  time.Time      `json:"updated_at"`
 }
 
+// ReferenceType groups the values allowed in one lookup column, named "<table>.<column>"
+type ReferenceType struct {
+	ID          int              `json:"id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	Values      []ReferenceValue `json:"values,omitempty"`
+}
+
 type ReferenceValue struct {
 	ID          int       `json:"id"`
 	TypeID      int       `json:"type_id"`
@@ -145,12 +165,199 @@ type ReferenceValue struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+// AuditPlan schedules an audit of a standard. Status and Type are reference values of
+// audit_plans.status_id and audit_plans.type_id.
+type AuditPlan struct {
+	ID            int            `json:"id"`
+	StandardID    int            `json:"standard_id"`
+	LeadAuditorID int            `json:"lead_auditor_id"`
+	Name          string         `json:"name"`
+	StatusVal     ReferenceValue `json:"status"`
+	ScheduledDate time.Time      `json:"scheduled_date"`
+	Team          string         `json:"team"`
+	Scope         string         `json:"scope"`
+	TypeVal       ReferenceValue `json:"type"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// AuditPlanFilter narrows the audit plan list, zero values are ignored. Status and Type are
+// reference codes that the service resolves into StatusID and TypeID.
+type AuditPlanFilter struct {
+	StandardID int
+	AuditorID  int // lead or support auditor
+	Status     string
+	Type       string
+	StatusID   int
+	TypeID     int
+	From       time.Time
+	To         time.Time
+}
+
+// Roles of an auditor on an audit plan
+const (
+	AuditRoleLead    = "LEAD"
+	AuditRoleSupport = "SUPPORT"
+)
+
+// AuditAssignment puts an auditor on the team of an audit plan, either as its lead auditor or as
+// one of its support auditors
+type AuditAssignment struct {
+	AuditPlanID   int            `json:"audit_plan_id"`
+	AuditPlanName string         `json:"audit_plan_name"`
+	StatusVal     ReferenceValue `json:"status"`
+	ScheduledDate time.Time      `json:"scheduled_date"`
+	UserID        int            `json:"user_id"`
+	UserName      string         `json:"user_name"`
+	Role          string         `json:"role"`
+	AssignedAt    time.Time      `json:"assigned_at"`
+}
+
+// AuditQuestion is a question of the standard copied into the checklist of an audit, with the
+// expected evidence and guidance to ask for and what the auditee provided
 type AuditQuestion struct {
 	ID               int                `json:"id"`
 	AuditID          int                `json:"audit_id"`
 	QuestionID       int                `json:"question_id"`
+	RequirementID    int                `json:"requirement_id"`
+	ReferenceCode    string             `json:"reference_code"`
+	Question         string             `json:"question"`
+	Guidance         string             `json:"guidance"`
+	Evidence         []Evidence         `json:"expected_evidence"`
 	EvidenceProvided []EvidenceProvided `json:"evidence_provided"`
 	Comments         []Comment          `json:"comments"`
+	Satisfied        bool               `json:"satisfied"` // every expected evidence has an ACCEPTED submission
+}
+
+// AuditProgress counts how far the questions of an audit have been worked through
+type AuditProgress struct {
+	AuditPlanID      int `json:"audit_plan_id"`
+	Questions        int `json:"questions"`
+	Answered         int `json:"answered"`  // at least one provided evidence
+	Satisfied        int `json:"satisfied"` // every expected evidence has an ACCEPTED submission
+	Unanswered       int `json:"unanswered"`
+	Commented        int `json:"commented"`
+	EvidenceProvided int `json:"evidence_provided"`
+}
+
+// AuditChecklist is the scope of an audit plan, the requirement subtrees picked by the lead
+// auditor, and the audit questions it expands to
+type AuditChecklist struct {
+	AuditPlanID    int                 `json:"audit_plan_id"`
+	RequirementIDs []int               `json:"requirement_ids"`
+	Questions      []AuditQuestion     `json:"questions"`
+	Changes        *AuditChecklistDiff `json:"changes,omitempty"`
+}
+
+// AuditChecklistDiff counts the audit questions a checklist generation added, removed and kept
+type AuditChecklistDiff struct {
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+	Kept    int `json:"kept"`
+}
+
+// AuditChecklistForm selects the requirement subtrees of an audit plan, an empty list clears it
+type AuditChecklistForm struct {
+	RequirementIDs []int `json:"requirement_ids" validate:"required"`
+}
+
+// Finding is an issue raised against an audit question. Type, Severity and Status are reference
+// values of findings.type_id, findings.severity_id and findings.status_id.
+type Finding struct {
+	ID                int                `json:"id"`
+	AuditID           int                `json:"audit_id"`
+	AuditQuestionID   int                `json:"audit_question_id"`
+	QuestionID        int                `json:"question_id"`
+	ReferenceCode     string             `json:"reference_code"`
+	TypeVal           ReferenceValue     `json:"type"`
+	SeverityVal       ReferenceValue     `json:"severity"`
+	Description       string             `json:"description"`
+	DueDate           time.Time          `json:"due_date"`
+	ResponsibleUserID int                `json:"responsible_user_id"`
+	ResponsibleUser   string             `json:"responsible_user"`
+	StatusVal         ReferenceValue     `json:"status"`
+	StatusReason      string             `json:"status_reason"`
+	NeedsOwner        bool               `json:"needs_owner"` // the responsible user was deactivated
+	CreatedBy         int                `json:"created_by"`
+	CreatedAt         time.Time          `json:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at"`
+	CorrectiveActions []CorrectiveAction `json:"corrective_actions,omitempty"`
+}
+
+// CorrectiveAction is work planned to resolve a finding. The owner completes it with a note and
+// an auditor verifies it, a finding can only be closed once all of its actions are verified.
+type CorrectiveAction struct {
+	ID             int        `json:"id"`
+	FindingID      int        `json:"finding_id"`
+	Action         string     `json:"action"`
+	OwnerID        int        `json:"owner_id"`
+	Owner          string     `json:"owner"`
+	TargetDate     time.Time  `json:"target_date"`
+	CompletionNote string     `json:"completion_note"`
+	CompletedAt    *time.Time `json:"completed_at"`
+	VerifiedBy     int        `json:"verified_by,omitempty"`
+	VerifiedAt     *time.Time `json:"verified_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// FindingFilter narrows the findings register, zero values are ignored. Severity and Status are
+// reference codes that the service resolves into SeverityID and StatusID. StatusIDs matches any of
+// the listed statuses, e.g. all statuses that are not final.
+type FindingFilter struct {
+	AuditID           int
+	AuditorID         int // lead or support auditor of the audit of the finding
+	ResponsibleUserID int
+	FollowUpUserID    int // responsible for the finding or owner of one of its corrective actions
+	Severity          string
+	Status            string
+	SeverityID        int
+	StatusID          int
+	StatusIDs         []int
+	DueFrom           time.Time
+	DueTo             time.Time
+	NeedsOwner        bool
+}
+
+// OverdueFindingReport lists the open findings past their due date and those due within the
+// warning window, both ordered by due date
+type OverdueFindingReport struct {
+	AsOf        time.Time `json:"as_of"`
+	WarningDays int       `json:"warning_days"`
+	Overdue     []Finding `json:"overdue"`
+	DueSoon     []Finding `json:"due_soon"`
+}
+
+// FindingQuery holds the query parameters of the findings list, shared by the API and the web
+// pages. Dates use YYYY-MM-DD and due_to is inclusive.
+type FindingQuery struct {
+	AuditID           int       `form:"audit_id"`
+	AuditorID         int       `form:"auditor_id"`
+	ResponsibleUserID int       `form:"owner_id"`
+	Severity          string    `form:"severity"`
+	Status            string    `form:"status"`
+	DueFrom           time.Time `form:"due_from" time_format:"2006-01-02"`
+	DueTo             time.Time `form:"due_to" time_format:"2006-01-02"`
+	NeedsOwner        bool      `form:"needs_owner"`
+}
+
+// Filter converts the query into a repository filter. The repository compares the upper bound
+// with <, so due_to moves to the start of the next day to include it.
+func (q FindingQuery) Filter() FindingFilter {
+	filter := FindingFilter{
+		AuditID:           q.AuditID,
+		AuditorID:         q.AuditorID,
+		ResponsibleUserID: q.ResponsibleUserID,
+		Severity:          q.Severity,
+		Status:            q.Status,
+		DueFrom:           q.DueFrom,
+		DueTo:             q.DueTo,
+		NeedsOwner:        q.NeedsOwner,
+	}
+	if !filter.DueTo.IsZero() {
+		filter.DueTo = filter.DueTo.AddDate(0, 0, 1)
+	}
+	return filter
 }
 
 type Draft struct {
@@ -164,10 +371,33 @@ type Draft struct {
 	UserID          int             `json:"user_id"`
 	ApproverID      int             `json:"approver_id"`
 	ApprovalComment string          `json:"approval_comment"`
+	PublishedAt     *time.Time      `json:"published_at"`
 	PublishError    string          `json:"publish_error"`
 	CreatedAt       time.Time       `json:"created_at"`
 	UpdatedAt       time.Time       `json:"updated_at"`
 	ExpiresAt       time.Time       `json:"expires_at"`
+	// Steps of the approval workflow, oldest first. Only loaded for a single draft.
+	Transitions []DraftTransition `json:"transitions,omitempty"`
+}
+
+// DraftTransition is one step of the approval workflow of a draft. Approvals keep the draft
+// pending, so their from and to status are the same.
+type DraftTransition struct {
+	ID           int       `json:"id"`
+	DraftID      int       `json:"draft_id"`
+	Action       string    `json:"action"` // submit, approve, reject or publish
+	FromStatusID int       `json:"from_status_id"`
+	ToStatusID   int       `json:"to_status_id"`
+	UserID       int       `json:"user_id"`
+	Comment      string    `json:"comment"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// DraftTransitionForm submits, approves, rejects or publishes a draft. A comment is always
+// required. UserID is the signed in user.
+type DraftTransitionForm struct {
+	Comment string `json:"comment" validate:"required,max=65535"`
+	UserID  int    `json:"-"`
 }
 
 type MaterializedJSONQuery struct {
@@ -198,29 +428,272 @@ type MaterializedHTMLQuery struct {
 
 type ISOStandardForm struct {
 	// Name    string        `form:"name" validate:"required,min=3,max=100,not_boolean"`
-	Name string `form:"name" validate:"required,min=3,max=100,not_boolean"`
+	Name        string `json:"name" form:"name" validate:"required,min=3,max=100,not_boolean"`
+	Description string `json:"description" form:"description" validate:"max=255"`
+	Version     string `json:"version" form:"version" validate:"required,max=50,not_boolean"`
 	// Clauses []*ClauseForm `form:"clauses,omitempty"`
 }
 
+// EvidenceProvided is what an auditee handed over for an expected evidence during an audit.
+// Type, Confidentiality and Status are reference values of the evidence_provided columns.
 type EvidenceProvided struct {
-	ID              int    `json:"id"`
-	EvidenceID      int    `json:"evidence_id"`
-	AuditQuestionID int    `json:"audit_question_id"`
-	Provided        string `json:"provided"`
+	ID                 int             `json:"id"`
+	EvidenceID         int             `json:"evidence_id"`
+	AuditQuestionID    int             `json:"audit_question_id"`
+	UserID             int             `json:"user_id"`
+	Provided           string          `json:"provided"`
+	TypeVal            ReferenceValue  `json:"type"`
+	ConfidentialityVal ReferenceValue  `json:"confidentiality"`
+	StatusVal          ReferenceValue  `json:"status"`
+	RetentionDays      int             `json:"retention_days"`
+	File               *EvidenceFile   `json:"file,omitempty"`
+	Review             *EvidenceReview `json:"review,omitempty"`
+	SupersededBy       int             `json:"superseded_by,omitempty"` // Provided evidence replacing this one
+	ExpiresAt          time.Time       `json:"expires_at"`              // CreatedAt plus RetentionDays
+	Redacted           bool            `json:"redacted,omitempty"`      // Provided and File withheld from the caller
+	CreatedAt          time.Time       `json:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+}
+
+// EvidenceReview is the decision of an auditor on provided evidence
+type EvidenceReview struct {
+	Reason     string    `json:"reason"`
+	ReviewedBy int       `json:"reviewed_by"`
+	ReviewedAt time.Time `json:"reviewed_at"`
+}
+
+// EvidenceReviewForm accepts or rejects PENDING provided evidence. Status is ACCEPTED,
+// PARTIALLY_ACCEPTED or REJECTED and a reason is always required. ReviewedBy is the signed in user.
+type EvidenceReviewForm struct {
+	Status     string `json:"status" validate:"required"`
+	Reason     string `json:"reason" validate:"required,max=65535"`
+	ReviewedBy int    `json:"-"`
+}
+
+// EvidenceAccessGrant lets one user see a CLASSIFIED provided evidence
+type EvidenceAccessGrant struct {
+	EvidenceProvidedID int       `json:"evidence_provided_id"`
+	UserID             int       `json:"user_id"`
+	User               string    `json:"user"`
+	GrantedBy          int       `json:"granted_by"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// EvidenceAccessGrantForm grants UserID access to CLASSIFIED provided evidence on behalf of
+// GrantedBy, the signed in user
+type EvidenceAccessGrantForm struct {
+	UserID    int `json:"user_id" validate:"required"`
+	GrantedBy int `json:"-"`
+}
+
+// EvidenceFile describes the file uploaded for provided evidence of a FILE, IMAGE or VIDEO type.
+// The content itself lives in the blob store under Key.
+type EvidenceFile struct {
+	Key      string `json:"-"`
+	Name     string `json:"name"`
+	Size     int64  `json:"size"`
+	SHA256   string `json:"sha256"`
+	MIMEType string `json:"mime_type"`
+}
+
+// RetentionItem is provided evidence the retention engine expires or purges. Error is set when
+// a purge failed and will be retried by the next sweep.
+type RetentionItem struct {
+	EvidenceProvidedID int       `json:"evidence_provided_id"`
+	AuditQuestionID    int       `json:"audit_question_id"`
+	ExpiresAt          time.Time `json:"expires_at"`
+	PurgeAfter         time.Time `json:"purge_after"`
+	HasFile            bool      `json:"has_file"`
+	Error              string    `json:"error,omitempty"`
+}
+
+// RetentionReport lists the provided evidence a sweep expires and purges. A dry run only reports
+// what a sweep would do, Purge then lists everything eligible for approval.
+type RetentionReport struct {
+	AsOf       time.Time       `json:"as_of"`
+	GraceDays  int             `json:"grace_days"`
+	DryRun     bool            `json:"dry_run"`
+	ApprovedBy int             `json:"approved_by,omitempty"`
+	Expire     []RetentionItem `json:"expire"`
+	Purge      []RetentionItem `json:"purge"`
+}
+
+// RetentionSweepForm approves the purge of the listed provided evidence, taken from a dry-run
+// report. Evidence that is not eligible any more is skipped. The signed in user approves it.
+type RetentionSweepForm struct {
+	PurgeIDs []int `json:"purge_ids"`
+}
+
+// EvidenceProvidedForm represents the payload used to record or update provided evidence.
+// TypeID and ConfidentialityID are reference_values.id of their evidence_provided columns. The
+// evidence is recorded on behalf of the signed in user.
+type EvidenceProvidedForm struct {
+	EvidenceID        int    `json:"evidence_id" validate:"required"`
+	Provided          string `json:"provided" validate:"required,max=255"`
+	TypeID            int    `json:"type_id" validate:"required"`
+	ConfidentialityID int    `json:"confidentiality_id" validate:"required"`
+	RetentionDays     int    `json:"retention_days" validate:"required"`
 }
 
 type Comment struct {
-	ID     int    `json:"id"`
-	UserID string `json:"user_id"`
-	Text   string `json:"text"`
-	User   User   `json:"user"`
+	ID              int        `json:"id"`
+	ParentID        int        `json:"parent_id,omitempty"`
+	AuditQuestionID int        `json:"audit_question_id,omitempty"`
+	UserID          int        `json:"user_id"`
+	Text            string     `json:"text"` // Empty once deleted
+	User            UserRef    `json:"user"`
+	Mentions        []UserRef  `json:"mentions,omitempty"`
+	Replies         []Comment  `json:"replies,omitempty"`
+	Deleted         bool       `json:"deleted,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	EditedAt        *time.Time `json:"edited_at,omitempty"`
+}
+
+// CommentRevision is the text a comment had before an edit
+type CommentRevision struct {
+	ID        int       `json:"id"`
+	CommentID int       `json:"comment_id"`
+	Text      string    `json:"text"`
+	EditedBy  int       `json:"edited_by"`
+	EditedAt  time.Time `json:"edited_at"`
+}
+
+// CommentEditForm replaces the text of a comment, only its author may edit it. UserID is the
+// signed in user.
+type CommentEditForm struct {
+	UserID int    `json:"-" form:"-"`
+	Text   string `json:"text" form:"text" validate:"required,max=65535"`
 }
 
+// User is an account of the users table. Only the ID of its role is set by the repository.
 type User struct {
-	ID   string `json:"id"`
+	ID          int            `json:"id"`
+	Email       string         `json:"email"`
+	Name        string         `json:"name"`
+	RoleVal     ReferenceValue `json:"role"`
+	IsActive    bool           `json:"is_active"`
+	LastLoginAt *time.Time     `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// UserRef names a user where only who it is matters, such as the author of a comment
+type UserRef struct {
+	ID   int    `json:"id"`
 	Name string `json:"name"`
 }
 
+// UserForm represents the payload used to create a user, RoleID is a users.role_id reference value.
+// Without a password the user cannot sign in until one is set.
+type UserForm struct {
+	Email    string `json:"email" validate:"required,email,max=255"`
+	Name     string `json:"name" validate:"required,min=2,max=255"`
+	RoleID   int    `json:"role_id" validate:"required"`
+	Password string `json:"password" validate:"omitempty,min=12,max=72"`
+}
+
+// UserPasswordForm sets the password a user signs in with. bcrypt ignores anything past 72 bytes.
+type UserPasswordForm struct {
+	Password string `json:"password" validate:"required,min=12,max=72"`
+}
+
+// LoginForm signs a user in with their email and password
+type LoginForm struct {
+	Email    string `json:"email" form:"email" validate:"required,max=255"`
+	Password string `json:"password" form:"password" validate:"required,max=72"`
+	Next     string `json:"-" form:"next"` // Local path the web login returns to
+}
+
+// Session is a signed in user. Token is only set when the session is created, the database keeps
+// its SHA-256.
+type Session struct {
+	Token     string    `json:"-"`
+	User      User      `json:"user"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// APIToken lets scripts act as the user who created it, with the HTTP methods its scopes allow.
+// Token is only set in the response that creates it, the database keeps its SHA-256.
+type APIToken struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"user_id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	Token      string     `json:"token,omitempty"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// APITokenForm names a new API token, lists its scopes and says in how many days it expires
+type APITokenForm struct {
+	Name          string   `json:"name" validate:"required,max=100"`
+	Scopes        []string `json:"scopes" validate:"required"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"required"`
+}
+
+// SSOAttempt is a single sign-on login waiting for the identity provider to send the user back.
+// The browser keeps it in a short lived cookie, URL is where the user is sent to sign in.
+type SSOAttempt struct {
+	URL      string `json:"-"`
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	Next     string `json:"next"`
+}
+
+// UserRoleForm gives a user another users.role_id reference value
+type UserRoleForm struct {
+	RoleID int `json:"role_id" validate:"required"`
+}
+
+// UserDeactivationForm deactivates a user. Open findings owned by the user move to ReassignTo when
+// it is set and are flagged as needing a new owner otherwise.
+type UserDeactivationForm struct {
+	ReassignTo int `json:"reassign_to"`
+}
+
+// UserDeactivation reports what happened to the open findings of a deactivated user
+type UserDeactivation struct {
+	User       User  `json:"user"`
+	ReassignTo int   `json:"reassign_to,omitempty"`
+	Reassigned []int `json:"reassigned_finding_ids"`
+	Flagged    []int `json:"flagged_finding_ids"`
+}
+
+// UserFilter narrows the user directory, zero values are ignored
+type UserFilter struct {
+	RoleID          int  `form:"role_id"`
+	IncludeInactive bool `form:"include_inactive"`
+}
+
+// ActivityEntry is one write recorded in the append-only activity log. Before and After hold the
+// row as it was and as it became, Before is null for creates and After for hard deletes.
+type ActivityEntry struct {
+	ID          int64           `json:"id"`
+	ActorUserID *int            `json:"actor_user_id"` // null for background jobs
+	Action      string          `json:"action"`
+	EntityType  string          `json:"entity_type"`
+	EntityID    int             `json:"entity_id"`
+	Before      json.RawMessage `json:"before"`
+	After       json.RawMessage `json:"after"`
+	RequestID   string          `json:"request_id"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// ActivityFilter narrows the activity log, zero values are ignored. Times use RFC 3339, from is
+// inclusive and to exclusive. Entries come newest first, BeforeID pages back from an entry.
+type ActivityFilter struct {
+	EntityType string    `form:"entity_type"`
+	EntityID   int       `form:"entity_id"`
+	UserID     int       `form:"user_id"`
+	From       time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	BeforeID   int64     `form:"before_id"`
+	Limit      int       `form:"limit"`
+}
+
 // AuditContentModification represents a modification to audit content
 type AuditContentModification struct {
 	ContentType     string          `json:"content_type"`     // "requirement", "question", "evidence"
@@ -255,6 +728,117 @@ type AuditContentDraft struct {
 	UserID   int                      `json:"user_id"` // Admin making the change
 }
 
+// RequirementForm represents the payload used to create or update a requirement
+type RequirementForm struct {
+	ParentID      int    `json:"parent_id"`
+	LevelID       int    `json:"level_id" validate:"required"`
+	ReferenceCode string `json:"reference_code" validate:"required,max=50"`
+	Name          string `json:"name" validate:"required,min=2,max=255,not_boolean"`
+	Description   string `json:"description"`
+}
+
+// RequirementLevelForm represents the payload used to create or update a requirement level
+type RequirementLevelForm struct {
+	LevelOrder int    `json:"level_order" validate:"required,min=1"`
+	LevelName  string `json:"level_name" validate:"required,min=2,max=255,not_boolean"`
+}
+
+// ReferenceValueForm represents the payload used to create or update a reference value.
+// The type is taken from the path on create and cannot be changed afterwards.
+type ReferenceValueForm struct {
+	Code        string `json:"code" validate:"required,min=2,max=50,not_boolean"`
+	Name        string `json:"name" validate:"required,min=2,max=100,not_boolean"`
+	Description string `json:"description" validate:"max=255"`
+	IsActive    bool   `json:"is_active"`
+}
+
+// AuditPlanForm represents the payload used to create or update an audit plan.
+// TypeID is a reference_values.id of audit_plans.type_id, the status is changed separately.
+type AuditPlanForm struct {
+	StandardID    int       `json:"standard_id" validate:"required"`
+	LeadAuditorID int       `json:"lead_auditor_id" validate:"required"`
+	Name          string    `json:"name" validate:"required,min=2,max=255,not_boolean"`
+	ScheduledDate time.Time `json:"scheduled_date" validate:"required"`
+	Team          string    `json:"team" validate:"required,max=255"`
+	Scope         string    `json:"scope" validate:"required,max=255"`
+	TypeID        int       `json:"type_id" validate:"required"`
+}
+
+// AuditPlanStatusForm moves an audit plan to the status with the given code, e.g. SCHEDULED
+type AuditPlanStatusForm struct {
+	Status string `json:"status" validate:"required"`
+}
+
+// AuditAssignmentForm adds a user as support auditor of an audit plan
+type AuditAssignmentForm struct {
+	UserID int `json:"user_id" validate:"required"`
+}
+
+// FindingForm represents the payload used to raise or update a finding. The audit question is
+// taken from the path on create, TypeID and SeverityID are reference_values.id. It is raised by the
+// signed in user.
+type FindingForm struct {
+	TypeID            int       `json:"type_id" validate:"required"`
+	SeverityID        int       `json:"severity_id" validate:"required"`
+	Description       string    `json:"description" validate:"required,min=2,max=65535"`
+	DueDate           time.Time `json:"due_date" validate:"required"`
+	ResponsibleUserID int       `json:"responsible_user_id" validate:"required"`
+}
+
+// FindingStatusForm moves a finding to the status with the given code, e.g. PENDING_REVIEW.
+// Waiving requires a reason. The change is made by the signed in user.
+type FindingStatusForm struct {
+	Status string `json:"status" validate:"required"`
+	Reason string `json:"reason" validate:"max=65535"`
+}
+
+// CorrectiveActionForm represents the payload used to plan or change a corrective action
+type CorrectiveActionForm struct {
+	Action     string    `json:"action" validate:"required,min=2,max=255"`
+	OwnerID    int       `json:"owner_id" validate:"required"`
+	TargetDate time.Time `json:"target_date" validate:"required"`
+}
+
+// CorrectiveActionCompleteForm records what was done to carry out a corrective action
+type CorrectiveActionCompleteForm struct {
+	CompletionNote string `json:"completion_note" validate:"required,max=65535"`
+}
+
+// RequirementMoveForm moves a requirement under a new parent (0 for top level) at the given sibling index
+type RequirementMoveForm struct {
+	ParentID int `json:"parent_id"`
+	Position int `json:"position"`
+}
+
+// RequirementReorderForm lists every child of a parent in the desired order
+type RequirementReorderForm struct {
+	ParentID       int   `json:"parent_id"`
+	RequirementIDs []int `json:"requirement_ids" validate:"required"`
+}
+
+// QuestionForm represents the payload used to create or update an audit question
+type QuestionForm struct {
+	Question string `json:"question" validate:"required,min=5,max=255,not_boolean"`
+	Guidance string `json:"guidance"`
+}
+
+// QuestionReorderForm lists every question of a requirement in the desired order
+type QuestionReorderForm struct {
+	QuestionIDs []int `json:"question_ids" validate:"required"`
+}
+
+// EvidenceForm represents the payload used to create or update expected evidence.
+// TypeID is a reference_values.id of the evidence.type_id reference type.
+type EvidenceForm struct {
+	TypeID   int    `json:"type_id" validate:"required"`
+	Expected string `json:"expected" validate:"required,min=2,not_boolean"`
+}
+
+// EvidenceReorderForm lists every expected evidence of a question in the desired order
+type EvidenceReorderForm struct {
+	EvidenceIDs []int `json:"evidence_ids" validate:"required"`
+}
+
 // RequirementEditForm represents the form for editing requirements
 type RequirementEditForm struct {
 	RequirementID int    `form:"requirement_id" validate:"required"`
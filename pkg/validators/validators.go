@@ -4,6 +4,7 @@ import (
 	"ISO_Auditing_Tool/pkg/custom_errors"
 	"context"
 	"fmt"
+	"strconv"
 	"sync"
 
 	"github.com/go-playground/validator/v10"
@@ -57,9 +58,9 @@ func ValidateStruct(data any) *custom_errors.CustomError {
 			case "required":
 				return custom_errors.EmptyField(context.TODO(), "string", fieldName)
 			case "min":
-				return custom_errors.MinFieldCharacters(context.TODO(), fieldName, 2)
+				return custom_errors.MinFieldCharacters(context.TODO(), fieldName, paramAsInt(e.Param()))
 			case "max":
-				return custom_errors.MaxFieldCharacters(context.TODO(), fieldName, 100)
+				return custom_errors.MaxFieldCharacters(context.TODO(), fieldName, paramAsInt(e.Param()))
 			case "not_boolean":
 				return custom_errors.IsABool(context.TODO(), fieldName)
 			}
@@ -68,3 +69,12 @@ func ValidateStruct(data any) *custom_errors.CustomError {
 	// return custom_errors.NewError(context.TODO(), custom_errors.ErrInvalidData, "Unexpected validation error", http.StatusInternalServerError, err)
 	return custom_errors.ErrInvalidFormData
 }
+
+// paramAsInt reads the numeric parameter of a min/max tag, e.g. the 3 in "min=3"
+func paramAsInt(param string) int {
+	n, err := strconv.Atoi(param)
+	if err != nil {
+		return 0
+	}
+	return n
+}
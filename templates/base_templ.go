@@ -9,6 +9,7 @@ import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
 import "ISO_Auditing_Tool/templates/components"
+import "strconv"
 import "time"
 
 func Base(content templ.Component) templ.Component {
@@ -44,7 +45,7 @@ func Base(content templ.Component) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = components.Footer(string(time.Now().Year())).Render(ctx, templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = components.Footer(strconv.Itoa(time.Now().Year())).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
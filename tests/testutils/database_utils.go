@@ -2,7 +2,9 @@ package testutils
 
 import (
 	"database/sql"
+	"fmt"
 	"github.com/DATA-DOG/go-sqlmock"
+	"regexp"
 	"testing"
 )
 
@@ -19,3 +21,25 @@ func SetupTestDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock, func()) {
 
 	return db, mock, cleanup
 }
+
+// ExpectSnapshot expects the row of table with the given id to be read for the activity log
+func ExpectSnapshot(mock sqlmock.Sqlmock, table string, id int) {
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf("SELECT * FROM %s WHERE id = ? FOR UPDATE", table))).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
+}
+
+// ExpectMissingSnapshot expects the row of table with the given id to be read for the activity
+// log and not to be found
+func ExpectMissingSnapshot(mock sqlmock.Sqlmock, table string, id int) {
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf("SELECT * FROM %s WHERE id = ? FOR UPDATE", table))).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+}
+
+// ExpectActivity expects an entry of action on the given entity to be appended to the activity log
+func ExpectActivity(mock sqlmock.Sqlmock, action, entityType string, id int) {
+	mock.ExpectExec("INSERT INTO activity_log").
+		WithArgs(sqlmock.AnyArg(), action, entityType, id, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
@@ -167,9 +167,13 @@ package controllers_test
 
 import (
 	"ISO_Auditing_Tool/pkg/controllers/api"
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/middleware"
+	"ISO_Auditing_Tool/pkg/services"
 	"ISO_Auditing_Tool/pkg/types"
 	"ISO_Auditing_Tool/tests/unit/repositories/mocks"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -236,6 +240,19 @@ func createTestContext(method, path string, body *bytes.Buffer) (*gin.Context, *
 	return c, w
 }
 
+// newDraftRouter routes the draft endpoints through the error middleware for user 20
+func newDraftRouter(controller *controllers.ApiDraftController) *gin.Engine {
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(services.WithCurrentUser(c.Request.Context(), types.User{ID: 20}))
+	}, middleware.ErrorHandler())
+	router.POST("/drafts", controller.Create)
+	router.PUT("/drafts/:id", controller.Update)
+	router.POST("/drafts/:id/approve", controller.Approve)
+	router.POST("/drafts/:id/publish", controller.Publish)
+	return router
+}
+
 // --- Happy Path Tests ---
 
 func (suite *ApiDraftControllerHappyPathSuite) TestCreate_ValidInput_ReturnsCreated() {
@@ -273,10 +290,12 @@ func (suite *ApiDraftControllerHappyPathSuite) TestUpdate_ValidInput_ReturnsOK()
 
 func (suite *ApiDraftControllerErrorSuite) TestCreate_InvalidJSON_ReturnsBadRequest() {
 	// Setup
-	c, w := createTestContext("POST", "/drafts", invalidJSONBuffer)
+	req := httptest.NewRequest("POST", "/drafts", bytes.NewBufferString(`{"invalid": json}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
 
 	// Act
-	suite.controller.Create(c)
+	newDraftRouter(suite.controller).ServeHTTP(w, req)
 
 	// Assert
 	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
@@ -287,23 +306,43 @@ func (suite *ApiDraftControllerErrorSuite) TestCreate_ServiceError_ReturnsIntern
 	suite.mockService.On("Create", mock.Anything, mock.AnythingOfType("types.Draft")).
 		Return(types.Draft{}, errors.New("service error"))
 
-	c, w := createTestContext("POST", "/drafts", bytes.NewBuffer(testDraftJSON))
+	req := httptest.NewRequest("POST", "/drafts", bytes.NewBuffer(testDraftJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
 
 	// Act
-	suite.controller.Create(c)
+	newDraftRouter(suite.controller).ServeHTTP(w, req)
 
 	// Assert
 	assert.Equal(suite.T(), http.StatusInternalServerError, w.Code)
 	suite.mockService.AssertExpectations(suite.T())
 }
 
+func (suite *ApiDraftControllerErrorSuite) TestCreate_ForbiddenByService_ReturnsForbidden() {
+	// Setup
+	suite.mockService.On("Create", mock.Anything, mock.AnythingOfType("types.Draft")).
+		Return(types.Draft{}, custom_errors.Forbidden(context.Background(), "drafts of this type are managed elsewhere"))
+
+	req := httptest.NewRequest("POST", "/drafts", bytes.NewBuffer(testDraftJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act
+	newDraftRouter(suite.controller).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusForbidden, w.Code)
+	suite.mockService.AssertExpectations(suite.T())
+}
+
 func (suite *ApiDraftControllerErrorSuite) TestUpdate_InvalidJSON_ReturnsBadRequest() {
 	// Setup
-	c, w := createTestContext("PUT", "/drafts/1", invalidJSONBuffer)
-	c.Params = gin.Params{{Key: "id", Value: "1"}}
+	req := httptest.NewRequest("PUT", "/drafts/1", bytes.NewBufferString(`{"invalid": json}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
 
 	// Act
-	suite.controller.Update(c)
+	newDraftRouter(suite.controller).ServeHTTP(w, req)
 
 	// Assert
 	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
@@ -311,11 +350,12 @@ func (suite *ApiDraftControllerErrorSuite) TestUpdate_InvalidJSON_ReturnsBadRequ
 
 func (suite *ApiDraftControllerErrorSuite) TestUpdate_InvalidID_ReturnsBadRequest() {
 	// Setup
-	c, w := createTestContext("PUT", "/drafts/invalid", bytes.NewBuffer(testDraftJSON))
-	c.Params = gin.Params{{Key: "id", Value: "invalid"}}
+	req := httptest.NewRequest("PUT", "/drafts/invalid", bytes.NewBuffer(testDraftJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
 
 	// Act
-	suite.controller.Update(c)
+	newDraftRouter(suite.controller).ServeHTTP(w, req)
 
 	// Assert
 	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
@@ -326,17 +366,72 @@ func (suite *ApiDraftControllerErrorSuite) TestUpdate_ServiceError_ReturnsIntern
 	suite.mockService.On("Update", mock.Anything, mock.AnythingOfType("types.Draft")).
 		Return(types.Draft{}, errors.New("service error"))
 
-	c, w := createTestContext("PUT", "/drafts/1", bytes.NewBuffer(testDraftJSON))
-	c.Params = gin.Params{{Key: "id", Value: "1"}}
+	req := httptest.NewRequest("PUT", "/drafts/1", bytes.NewBuffer(testDraftJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
 
 	// Act
-	suite.controller.Update(c)
+	newDraftRouter(suite.controller).ServeHTTP(w, req)
 
 	// Assert
 	assert.Equal(suite.T(), http.StatusInternalServerError, w.Code)
 	suite.mockService.AssertExpectations(suite.T())
 }
 
+func (suite *ApiDraftControllerErrorSuite) TestApprove_WithoutComment_ReturnsBadRequest() {
+	// Setup
+	req := httptest.NewRequest("POST", "/drafts/1/approve", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act
+	newDraftRouter(suite.controller).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	suite.mockService.AssertNotCalled(suite.T(), "Approve", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ApiDraftControllerErrorSuite) TestPublish_WhenNotApproved_ReturnsConflict() {
+	// Setup
+	form := types.DraftTransitionForm{Comment: "publishing", UserID: 20}
+	suite.mockService.On("Publish", mock.Anything, 1, form).
+		Return(types.Draft{}, custom_errors.Conflict(context.Background(), "Draft", "must be approved before it is published"))
+
+	req := httptest.NewRequest("POST", "/drafts/1/publish", bytes.NewBufferString(`{"comment": "publishing"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act
+	newDraftRouter(suite.controller).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+func (suite *ApiDraftControllerHappyPathSuite) TestApprove_PassesSignedInUser() {
+	// Setup
+	approved := testDraft
+	approved.ApproverID = 20
+	form := types.DraftTransitionForm{Comment: "looks good", UserID: 20}
+	suite.mockService.On("Approve", mock.Anything, 1, form).Return(approved, nil)
+
+	req := httptest.NewRequest("POST", "/drafts/1/approve", bytes.NewBufferString(`{"comment": "looks good"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act
+	newDraftRouter(suite.controller).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	var response types.Draft
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(suite.T(), 20, response.ApproverID)
+	suite.mockService.AssertExpectations(suite.T())
+}
+
 // Test runners
 func TestApiDraftController_HappyPath(t *testing.T) {
 	suite.Run(t, new(ApiDraftControllerHappyPathSuite))
@@ -0,0 +1,162 @@
+package controllers_test
+
+import (
+	"ISO_Auditing_Tool/pkg/controllers/api"
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/middleware"
+	"ISO_Auditing_Tool/pkg/types"
+	"ISO_Auditing_Tool/tests/unit/repositories/mocks"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ApiStandardControllerSuite struct {
+	suite.Suite
+	mockService *mocks.MockStandardService
+	router      *gin.Engine
+}
+
+func (suite *ApiStandardControllerSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	suite.mockService = new(mocks.MockStandardService)
+	controller := controllers.NewAPIStandardController(suite.mockService)
+
+	// Route through the error middleware so custom error codes map to HTTP statuses
+	suite.router = gin.New()
+	api := suite.router.Group("/api")
+	api.Use(middleware.ErrorHandler())
+	api.GET("/standards", controller.GetAll)
+	api.GET("/standards/:id", controller.GetByID)
+	api.POST("/standards", controller.Create)
+	api.PUT("/standards/:id", controller.Update)
+	api.DELETE("/standards/:id", controller.Delete)
+}
+
+func (suite *ApiStandardControllerSuite) TearDownTest() {
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+func (suite *ApiStandardControllerSuite) serve(method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	suite.router.ServeHTTP(w, req)
+	return w
+}
+
+func (suite *ApiStandardControllerSuite) TestGetAll_ReturnsStandards() {
+	suite.mockService.On("GetAll", mock.Anything).
+		Return([]types.Standard{{ID: 1, Name: "ISO 9001", Version: "2015"}}, nil)
+
+	w := suite.serve("GET", "/api/standards", "")
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"total":1`)
+}
+
+func (suite *ApiStandardControllerSuite) TestGetByID_Full_UsesHierarchy() {
+	suite.mockService.On("GetByIDWithFullHierarchy", mock.Anything, types.Standard{ID: 1}).
+		Return(types.Standard{ID: 1, Requirements: []types.Requirement{{ID: 10}}}, nil)
+
+	w := suite.serve("GET", "/api/standards/1?full=true", "")
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"requirements":[{"id":10`)
+}
+
+func (suite *ApiStandardControllerSuite) TestGetByID_NotFound_Returns404() {
+	suite.mockService.On("GetByID", mock.Anything, types.Standard{ID: 5}).
+		Return(types.Standard{}, custom_errors.NotFound(context.Background(), "Standard"))
+
+	w := suite.serve("GET", "/api/standards/5", "")
+
+	suite.Equal(http.StatusNotFound, w.Code)
+	suite.Contains(w.Body.String(), string(custom_errors.ErrCodeNotFound))
+}
+
+func (suite *ApiStandardControllerSuite) TestGetByID_InvalidID_Returns400() {
+	w := suite.serve("GET", "/api/standards/abc", "")
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Contains(w.Body.String(), string(custom_errors.ErrCodeInvalidID))
+}
+
+func (suite *ApiStandardControllerSuite) TestCreate_ValidInput_ReturnsCreated() {
+	input := types.Standard{Name: "ISO 14001", Description: "Environmental management", Version: "2015"}
+	suite.mockService.On("Create", mock.Anything, input).
+		Return(types.Standard{ID: 3, Name: input.Name, Description: input.Description, Version: input.Version}, nil)
+
+	body, _ := json.Marshal(input)
+	w := suite.serve("POST", "/api/standards", string(body))
+
+	suite.Equal(http.StatusCreated, w.Code)
+	suite.Contains(w.Body.String(), `"id":3`)
+}
+
+func (suite *ApiStandardControllerSuite) TestCreate_InvalidJSON_Returns400() {
+	w := suite.serve("POST", "/api/standards", `{"name": }`)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Contains(w.Body.String(), string(custom_errors.ErrCodeInvalidJSON))
+}
+
+func (suite *ApiStandardControllerSuite) TestCreate_ValidationErrors_Return400() {
+	tests := []struct {
+		name string
+		body string
+		code custom_errors.ErrorCode
+	}{
+		{"missing name", `{"version": "2015"}`, custom_errors.ErrCodeEmptyField},
+		{"short name", `{"name": "IS", "version": "2015"}`, custom_errors.ErrCodeMinChars},
+		{"boolean name", `{"name": "true", "version": "2015"}`, custom_errors.ErrCodeIsABool},
+		{"missing version", `{"name": "ISO 9001"}`, custom_errors.ErrCodeEmptyField},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.name, func() {
+			w := suite.serve("POST", "/api/standards", tc.body)
+
+			suite.Equal(http.StatusBadRequest, w.Code)
+			suite.Contains(w.Body.String(), string(tc.code))
+		})
+	}
+}
+
+func (suite *ApiStandardControllerSuite) TestUpdate_ValidInput_ReturnsOK() {
+	input := types.Standard{ID: 1, Name: "ISO 9001", Version: "2015"}
+	suite.mockService.On("Update", mock.Anything, input).Return(input, nil)
+
+	w := suite.serve("PUT", "/api/standards/1", `{"name": "ISO 9001", "version": "2015"}`)
+
+	suite.Equal(http.StatusOK, w.Code)
+}
+
+func (suite *ApiStandardControllerSuite) TestDelete_ReturnsNoContent() {
+	suite.mockService.On("Delete", mock.Anything, types.Standard{ID: 1}).Return(nil)
+
+	w := suite.serve("DELETE", "/api/standards/1", "")
+
+	suite.Equal(http.StatusNoContent, w.Code)
+}
+
+func (suite *ApiStandardControllerSuite) TestDelete_HasRequirements_Returns409() {
+	suite.mockService.On("Delete", mock.Anything, types.Standard{ID: 1}).
+		Return(custom_errors.Conflict(context.Background(), "Standard", "still has requirements"))
+
+	w := suite.serve("DELETE", "/api/standards/1", "")
+
+	suite.Equal(http.StatusConflict, w.Code)
+	suite.Contains(w.Body.String(), string(custom_errors.ErrCodeConflict))
+}
+
+func TestApiStandardController(t *testing.T) {
+	suite.Run(t, new(ApiStandardControllerSuite))
+}
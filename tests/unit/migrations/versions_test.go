@@ -0,0 +1,96 @@
+package migrations_test
+
+import (
+	"ISO_Auditing_Tool/internal/migrations"
+	"ISO_Auditing_Tool/tests/testutils"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MigrationVersionsTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	mock    sqlmock.Sqlmock
+	cleanup func()
+	file    string
+}
+
+func (s *MigrationVersionsTestSuite) SetupTest() {
+	s.db, s.mock, s.cleanup = testutils.SetupTestDB(s.T())
+
+	s.file = filepath.Join(s.T().TempDir(), "003_sort_order.up.sql")
+	err := os.WriteFile(s.file, []byte("-- Order questions\nALTER TABLE questions\n    ADD COLUMN sort_order INT NOT NULL DEFAULT 0;\n"), 0o600)
+	s.NoError(err)
+}
+
+func (s *MigrationVersionsTestSuite) TearDownTest() {
+	s.NoError(s.mock.ExpectationsWereMet())
+	s.cleanup()
+}
+
+func (s *MigrationVersionsTestSuite) expectApplied(count int) {
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM schema_migrations WHERE version = ?")).
+		WithArgs("003_sort_order").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+}
+
+func (s *MigrationVersionsTestSuite) TestVersion_StripsDirectionAndExtension() {
+	s.Equal("002_requirement_sort_order", migrations.Version("/migrations/sql/002_requirement_sort_order.up.sql"))
+	s.Equal("002_requirement_sort_order", migrations.Version("002_requirement_sort_order.down.sql"))
+}
+
+func (s *MigrationVersionsTestSuite) TestUp_RunsAndRecordsNewMigration() {
+	s.expectApplied(0)
+	s.mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE questions ADD COLUMN sort_order INT NOT NULL DEFAULT 0;")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schema_migrations (version) VALUES (?)")).
+		WithArgs("003_sort_order").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	ran, err := migrations.Up(s.db, s.file)
+
+	s.NoError(err)
+	s.True(ran)
+}
+
+func (s *MigrationVersionsTestSuite) TestUp_SkipsAppliedMigration() {
+	s.expectApplied(1)
+
+	ran, err := migrations.Up(s.db, s.file)
+
+	s.NoError(err)
+	s.False(ran)
+}
+
+func (s *MigrationVersionsTestSuite) TestDown_SkipsMigrationThatWasNeverApplied() {
+	s.expectApplied(0)
+
+	ran, err := migrations.Down(s.db, s.file)
+
+	s.NoError(err)
+	s.False(ran)
+}
+
+func (s *MigrationVersionsTestSuite) TestDown_RunsAndRemovesAppliedMigration() {
+	s.expectApplied(1)
+	s.mock.ExpectExec("ALTER TABLE questions").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM schema_migrations WHERE version = ?")).
+		WithArgs("003_sort_order").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ran, err := migrations.Down(s.db, s.file)
+
+	s.NoError(err)
+	s.True(ran)
+}
+
+func TestMigrationVersions(t *testing.T) {
+	suite.Run(t, new(MigrationVersionsTestSuite))
+}
@@ -0,0 +1,163 @@
+package oidc_test
+
+import (
+	"ISO_Auditing_Tool/pkg/oidc"
+	"ISO_Auditing_Tool/tests/testutils"
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const redirectURL = "http://localhost:8080/web/sso/callback"
+
+func newClient(t *testing.T, provider *testutils.OIDCProvider) *oidc.Client {
+	client, err := oidc.NewClient(oidc.Config{
+		IssuerURL:    provider.Issuer(),
+		ClientID:     provider.ClientID,
+		ClientSecret: provider.ClientSecret,
+		RedirectURL:  redirectURL,
+	})
+	require.NoError(t, err)
+	return client
+}
+
+// signIn runs the authorization request and returns the code the provider sent back
+func signIn(t *testing.T, provider *testutils.OIDCProvider, client *oidc.Client, state, nonce, verifier string) string {
+	authURL, err := client.AuthCodeURL(context.Background(), state, nonce, verifier)
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "S256", parsed.Query().Get("code_challenge_method"))
+	assert.Equal(t, oidc.Challenge(verifier), parsed.Query().Get("code_challenge"))
+	assert.Equal(t, "openid email profile", parsed.Query().Get("scope"))
+
+	back := provider.Authorize(t, authURL)
+	assert.Equal(t, state, back.Query().Get("state"))
+	return back.Query().Get("code")
+}
+
+func TestExchange_ReturnsVerifiedClaims(t *testing.T) {
+	provider := testutils.NewOIDCProvider(t)
+	provider.User.Groups = []string{"iso-leads", "staff"}
+	client := newClient(t, provider)
+	verifier, err := oidc.NewVerifier()
+	require.NoError(t, err)
+
+	code := signIn(t, provider, client, "state-1", "nonce-1", verifier)
+	claims, err := client.Exchange(context.Background(), code, verifier, "nonce-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", claims.Email)
+	assert.Equal(t, "Alice", claims.Name)
+	require.NotNil(t, claims.EmailVerified)
+	assert.True(t, *claims.EmailVerified)
+	assert.Equal(t, []string{"iso-leads", "staff"}, claims.Groups)
+}
+
+func TestExchange_WrongVerifierIsRefused(t *testing.T) {
+	provider := testutils.NewOIDCProvider(t)
+	client := newClient(t, provider)
+	verifier, err := oidc.NewVerifier()
+	require.NoError(t, err)
+
+	code := signIn(t, provider, client, "state-1", "nonce-1", verifier)
+	_, err = client.Exchange(context.Background(), code, "another-verifier", "nonce-1")
+
+	assert.ErrorContains(t, err, "invalid_grant")
+}
+
+func TestExchange_NonceOfAnotherAttemptIsRejected(t *testing.T) {
+	provider := testutils.NewOIDCProvider(t)
+	client := newClient(t, provider)
+	verifier, err := oidc.NewVerifier()
+	require.NoError(t, err)
+
+	code := signIn(t, provider, client, "state-1", "nonce-1", verifier)
+	_, err = client.Exchange(context.Background(), code, verifier, "nonce-2")
+
+	assert.True(t, errors.Is(err, oidc.ErrInvalidIDToken))
+}
+
+func TestExchange_TokenIssuedInTheFutureIsRejected(t *testing.T) {
+	provider := testutils.NewOIDCProvider(t)
+	provider.Claims = map[string]any{"iat": time.Now().Add(time.Hour).Unix()}
+	client := newClient(t, provider)
+	verifier, err := oidc.NewVerifier()
+	require.NoError(t, err)
+
+	code := signIn(t, provider, client, "state-1", "nonce-1", verifier)
+	_, err = client.Exchange(context.Background(), code, verifier, "nonce-1")
+
+	assert.True(t, errors.Is(err, oidc.ErrInvalidIDToken))
+	assert.ErrorContains(t, err, "issued in the future")
+}
+
+func TestExchange_TokenNotValidYetIsRejected(t *testing.T) {
+	provider := testutils.NewOIDCProvider(t)
+	provider.Claims = map[string]any{"nbf": time.Now().Add(time.Hour).Unix()}
+	client := newClient(t, provider)
+	verifier, err := oidc.NewVerifier()
+	require.NoError(t, err)
+
+	code := signIn(t, provider, client, "state-1", "nonce-1", verifier)
+	_, err = client.Exchange(context.Background(), code, verifier, "nonce-1")
+
+	assert.True(t, errors.Is(err, oidc.ErrInvalidIDToken))
+	assert.ErrorContains(t, err, "not valid yet")
+}
+
+func TestExchange_IssuerWithTrailingSlashIsAccepted(t *testing.T) {
+	provider := testutils.NewOIDCProvider(t)
+	provider.Claims = map[string]any{"iss": provider.Issuer() + "/"}
+	client := newClient(t, provider)
+	verifier, err := oidc.NewVerifier()
+	require.NoError(t, err)
+
+	code := signIn(t, provider, client, "state-1", "nonce-1", verifier)
+	_, err = client.Exchange(context.Background(), code, verifier, "nonce-1")
+
+	assert.NoError(t, err)
+}
+
+func TestExchange_MissingEmailVerifiedIsLeftUnset(t *testing.T) {
+	provider := testutils.NewOIDCProvider(t)
+	provider.Claims = map[string]any{"email_verified": nil}
+	client := newClient(t, provider)
+	verifier, err := oidc.NewVerifier()
+	require.NoError(t, err)
+
+	code := signIn(t, provider, client, "state-1", "nonce-1", verifier)
+	claims, err := client.Exchange(context.Background(), code, verifier, "nonce-1")
+
+	require.NoError(t, err)
+	assert.Nil(t, claims.EmailVerified)
+}
+
+func TestExchange_UnknownKeyDoesNotRefetchKeySetRightAway(t *testing.T) {
+	provider := testutils.NewOIDCProvider(t)
+	provider.KeyID = "rotated-key"
+	client := newClient(t, provider)
+
+	for _, state := range []string{"state-1", "state-2"} {
+		verifier, err := oidc.NewVerifier()
+		require.NoError(t, err)
+
+		code := signIn(t, provider, client, state, "nonce-1", verifier)
+		_, err = client.Exchange(context.Background(), code, verifier, "nonce-1")
+
+		assert.True(t, errors.Is(err, oidc.ErrInvalidIDToken))
+	}
+	assert.Equal(t, 1, provider.JWKSRequests())
+}
+
+func TestNewClient_RequiresRegistration(t *testing.T) {
+	_, err := oidc.NewClient(oidc.Config{IssuerURL: "https://login.example.com"})
+
+	assert.Error(t, err)
+}
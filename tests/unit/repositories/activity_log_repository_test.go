@@ -0,0 +1,77 @@
+package repositories_test
+
+import (
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"ISO_Auditing_Tool/tests/testutils"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+)
+
+var activityRowColumns = []string{"id", "actor_user_id", "action", "entity_type", "entity_id", "before_data", "after_data", "request_id", "created_at"}
+
+type ActivityLogRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	mock    sqlmock.Sqlmock
+	cleanup func()
+	repo    repositories.ActivityLogRepositoryInterface
+}
+
+func (s *ActivityLogRepositoryTestSuite) SetupTest() {
+	s.db, s.mock, s.cleanup = testutils.SetupTestDB(s.T())
+
+	repo, err := repositories.NewActivityLogRepository(s.db)
+	s.NoError(err)
+	s.repo = repo
+}
+
+func (s *ActivityLogRepositoryTestSuite) TearDownTest() {
+	s.NoError(s.mock.ExpectationsWereMet())
+	s.cleanup()
+}
+
+func (s *ActivityLogRepositoryTestSuite) TestGetActivityEntries_FiltersEntityUserAndTime() {
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	now := from.Add(time.Hour)
+
+	s.mock.ExpectQuery("FROM activity_log WHERE entity_type = \\? AND entity_id = \\? AND actor_user_id = \\? AND created_at >= \\? AND created_at < \\? ORDER BY id DESC LIMIT \\?").
+		WithArgs("finding", 9, 3, from, to, 50).
+		WillReturnRows(sqlmock.NewRows(activityRowColumns).
+			AddRow(12, 3, "updated", "finding", 9, `{"status_id":30}`, `{"status_id":31}`, "4f2a", now).
+			AddRow(11, nil, "created", "finding", 9, nil, `{"status_id":30}`, nil, now))
+
+	entries, err := s.repo.GetActivityEntries(context.Background(), types.ActivityFilter{
+		EntityType: "finding", EntityID: 9, UserID: 3, From: from, To: to, Limit: 50,
+	})
+
+	s.NoError(err)
+	s.Len(entries, 2)
+	s.Require().NotNil(entries[0].ActorUserID)
+	s.Equal(3, *entries[0].ActorUserID)
+	s.JSONEq(`{"status_id":30}`, string(entries[0].Before))
+	s.Equal("4f2a", entries[0].RequestID)
+	s.Nil(entries[1].ActorUserID)
+	s.Nil(entries[1].Before)
+}
+
+func (s *ActivityLogRepositoryTestSuite) TestGetActivityEntries_NoFilter_PagesByID() {
+	s.mock.ExpectQuery("FROM activity_log WHERE id < \\? ORDER BY id DESC LIMIT \\?").
+		WithArgs(int64(11), 100).
+		WillReturnRows(sqlmock.NewRows(activityRowColumns))
+
+	entries, err := s.repo.GetActivityEntries(context.Background(), types.ActivityFilter{BeforeID: 11, Limit: 100})
+
+	s.NoError(err)
+	s.Empty(entries)
+}
+
+func TestActivityLogRepository(t *testing.T) {
+	suite.Run(t, new(ActivityLogRepositoryTestSuite))
+}
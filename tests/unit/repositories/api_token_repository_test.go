@@ -0,0 +1,108 @@
+package repositories_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"ISO_Auditing_Tool/tests/testutils"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+)
+
+var apiTokenRowColumns = []string{"id", "user_id", "name", "scopes", "expires_at", "last_used_at", "revoked_at", "created_at"}
+
+type APITokenRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	mock    sqlmock.Sqlmock
+	cleanup func()
+	repo    repositories.APITokenRepositoryInterface
+}
+
+func (s *APITokenRepositoryTestSuite) SetupTest() {
+	s.db, s.mock, s.cleanup = testutils.SetupTestDB(s.T())
+
+	repo, err := repositories.NewAPITokenRepository(s.db)
+	s.NoError(err)
+	s.repo = repo
+}
+
+func (s *APITokenRepositoryTestSuite) TearDownTest() {
+	s.NoError(s.mock.ExpectationsWereMet())
+	s.cleanup()
+}
+
+func (s *APITokenRepositoryTestSuite) TestGetByUserIDAPITokens() {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	s.mock.ExpectQuery("FROM api_tokens AS t WHERE t.user_id = \\? ORDER BY t.created_at DESC, t.id DESC").
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows(apiTokenRowColumns).
+			AddRow(2, 7, "ci", "read,write", now.Add(time.Hour), now, nil, now).
+			AddRow(1, 7, "report", "read", now, nil, now, now))
+
+	tokens, err := s.repo.GetByUserIDAPITokens(context.Background(), 7)
+
+	s.NoError(err)
+	s.Len(tokens, 2)
+	s.Equal([]string{"read", "write"}, tokens[0].Scopes)
+	s.NotNil(tokens[0].LastUsedAt)
+	s.Nil(tokens[0].RevokedAt)
+	s.NotNil(tokens[1].RevokedAt)
+}
+
+func (s *APITokenRepositoryTestSuite) TestGetUserByAPIToken_RevokedOrExpired() {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	s.mock.ExpectQuery("FROM api_tokens AS t INNER JOIN users AS u ON u.id = t.user_id WHERE t.token_hash = \\? AND t.revoked_at IS NULL AND t.expires_at > \\?").
+		WithArgs("hash", now).
+		WillReturnError(sql.ErrNoRows)
+
+	_, _, err := s.repo.GetUserByAPIToken(context.Background(), "hash", now)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func (s *APITokenRepositoryTestSuite) TestCreateAPIToken() {
+	now := time.Now().UTC().Truncate(time.Second)
+	expiresAt := now.Add(30 * 24 * time.Hour)
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec("INSERT INTO api_tokens \\(user_id, name, token_hash, scopes, expires_at\\) VALUES").
+		WithArgs(7, "ci", "hash", "read,write", expiresAt).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	testutils.ExpectSnapshot(s.mock, "api_tokens", 3)
+	testutils.ExpectActivity(s.mock, "created", "api_token", 3)
+	s.mock.ExpectCommit()
+	s.mock.ExpectQuery("FROM api_tokens AS t WHERE t.id = \\?").
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(apiTokenRowColumns).AddRow(3, 7, "ci", "read,write", expiresAt, nil, nil, now))
+
+	token, err := s.repo.CreateAPIToken(context.Background(), types.APIToken{UserID: 7, Name: "ci", Scopes: []string{"read", "write"}, ExpiresAt: expiresAt}, "hash")
+
+	s.NoError(err)
+	s.Equal(3, token.ID)
+	s.Empty(token.Token)
+}
+
+func (s *APITokenRepositoryTestSuite) TestRevokeAPIToken_AlreadyRevoked() {
+	s.mock.ExpectBegin()
+	testutils.ExpectSnapshot(s.mock, "api_tokens", 3)
+	s.mock.ExpectExec("UPDATE api_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE id = \\? AND revoked_at IS NULL").
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	err := s.repo.RevokeAPIToken(context.Background(), 3)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func TestAPITokenRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(APITokenRepositoryTestSuite))
+}
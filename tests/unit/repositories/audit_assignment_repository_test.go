@@ -0,0 +1,162 @@
+package repositories_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/tests/testutils"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuditAssignmentRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	mock    sqlmock.Sqlmock
+	cleanup func()
+	repo    repositories.AuditAssignmentRepositoryInterface
+}
+
+func (s *AuditAssignmentRepositoryTestSuite) SetupTest() {
+	s.db, s.mock, s.cleanup = testutils.SetupTestDB(s.T())
+
+	repo, err := repositories.NewAuditAssignmentRepository(s.db)
+	s.NoError(err)
+	s.repo = repo
+}
+
+func (s *AuditAssignmentRepositoryTestSuite) TearDownTest() {
+	s.NoError(s.mock.ExpectationsWereMet())
+	s.cleanup()
+}
+
+func (s *AuditAssignmentRepositoryTestSuite) TestGetByUserIDAuditAssignments_LeadAndSupport() {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	s.mock.ExpectQuery("WHERE ap.lead_auditor_id = \\? AND ap.deleted_at IS NULL UNION ALL (.+) WHERE asa.user_id = \\?").
+		WithArgs(3, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "status_id", "scheduled_date", "user_id", "user_name", "role", "assigned_at"}).
+			AddRow(2, "ISO 9001 surveillance", 7, now, 3, "Alice", "LEAD", now).
+			AddRow(5, "ISO 27001 internal", 8, now, 3, "Alice", "SUPPORT", now))
+
+	assignments, err := s.repo.GetByUserIDAuditAssignments(context.Background(), 3)
+
+	s.NoError(err)
+	s.Len(assignments, 2)
+	s.Equal("LEAD", assignments[0].Role)
+	s.Equal(5, assignments[1].AuditPlanID)
+	s.Equal(8, assignments[1].StatusVal.ID)
+}
+
+func (s *AuditAssignmentRepositoryTestSuite) TestCreateAuditAssignment() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT lead_auditor_id FROM audit_plans WHERE id = \\? AND deleted_at IS NULL FOR UPDATE").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"lead_auditor_id"}).AddRow(1))
+	s.mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users").
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM audit_support_auditors").
+		WithArgs(2, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	s.mock.ExpectExec("INSERT INTO audit_support_auditors \\(audit_id, user_id\\)").
+		WithArgs(2, 3).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	testutils.ExpectSnapshot(s.mock, "audit_support_auditors", 1)
+	testutils.ExpectActivity(s.mock, "created", "audit_assignment", 1)
+	s.mock.ExpectCommit()
+
+	err := s.repo.CreateAuditAssignment(context.Background(), 2, 3)
+
+	s.NoError(err)
+}
+
+func (s *AuditAssignmentRepositoryTestSuite) TestCreateAuditAssignment_LeadAuditor_ReturnsConflict() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT lead_auditor_id FROM audit_plans").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"lead_auditor_id"}).AddRow(3))
+	s.mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users").
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectRollback()
+
+	err := s.repo.CreateAuditAssignment(context.Background(), 2, 3)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (s *AuditAssignmentRepositoryTestSuite) TestCreateAuditAssignment_InactiveUser_ReturnsInvalidData() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT lead_auditor_id FROM audit_plans").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"lead_auditor_id"}).AddRow(1))
+	s.mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users").
+		WithArgs(9).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	s.mock.ExpectRollback()
+
+	err := s.repo.CreateAuditAssignment(context.Background(), 2, 9)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+}
+
+func (s *AuditAssignmentRepositoryTestSuite) TestDeleteAuditAssignment() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT id FROM audit_support_auditors WHERE audit_id = \\? AND user_id = \\? FOR UPDATE").
+		WithArgs(2, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	testutils.ExpectSnapshot(s.mock, "audit_support_auditors", 5)
+	s.mock.ExpectExec("DELETE FROM audit_support_auditors WHERE id = \\?").
+		WithArgs(5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	testutils.ExpectMissingSnapshot(s.mock, "audit_support_auditors", 5)
+	testutils.ExpectActivity(s.mock, "deleted", "audit_assignment", 5)
+	s.mock.ExpectCommit()
+
+	err := s.repo.DeleteAuditAssignment(context.Background(), 2, 3)
+
+	s.NoError(err)
+}
+
+func (s *AuditAssignmentRepositoryTestSuite) TestDeleteAuditAssignment_Missing_ReturnsNotFound() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT id FROM audit_support_auditors WHERE audit_id = \\? AND user_id = \\? FOR UPDATE").
+		WithArgs(2, 3).
+		WillReturnError(sql.ErrNoRows)
+	s.mock.ExpectRollback()
+
+	err := s.repo.DeleteAuditAssignment(context.Background(), 2, 3)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func (s *AuditAssignmentRepositoryTestSuite) TestIsAuditTeamMember() {
+	s.mock.ExpectQuery("SELECT EXISTS (.+) OR EXISTS").
+		WithArgs(2, 3, 2, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"member"}).AddRow(true))
+
+	member, err := s.repo.IsAuditTeamMember(context.Background(), 2, 3)
+
+	s.NoError(err)
+	s.True(member)
+}
+
+func (s *AuditAssignmentRepositoryTestSuite) TestIsAuditFollowUpOwner() {
+	s.mock.ExpectQuery("SELECT EXISTS (.+) FROM findings (.+) finding_corrective_actions").
+		WithArgs(2, 9, 9).
+		WillReturnRows(sqlmock.NewRows([]string{"owner"}).AddRow(false))
+
+	owner, err := s.repo.IsAuditFollowUpOwner(context.Background(), 2, 9)
+
+	s.NoError(err)
+	s.False(owner)
+}
+
+func TestAuditAssignmentRepository(t *testing.T) {
+	suite.Run(t, new(AuditAssignmentRepositoryTestSuite))
+}
@@ -0,0 +1,122 @@
+package repositories_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"ISO_Auditing_Tool/tests/testutils"
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuditChecklistRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	mock    sqlmock.Sqlmock
+	cleanup func()
+	repo    repositories.AuditChecklistRepositoryInterface
+}
+
+func (s *AuditChecklistRepositoryTestSuite) SetupTest() {
+	s.db, s.mock, s.cleanup = testutils.SetupTestDB(s.T())
+
+	repo, err := repositories.NewAuditChecklistRepository(s.db)
+	s.NoError(err)
+	s.repo = repo
+}
+
+func (s *AuditChecklistRepositoryTestSuite) TearDownTest() {
+	s.NoError(s.mock.ExpectationsWereMet())
+	s.cleanup()
+}
+
+func (s *AuditChecklistRepositoryTestSuite) expectLockedPlan() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT id FROM audit_plans WHERE id = \\? AND deleted_at IS NULL FOR UPDATE").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+}
+
+func (s *AuditChecklistRepositoryTestSuite) TestSyncAuditChecklist_AppliesDiff() {
+	s.expectLockedPlan()
+	s.mock.ExpectQuery("SELECT id FROM questions WHERE requirement_id IN \\(\\?, \\?\\)").
+		WithArgs(4, 6).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10).AddRow(11).AddRow(12))
+	// Question 10 is kept, 13 left the scope
+	s.mock.ExpectQuery("SELECT id, question_id FROM audit_questions WHERE audit_id = \\? FOR UPDATE").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "question_id"}).AddRow(100, 10).AddRow(101, 13))
+	s.mock.ExpectQuery("FROM audit_question_findings WHERE audit_question_id IN \\(\\?\\)").
+		WithArgs(101, 101, 101).
+		WillReturnRows(sqlmock.NewRows([]string{"findings", "comments", "provided"}).AddRow(0, 0, 0))
+	s.mock.ExpectExec("DELETE FROM audit_questions WHERE id IN \\(\\?\\)").
+		WithArgs(101).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO audit_questions \\(audit_id, question_id\\) VALUES \\(\\?, \\?\\), \\(\\?, \\?\\)").
+		WithArgs(2, 11, 2, 12).
+		WillReturnResult(sqlmock.NewResult(102, 2))
+	s.mock.ExpectQuery("SELECT requirement_id FROM audit_plan_requirements WHERE audit_plan_id = \\?").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"requirement_id"}).AddRow(4))
+	s.mock.ExpectExec("DELETE FROM audit_plan_requirements WHERE audit_plan_id = \\?").
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO audit_plan_requirements (.+) SELECT \\?, id, reference_code FROM requirement WHERE id IN \\(\\?\\)").
+		WithArgs(2, 4).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	testutils.ExpectActivity(s.mock, "updated", "audit_plan", 2)
+	s.mock.ExpectCommit()
+
+	diff, err := s.repo.SyncAuditChecklist(context.Background(), 2, []int{4}, []int{4, 6})
+
+	s.NoError(err)
+	s.Equal(types.AuditChecklistDiff{Added: 2, Removed: 1, Kept: 1}, diff)
+}
+
+func (s *AuditChecklistRepositoryTestSuite) TestSyncAuditChecklist_RemovingAnsweredQuestion_ReturnsConflict() {
+	s.expectLockedPlan()
+	s.mock.ExpectQuery("SELECT id, question_id FROM audit_questions WHERE audit_id = \\? FOR UPDATE").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "question_id"}).AddRow(100, 10))
+	s.mock.ExpectQuery("FROM audit_question_findings").
+		WithArgs(100, 100, 100).
+		WillReturnRows(sqlmock.NewRows([]string{"findings", "comments", "provided"}).AddRow(0, 0, 1))
+	s.mock.ExpectRollback()
+
+	_, err := s.repo.SyncAuditChecklist(context.Background(), 2, []int{}, []int{})
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (s *AuditChecklistRepositoryTestSuite) TestSyncAuditChecklist_SameScope_ChangesNothing() {
+	s.expectLockedPlan()
+	s.mock.ExpectQuery("SELECT id FROM questions WHERE requirement_id IN \\(\\?\\)").
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+	s.mock.ExpectQuery("SELECT id, question_id FROM audit_questions").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "question_id"}).AddRow(100, 10))
+	s.mock.ExpectQuery("SELECT requirement_id FROM audit_plan_requirements").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"requirement_id"}).AddRow(4))
+	s.mock.ExpectExec("DELETE FROM audit_plan_requirements").
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO audit_plan_requirements").
+		WithArgs(2, 4).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectCommit()
+
+	diff, err := s.repo.SyncAuditChecklist(context.Background(), 2, []int{4}, []int{4})
+
+	s.NoError(err)
+	s.Equal(types.AuditChecklistDiff{Kept: 1}, diff)
+}
+
+func TestAuditChecklistRepository(t *testing.T) {
+	suite.Run(t, new(AuditChecklistRepositoryTestSuite))
+}
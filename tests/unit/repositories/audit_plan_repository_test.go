@@ -0,0 +1,124 @@
+package repositories_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"ISO_Auditing_Tool/tests/testutils"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+)
+
+var auditPlanRowColumns = []string{
+	"id", "standard_id", "lead_auditor_id", "name", "status_id", "scheduled_date",
+	"team", "scope", "type_id", "created_at", "updated_at",
+}
+
+type AuditPlanRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	mock    sqlmock.Sqlmock
+	cleanup func()
+	repo    repositories.AuditPlanRepositoryInterface
+}
+
+func (s *AuditPlanRepositoryTestSuite) SetupTest() {
+	s.db, s.mock, s.cleanup = testutils.SetupTestDB(s.T())
+
+	repo, err := repositories.NewAuditPlanRepository(s.db)
+	s.NoError(err)
+	s.repo = repo
+}
+
+func (s *AuditPlanRepositoryTestSuite) TearDownTest() {
+	s.NoError(s.mock.ExpectationsWereMet())
+	s.cleanup()
+}
+
+func (s *AuditPlanRepositoryTestSuite) TestGetAllAuditPlans_AppliesFilter() {
+	now := time.Now().UTC().Truncate(time.Second)
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	s.mock.ExpectQuery(`FROM audit_plans AS ap WHERE ap.deleted_at IS NULL AND ap.standard_id = \? `+
+		`AND \(ap.lead_auditor_id = \? OR EXISTS \(SELECT 1 FROM audit_support_auditors (.+)\)\) `+
+		`AND ap.status_id = \? AND ap.type_id = \? AND ap.scheduled_date >= \? AND ap.scheduled_date < \? ORDER BY`).
+		WithArgs(1, 3, 3, 7, 13, from, to).
+		WillReturnRows(sqlmock.NewRows(auditPlanRowColumns).
+			AddRow(1, 1, 3, "ISO 9001 surveillance", 7, from, "Quality", "All sites", 13, now, now))
+
+	plans, err := s.repo.GetAllAuditPlans(context.Background(), types.AuditPlanFilter{
+		StandardID: 1, AuditorID: 3, StatusID: 7, TypeID: 13, From: from, To: to,
+	})
+
+	s.NoError(err)
+	s.Len(plans, 1)
+	s.Equal(7, plans[0].StatusVal.ID)
+	s.Equal(13, plans[0].TypeVal.ID)
+}
+
+func (s *AuditPlanRepositoryTestSuite) TestGetAllAuditPlans_NoFilter_OnlySkipsDeleted() {
+	s.mock.ExpectQuery(`FROM audit_plans AS ap WHERE ap.deleted_at IS NULL ORDER BY`).
+		WithoutArgs().
+		WillReturnRows(sqlmock.NewRows(auditPlanRowColumns))
+
+	plans, err := s.repo.GetAllAuditPlans(context.Background(), types.AuditPlanFilter{})
+
+	s.NoError(err)
+	s.Empty(plans)
+}
+
+func (s *AuditPlanRepositoryTestSuite) TestCreateAuditPlan_InactiveLeadAuditor_ReturnsInvalidData() {
+	s.mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users").
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	_, err := s.repo.CreateAuditPlan(context.Background(), types.AuditPlan{LeadAuditorID: 3})
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+}
+
+func (s *AuditPlanRepositoryTestSuite) TestUpdateAuditPlanStatus_StatusChanged_ReturnsConflict() {
+	s.mock.ExpectBegin()
+	testutils.ExpectSnapshot(s.mock, "audit_plans", 1)
+	s.mock.ExpectExec("UPDATE audit_plans SET status_id = \\? WHERE id = \\? AND status_id = \\?").
+		WithArgs(8, 1, 7).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	err := s.repo.UpdateAuditPlanStatus(context.Background(), 1, 7, 8)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (s *AuditPlanRepositoryTestSuite) TestDeleteAuditPlan_SoftDeletes() {
+	s.mock.ExpectBegin()
+	testutils.ExpectSnapshot(s.mock, "audit_plans", 1)
+	s.mock.ExpectExec("UPDATE audit_plans SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP WHERE id = \\? AND deleted_at IS NULL").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	testutils.ExpectSnapshot(s.mock, "audit_plans", 1)
+	testutils.ExpectActivity(s.mock, "deleted", "audit_plan", 1)
+	s.mock.ExpectCommit()
+
+	s.NoError(s.repo.DeleteAuditPlan(context.Background(), 1))
+}
+
+func (s *AuditPlanRepositoryTestSuite) TestGetByIDAuditPlan_Deleted_ReturnsNotFound() {
+	s.mock.ExpectQuery("FROM audit_plans AS ap WHERE ap.id = \\? AND ap.deleted_at IS NULL").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(auditPlanRowColumns))
+
+	_, err := s.repo.GetByIDAuditPlan(context.Background(), types.AuditPlan{ID: 1})
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func TestAuditPlanRepository(t *testing.T) {
+	suite.Run(t, new(AuditPlanRepositoryTestSuite))
+}
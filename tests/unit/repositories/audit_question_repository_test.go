@@ -0,0 +1,98 @@
+package repositories_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"ISO_Auditing_Tool/tests/testutils"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuditQuestionRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	mock    sqlmock.Sqlmock
+	cleanup func()
+	repo    repositories.AuditQuestionRepositoryInterface
+}
+
+func (s *AuditQuestionRepositoryTestSuite) SetupTest() {
+	s.db, s.mock, s.cleanup = testutils.SetupTestDB(s.T())
+
+	repo, err := repositories.NewAuditQuestionRepository(s.db)
+	s.NoError(err)
+	s.repo = repo
+}
+
+func (s *AuditQuestionRepositoryTestSuite) TearDownTest() {
+	s.NoError(s.mock.ExpectationsWereMet())
+	s.cleanup()
+}
+
+func (s *AuditQuestionRepositoryTestSuite) TestGetByAuditIDAuditQuestions_AttachesChildren() {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	s.mock.ExpectQuery("FROM audit_questions AS aq (.+) WHERE aq.audit_id = \\?").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "audit_id", "question_id", "requirement_id", "reference_code", "question", "guidance"}).
+			AddRow(100, 2, 10, 4, "4.1", "Is the scope documented?", nil).
+			AddRow(101, 2, 11, 4, "4.1", "Is it reviewed?", "Check the minutes"))
+	s.mock.ExpectQuery("FROM evidence AS e (.+) WHERE aq.audit_id = \\?").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "question_id", "expected", "created_at", "updated_at", "sort_order",
+			"rv_id", "type_id", "code", "name", "description", "is_active", "rv_created_at", "rv_updated_at"}).
+			AddRow(20, 11, "Review minutes", now, now, 1, 36, 7, "DOCUMENT", "Document", nil, true, now, now))
+	s.mock.ExpectQuery("FROM evidence_provided AS ep (.+) WHERE aq.audit_id = \\? AND ep.deleted_at IS NULL").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "evidence_id", "audit_question_id", "user_id", "evidence", "type_id",
+			"confidentiality_id", "status_id", "review_reason", "reviewed_by", "reviewed_at", "superseded_by", "retention_days", "file_key", "file_name", "file_size", "file_sha256",
+			"file_mime_type", "created_at", "updated_at"}).
+			AddRow(7, 20, 101, 3, "MR-2026-01", 42, 49, 53, nil, nil, nil, nil, 365, nil, nil, nil, nil, nil, now, now))
+	s.mock.ExpectQuery("FROM comments AS c (.+) WHERE aq.audit_id = \\?").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "parent_id", "audit_question_id", "user_id", "comment", "created_at", "edited_at",
+			"deleted_at", "user_id", "name"}).
+			AddRow(5, nil, 100, 3, "Not available yet", now, nil, nil, 3, "Alice"))
+
+	questions, err := s.repo.GetByAuditIDAuditQuestions(context.Background(), 2)
+
+	s.NoError(err)
+	s.Len(questions, 2)
+	s.Empty(questions[0].Evidence)
+	s.Len(questions[0].Comments, 1)
+	s.Equal(3, questions[0].Comments[0].User.ID)
+	s.Len(questions[1].Evidence, 1)
+	s.Len(questions[1].EvidenceProvided, 1)
+	s.Equal("Check the minutes", questions[1].Guidance)
+}
+
+func (s *AuditQuestionRepositoryTestSuite) TestGetByIDAuditQuestion_Missing_ReturnsNotFound() {
+	s.mock.ExpectQuery("FROM audit_questions AS aq (.+) WHERE aq.id = \\?").
+		WithArgs(100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "audit_id", "question_id", "requirement_id", "reference_code", "question", "guidance"}))
+
+	_, err := s.repo.GetByIDAuditQuestion(context.Background(), types.AuditQuestion{ID: 100})
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func (s *AuditQuestionRepositoryTestSuite) TestGetAuditProgress_CountsUnanswered() {
+	s.mock.ExpectQuery("FROM audit_questions AS aq WHERE aq.audit_id = \\?").
+		WithArgs(54, 2, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"questions", "answered", "satisfied", "commented", "provided"}).AddRow(10, 4, 1, 2, 6))
+
+	progress, err := s.repo.GetAuditProgress(context.Background(), 2, 54)
+
+	s.NoError(err)
+	s.Equal(types.AuditProgress{AuditPlanID: 2, Questions: 10, Answered: 4, Unanswered: 6, Satisfied: 1, Commented: 2, EvidenceProvided: 6}, progress)
+}
+
+func TestAuditQuestionRepository(t *testing.T) {
+	suite.Run(t, new(AuditQuestionRepositoryTestSuite))
+}
@@ -0,0 +1,166 @@
+package repositories_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"ISO_Auditing_Tool/tests/testutils"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+)
+
+var commentRowColumns = []string{"id", "parent_id", "audit_question_id", "user_id", "comment", "created_at", "edited_at",
+	"deleted_at", "user_id", "name"}
+
+type CommentRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	mock    sqlmock.Sqlmock
+	cleanup func()
+	repo    repositories.CommentRepositoryInterface
+}
+
+func (s *CommentRepositoryTestSuite) SetupTest() {
+	s.db, s.mock, s.cleanup = testutils.SetupTestDB(s.T())
+
+	repo, err := repositories.NewCommentRepository(s.db)
+	s.NoError(err)
+	s.repo = repo
+}
+
+func (s *CommentRepositoryTestSuite) TearDownTest() {
+	s.NoError(s.mock.ExpectationsWereMet())
+	s.cleanup()
+}
+
+func (s *CommentRepositoryTestSuite) TestCreateAuditQuestionComment_LinksReplyAndMentionsInTransaction() {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec("INSERT INTO comments \\(parent_id, user_id, comment\\)").
+		WithArgs(4, 3, "Over to @bob").
+		WillReturnResult(sqlmock.NewResult(5, 1))
+	s.mock.ExpectExec("INSERT INTO audit_questions_comments \\(audit_question_id, comment_id\\)").
+		WithArgs(100, int64(5)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec("INSERT INTO comment_mentions \\(comment_id, user_id\\)").
+		WithArgs(5, 8).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	testutils.ExpectSnapshot(s.mock, "comments", 5)
+	testutils.ExpectActivity(s.mock, "created", "comment", 5)
+	s.mock.ExpectCommit()
+	s.mock.ExpectQuery("FROM comments AS c (.+) WHERE c.id = \\?").
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows(commentRowColumns).AddRow(5, 4, 100, 3, "Over to @bob", now, nil, nil, 3, "Alice"))
+	s.mock.ExpectQuery("FROM comment_mentions AS cm (.+) WHERE cm.comment_id IN \\(\\?\\)").
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"comment_id", "id", "name"}).AddRow(5, 8, "Bob"))
+
+	comment, err := s.repo.CreateAuditQuestionComment(context.Background(), 100, types.Comment{ParentID: 4, UserID: 3, Text: "Over to @bob"}, []int{8})
+
+	s.NoError(err)
+	s.Equal(5, comment.ID)
+	s.Equal(4, comment.ParentID)
+	s.Equal("Alice", comment.User.Name)
+	s.Equal([]types.UserRef{{ID: 8, Name: "Bob"}}, comment.Mentions)
+}
+
+func (s *CommentRepositoryTestSuite) TestGetByIDComment_Deleted_DropsText() {
+	now := time.Now().UTC().Truncate(time.Second)
+	s.mock.ExpectQuery("FROM comments AS c (.+) WHERE c.id = \\?").
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows(commentRowColumns).AddRow(5, nil, 100, 3, "Not available yet", now, now, now, 3, "Alice"))
+	s.mock.ExpectQuery("FROM comment_mentions AS cm").
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"comment_id", "id", "name"}).AddRow(5, 8, "Bob"))
+
+	comment, err := s.repo.GetByIDComment(context.Background(), types.Comment{ID: 5})
+
+	s.NoError(err)
+	s.True(comment.Deleted)
+	s.Empty(comment.Text)
+	s.Empty(comment.Mentions)
+	s.Require().NotNil(comment.EditedAt)
+}
+
+func (s *CommentRepositoryTestSuite) TestUpdateComment_KeepsPreviousText() {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT comment FROM comments WHERE id = \\? AND deleted_at IS NULL FOR UPDATE").
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"comment"}).AddRow("Not available yet"))
+	testutils.ExpectSnapshot(s.mock, "comments", 5)
+	s.mock.ExpectExec("INSERT INTO comment_revisions \\(comment_id, comment, edited_by\\)").
+		WithArgs(5, "Not available yet", 3).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec("UPDATE comments SET comment = \\?, edited_at = CURRENT_TIMESTAMP WHERE id = \\?").
+		WithArgs("Available from Monday", 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("DELETE FROM comment_mentions WHERE comment_id = \\?").
+		WithArgs(5).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	testutils.ExpectSnapshot(s.mock, "comments", 5)
+	testutils.ExpectActivity(s.mock, "updated", "comment", 5)
+	s.mock.ExpectCommit()
+	s.mock.ExpectQuery("FROM comments AS c (.+) WHERE c.id = \\?").
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows(commentRowColumns).AddRow(5, nil, 100, 3, "Available from Monday", now, now, nil, 3, "Alice"))
+	s.mock.ExpectQuery("FROM comment_mentions AS cm").
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"comment_id", "id", "name"}))
+
+	comment, err := s.repo.UpdateComment(context.Background(), types.Comment{ID: 5, Text: "Available from Monday"}, 3, nil)
+
+	s.NoError(err)
+	s.Equal("Available from Monday", comment.Text)
+	s.Require().NotNil(comment.EditedAt)
+}
+
+func (s *CommentRepositoryTestSuite) TestUpdateComment_Deleted_ReturnsNotFound() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT comment FROM comments WHERE id = \\? AND deleted_at IS NULL FOR UPDATE").
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"comment"}))
+	s.mock.ExpectRollback()
+
+	_, err := s.repo.UpdateComment(context.Background(), types.Comment{ID: 5, Text: "Available from Monday"}, 3, nil)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func (s *CommentRepositoryTestSuite) TestDeleteComment_Missing_ReturnsNotFound() {
+	s.mock.ExpectBegin()
+	testutils.ExpectMissingSnapshot(s.mock, "comments", 5)
+	s.mock.ExpectExec("UPDATE comments SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP WHERE id = \\? AND deleted_at IS NULL").
+		WithArgs(5).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	err := s.repo.DeleteComment(context.Background(), 5)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func (s *CommentRepositoryTestSuite) TestGetMentionedUserIDs_MatchesEmailOrLocalPart() {
+	s.mock.ExpectQuery("FROM users WHERE is_active = TRUE AND deleted_at IS NULL").
+		WithArgs("bob", "carol@example.com", "bob", "carol@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).
+			AddRow(8, "bob@example.com").
+			AddRow(9, "carol@example.com").
+			AddRow(10, "bob@supplier.example"))
+
+	matches, err := s.repo.GetMentionedUserIDs(context.Background(), []string{"bob", "carol@example.com"})
+
+	s.NoError(err)
+	s.Equal(map[string][]int{"bob": {8, 10}, "carol@example.com": {9}}, matches)
+}
+
+func TestCommentRepository(t *testing.T) {
+	suite.Run(t, new(CommentRepositoryTestSuite))
+}
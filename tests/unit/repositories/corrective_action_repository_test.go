@@ -0,0 +1,98 @@
+package repositories_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"ISO_Auditing_Tool/tests/testutils"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+)
+
+var correctiveActionRowColumns = []string{
+	"id", "finding_id", "action", "owner_id", "name", "target_date", "completion_note",
+	"completed_at", "verified_by", "verified_at", "created_at", "updated_at",
+}
+
+type CorrectiveActionRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	mock    sqlmock.Sqlmock
+	cleanup func()
+	repo    repositories.CorrectiveActionRepositoryInterface
+}
+
+func (s *CorrectiveActionRepositoryTestSuite) SetupTest() {
+	s.db, s.mock, s.cleanup = testutils.SetupTestDB(s.T())
+
+	repo, err := repositories.NewCorrectiveActionRepository(s.db)
+	s.NoError(err)
+	s.repo = repo
+}
+
+func (s *CorrectiveActionRepositoryTestSuite) TearDownTest() {
+	s.NoError(s.mock.ExpectationsWereMet())
+	s.cleanup()
+}
+
+func (s *CorrectiveActionRepositoryTestSuite) TestGetByFindingIDCorrectiveActions_ScansOptionalColumns() {
+	now := time.Now().UTC().Truncate(time.Second)
+	target := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	s.mock.ExpectQuery("WHERE ca.finding_id = \\? ORDER BY ca.id").
+		WithArgs(9).
+		WillReturnRows(sqlmock.NewRows(correctiveActionRowColumns).
+			AddRow(1, 9, "Sign minutes", nil, nil, nil, nil, nil, nil, nil, now, now).
+			AddRow(2, 9, "Train secretaries", 4, "Bob", target, "Training held", now, 3, now, now, now))
+
+	actions, err := s.repo.GetByFindingIDCorrectiveActions(context.Background(), 9)
+
+	s.NoError(err)
+	s.Len(actions, 2)
+	s.Nil(actions[0].CompletedAt)
+	s.Equal("Bob", actions[1].Owner)
+	s.Equal(3, actions[1].VerifiedBy)
+	s.NotNil(actions[1].VerifiedAt)
+}
+
+func (s *CorrectiveActionRepositoryTestSuite) TestCreateCorrectiveAction_InactiveOwner_ReturnsInvalidData() {
+	s.mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users").
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	_, err := s.repo.CreateCorrectiveAction(context.Background(), types.CorrectiveAction{FindingID: 9, OwnerID: 4})
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+}
+
+func (s *CorrectiveActionRepositoryTestSuite) TestVerifyCorrectiveAction_NotCompleted_ReturnsConflict() {
+	s.mock.ExpectBegin()
+	testutils.ExpectSnapshot(s.mock, "finding_corrective_actions", 1)
+	s.mock.ExpectExec("UPDATE finding_corrective_actions SET verified_by = \\?, verified_at = CURRENT_TIMESTAMP WHERE id = \\? AND completed_at IS NOT NULL AND verified_at IS NULL").
+		WithArgs(3, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	err := s.repo.VerifyCorrectiveAction(context.Background(), 1, 3)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (s *CorrectiveActionRepositoryTestSuite) TestGetByIDCorrectiveAction_Missing_ReturnsNotFound() {
+	s.mock.ExpectQuery("WHERE ca.id = \\?").
+		WithArgs(1).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := s.repo.GetByIDCorrectiveAction(context.Background(), types.CorrectiveAction{ID: 1})
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func TestCorrectiveActionRepository(t *testing.T) {
+	suite.Run(t, new(CorrectiveActionRepositoryTestSuite))
+}
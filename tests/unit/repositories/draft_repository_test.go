@@ -365,12 +365,17 @@
 package repositories_test
 
 import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
 	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
 	"ISO_Auditing_Tool/tests/testutils"
 	"ISO_Auditing_Tool/tests/unit/repositories/mocks"
 	"context"
+	"database/sql"
 	"testing"
+	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -399,6 +404,128 @@ func (s *DraftRepositoryTestSuite) TestCreateDraft() {
 	s.mockRepo.AssertExpectations(s.T())
 }
 
+// DraftTransitionTestSuite runs the approval workflow queries against sqlmock
+type DraftTransitionTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	mock    sqlmock.Sqlmock
+	cleanup func()
+	repo    repositories.DraftRepositoryInterface
+}
+
+func (s *DraftTransitionTestSuite) SetupTest() {
+	s.db, s.mock, s.cleanup = testutils.SetupTestDB(s.T())
+
+	repo, err := repositories.NewDraftRepository(s.db)
+	s.NoError(err)
+	s.repo = repo
+}
+
+func (s *DraftTransitionTestSuite) TearDownTest() {
+	s.NoError(s.mock.ExpectationsWereMet())
+	s.cleanup()
+}
+
+func (s *DraftTransitionTestSuite) TestTransitionDraft_UpdatesStatusAndRecordsTransition() {
+	publishedAt := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	current := types.Draft{ID: 3, StatusID: 62, ApproverID: 20, ApprovalComment: "looks good"}
+	next := current
+	next.StatusID = 64
+	next.PublishedAt = &publishedAt
+
+	s.mock.ExpectBegin()
+	testutils.ExpectSnapshot(s.mock, "drafts", 3)
+	s.mock.ExpectExec("UPDATE drafts SET status_id = \\?, approver_id = \\?, approval_comment = \\?, published_at = \\? WHERE id = \\? AND status_id = \\? AND COALESCE\\(approver_id, 0\\) = \\?").
+		WithArgs(64, 20, "looks good", publishedAt, 3, 62, 20).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO draft_transitions \\(draft_id, action, from_status_id, to_status_id, user_id, comment\\)").
+		WithArgs(3, "publish", 62, 64, 20, "publishing").
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	testutils.ExpectSnapshot(s.mock, "drafts", 3)
+	testutils.ExpectActivity(s.mock, "updated", "draft", 3)
+	s.mock.ExpectCommit()
+
+	err := s.repo.TransitionDraft(context.Background(), current, next, types.DraftTransition{Action: "publish", UserID: 20, Comment: "publishing"})
+
+	s.NoError(err)
+}
+
+func (s *DraftTransitionTestSuite) TestTransitionDraft_ChangedConcurrently_ReturnsConflict() {
+	current := types.Draft{ID: 3, StatusID: 62}
+	next := current
+	next.StatusID = 63
+	next.ApproverID = 20
+	next.ApprovalComment = "missing scope"
+
+	s.mock.ExpectBegin()
+	testutils.ExpectSnapshot(s.mock, "drafts", 3)
+	s.mock.ExpectExec("UPDATE drafts SET status_id = \\?").
+		WithArgs(63, 20, "missing scope", nil, 3, 62, 0).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	err := s.repo.TransitionDraft(context.Background(), current, next, types.DraftTransition{Action: "reject", UserID: 20, Comment: "missing scope"})
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (s *DraftTransitionTestSuite) TestGetDraftByID_Missing_ReturnsNotFound() {
+	s.mock.ExpectQuery("FROM drafts WHERE id = \\?").
+		WithArgs(3).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := s.repo.GetDraftByID(context.Background(), types.Draft{ID: 3})
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func (s *DraftTransitionTestSuite) TestUpdateDraft_ReplacesDataWhileStatusIsUnchanged() {
+	data := []byte(`{"name": "Context"}`)
+
+	s.mock.ExpectBegin()
+	testutils.ExpectSnapshot(s.mock, "drafts", 3)
+	s.mock.ExpectQuery("SELECT status_id FROM drafts WHERE id = \\? FOR UPDATE").
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"status_id"}).AddRow(61))
+	s.mock.ExpectExec("UPDATE drafts SET data = \\? WHERE id = \\? AND status_id = \\?").
+		WithArgs(data, 3, 61).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	testutils.ExpectSnapshot(s.mock, "drafts", 3)
+	testutils.ExpectActivity(s.mock, "updated", "draft", 3)
+	s.mock.ExpectCommit()
+
+	_, err := s.repo.UpdateDraft(context.Background(), types.Draft{ID: 3, Data: data}, 61)
+
+	s.NoError(err)
+}
+
+func (s *DraftTransitionTestSuite) TestUpdateDraft_SubmittedConcurrently_ReturnsConflict() {
+	s.mock.ExpectBegin()
+	testutils.ExpectSnapshot(s.mock, "drafts", 3)
+	s.mock.ExpectQuery("SELECT status_id FROM drafts WHERE id = \\? FOR UPDATE").
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"status_id"}).AddRow(62))
+	s.mock.ExpectRollback()
+
+	_, err := s.repo.UpdateDraft(context.Background(), types.Draft{ID: 3, Data: []byte(`{}`)}, 61)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (s *DraftTransitionTestSuite) TestUpdateDraft_Missing_ReturnsNotFound() {
+	s.mock.ExpectBegin()
+	testutils.ExpectMissingSnapshot(s.mock, "drafts", 3)
+	s.mock.ExpectQuery("SELECT status_id FROM drafts WHERE id = \\? FOR UPDATE").
+		WithArgs(3).
+		WillReturnError(sql.ErrNoRows)
+	s.mock.ExpectRollback()
+
+	_, err := s.repo.UpdateDraft(context.Background(), types.Draft{ID: 3, Data: []byte(`{}`)}, 61)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
 func TestDraftRepository(t *testing.T) {
 	suite.Run(t, new(DraftRepositoryTestSuite))
+	suite.Run(t, new(DraftTransitionTestSuite))
 }
@@ -0,0 +1,82 @@
+package repositories_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"ISO_Auditing_Tool/tests/testutils"
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+)
+
+type EvidenceAccessRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	mock    sqlmock.Sqlmock
+	cleanup func()
+	repo    repositories.EvidenceAccessRepositoryInterface
+}
+
+func (s *EvidenceAccessRepositoryTestSuite) SetupTest() {
+	s.db, s.mock, s.cleanup = testutils.SetupTestDB(s.T())
+
+	repo, err := repositories.NewEvidenceAccessRepository(s.db)
+	s.NoError(err)
+	s.repo = repo
+}
+
+func (s *EvidenceAccessRepositoryTestSuite) TearDownTest() {
+	s.NoError(s.mock.ExpectationsWereMet())
+	s.cleanup()
+}
+
+func (s *EvidenceAccessRepositoryTestSuite) TestGetUserRoleID_Inactive_ReturnsNotFound() {
+	s.mock.ExpectQuery("SELECT role_id FROM users WHERE id = \\? AND is_active = TRUE AND deleted_at IS NULL").
+		WithArgs(8).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := s.repo.GetUserRoleID(context.Background(), 8)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func (s *EvidenceAccessRepositoryTestSuite) TestGetGrantedIDsEvidenceAccessGrants() {
+	s.mock.ExpectQuery("FROM evidence_access_grants WHERE user_id = \\? AND evidence_provided_id IN \\(\\?, \\?\\)").
+		WithArgs(3, 5, 6).
+		WillReturnRows(sqlmock.NewRows([]string{"evidence_provided_id"}).AddRow(6))
+
+	granted, err := s.repo.GetGrantedIDsEvidenceAccessGrants(context.Background(), 3, []int{5, 6})
+
+	s.NoError(err)
+	s.Equal(map[int]bool{6: true}, granted)
+}
+
+func (s *EvidenceAccessRepositoryTestSuite) TestCreateEvidenceAccessGrant_Duplicate_ReturnsConflict() {
+	s.mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM evidence_access_grants WHERE evidence_provided_id = \\? AND user_id = \\?").
+		WithArgs(5, 9).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	err := s.repo.CreateEvidenceAccessGrant(context.Background(), types.EvidenceAccessGrant{EvidenceProvidedID: 5, UserID: 9, GrantedBy: 2})
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (s *EvidenceAccessRepositoryTestSuite) TestDeleteEvidenceAccessGrant_Missing_ReturnsNotFound() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT id FROM evidence_access_grants WHERE evidence_provided_id = \\? AND user_id = \\? FOR UPDATE").
+		WithArgs(5, 9).
+		WillReturnError(sql.ErrNoRows)
+	s.mock.ExpectRollback()
+
+	err := s.repo.DeleteEvidenceAccessGrant(context.Background(), 5, 9)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func TestEvidenceAccessRepository(t *testing.T) {
+	suite.Run(t, new(EvidenceAccessRepositoryTestSuite))
+}
@@ -0,0 +1,202 @@
+package repositories_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"ISO_Auditing_Tool/tests/testutils"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+)
+
+type EvidenceProvidedRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	mock    sqlmock.Sqlmock
+	cleanup func()
+	repo    repositories.EvidenceProvidedRepositoryInterface
+}
+
+func (s *EvidenceProvidedRepositoryTestSuite) SetupTest() {
+	s.db, s.mock, s.cleanup = testutils.SetupTestDB(s.T())
+
+	repo, err := repositories.NewEvidenceProvidedRepository(s.db)
+	s.NoError(err)
+	s.repo = repo
+}
+
+func (s *EvidenceProvidedRepositoryTestSuite) TearDownTest() {
+	s.NoError(s.mock.ExpectationsWereMet())
+	s.cleanup()
+}
+
+func (s *EvidenceProvidedRepositoryTestSuite) TestGetByIDEvidenceProvided_Deleted_ReturnsNotFound() {
+	s.mock.ExpectQuery("FROM evidence_provided AS ep WHERE ep.id = \\? AND ep.deleted_at IS NULL").
+		WithArgs(7).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := s.repo.GetByIDEvidenceProvided(context.Background(), types.EvidenceProvided{ID: 7})
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func (s *EvidenceProvidedRepositoryTestSuite) TestGetByIDEvidenceProvided_ScansFile() {
+	now := time.Now().UTC().Truncate(time.Second)
+	s.mock.ExpectQuery("FROM evidence_provided AS ep WHERE ep.id = \\? AND ep.deleted_at IS NULL").
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "evidence_id", "audit_question_id", "user_id", "evidence", "type_id",
+			"confidentiality_id", "status_id", "review_reason", "reviewed_by", "reviewed_at", "superseded_by", "retention_days", "file_key", "file_name", "file_size", "file_sha256",
+			"file_mime_type", "created_at", "updated_at"}).
+			AddRow(7, 20, 101, 3, "Minutes", 42, 49, 53, nil, nil, nil, nil, 365, "evidence/7/abc", "minutes.pdf", 2048, "5f2c", "application/pdf", now, now))
+
+	provided, err := s.repo.GetByIDEvidenceProvided(context.Background(), types.EvidenceProvided{ID: 7})
+
+	s.NoError(err)
+	s.Require().NotNil(provided.File)
+	s.Equal("evidence/7/abc", provided.File.Key)
+	s.Equal(int64(2048), provided.File.Size)
+	s.Equal(now.AddDate(0, 0, 365), provided.ExpiresAt)
+	s.Nil(provided.Review)
+}
+
+func (s *EvidenceProvidedRepositoryTestSuite) TestGetByIDEvidenceProvided_ScansReviewAndSuccessor() {
+	now := time.Now().UTC().Truncate(time.Second)
+	s.mock.ExpectQuery("FROM evidence_provided AS ep WHERE ep.id = \\? AND ep.deleted_at IS NULL").
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "evidence_id", "audit_question_id", "user_id", "evidence", "type_id",
+			"confidentiality_id", "status_id", "review_reason", "reviewed_by", "reviewed_at", "superseded_by", "retention_days", "file_key", "file_name", "file_size", "file_sha256",
+			"file_mime_type", "created_at", "updated_at"}).
+			AddRow(7, 20, 101, 3, "Minutes", 45, 49, 58, "Unsigned", 4, now, 9, 365, nil, nil, nil, nil, nil, now, now))
+
+	provided, err := s.repo.GetByIDEvidenceProvided(context.Background(), types.EvidenceProvided{ID: 7})
+
+	s.NoError(err)
+	s.Require().NotNil(provided.Review)
+	s.Equal(types.EvidenceReview{Reason: "Unsigned", ReviewedBy: 4, ReviewedAt: now}, *provided.Review)
+	s.Equal(9, provided.SupersededBy)
+}
+
+func (s *EvidenceProvidedRepositoryTestSuite) TestReviewEvidenceProvided_Changed_ReturnsConflict() {
+	s.mock.ExpectBegin()
+	testutils.ExpectSnapshot(s.mock, "evidence_provided", 7)
+	s.mock.ExpectExec("UPDATE evidence_provided SET status_id = \\?, review_reason = \\?, reviewed_by = \\?, reviewed_at = CURRENT_TIMESTAMP WHERE id = \\? AND status_id = \\? AND deleted_at IS NULL").
+		WithArgs(54, "Signed minutes", 4, 7, 53).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	err := s.repo.ReviewEvidenceProvided(context.Background(), 7, 53, 54, types.EvidenceReview{Reason: "Signed minutes", ReviewedBy: 4})
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (s *EvidenceProvidedRepositoryTestSuite) TestSupersedeEvidenceProvided_AlreadyReplaced_RollsBack() {
+	replaced := types.EvidenceProvided{ID: 7, StatusVal: types.ReferenceValue{ID: 55}}
+	replacement := types.EvidenceProvided{EvidenceID: 20, AuditQuestionID: 101, UserID: 3, Provided: "Signed minutes",
+		TypeVal: types.ReferenceValue{ID: 45}, ConfidentialityVal: types.ReferenceValue{ID: 49}, StatusVal: types.ReferenceValue{ID: 53}, RetentionDays: 365}
+
+	s.mock.ExpectBegin()
+	testutils.ExpectSnapshot(s.mock, "evidence_provided", 7)
+	s.mock.ExpectExec("INSERT INTO evidence_provided").
+		WithArgs(20, 101, 3, "Signed minutes", 45, 49, 53, 365).
+		WillReturnResult(sqlmock.NewResult(9, 1))
+	s.mock.ExpectExec("UPDATE evidence_provided SET status_id = \\?, superseded_by = \\? WHERE id = \\? AND status_id = \\? AND superseded_by IS NULL AND deleted_at IS NULL").
+		WithArgs(58, int64(9), 7, 55).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	_, err := s.repo.SupersedeEvidenceProvided(context.Background(), replaced, replacement, 58)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+	s.NoError(s.mock.ExpectationsWereMet())
+}
+
+func (s *EvidenceProvidedRepositoryTestSuite) TestGetExpiredEvidenceProvided_FiltersByRetention() {
+	asOf := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	s.mock.ExpectQuery("WHERE ep.deleted_at IS NULL AND DATE_ADD\\(ep.created_at, INTERVAL ep.retention_days DAY\\) <= \\? ORDER BY ep.created_at, ep.id").
+		WithArgs(asOf).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	expired, err := s.repo.GetExpiredEvidenceProvided(context.Background(), asOf)
+
+	s.NoError(err)
+	s.Empty(expired)
+}
+
+func (s *EvidenceProvidedRepositoryTestSuite) TestUpdateEvidenceProvidedStatus_Changed_ReturnsConflict() {
+	s.mock.ExpectBegin()
+	testutils.ExpectSnapshot(s.mock, "evidence_provided", 7)
+	s.mock.ExpectExec("UPDATE evidence_provided SET status_id = \\? WHERE id = \\? AND status_id = \\? AND deleted_at IS NULL").
+		WithArgs(57, 7, 53).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	err := s.repo.UpdateEvidenceProvidedStatus(context.Background(), 7, 53, 57)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (s *EvidenceProvidedRepositoryTestSuite) TestPurgeEvidenceProvided_ClearsFileKey() {
+	s.mock.ExpectBegin()
+	testutils.ExpectSnapshot(s.mock, "evidence_provided", 7)
+	s.mock.ExpectExec("UPDATE evidence_provided SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP, file_key = NULL WHERE id = \\? AND deleted_at IS NULL").
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	testutils.ExpectSnapshot(s.mock, "evidence_provided", 7)
+	testutils.ExpectActivity(s.mock, "deleted", "evidence_provided", 7)
+	s.mock.ExpectCommit()
+
+	err := s.repo.PurgeEvidenceProvided(context.Background(), 7)
+
+	s.NoError(err)
+}
+
+func (s *EvidenceProvidedRepositoryTestSuite) TestUpdateEvidenceProvidedFile_Missing_ReturnsNotFound() {
+	file := types.EvidenceFile{Key: "evidence/7/abc", Name: "minutes.pdf", Size: 2048, SHA256: "5f2c", MIMEType: "application/pdf"}
+	s.mock.ExpectBegin()
+	testutils.ExpectMissingSnapshot(s.mock, "evidence_provided", 7)
+	s.mock.ExpectExec("UPDATE evidence_provided SET file_key = \\?, file_name = \\?, file_size = \\?, file_sha256 = \\?, file_mime_type = \\? WHERE id = \\? AND deleted_at IS NULL").
+		WithArgs("evidence/7/abc", "minutes.pdf", int64(2048), "5f2c", "application/pdf", 7).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	err := s.repo.UpdateEvidenceProvidedFile(context.Background(), 7, file)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func (s *EvidenceProvidedRepositoryTestSuite) TestDeleteEvidenceProvided_SoftDeletes() {
+	s.mock.ExpectBegin()
+	testutils.ExpectSnapshot(s.mock, "evidence_provided", 7)
+	s.mock.ExpectExec("UPDATE evidence_provided SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP WHERE id = \\? AND deleted_at IS NULL").
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	testutils.ExpectSnapshot(s.mock, "evidence_provided", 7)
+	testutils.ExpectActivity(s.mock, "deleted", "evidence_provided", 7)
+	s.mock.ExpectCommit()
+
+	err := s.repo.DeleteEvidenceProvided(context.Background(), 7)
+
+	s.NoError(err)
+}
+
+func (s *EvidenceProvidedRepositoryTestSuite) TestDeleteEvidenceProvided_Missing_ReturnsNotFound() {
+	s.mock.ExpectBegin()
+	testutils.ExpectMissingSnapshot(s.mock, "evidence_provided", 7)
+	s.mock.ExpectExec("UPDATE evidence_provided SET is_active = FALSE").
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	err := s.repo.DeleteEvidenceProvided(context.Background(), 7)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func TestEvidenceProvidedRepository(t *testing.T) {
+	suite.Run(t, new(EvidenceProvidedRepositoryTestSuite))
+}
@@ -0,0 +1,78 @@
+package repositories_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/tests/testutils"
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+)
+
+type EvidenceRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	mock    sqlmock.Sqlmock
+	cleanup func()
+	repo    repositories.EvidenceRepositoryInterface
+}
+
+func (s *EvidenceRepositoryTestSuite) SetupTest() {
+	s.db, s.mock, s.cleanup = testutils.SetupTestDB(s.T())
+
+	repo, err := repositories.NewEvidenceRepository(s.db)
+	s.NoError(err)
+	s.repo = repo
+}
+
+func (s *EvidenceRepositoryTestSuite) TearDownTest() {
+	s.NoError(s.mock.ExpectationsWereMet())
+	s.cleanup()
+}
+
+func (s *EvidenceRepositoryTestSuite) TestGetEvidenceType_OtherReferenceType_ReturnsInvalidData() {
+	s.mock.ExpectQuery("SELECT (.+) FROM reference_values AS rv").
+		WithArgs(11, "evidence.type_id").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "type_id", "code", "name", "description", "is_active", "created_at", "updated_at"}))
+
+	_, err := s.repo.GetEvidenceType(context.Background(), 11)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+}
+
+func (s *EvidenceRepositoryTestSuite) TestDeleteEvidence_Provided_ReturnsConflict() {
+	s.mock.ExpectQuery("SELECT COUNT(.+) FROM evidence_provided").
+		WithArgs(15).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	err := s.repo.DeleteEvidence(context.Background(), 15)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (s *EvidenceRepositoryTestSuite) TestReorderEvidence_RenumbersInGivenOrder() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT id FROM evidence").
+		WithArgs(8).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(15).AddRow(16))
+	for i, id := range []int{16, 15} {
+		s.mock.ExpectExec("UPDATE evidence SET sort_order = ?").
+			WithArgs(i+1, id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	s.mock.ExpectExec("INSERT INTO activity_log").
+		WithArgs(sqlmock.AnyArg(), "updated", "question", 8, `{"evidence_ids":[15,16]}`, `{"evidence_ids":[16,15]}`, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repo.ReorderEvidence(context.Background(), 8, []int{16, 15})
+
+	s.NoError(err)
+}
+
+func TestEvidenceRepository(t *testing.T) {
+	suite.Run(t, new(EvidenceRepositoryTestSuite))
+}
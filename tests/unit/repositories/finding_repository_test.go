@@ -0,0 +1,169 @@
+package repositories_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"ISO_Auditing_Tool/tests/testutils"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+)
+
+var findingRowColumns = []string{
+	"id", "audit_id", "audit_question_id", "question_id", "reference_code", "finding_type_id",
+	"severity_id", "description", "due_date", "responsible_user_id", "name", "status_id",
+	"status_reason", "needs_owner", "created_by", "created_at", "updated_at",
+}
+
+type FindingRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	mock    sqlmock.Sqlmock
+	cleanup func()
+	repo    repositories.FindingRepositoryInterface
+}
+
+func (s *FindingRepositoryTestSuite) SetupTest() {
+	s.db, s.mock, s.cleanup = testutils.SetupTestDB(s.T())
+
+	repo, err := repositories.NewFindingRepository(s.db)
+	s.NoError(err)
+	s.repo = repo
+}
+
+func (s *FindingRepositoryTestSuite) TearDownTest() {
+	s.NoError(s.mock.ExpectationsWereMet())
+	s.cleanup()
+}
+
+func (s *FindingRepositoryTestSuite) TestGetAllFindings_AppliesFilter() {
+	now := time.Now().UTC().Truncate(time.Second)
+	from := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	s.mock.ExpectQuery("WHERE f.deleted_at IS NULL AND f.audit_id = \\? AND f.responsible_user_id = \\? AND f.severity_id = \\? AND f.status_id = \\? AND f.due_date >= \\? AND f.due_date < \\? ORDER BY f.due_date, f.id").
+		WithArgs(2, 4, 25, 30, from, to).
+		WillReturnRows(sqlmock.NewRows(findingRowColumns).
+			AddRow(9, 2, 100, 10, "9.3", 20, 25, "Minutes not signed", from, 4, "Bob", 30, nil, false, 3, now, now))
+
+	findings, err := s.repo.GetAllFindings(context.Background(), types.FindingFilter{
+		AuditID: 2, ResponsibleUserID: 4, SeverityID: 25, StatusID: 30, DueFrom: from, DueTo: to,
+	})
+
+	s.NoError(err)
+	s.Len(findings, 1)
+	s.Equal(100, findings[0].AuditQuestionID)
+	s.Equal("Bob", findings[0].ResponsibleUser)
+}
+
+func (s *FindingRepositoryTestSuite) TestGetAllFindings_OfAuditor() {
+	s.mock.ExpectQuery("WHERE f.deleted_at IS NULL AND EXISTS \\(SELECT 1 FROM audit_plans AS ap WHERE ap.id = f.audit_id AND \\(ap.lead_auditor_id = \\? OR EXISTS").
+		WithArgs(7, 7).
+		WillReturnRows(sqlmock.NewRows(findingRowColumns))
+
+	findings, err := s.repo.GetAllFindings(context.Background(), types.FindingFilter{AuditorID: 7})
+
+	s.NoError(err)
+	s.Empty(findings)
+}
+
+func (s *FindingRepositoryTestSuite) TestGetAllFindings_FollowedUpByUser() {
+	s.mock.ExpectQuery("WHERE f.deleted_at IS NULL AND \\(f.responsible_user_id = \\? OR EXISTS \\(SELECT 1 FROM finding_corrective_actions AS fca WHERE fca.finding_id = f.id AND fca.owner_id = \\?\\)\\)").
+		WithArgs(20, 20).
+		WillReturnRows(sqlmock.NewRows(findingRowColumns))
+
+	findings, err := s.repo.GetAllFindings(context.Background(), types.FindingFilter{FollowUpUserID: 20})
+
+	s.NoError(err)
+	s.Empty(findings)
+}
+
+func (s *FindingRepositoryTestSuite) TestGetAllFindings_MatchesAnyStatus() {
+	to := time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC)
+
+	s.mock.ExpectQuery("WHERE f.deleted_at IS NULL AND f.status_id IN \\(\\?, \\?, \\?\\) AND f.due_date < \\?").
+		WithArgs(30, 31, 32, to).
+		WillReturnRows(sqlmock.NewRows(findingRowColumns))
+
+	findings, err := s.repo.GetAllFindings(context.Background(), types.FindingFilter{StatusIDs: []int{30, 31, 32}, DueTo: to})
+
+	s.NoError(err)
+	s.Empty(findings)
+}
+
+func (s *FindingRepositoryTestSuite) TestCreateFinding_LinksAuditQuestion() {
+	now := time.Now().UTC().Truncate(time.Second)
+	due := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	s.mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users").
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec("INSERT INTO findings").
+		WithArgs(2, 10, 20, 25, "Minutes not signed", due, 4, 30, 3).
+		WillReturnResult(sqlmock.NewResult(9, 1))
+	s.mock.ExpectExec("INSERT INTO audit_question_findings \\(audit_question_id, finding_id\\)").
+		WithArgs(100, int64(9)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	testutils.ExpectSnapshot(s.mock, "findings", 9)
+	testutils.ExpectActivity(s.mock, "created", "finding", 9)
+	s.mock.ExpectCommit()
+	s.mock.ExpectQuery("WHERE f.id = \\? AND f.deleted_at IS NULL").
+		WithArgs(9).
+		WillReturnRows(sqlmock.NewRows(findingRowColumns).
+			AddRow(9, 2, 100, 10, "9.3", 20, 25, "Minutes not signed", due, 4, "Bob", 30, nil, false, 3, now, now))
+
+	created, err := s.repo.CreateFinding(context.Background(), types.Finding{
+		AuditID: 2, AuditQuestionID: 100, QuestionID: 10, Description: "Minutes not signed", DueDate: due,
+		TypeVal: types.ReferenceValue{ID: 20}, SeverityVal: types.ReferenceValue{ID: 25}, StatusVal: types.ReferenceValue{ID: 30},
+		ResponsibleUserID: 4, CreatedBy: 3,
+	})
+
+	s.NoError(err)
+	s.Equal(9, created.ID)
+}
+
+func (s *FindingRepositoryTestSuite) TestCreateFinding_InactiveOwner_ReturnsInvalidData() {
+	s.mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users").
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	_, err := s.repo.CreateFinding(context.Background(), types.Finding{ResponsibleUserID: 4})
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+}
+
+func (s *FindingRepositoryTestSuite) TestDeleteFinding_Missing_ReturnsNotFound() {
+	s.mock.ExpectBegin()
+	testutils.ExpectMissingSnapshot(s.mock, "findings", 9)
+	s.mock.ExpectExec("UPDATE findings SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP WHERE id = \\? AND deleted_at IS NULL").
+		WithArgs(9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	err := s.repo.DeleteFinding(context.Background(), 9)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func (s *FindingRepositoryTestSuite) TestUpdateFindingStatus_ChangedConcurrently_ReturnsConflict() {
+	s.mock.ExpectBegin()
+	testutils.ExpectSnapshot(s.mock, "findings", 9)
+	s.mock.ExpectExec("UPDATE findings SET status_id = \\?, status_reason = \\? WHERE id = \\? AND status_id = \\? AND deleted_at IS NULL").
+		WithArgs(34, "Process retired", 9, 31).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	err := s.repo.UpdateFindingStatus(context.Background(), 9, 31, 34, "Process retired")
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func TestFindingRepository(t *testing.T) {
+	suite.Run(t, new(FindingRepositoryTestSuite))
+}
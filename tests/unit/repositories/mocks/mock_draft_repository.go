@@ -25,8 +25,8 @@ func (m *MockDraftRepository) GetDraftByID(ctx context.Context, draft types.Draf
 	return args.Get(0).(types.Draft), args.Error(1)
 }
 
-func (m *MockDraftRepository) UpdateDraft(ctx context.Context, draft types.Draft) (types.Draft, error) {
-	args := m.Called(ctx, draft)
+func (m *MockDraftRepository) UpdateDraft(ctx context.Context, draft types.Draft, fromStatusID int) (types.Draft, error) {
+	args := m.Called(ctx, draft, fromStatusID)
 	return args.Get(0).(types.Draft), args.Error(1)
 }
 
@@ -40,9 +40,14 @@ func (m *MockDraftRepository) GetDraftsByTypeAndObject(ctx context.Context, type
 	return args.Get(0).([]types.Draft), args.Error(1)
 }
 
-func (m *MockDraftRepository) UpdateRequirementAndDeleteDraft(ctx context.Context, requirement types.Requirement, draft types.Draft) (types.Requirement, error) {
-	args := m.Called(ctx, requirement)
-	return args.Get(0).(types.Requirement), args.Error(1)
+func (m *MockDraftRepository) GetDraftTransitions(ctx context.Context, draftID int) ([]types.DraftTransition, error) {
+	args := m.Called(ctx, draftID)
+	return args.Get(0).([]types.DraftTransition), args.Error(1)
+}
+
+func (m *MockDraftRepository) TransitionDraft(ctx context.Context, current, next types.Draft, transition types.DraftTransition) error {
+	args := m.Called(ctx, current, next, transition)
+	return args.Error(0)
 }
 
 // Reset clears all expectations and calls
@@ -30,6 +30,26 @@ func (m *MockDraftService) Update(ctx context.Context, draft types.Draft) (types
 	return args.Get(0).(types.Draft), args.Error(1)
 }
 
+func (m *MockDraftService) Submit(ctx context.Context, id int, form types.DraftTransitionForm) (types.Draft, error) {
+	args := m.Called(ctx, id, form)
+	return args.Get(0).(types.Draft), args.Error(1)
+}
+
+func (m *MockDraftService) Approve(ctx context.Context, id int, form types.DraftTransitionForm) (types.Draft, error) {
+	args := m.Called(ctx, id, form)
+	return args.Get(0).(types.Draft), args.Error(1)
+}
+
+func (m *MockDraftService) Reject(ctx context.Context, id int, form types.DraftTransitionForm) (types.Draft, error) {
+	args := m.Called(ctx, id, form)
+	return args.Get(0).(types.Draft), args.Error(1)
+}
+
+func (m *MockDraftService) Publish(ctx context.Context, id int, form types.DraftTransitionForm) (types.Draft, error) {
+	args := m.Called(ctx, id, form)
+	return args.Get(0).(types.Draft), args.Error(1)
+}
+
 func (m *MockDraftService) Reset() {
 	m.ExpectedCalls = nil
 	m.Calls = nil
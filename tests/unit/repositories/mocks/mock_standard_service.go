@@ -0,0 +1,46 @@
+package mocks
+
+import (
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStandardService struct {
+	mock.Mock
+}
+
+func (m *MockStandardService) GetAll(ctx context.Context) ([]types.Standard, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]types.Standard), args.Error(1)
+}
+
+func (m *MockStandardService) GetByID(ctx context.Context, standard types.Standard) (types.Standard, error) {
+	args := m.Called(ctx, standard)
+	return args.Get(0).(types.Standard), args.Error(1)
+}
+
+func (m *MockStandardService) GetByIDWithFullHierarchy(ctx context.Context, standard types.Standard) (types.Standard, error) {
+	args := m.Called(ctx, standard)
+	return args.Get(0).(types.Standard), args.Error(1)
+}
+
+func (m *MockStandardService) Create(ctx context.Context, standard types.Standard) (types.Standard, error) {
+	args := m.Called(ctx, standard)
+	return args.Get(0).(types.Standard), args.Error(1)
+}
+
+func (m *MockStandardService) Update(ctx context.Context, standard types.Standard) (types.Standard, error) {
+	args := m.Called(ctx, standard)
+	return args.Get(0).(types.Standard), args.Error(1)
+}
+
+func (m *MockStandardService) Delete(ctx context.Context, standard types.Standard) error {
+	args := m.Called(ctx, standard)
+	return args.Error(0)
+}
+
+func (m *MockStandardService) Reset() {
+	m.ExpectedCalls = nil
+	m.Calls = nil
+}
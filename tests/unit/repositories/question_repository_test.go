@@ -0,0 +1,88 @@
+package repositories_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"ISO_Auditing_Tool/tests/testutils"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+)
+
+type QuestionRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	mock    sqlmock.Sqlmock
+	cleanup func()
+	repo    repositories.QuestionRepositoryInterface
+}
+
+func (s *QuestionRepositoryTestSuite) SetupTest() {
+	s.db, s.mock, s.cleanup = testutils.SetupTestDB(s.T())
+
+	repo, err := repositories.NewQuestionRepository(s.db)
+	s.NoError(err)
+	s.repo = repo
+}
+
+func (s *QuestionRepositoryTestSuite) TearDownTest() {
+	s.NoError(s.mock.ExpectationsWereMet())
+	s.cleanup()
+}
+
+func (s *QuestionRepositoryTestSuite) TestCreateQuestion_AppendsAfterLastQuestion() {
+	now := time.Now().UTC().Truncate(time.Second)
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT COALESCE(.+) FROM questions").
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(2))
+	s.mock.ExpectExec("INSERT INTO questions").
+		WithArgs(4, "Is the scope documented?", sql.NullString{}, 3).
+		WillReturnResult(sqlmock.NewResult(8, 1))
+	testutils.ExpectSnapshot(s.mock, "questions", 8)
+	testutils.ExpectActivity(s.mock, "created", "question", 8)
+	s.mock.ExpectCommit()
+	s.mock.ExpectQuery("SELECT (.+) FROM questions WHERE id = ?").
+		WithArgs(8).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "requirement_id", "question", "guidance", "created_at", "updated_at", "sort_order"}).
+			AddRow(8, 4, "Is the scope documented?", nil, now, now, 3))
+
+	question, err := s.repo.CreateQuestion(context.Background(), types.Question{RequirementID: 4, Question: "Is the scope documented?"})
+
+	s.NoError(err)
+	s.Equal(8, question.ID)
+	s.Equal(3, question.SortOrder)
+}
+
+func (s *QuestionRepositoryTestSuite) TestDeleteQuestion_UsedByAudit_ReturnsConflict() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT (.+) FROM audit_questions").
+		WithArgs(8, 8).
+		WillReturnRows(sqlmock.NewRows([]string{"audits", "provided"}).AddRow(1, 0))
+	s.mock.ExpectRollback()
+
+	err := s.repo.DeleteQuestion(context.Background(), 8)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (s *QuestionRepositoryTestSuite) TestReorderQuestions_MissingID_ReturnsInvalidData() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT id FROM questions").
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8).AddRow(9))
+	s.mock.ExpectRollback()
+
+	err := s.repo.ReorderQuestions(context.Background(), 4, []int{9})
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+}
+
+func TestQuestionRepository(t *testing.T) {
+	suite.Run(t, new(QuestionRepositoryTestSuite))
+}
@@ -0,0 +1,80 @@
+package repositories_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/tests/testutils"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReferenceDataRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	mock    sqlmock.Sqlmock
+	cleanup func()
+	repo    repositories.ReferenceDataRepositoryInterface
+}
+
+func (s *ReferenceDataRepositoryTestSuite) SetupTest() {
+	s.db, s.mock, s.cleanup = testutils.SetupTestDB(s.T())
+
+	repo, err := repositories.NewReferenceDataRepository(s.db)
+	s.NoError(err)
+	s.repo = repo
+}
+
+func (s *ReferenceDataRepositoryTestSuite) TearDownTest() {
+	s.NoError(s.mock.ExpectationsWereMet())
+	s.cleanup()
+}
+
+func (s *ReferenceDataRepositoryTestSuite) TestGetAllReferenceValues_SkipsDeletedRows() {
+	now := time.Now().UTC().Truncate(time.Second)
+	s.mock.ExpectQuery("SELECT (.+) FROM reference_values WHERE deleted_at IS NULL").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "type_id", "code", "name", "description", "is_active", "created_at", "updated_at"}).
+			AddRow(62, 12, "DRAFT_PENDING_APPROVAL", "Pending Approval", nil, true, now, now))
+
+	values, err := s.repo.GetAllReferenceValues(context.Background())
+
+	s.NoError(err)
+	s.Len(values, 1)
+	s.Equal("", values[0].Description)
+}
+
+func (s *ReferenceDataRepositoryTestSuite) TestDeleteReferenceValue_SoftDeletes() {
+	s.mock.ExpectBegin()
+	testutils.ExpectSnapshot(s.mock, "reference_values", 62)
+	s.mock.ExpectExec("UPDATE reference_values SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP").
+		WithArgs(62).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	testutils.ExpectSnapshot(s.mock, "reference_values", 62)
+	testutils.ExpectActivity(s.mock, "deleted", "reference_value", 62)
+	s.mock.ExpectCommit()
+
+	err := s.repo.DeleteReferenceValue(context.Background(), 62)
+
+	s.NoError(err)
+}
+
+func (s *ReferenceDataRepositoryTestSuite) TestDeleteReferenceValue_AlreadyDeleted_ReturnsNotFound() {
+	s.mock.ExpectBegin()
+	testutils.ExpectSnapshot(s.mock, "reference_values", 62)
+	s.mock.ExpectExec("UPDATE reference_values SET is_active = FALSE").
+		WithArgs(62).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	err := s.repo.DeleteReferenceValue(context.Background(), 62)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func TestReferenceDataRepository(t *testing.T) {
+	suite.Run(t, new(ReferenceDataRepositoryTestSuite))
+}
@@ -0,0 +1,78 @@
+package repositories_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/tests/testutils"
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RequirementLevelRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	mock    sqlmock.Sqlmock
+	cleanup func()
+	repo    repositories.RequirementLevelRepositoryInterface
+}
+
+func (s *RequirementLevelRepositoryTestSuite) SetupTest() {
+	s.db, s.mock, s.cleanup = testutils.SetupTestDB(s.T())
+
+	repo, err := repositories.NewRequirementLevelRepository(s.db)
+	s.NoError(err)
+	s.repo = repo
+}
+
+func (s *RequirementLevelRepositoryTestSuite) TearDownTest() {
+	s.NoError(s.mock.ExpectationsWereMet())
+	s.cleanup()
+}
+
+func (s *RequirementLevelRepositoryTestSuite) TestGetByStandardIDRequirementLevels_ReturnsRows() {
+	s.mock.ExpectQuery("SELECT (.+) FROM requirement_level WHERE standard_id = ?").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "standard_id", "level_order", "level_name"}).
+			AddRow(1, 1, 1, "Clause").
+			AddRow(2, 1, 2, "Subclause"))
+
+	levels, err := s.repo.GetByStandardIDRequirementLevels(context.Background(), 1)
+
+	s.NoError(err)
+	s.Len(levels, 2)
+	s.Equal("Subclause", levels[1].LevelName)
+}
+
+func (s *RequirementLevelRepositoryTestSuite) TestDeleteRequirementLevel_InUse_ReturnsConflict() {
+	s.mock.ExpectQuery("SELECT COUNT(.+) FROM requirement WHERE requirement_level_id = ?").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+
+	err := s.repo.DeleteRequirementLevel(context.Background(), 2)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (s *RequirementLevelRepositoryTestSuite) TestDeleteRequirementLevel_Missing_ReturnsNotFound() {
+	s.mock.ExpectQuery("SELECT COUNT(.+) FROM requirement WHERE requirement_level_id = ?").
+		WithArgs(9).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	s.mock.ExpectBegin()
+	testutils.ExpectMissingSnapshot(s.mock, "requirement_level", 9)
+	s.mock.ExpectExec("DELETE FROM requirement_level WHERE id = ?").
+		WithArgs(9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	err := s.repo.DeleteRequirementLevel(context.Background(), 9)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func TestRequirementLevelRepository(t *testing.T) {
+	suite.Run(t, new(RequirementLevelRepositoryTestSuite))
+}
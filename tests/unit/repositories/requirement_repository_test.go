@@ -0,0 +1,208 @@
+package repositories_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"ISO_Auditing_Tool/tests/testutils"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RequirementRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	mock    sqlmock.Sqlmock
+	cleanup func()
+	repo    repositories.RequirementRepositoryInterface
+}
+
+func (s *RequirementRepositoryTestSuite) SetupTest() {
+	s.db, s.mock, s.cleanup = testutils.SetupTestDB(s.T())
+
+	repo, err := repositories.NewRequirementRepository(s.db)
+	s.NoError(err)
+	s.repo = repo
+}
+
+func (s *RequirementRepositoryTestSuite) TearDownTest() {
+	s.NoError(s.mock.ExpectationsWereMet())
+	s.cleanup()
+}
+
+var requirementColumns = []string{"id", "standard_id", "requirement_level_id", "parent_id", "reference_code", "name", "description", "sort_order"}
+
+func (s *RequirementRepositoryTestSuite) TestGetByIDRequirement_NotFound_ReturnsNotFoundCode() {
+	s.mock.ExpectQuery("SELECT (.+) FROM requirement WHERE id = ?").
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(requirementColumns))
+
+	_, err := s.repo.GetByIDRequirement(context.Background(), types.Requirement{ID: 3})
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func (s *RequirementRepositoryTestSuite) TestCreateRequirement_AppendsAfterLastSibling() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT COALESCE(.+) FROM requirement").
+		WithArgs(1, sql.NullInt64{Int64: 10, Valid: true}).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(2))
+	s.mock.ExpectExec("INSERT INTO requirement").
+		WithArgs(1, 2, sql.NullInt64{Int64: 10, Valid: true}, "4.3", "Scope", sql.NullString{}, 3).
+		WillReturnResult(sqlmock.NewResult(14, 1))
+	testutils.ExpectSnapshot(s.mock, "requirement", 14)
+	testutils.ExpectActivity(s.mock, "created", "requirement", 14)
+	s.mock.ExpectCommit()
+
+	requirement, err := s.repo.CreateRequirement(context.Background(), types.Requirement{
+		StandardID: 1, LevelID: 2, ParentID: 10, ReferenceCode: "4.3", Name: "Scope",
+	})
+
+	s.NoError(err)
+	s.Equal(14, requirement.ID)
+	s.Equal(3, requirement.SortOrder)
+}
+
+func (s *RequirementRepositoryTestSuite) TestMoveRequirement_InsertsAtPositionAndRenumbers() {
+	s.mock.ExpectBegin()
+	testutils.ExpectSnapshot(s.mock, "requirement", 11)
+	s.mock.ExpectQuery("SELECT id FROM requirement").
+		WithArgs(1, sql.NullInt64{}).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10).AddRow(20))
+	s.mock.ExpectExec("UPDATE requirement SET parent_id = ?").
+		WithArgs(sql.NullInt64{}, 11).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	for i, id := range []int{10, 11, 20} {
+		s.mock.ExpectExec("UPDATE requirement SET sort_order = ?").
+			WithArgs(i+1, id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	testutils.ExpectSnapshot(s.mock, "requirement", 11)
+	testutils.ExpectActivity(s.mock, "updated", "requirement", 11)
+	s.mock.ExpectCommit()
+	s.mock.ExpectQuery("SELECT (.+) FROM requirement WHERE id = ?").
+		WithArgs(11).
+		WillReturnRows(sqlmock.NewRows(requirementColumns).AddRow(11, 1, 1, nil, "5", "Leadership", nil, 2))
+
+	requirement, err := s.repo.MoveRequirement(context.Background(), types.Requirement{ID: 11, StandardID: 1}, 1)
+
+	s.NoError(err)
+	s.Equal(0, requirement.ParentID)
+	s.Equal(2, requirement.SortOrder)
+}
+
+func (s *RequirementRepositoryTestSuite) TestReorderRequirements_MismatchedIDs_ReturnsInvalidData() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT id FROM requirement").
+		WithArgs(1, sql.NullInt64{Int64: 10, Valid: true}).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11).AddRow(12))
+	s.mock.ExpectRollback()
+
+	err := s.repo.ReorderRequirements(context.Background(), 1, 10, []int{12, 13})
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+}
+
+func (s *RequirementRepositoryTestSuite) TestReorderRequirements_UpdatesSortOrder() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT id FROM requirement").
+		WithArgs(1, sql.NullInt64{Int64: 10, Valid: true}).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11).AddRow(12))
+	s.mock.ExpectExec("UPDATE requirement SET sort_order = ?").WithArgs(1, 12).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("UPDATE requirement SET sort_order = ?").WithArgs(2, 11).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO activity_log").
+		WithArgs(sqlmock.AnyArg(), "updated", "requirement", 10, `{"requirement_ids":[11,12]}`, `{"requirement_ids":[12,11]}`, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectCommit()
+
+	s.NoError(s.repo.ReorderRequirements(context.Background(), 1, 10, []int{12, 11}))
+}
+
+func (s *RequirementRepositoryTestSuite) expectLockedRequirement(id int, description string) {
+	s.mock.ExpectQuery("SELECT (.+) FROM requirement WHERE id = \\? FOR UPDATE").
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "standard_id", "requirement_level_id", "parent_id", "reference_code", "name", "description", "sort_order"}).
+			AddRow(id, 1, 3, nil, "4.2", "Renamed", description, 1))
+}
+
+func (s *RequirementRepositoryTestSuite) TestUpdateRequirementDescriptionAndPublishDraft_ChangesDescriptionOnlyAndKeepsDraft() {
+	publishedAt := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	current := types.Draft{ID: 3, StatusID: 62, ApproverID: 20, ApprovalComment: "looks good"}
+	next := current
+	next.StatusID = 64
+	next.PublishedAt = &publishedAt
+
+	s.mock.ExpectBegin()
+	testutils.ExpectSnapshot(s.mock, "requirement", 11)
+	testutils.ExpectSnapshot(s.mock, "drafts", 3)
+	s.expectLockedRequirement(11, "Old description")
+	s.mock.ExpectExec("UPDATE requirement SET description = \\? WHERE id = \\?").
+		WithArgs("New description", 11).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("UPDATE drafts SET status_id = \\?, approver_id = \\?, approval_comment = \\?, published_at = \\? WHERE id = \\? AND status_id = \\?").
+		WithArgs(64, 20, "looks good", publishedAt, 3, 62, 20).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO draft_transitions").
+		WithArgs(3, "publish", 62, 64, 20, "publishing").
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	testutils.ExpectSnapshot(s.mock, "requirement", 11)
+	testutils.ExpectActivity(s.mock, "updated", "requirement", 11)
+	testutils.ExpectSnapshot(s.mock, "drafts", 3)
+	testutils.ExpectActivity(s.mock, "updated", "draft", 3)
+	s.mock.ExpectCommit()
+
+	requirement, err := s.repo.UpdateRequirementDescriptionAndPublishDraft(context.Background(), 11, "Old description", "New description",
+		current, next, types.DraftTransition{Action: "publish", UserID: 20, Comment: "publishing"})
+
+	s.NoError(err)
+	s.Equal("New description", requirement.Description)
+	s.Equal("Renamed", requirement.Name)
+	s.Equal(3, requirement.LevelID)
+}
+
+func (s *RequirementRepositoryTestSuite) TestUpdateRequirementDescriptionAndPublishDraft_DescriptionChangedSinceDraft_ReturnsConflict() {
+	current := types.Draft{ID: 3, StatusID: 62, ApproverID: 20}
+	next := current
+	next.StatusID = 64
+
+	s.mock.ExpectBegin()
+	testutils.ExpectSnapshot(s.mock, "requirement", 11)
+	testutils.ExpectSnapshot(s.mock, "drafts", 3)
+	s.expectLockedRequirement(11, "Edited meanwhile")
+	s.mock.ExpectRollback()
+
+	_, err := s.repo.UpdateRequirementDescriptionAndPublishDraft(context.Background(), 11, "Old description", "New description",
+		current, next, types.DraftTransition{Action: "publish", UserID: 20, Comment: "publishing"})
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (s *RequirementRepositoryTestSuite) TestUpdateRequirementDescriptionAndPublishDraft_DraftChangedConcurrently_RollsBack() {
+	current := types.Draft{ID: 3, StatusID: 62, ApproverID: 20}
+	next := current
+	next.StatusID = 64
+
+	s.mock.ExpectBegin()
+	testutils.ExpectSnapshot(s.mock, "requirement", 11)
+	testutils.ExpectSnapshot(s.mock, "drafts", 3)
+	s.expectLockedRequirement(11, "Old description")
+	s.mock.ExpectExec("UPDATE requirement").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("UPDATE drafts").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	_, err := s.repo.UpdateRequirementDescriptionAndPublishDraft(context.Background(), 11, "Old description", "New description",
+		current, next, types.DraftTransition{Action: "publish", UserID: 20, Comment: "publishing"})
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func TestRequirementRepository(t *testing.T) {
+	suite.Run(t, new(RequirementRepositoryTestSuite))
+}
@@ -0,0 +1,83 @@
+package repositories_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/tests/testutils"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+)
+
+type SessionRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	mock    sqlmock.Sqlmock
+	cleanup func()
+	repo    repositories.SessionRepositoryInterface
+}
+
+func (s *SessionRepositoryTestSuite) SetupTest() {
+	s.db, s.mock, s.cleanup = testutils.SetupTestDB(s.T())
+
+	repo, err := repositories.NewSessionRepository(s.db)
+	s.NoError(err)
+	s.repo = repo
+}
+
+func (s *SessionRepositoryTestSuite) TearDownTest() {
+	s.NoError(s.mock.ExpectationsWereMet())
+	s.cleanup()
+}
+
+func (s *SessionRepositoryTestSuite) TestGetUserBySessionToken() {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	s.mock.ExpectQuery("FROM user_sessions AS s INNER JOIN users AS u ON u.id = s.user_id WHERE s.token_hash = \\? AND s.expires_at > \\? AND u.is_active = TRUE").
+		WithArgs("hash", now).
+		WillReturnRows(sqlmock.NewRows(userRowColumns).AddRow(7, "alice@example.com", "Alice", 2, true, now, now, now))
+
+	user, err := s.repo.GetUserBySessionToken(context.Background(), "hash", now)
+
+	s.NoError(err)
+	s.Equal(7, user.ID)
+	s.Equal(2, user.RoleVal.ID)
+}
+
+func (s *SessionRepositoryTestSuite) TestGetUserBySessionToken_ExpiredOrUnknown() {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	s.mock.ExpectQuery("FROM user_sessions AS s").
+		WithArgs("hash", now).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := s.repo.GetUserBySessionToken(context.Background(), "hash", now)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func (s *SessionRepositoryTestSuite) TestCreateSession() {
+	expiresAt := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+
+	s.mock.ExpectExec("INSERT INTO user_sessions \\(user_id, token_hash, expires_at\\) VALUES").
+		WithArgs(7, "hash", expiresAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.NoError(s.repo.CreateSession(context.Background(), 7, "hash", expiresAt))
+}
+
+func (s *SessionRepositoryTestSuite) TestDeleteByUserIDSessions() {
+	s.mock.ExpectExec("DELETE FROM user_sessions WHERE user_id = \\?").
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	s.NoError(s.repo.DeleteByUserIDSessions(context.Background(), 7))
+}
+
+func TestSessionRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(SessionRepositoryTestSuite))
+}
@@ -0,0 +1,205 @@
+package repositories_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"ISO_Auditing_Tool/tests/testutils"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+)
+
+type StandardRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	mock    sqlmock.Sqlmock
+	cleanup func()
+	repo    repositories.StandardRepositoryInterface
+}
+
+func (s *StandardRepositoryTestSuite) SetupTest() {
+	s.db, s.mock, s.cleanup = testutils.SetupTestDB(s.T())
+
+	repo, err := repositories.NewStandardRepository(s.db)
+	s.NoError(err)
+	s.repo = repo
+}
+
+func (s *StandardRepositoryTestSuite) TearDownTest() {
+	s.NoError(s.mock.ExpectationsWereMet())
+	s.cleanup()
+}
+
+var standardColumns = []string{"id", "name", "description", "version"}
+
+func (s *StandardRepositoryTestSuite) TestGetAllStandards_ReturnsRows() {
+	s.mock.ExpectQuery("SELECT (.+) FROM standards").
+		WillReturnRows(sqlmock.NewRows(standardColumns).
+			AddRow(1, "ISO 9001", "Quality management systems", "2015").
+			AddRow(2, "ISO 27001", nil, "2013"))
+
+	standards, err := s.repo.GetAllStandards(context.Background())
+
+	s.NoError(err)
+	s.Len(standards, 2)
+	s.Equal("ISO 9001", standards[0].Name)
+	s.Equal("", standards[1].Description)
+}
+
+func (s *StandardRepositoryTestSuite) TestGetAllStandards_QueryError_ReturnsError() {
+	s.mock.ExpectQuery("SELECT (.+) FROM standards").WillReturnError(errors.New("connection lost"))
+
+	_, err := s.repo.GetAllStandards(context.Background())
+
+	s.ErrorContains(err, "failed to query standards")
+}
+
+func (s *StandardRepositoryTestSuite) TestGetByIDStandard_NotFound_ReturnsNotFoundCode() {
+	s.mock.ExpectQuery("SELECT (.+) FROM standards WHERE id = ?").
+		WithArgs(42).
+		WillReturnRows(sqlmock.NewRows(standardColumns))
+
+	_, err := s.repo.GetByIDStandard(context.Background(), types.Standard{ID: 42})
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func (s *StandardRepositoryTestSuite) TestGetByIDWithFullHierarchyStandard_BuildsTree() {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	s.mock.ExpectQuery("SELECT (.+) FROM standards WHERE id = ?").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(standardColumns).AddRow(1, "ISO 9001", "QMS", "2015"))
+
+	s.mock.ExpectQuery("SELECT (.+) FROM requirement WHERE standard_id = ?").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "standard_id", "requirement_level_id", "parent_id", "reference_code", "name", "description", "sort_order"}).
+			AddRow(10, 1, 1, nil, "4", "Context of the organization", nil, 1).
+			AddRow(11, 1, 2, 10, "4.1", "Understanding the organization", "Determine issues", 1))
+
+	s.mock.ExpectQuery("SELECT (.+) FROM questions AS q").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "requirement_id", "question", "guidance", "created_at", "updated_at", "sort_order"}).
+			AddRow(100, 11, "What is the scope of the QMS?", nil, now, now, 1))
+
+	s.mock.ExpectQuery("SELECT (.+) FROM evidence AS e").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "question_id", "expected", "created_at", "updated_at", "sort_order",
+			"rv.id", "rv.type_id", "rv.code", "rv.name", "rv.description", "rv.is_active", "rv.created_at", "rv.updated_at"}).
+			AddRow(1000, 100, "Scope statement", now, now, 1, 37, 7, "DOCUMENT", "Document", nil, true, now, now))
+
+	standard, err := s.repo.GetByIDWithFullHierarchyStandard(context.Background(), types.Standard{ID: 1})
+
+	s.NoError(err)
+	s.Len(standard.Requirements, 2)
+	s.Equal(0, standard.Requirements[0].ParentID)
+	s.Empty(standard.Requirements[0].Questions)
+	s.Equal(10, standard.Requirements[1].ParentID)
+	s.Len(standard.Requirements[1].Questions, 1)
+	s.Len(standard.Requirements[1].Questions[0].Evidence, 1)
+	s.Equal("DOCUMENT", standard.Requirements[1].Questions[0].Evidence[0].TypeVal.Code)
+}
+
+func (s *StandardRepositoryTestSuite) TestGetByIDWithFullHierarchyStandard_StandardMissing_StopsEarly() {
+	s.mock.ExpectQuery("SELECT (.+) FROM standards WHERE id = ?").
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows(standardColumns))
+
+	_, err := s.repo.GetByIDWithFullHierarchyStandard(context.Background(), types.Standard{ID: 7})
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func (s *StandardRepositoryTestSuite) TestCreateStandard_ReturnsInsertedID() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec("INSERT INTO standards").
+		WithArgs("ISO 14001", sql.NullString{}, "2015").
+		WillReturnResult(sqlmock.NewResult(5, 1))
+	testutils.ExpectSnapshot(s.mock, "standards", 5)
+	testutils.ExpectActivity(s.mock, "created", "standard", 5)
+	s.mock.ExpectCommit()
+
+	standard, err := s.repo.CreateStandard(context.Background(), types.Standard{Name: "ISO 14001", Version: "2015"})
+
+	s.NoError(err)
+	s.Equal(5, standard.ID)
+}
+
+func (s *StandardRepositoryTestSuite) TestUpdateStandard_NotFound_ReturnsNotFoundCode() {
+	s.mock.ExpectQuery("SELECT (.+) FROM standards WHERE id = ?").
+		WithArgs(9).
+		WillReturnRows(sqlmock.NewRows(standardColumns))
+
+	_, err := s.repo.UpdateStandard(context.Background(), types.Standard{ID: 9, Name: "ISO 9001", Version: "2015"})
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func (s *StandardRepositoryTestSuite) TestDeleteStandard_RemovesLevelsAndStandard() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT (.+) FROM requirement WHERE standard_id = ?").
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"requirements", "audit_plans"}).AddRow(0, 0))
+	s.mock.ExpectQuery("SELECT id FROM requirement_level WHERE standard_id = \\? ORDER BY level_order, id FOR UPDATE").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2).AddRow(3))
+	testutils.ExpectSnapshot(s.mock, "requirement_level", 2)
+	testutils.ExpectSnapshot(s.mock, "requirement_level", 3)
+	testutils.ExpectSnapshot(s.mock, "standards", 1)
+	s.mock.ExpectExec("DELETE FROM requirement_level WHERE standard_id = ?").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	s.mock.ExpectExec("DELETE FROM standards WHERE id = ?").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	testutils.ExpectActivity(s.mock, "deleted", "requirement_level", 2)
+	testutils.ExpectActivity(s.mock, "deleted", "requirement_level", 3)
+	testutils.ExpectActivity(s.mock, "deleted", "standard", 1)
+	s.mock.ExpectCommit()
+
+	s.NoError(s.repo.DeleteStandard(context.Background(), 1))
+}
+
+func (s *StandardRepositoryTestSuite) TestDeleteStandard_WithRequirements_ReturnsConflict() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT (.+) FROM requirement WHERE standard_id = ?").
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"requirements", "audit_plans"}).AddRow(4, 0))
+	s.mock.ExpectRollback()
+
+	err := s.repo.DeleteStandard(context.Background(), 1)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (s *StandardRepositoryTestSuite) TestDeleteStandard_Missing_ReturnsNotFoundCode() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT (.+) FROM requirement WHERE standard_id = ?").
+		WithArgs(8, 8).
+		WillReturnRows(sqlmock.NewRows([]string{"requirements", "audit_plans"}).AddRow(0, 0))
+	s.mock.ExpectQuery("SELECT id FROM requirement_level WHERE standard_id = \\?").
+		WithArgs(8).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	testutils.ExpectMissingSnapshot(s.mock, "standards", 8)
+	s.mock.ExpectExec("DELETE FROM requirement_level WHERE standard_id = ?").
+		WithArgs(8).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec("DELETE FROM standards WHERE id = ?").
+		WithArgs(8).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	err := s.repo.DeleteStandard(context.Background(), 8)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func TestStandardRepository(t *testing.T) {
+	suite.Run(t, new(StandardRepositoryTestSuite))
+}
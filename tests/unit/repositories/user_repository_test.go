@@ -0,0 +1,198 @@
+package repositories_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/repositories"
+	"ISO_Auditing_Tool/pkg/types"
+	"ISO_Auditing_Tool/tests/testutils"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+)
+
+var userRowColumns = []string{"id", "email", "name", "role_id", "is_active", "last_login_at", "created_at", "updated_at"}
+
+type UserRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	mock    sqlmock.Sqlmock
+	cleanup func()
+	repo    repositories.UserRepositoryInterface
+}
+
+func (s *UserRepositoryTestSuite) SetupTest() {
+	s.db, s.mock, s.cleanup = testutils.SetupTestDB(s.T())
+
+	repo, err := repositories.NewUserRepository(s.db)
+	s.NoError(err)
+	s.repo = repo
+}
+
+func (s *UserRepositoryTestSuite) TearDownTest() {
+	s.NoError(s.mock.ExpectationsWereMet())
+	s.cleanup()
+}
+
+func (s *UserRepositoryTestSuite) TestGetAllUsers_ActiveByRole() {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	s.mock.ExpectQuery("FROM users WHERE deleted_at IS NULL AND is_active = TRUE AND role_id = \\? ORDER BY name, id").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows(userRowColumns).
+			AddRow(7, "alice@example.com", "Alice", 2, true, now, now, now).
+			AddRow(8, "bob@example.com", "Bob", 2, true, nil, now, now))
+
+	users, err := s.repo.GetAllUsers(context.Background(), types.UserFilter{RoleID: 2})
+
+	s.NoError(err)
+	s.Len(users, 2)
+	s.Equal(2, users[0].RoleVal.ID)
+	s.NotNil(users[0].LastLoginAt)
+	s.Nil(users[1].LastLoginAt)
+}
+
+func (s *UserRepositoryTestSuite) TestGetByIDUser_NotFound() {
+	s.mock.ExpectQuery("FROM users WHERE id = \\? AND deleted_at IS NULL").
+		WithArgs(7).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := s.repo.GetByIDUser(context.Background(), types.User{ID: 7})
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func (s *UserRepositoryTestSuite) TestCreateUser() {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	s.mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users WHERE LOWER\\(email\\) = LOWER\\(\\?\\)").
+		WithArgs("alice@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec("INSERT INTO users \\(email, name, role_id\\) VALUES").
+		WithArgs("alice@example.com", "Alice", 2).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	// The password hash is left out of the activity log
+	s.mock.ExpectQuery("SELECT \\* FROM users WHERE id = \\? FOR UPDATE").
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password_hash"}).AddRow(7, "alice@example.com", "$2a$10$hash"))
+	s.mock.ExpectExec("INSERT INTO activity_log").
+		WithArgs(sqlmock.AnyArg(), "created", "user", 7, nil, `{"email":"alice@example.com","id":7}`, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectCommit()
+	s.mock.ExpectQuery("FROM users WHERE id = \\? AND deleted_at IS NULL").
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows(userRowColumns).AddRow(7, "alice@example.com", "Alice", 2, true, nil, now, now))
+
+	user, err := s.repo.CreateUser(context.Background(), types.User{Email: "alice@example.com", Name: "Alice", RoleVal: types.ReferenceValue{ID: 2}})
+
+	s.NoError(err)
+	s.Equal(7, user.ID)
+	s.True(user.IsActive)
+}
+
+func (s *UserRepositoryTestSuite) TestCreateUser_DuplicateEmail() {
+	s.mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users WHERE LOWER\\(email\\)").
+		WithArgs("alice@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	_, err := s.repo.CreateUser(context.Background(), types.User{Email: "alice@example.com", Name: "Alice", RoleVal: types.ReferenceValue{ID: 2}})
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (s *UserRepositoryTestSuite) TestReactivateUser_AlreadyActive() {
+	s.mock.ExpectBegin()
+	testutils.ExpectSnapshot(s.mock, "users", 7)
+	s.mock.ExpectExec("UPDATE users SET is_active = TRUE WHERE id = \\? AND is_active = FALSE").
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	err := s.repo.ReactivateUser(context.Background(), 7)
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (s *UserRepositoryTestSuite) TestDeactivateUser_ReassignsOpenFindings() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT is_active FROM users WHERE id = \\? AND deleted_at IS NULL FOR UPDATE").
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"is_active"}).AddRow(true))
+	s.mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users WHERE id = \\? AND is_active = TRUE").
+		WithArgs(8).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	testutils.ExpectSnapshot(s.mock, "users", 7)
+	s.mock.ExpectExec("UPDATE users SET is_active = FALSE WHERE id = \\?").
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	testutils.ExpectSnapshot(s.mock, "users", 7)
+	testutils.ExpectActivity(s.mock, "updated", "user", 7)
+	s.mock.ExpectQuery("SELECT id FROM findings WHERE responsible_user_id = \\? AND status_id IN \\(\\?, \\?\\) (.+) FOR UPDATE").
+		WithArgs(7, 30, 31).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(40).AddRow(41))
+	testutils.ExpectSnapshot(s.mock, "findings", 40)
+	testutils.ExpectSnapshot(s.mock, "findings", 41)
+	s.mock.ExpectExec("UPDATE findings SET responsible_user_id = \\?, needs_owner = FALSE WHERE id IN \\(\\?, \\?\\)").
+		WithArgs(8, 40, 41).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	testutils.ExpectSnapshot(s.mock, "findings", 40)
+	testutils.ExpectActivity(s.mock, "updated", "finding", 40)
+	testutils.ExpectSnapshot(s.mock, "findings", 41)
+	testutils.ExpectActivity(s.mock, "updated", "finding", 41)
+	s.mock.ExpectCommit()
+
+	report, err := s.repo.DeactivateUser(context.Background(), 7, 8, []int{30, 31})
+
+	s.NoError(err)
+	s.Equal([]int{40, 41}, report.Reassigned)
+	s.Empty(report.Flagged)
+}
+
+func (s *UserRepositoryTestSuite) TestDeactivateUser_FlagsOpenFindings() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT is_active FROM users").
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"is_active"}).AddRow(true))
+	testutils.ExpectSnapshot(s.mock, "users", 7)
+	s.mock.ExpectExec("UPDATE users SET is_active = FALSE").
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	testutils.ExpectSnapshot(s.mock, "users", 7)
+	testutils.ExpectActivity(s.mock, "updated", "user", 7)
+	s.mock.ExpectQuery("SELECT id FROM findings").
+		WithArgs(7, 30).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(40))
+	testutils.ExpectSnapshot(s.mock, "findings", 40)
+	s.mock.ExpectExec("UPDATE findings SET needs_owner = TRUE WHERE id IN \\(\\?\\)").
+		WithArgs(40).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	testutils.ExpectSnapshot(s.mock, "findings", 40)
+	testutils.ExpectActivity(s.mock, "updated", "finding", 40)
+	s.mock.ExpectCommit()
+
+	report, err := s.repo.DeactivateUser(context.Background(), 7, 0, []int{30})
+
+	s.NoError(err)
+	s.Equal([]int{40}, report.Flagged)
+	s.Empty(report.Reassigned)
+}
+
+func (s *UserRepositoryTestSuite) TestDeactivateUser_AlreadyInactive() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT is_active FROM users").
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"is_active"}).AddRow(false))
+	s.mock.ExpectRollback()
+
+	_, err := s.repo.DeactivateUser(context.Background(), 7, 0, []int{30})
+
+	s.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func TestUserRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(UserRepositoryTestSuite))
+}
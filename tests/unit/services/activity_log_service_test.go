@@ -0,0 +1,71 @@
+package services_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockActivityLogRepository struct {
+	mock.Mock
+}
+
+func (m *MockActivityLogRepository) GetActivityEntries(ctx context.Context, filter types.ActivityFilter) ([]types.ActivityEntry, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]types.ActivityEntry), args.Error(1)
+}
+
+type ActivityLogServiceSuite struct {
+	suite.Suite
+	mockRepo *MockActivityLogRepository
+	service  *services.ActivityLogService
+}
+
+func (suite *ActivityLogServiceSuite) SetupTest() {
+	suite.mockRepo = new(MockActivityLogRepository)
+	suite.service = services.NewActivityLogService(suite.mockRepo)
+}
+
+func (suite *ActivityLogServiceSuite) TearDownTest() {
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ActivityLogServiceSuite) TestGetAll_DefaultsLimit() {
+	entries := []types.ActivityEntry{{ID: 12, Action: "updated", EntityType: "finding", EntityID: 9}}
+	suite.mockRepo.On("GetActivityEntries", context.Background(), types.ActivityFilter{EntityType: "finding", EntityID: 9, Limit: 100}).Return(entries, nil)
+
+	result, err := suite.service.GetAll(context.Background(), types.ActivityFilter{EntityType: "finding", EntityID: 9})
+
+	suite.NoError(err)
+	suite.Equal(entries, result)
+}
+
+func (suite *ActivityLogServiceSuite) TestGetAll_EntityIDWithoutType_ReturnsInvalidData() {
+	_, err := suite.service.GetAll(context.Background(), types.ActivityFilter{EntityID: 9})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+}
+
+func (suite *ActivityLogServiceSuite) TestGetAll_LimitTooLarge_ReturnsInvalidData() {
+	_, err := suite.service.GetAll(context.Background(), types.ActivityFilter{Limit: 501})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+}
+
+func (suite *ActivityLogServiceSuite) TestGetAll_ToNotAfterFrom_ReturnsInvalidData() {
+	from := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	_, err := suite.service.GetAll(context.Background(), types.ActivityFilter{From: from, To: from})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+}
+
+func TestActivityLogServiceSuite(t *testing.T) {
+	suite.Run(t, new(ActivityLogServiceSuite))
+}
@@ -0,0 +1,176 @@
+package services_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockAPITokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPITokenRepository) GetByUserIDAPITokens(ctx context.Context, userID int) ([]types.APIToken, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]types.APIToken), args.Error(1)
+}
+
+func (m *MockAPITokenRepository) GetByIDAPIToken(ctx context.Context, id int) (types.APIToken, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(types.APIToken), args.Error(1)
+}
+
+func (m *MockAPITokenRepository) GetUserByAPIToken(ctx context.Context, tokenHash string, asOf time.Time) (types.User, types.APIToken, error) {
+	args := m.Called(ctx, tokenHash, asOf)
+	return args.Get(0).(types.User), args.Get(1).(types.APIToken), args.Error(2)
+}
+
+func (m *MockAPITokenRepository) CreateAPIToken(ctx context.Context, token types.APIToken, tokenHash string) (types.APIToken, error) {
+	args := m.Called(ctx, token, tokenHash)
+	return args.Get(0).(types.APIToken), args.Error(1)
+}
+
+func (m *MockAPITokenRepository) RevokeAPIToken(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAPITokenRepository) UpdateAPITokenLastUsed(ctx context.Context, id int, usedAt time.Time) error {
+	args := m.Called(ctx, id, usedAt)
+	return args.Error(0)
+}
+
+type APITokenServiceSuite struct {
+	suite.Suite
+	mockRepo  *MockAPITokenRepository
+	mockUsers *MockUserRepository
+	now       time.Time
+	service   *services.APITokenService
+}
+
+func (suite *APITokenServiceSuite) SetupTest() {
+	suite.mockRepo = new(MockAPITokenRepository)
+	suite.mockUsers = new(MockUserRepository)
+	suite.now = time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	referenceRepo := new(MockReferenceDataRepository)
+	referenceRepo.On("GetAllReferenceTypes", mock.Anything).Return(userReferenceTypes, nil).Maybe()
+	referenceRepo.On("GetAllReferenceValues", mock.Anything).Return(userReferenceValues, nil).Maybe()
+	referenceData := services.NewReferenceDataService(referenceRepo, nil)
+
+	clock := func() time.Time { return suite.now }
+	suite.service = services.NewAPITokenService(suite.mockRepo, suite.mockUsers, referenceData, clock, nil)
+}
+
+func (suite *APITokenServiceSuite) TearDownTest() {
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockUsers.AssertExpectations(suite.T())
+}
+
+func (suite *APITokenServiceSuite) TestCreate_ReturnsSecretOnceAndStoresHash() {
+	expected := types.APIToken{UserID: 7, Name: "ci", Scopes: []string{"read", "write"}, ExpiresAt: suite.now.Add(30 * 24 * time.Hour)}
+	created := expected
+	created.ID = 3
+	suite.mockRepo.On("CreateAPIToken", mock.Anything, expected, mock.AnythingOfType("string")).Return(created, nil)
+
+	token, err := suite.service.Create(context.Background(), 7, types.APITokenForm{Name: " ci ", Scopes: []string{"read", "write", "read"}, ExpiresInDays: 30})
+
+	suite.NoError(err)
+	suite.True(strings.HasPrefix(token.Token, "iat_"))
+	storedHash := suite.mockRepo.Calls[0].Arguments.String(2)
+	suite.Len(storedHash, 64)
+	suite.NotContains(storedHash, token.Token)
+}
+
+func (suite *APITokenServiceSuite) TestCreate_RejectsUnknownScope() {
+	_, err := suite.service.Create(context.Background(), 7, types.APITokenForm{Name: "ci", Scopes: []string{"admin"}, ExpiresInDays: 30})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+}
+
+func (suite *APITokenServiceSuite) TestCreate_RejectsLifetimeOverAYear() {
+	_, err := suite.service.Create(context.Background(), 7, types.APITokenForm{Name: "ci", Scopes: []string{"read"}, ExpiresInDays: 366})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+}
+
+func (suite *APITokenServiceSuite) TestCreate_NotWithAnAPIToken() {
+	ctx := services.WithAPIToken(context.Background(), types.APIToken{ID: 1, Scopes: []string{"write"}})
+
+	_, err := suite.service.Create(ctx, 7, types.APITokenForm{Name: "ci", Scopes: []string{"read"}, ExpiresInDays: 30})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeForbidden))
+	suite.mockRepo.AssertNotCalled(suite.T(), "CreateAPIToken", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *APITokenServiceSuite) TestRevoke_TokenOfAnotherUserIsNotFound() {
+	suite.mockRepo.On("GetByIDAPIToken", mock.Anything, 3).Return(types.APIToken{ID: 3, UserID: 8}, nil)
+
+	_, err := suite.service.Revoke(context.Background(), 7, 3)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+	suite.mockRepo.AssertNotCalled(suite.T(), "RevokeAPIToken", mock.Anything, mock.Anything)
+}
+
+func (suite *APITokenServiceSuite) TestRevoke() {
+	suite.mockRepo.On("GetByIDAPIToken", mock.Anything, 3).Return(types.APIToken{ID: 3, UserID: 7}, nil)
+	suite.mockRepo.On("RevokeAPIToken", mock.Anything, 3).Return(nil)
+
+	token, err := suite.service.Revoke(context.Background(), 7, 3)
+
+	suite.NoError(err)
+	suite.Equal(suite.now, *token.RevokedAt)
+}
+
+func (suite *APITokenServiceSuite) TestAuthenticate_RecordsUseAndHydratesRole() {
+	user := types.User{ID: 7, RoleVal: types.ReferenceValue{ID: 2}, IsActive: true}
+	suite.mockRepo.On("GetUserByAPIToken", mock.Anything, mock.AnythingOfType("string"), suite.now).
+		Return(user, types.APIToken{ID: 3, UserID: 7, Scopes: []string{"read"}}, nil)
+	suite.mockRepo.On("UpdateAPITokenLastUsed", mock.Anything, 3, suite.now).Return(nil)
+
+	result, token, err := suite.service.Authenticate(context.Background(), "iat_secret")
+
+	suite.NoError(err)
+	suite.Equal("LEAD", result.RoleVal.Code)
+	suite.Equal(suite.now, *token.LastUsedAt)
+}
+
+func (suite *APITokenServiceSuite) TestAuthenticate_LastUseFailureDoesNotFailRequest() {
+	user := types.User{ID: 7, RoleVal: types.ReferenceValue{ID: 2}, IsActive: true}
+	suite.mockRepo.On("GetUserByAPIToken", mock.Anything, mock.AnythingOfType("string"), suite.now).
+		Return(user, types.APIToken{ID: 3, UserID: 7}, nil)
+	suite.mockRepo.On("UpdateAPITokenLastUsed", mock.Anything, 3, suite.now).Return(errors.New("lock wait timeout"))
+
+	_, _, err := suite.service.Authenticate(context.Background(), "iat_secret")
+
+	suite.NoError(err)
+}
+
+func (suite *APITokenServiceSuite) TestAuthenticate_RevokedOrExpired() {
+	suite.mockRepo.On("GetUserByAPIToken", mock.Anything, mock.AnythingOfType("string"), suite.now).
+		Return(types.User{}, types.APIToken{}, custom_errors.NotFound(context.Background(), "API token"))
+
+	_, _, err := suite.service.Authenticate(context.Background(), "iat_secret")
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeUnauthorized))
+}
+
+func (suite *APITokenServiceSuite) TestCheckAPITokenScope() {
+	readOnly := services.WithAPIToken(context.Background(), types.APIToken{Scopes: []string{"read"}})
+
+	suite.NoError(services.CheckAPITokenScope(readOnly, "GET"))
+	suite.True(custom_errors.IsErrorCode(services.CheckAPITokenScope(readOnly, "POST"), custom_errors.ErrCodeForbidden))
+	suite.NoError(services.CheckAPITokenScope(context.Background(), "POST"))
+}
+
+func TestAPITokenServiceSuite(t *testing.T) {
+	suite.Run(t, new(APITokenServiceSuite))
+}
@@ -0,0 +1,166 @@
+package services_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockAuditAssignmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditAssignmentRepository) GetByAuditPlanIDAuditAssignments(ctx context.Context, auditPlanID int) ([]types.AuditAssignment, error) {
+	args := m.Called(ctx, auditPlanID)
+	return args.Get(0).([]types.AuditAssignment), args.Error(1)
+}
+
+func (m *MockAuditAssignmentRepository) GetByUserIDAuditAssignments(ctx context.Context, userID int) ([]types.AuditAssignment, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]types.AuditAssignment), args.Error(1)
+}
+
+func (m *MockAuditAssignmentRepository) CreateAuditAssignment(ctx context.Context, auditPlanID, userID int) error {
+	args := m.Called(ctx, auditPlanID, userID)
+	return args.Error(0)
+}
+
+func (m *MockAuditAssignmentRepository) DeleteAuditAssignment(ctx context.Context, auditPlanID, userID int) error {
+	args := m.Called(ctx, auditPlanID, userID)
+	return args.Error(0)
+}
+
+func (m *MockAuditAssignmentRepository) IsAuditTeamMember(ctx context.Context, auditPlanID, userID int) (bool, error) {
+	args := m.Called(ctx, auditPlanID, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAuditAssignmentRepository) IsAuditFollowUpOwner(ctx context.Context, auditPlanID, userID int) (bool, error) {
+	args := m.Called(ctx, auditPlanID, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockAuditAssignmentService struct {
+	mock.Mock
+}
+
+func (m *MockAuditAssignmentService) GetByAuditPlanID(ctx context.Context, auditPlanID int) ([]types.AuditAssignment, error) {
+	args := m.Called(ctx, auditPlanID)
+	return args.Get(0).([]types.AuditAssignment), args.Error(1)
+}
+
+func (m *MockAuditAssignmentService) GetByUserID(ctx context.Context, userID int) ([]types.AuditAssignment, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]types.AuditAssignment), args.Error(1)
+}
+
+func (m *MockAuditAssignmentService) Assign(ctx context.Context, auditPlanID, userID int) ([]types.AuditAssignment, error) {
+	args := m.Called(ctx, auditPlanID, userID)
+	return args.Get(0).([]types.AuditAssignment), args.Error(1)
+}
+
+func (m *MockAuditAssignmentService) Unassign(ctx context.Context, auditPlanID, userID int) error {
+	args := m.Called(ctx, auditPlanID, userID)
+	return args.Error(0)
+}
+
+func (m *MockAuditAssignmentService) CheckTeamMember(ctx context.Context, auditPlanID, userID int) error {
+	args := m.Called(ctx, auditPlanID, userID)
+	return args.Error(0)
+}
+
+type AuditAssignmentServiceSuite struct {
+	suite.Suite
+	mockRepo       *MockAuditAssignmentRepository
+	mockAuditPlans *MockAuditPlanService
+	service        *services.AuditAssignmentService
+}
+
+func (suite *AuditAssignmentServiceSuite) SetupTest() {
+	suite.mockRepo = new(MockAuditAssignmentRepository)
+	suite.mockAuditPlans = new(MockAuditPlanService)
+
+	referenceRepo := new(MockReferenceDataRepository)
+	referenceRepo.On("GetAllReferenceTypes", mock.Anything).Return(auditPlanReferenceTypes, nil).Maybe()
+	referenceRepo.On("GetAllReferenceValues", mock.Anything).Return(auditPlanReferenceValues, nil).Maybe()
+	referenceData := services.NewReferenceDataService(referenceRepo, nil)
+
+	suite.service = services.NewAuditAssignmentService(suite.mockRepo, suite.mockAuditPlans, referenceData, nil)
+}
+
+func (suite *AuditAssignmentServiceSuite) TearDownTest() {
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockAuditPlans.AssertExpectations(suite.T())
+}
+
+func (suite *AuditAssignmentServiceSuite) expectPlan(statusCode string) {
+	plan := types.AuditPlan{ID: 2, StandardID: 1, StatusVal: types.ReferenceValue{ID: 8, Code: statusCode}}
+	suite.mockAuditPlans.On("GetByID", mock.Anything, types.AuditPlan{ID: 2}).Return(plan, nil)
+}
+
+func (suite *AuditAssignmentServiceSuite) TestAssign_ReturnsTeam() {
+	ctx := context.Background()
+	suite.expectPlan("SCHEDULED")
+
+	team := []types.AuditAssignment{
+		{AuditPlanID: 2, UserID: 1, Role: types.AuditRoleLead},
+		{AuditPlanID: 2, UserID: 3, Role: types.AuditRoleSupport},
+	}
+	suite.mockRepo.On("CreateAuditAssignment", ctx, 2, 3).Return(nil)
+	suite.mockRepo.On("GetByAuditPlanIDAuditAssignments", ctx, 2).Return(team, nil)
+
+	result, err := suite.service.Assign(ctx, 2, 3)
+
+	suite.NoError(err)
+	suite.Len(result, 2)
+	suite.Equal("SCHEDULED", result[1].StatusVal.Code)
+}
+
+func (suite *AuditAssignmentServiceSuite) TestAssign_ClosedPlan_ReturnsConflict() {
+	suite.expectPlan("CANCELLED")
+
+	_, err := suite.service.Assign(context.Background(), 2, 3)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+	suite.mockRepo.AssertNotCalled(suite.T(), "CreateAuditAssignment", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AuditAssignmentServiceSuite) TestUnassign_NotAssigned_ReturnsNotFound() {
+	ctx := context.Background()
+	suite.expectPlan("IN_PROGRESS")
+	suite.mockRepo.On("DeleteAuditAssignment", ctx, 2, 3).Return(custom_errors.NotFound(ctx, "Audit assignment"))
+
+	err := suite.service.Unassign(ctx, 2, 3)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func (suite *AuditAssignmentServiceSuite) TestGetByUserID_HydratesStatus() {
+	ctx := context.Background()
+	suite.mockRepo.On("GetByUserIDAuditAssignments", ctx, 3).Return([]types.AuditAssignment{
+		{AuditPlanID: 2, UserID: 3, Role: types.AuditRoleSupport, StatusVal: types.ReferenceValue{ID: 8}},
+	}, nil)
+
+	assignments, err := suite.service.GetByUserID(ctx, 3)
+
+	suite.NoError(err)
+	suite.Equal("IN_PROGRESS", assignments[0].StatusVal.Code)
+}
+
+func (suite *AuditAssignmentServiceSuite) TestCheckTeamMember() {
+	ctx := context.Background()
+	suite.mockRepo.On("IsAuditTeamMember", ctx, 2, 1).Return(true, nil)
+	suite.mockRepo.On("IsAuditTeamMember", ctx, 2, 4).Return(false, nil)
+
+	suite.NoError(suite.service.CheckTeamMember(ctx, 2, 1))
+	suite.True(custom_errors.IsErrorCode(suite.service.CheckTeamMember(ctx, 2, 4), custom_errors.ErrCodeForbidden))
+}
+
+func TestAuditAssignmentServiceSuite(t *testing.T) {
+	suite.Run(t, new(AuditAssignmentServiceSuite))
+}
@@ -0,0 +1,164 @@
+package services_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockAuditChecklistRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditChecklistRepository) GetAuditChecklist(ctx context.Context, auditPlanID int) (types.AuditChecklist, error) {
+	args := m.Called(ctx, auditPlanID)
+	return args.Get(0).(types.AuditChecklist), args.Error(1)
+}
+
+func (m *MockAuditChecklistRepository) SyncAuditChecklist(ctx context.Context, auditPlanID int, scopeIDs, requirementIDs []int) (types.AuditChecklistDiff, error) {
+	args := m.Called(ctx, auditPlanID, scopeIDs, requirementIDs)
+	return args.Get(0).(types.AuditChecklistDiff), args.Error(1)
+}
+
+type MockAuditPlanService struct {
+	mock.Mock
+}
+
+func (m *MockAuditPlanService) GetAll(ctx context.Context, filter types.AuditPlanFilter) ([]types.AuditPlan, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]types.AuditPlan), args.Error(1)
+}
+
+func (m *MockAuditPlanService) GetByID(ctx context.Context, plan types.AuditPlan) (types.AuditPlan, error) {
+	args := m.Called(ctx, plan)
+	return args.Get(0).(types.AuditPlan), args.Error(1)
+}
+
+func (m *MockAuditPlanService) Create(ctx context.Context, plan types.AuditPlan) (types.AuditPlan, error) {
+	args := m.Called(ctx, plan)
+	return args.Get(0).(types.AuditPlan), args.Error(1)
+}
+
+func (m *MockAuditPlanService) Update(ctx context.Context, plan types.AuditPlan) (types.AuditPlan, error) {
+	args := m.Called(ctx, plan)
+	return args.Get(0).(types.AuditPlan), args.Error(1)
+}
+
+func (m *MockAuditPlanService) ChangeStatus(ctx context.Context, plan types.AuditPlan, statusCode string) (types.AuditPlan, error) {
+	args := m.Called(ctx, plan, statusCode)
+	return args.Get(0).(types.AuditPlan), args.Error(1)
+}
+
+func (m *MockAuditPlanService) Delete(ctx context.Context, plan types.AuditPlan) error {
+	args := m.Called(ctx, plan)
+	return args.Error(0)
+}
+
+// checklistRequirements is a small tree of standard 1:
+//
+//	4 (1)        5 (2)
+//	├── 6 (1.1)  └── 9 (2.1)
+//	│   └── 8 (1.1.1)
+//	└── 7 (1.2)
+var checklistRequirements = []types.Requirement{
+	{ID: 4, StandardID: 1, ReferenceCode: "1", SortOrder: 1},
+	{ID: 5, StandardID: 1, ReferenceCode: "2", SortOrder: 2},
+	{ID: 6, StandardID: 1, ParentID: 4, ReferenceCode: "1.1", SortOrder: 1},
+	{ID: 7, StandardID: 1, ParentID: 4, ReferenceCode: "1.2", SortOrder: 2},
+	{ID: 8, StandardID: 1, ParentID: 6, ReferenceCode: "1.1.1", SortOrder: 1},
+	{ID: 9, StandardID: 1, ParentID: 5, ReferenceCode: "2.1", SortOrder: 1},
+}
+
+type AuditChecklistServiceSuite struct {
+	suite.Suite
+	mockRepo            *MockAuditChecklistRepository
+	mockRequirementRepo *MockRequirementRepository
+	mockAuditPlans      *MockAuditPlanService
+	service             *services.AuditChecklistService
+}
+
+func (suite *AuditChecklistServiceSuite) SetupTest() {
+	suite.mockRepo = new(MockAuditChecklistRepository)
+	suite.mockRequirementRepo = new(MockRequirementRepository)
+	suite.mockAuditPlans = new(MockAuditPlanService)
+	suite.service = services.NewAuditChecklistService(suite.mockRepo, suite.mockRequirementRepo, suite.mockAuditPlans, nil)
+}
+
+func (suite *AuditChecklistServiceSuite) TearDownTest() {
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockRequirementRepo.AssertExpectations(suite.T())
+	suite.mockAuditPlans.AssertExpectations(suite.T())
+}
+
+func (suite *AuditChecklistServiceSuite) expectPlan(statusCode string) {
+	plan := types.AuditPlan{ID: 2, StandardID: 1, StatusVal: types.ReferenceValue{Code: statusCode}}
+	suite.mockAuditPlans.On("GetByID", mock.Anything, types.AuditPlan{ID: 2}).Return(plan, nil)
+}
+
+func (suite *AuditChecklistServiceSuite) TestGenerate_ExpandsSubtrees() {
+	ctx := context.Background()
+	suite.expectPlan("DRAFT")
+	suite.mockRequirementRepo.On("GetByStandardIDRequirements", ctx, 1).Return(checklistRequirements, nil)
+
+	// 6 sits below 4 so selecting both expands to the same subtree, in tree order
+	diff := types.AuditChecklistDiff{Added: 5}
+	suite.mockRepo.On("SyncAuditChecklist", ctx, 2, []int{6, 4, 9}, []int{4, 6, 8, 7, 9}).Return(diff, nil)
+	suite.mockRepo.On("GetAuditChecklist", ctx, 2).Return(types.AuditChecklist{AuditPlanID: 2}, nil)
+
+	checklist, err := suite.service.Generate(ctx, 2, []int{6, 4, 9, 6})
+
+	suite.NoError(err)
+	suite.Equal(&diff, checklist.Changes)
+}
+
+func (suite *AuditChecklistServiceSuite) TestGenerate_RequirementOfOtherStandard_ReturnsInvalidData() {
+	ctx := context.Background()
+	suite.expectPlan("SCHEDULED")
+	suite.mockRequirementRepo.On("GetByStandardIDRequirements", ctx, 1).Return(checklistRequirements, nil)
+
+	_, err := suite.service.Generate(ctx, 2, []int{4, 42})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+	suite.mockRepo.AssertNotCalled(suite.T(), "SyncAuditChecklist", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AuditChecklistServiceSuite) TestGenerate_ClosedPlan_ReturnsConflict() {
+	suite.expectPlan("COMPLETED")
+
+	_, err := suite.service.Generate(context.Background(), 2, []int{4})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (suite *AuditChecklistServiceSuite) TestGetByAuditPlanID_SortsByRequirementTree() {
+	ctx := context.Background()
+	suite.expectPlan("IN_PROGRESS")
+	suite.mockRequirementRepo.On("GetByStandardIDRequirements", ctx, 1).Return(checklistRequirements, nil)
+	suite.mockRepo.On("GetAuditChecklist", ctx, 2).Return(types.AuditChecklist{
+		AuditPlanID: 2,
+		Questions: []types.AuditQuestion{
+			{ID: 1, RequirementID: 7},
+			{ID: 2, RequirementID: 8},
+			{ID: 3, RequirementID: 8},
+			{ID: 4, RequirementID: 4},
+		},
+	}, nil)
+
+	checklist, err := suite.service.GetByAuditPlanID(ctx, 2)
+
+	suite.NoError(err)
+	ids := []int{}
+	for _, question := range checklist.Questions {
+		ids = append(ids, question.ID)
+	}
+	suite.Equal([]int{4, 2, 3, 1}, ids)
+}
+
+func TestAuditChecklistServiceSuite(t *testing.T) {
+	suite.Run(t, new(AuditChecklistServiceSuite))
+}
@@ -5,9 +5,11 @@ import (
 	"ISO_Auditing_Tool/pkg/events"
 	"ISO_Auditing_Tool/pkg/services"
 	"ISO_Auditing_Tool/pkg/types"
+	"ISO_Auditing_Tool/tests/unit/repositories/mocks"
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -26,8 +28,8 @@ func (m *MockRequirementRepository) GetByIDWithQuestionsRequirement(ctx context.
 	return args.Get(0).(types.Requirement), args.Error(1)
 }
 
-func (m *MockRequirementRepository) UpdateRequirementAndDeleteDraft(ctx context.Context, requirement types.Requirement, draft types.Draft) (types.Requirement, error) {
-	args := m.Called(ctx, requirement, draft)
+func (m *MockRequirementRepository) UpdateRequirementDescriptionAndPublishDraft(ctx context.Context, requirementID int, from, to string, current, next types.Draft, transition types.DraftTransition) (types.Requirement, error) {
+	args := m.Called(ctx, requirementID, from, to, current, next, transition)
 	return args.Get(0).(types.Requirement), args.Error(1)
 }
 
@@ -45,6 +47,31 @@ func (m *MockQuestionRepository) GetByIDWithEvidenceQuestion(ctx context.Context
 	return args.Get(0).(types.Question), args.Error(1)
 }
 
+func (m *MockQuestionRepository) GetByRequirementIDQuestions(ctx context.Context, requirementID int) ([]types.Question, error) {
+	args := m.Called(ctx, requirementID)
+	return args.Get(0).([]types.Question), args.Error(1)
+}
+
+func (m *MockQuestionRepository) CreateQuestion(ctx context.Context, question types.Question) (types.Question, error) {
+	args := m.Called(ctx, question)
+	return args.Get(0).(types.Question), args.Error(1)
+}
+
+func (m *MockQuestionRepository) UpdateQuestion(ctx context.Context, question types.Question) (types.Question, error) {
+	args := m.Called(ctx, question)
+	return args.Get(0).(types.Question), args.Error(1)
+}
+
+func (m *MockQuestionRepository) DeleteQuestion(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockQuestionRepository) ReorderQuestions(ctx context.Context, requirementID int, questionIDs []int) error {
+	args := m.Called(ctx, requirementID, questionIDs)
+	return args.Error(0)
+}
+
 type MockEvidenceRepository struct {
 	mock.Mock
 }
@@ -54,6 +81,31 @@ func (m *MockEvidenceRepository) GetByIDEvidence(ctx context.Context, evidence t
 	return args.Get(0).(types.Evidence), args.Error(1)
 }
 
+func (m *MockEvidenceRepository) GetEvidenceType(ctx context.Context, typeID int) (types.ReferenceValue, error) {
+	args := m.Called(ctx, typeID)
+	return args.Get(0).(types.ReferenceValue), args.Error(1)
+}
+
+func (m *MockEvidenceRepository) CreateEvidence(ctx context.Context, evidence types.Evidence) (types.Evidence, error) {
+	args := m.Called(ctx, evidence)
+	return args.Get(0).(types.Evidence), args.Error(1)
+}
+
+func (m *MockEvidenceRepository) UpdateEvidence(ctx context.Context, evidence types.Evidence) (types.Evidence, error) {
+	args := m.Called(ctx, evidence)
+	return args.Get(0).(types.Evidence), args.Error(1)
+}
+
+func (m *MockEvidenceRepository) DeleteEvidence(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockEvidenceRepository) ReorderEvidence(ctx context.Context, questionID int, evidenceIDs []int) error {
+	args := m.Called(ctx, questionID, evidenceIDs)
+	return args.Error(0)
+}
+
 // Mock for DraftService (concrete type, not interface)
 type MockDraftService struct {
 	mock.Mock
@@ -250,6 +302,83 @@ func (suite *AuditContentServiceErrorSuite) TestGetRequirement_WhenRequirementNo
 	assert.Contains(suite.T(), err.Error(), "requirement not found")
 }
 
+func TestModifyRequirementDescription_SubmitsDraftForApproval(t *testing.T) {
+	mockDraftRepo := new(mocks.MockDraftRepository)
+	mockRequirementRepo := new(MockRequirementRepository)
+	mockReferenceRepo := new(MockReferenceDataRepository)
+	eventBus := events.NewEventBus()
+	referenceData := services.NewReferenceDataService(mockReferenceRepo, eventBus)
+
+	service := services.NewAuditContentService(
+		services.NewDraftService(mockDraftRepo, referenceData, nil),
+		mockRequirementRepo,
+		new(MockQuestionRepository),
+		new(MockEvidenceRepository),
+		referenceData,
+		eventBus,
+	)
+
+	requirement := createTestRequirement()
+	mockReferenceRepo.On("GetAllReferenceTypes", mock.Anything).Return(seededReferenceTypes, nil)
+	mockReferenceRepo.On("GetAllReferenceValues", mock.Anything).Return(seededReferenceValues, nil)
+	mockRequirementRepo.On("GetByIDRequirement", mock.Anything, types.Requirement{ID: 1}).Return(requirement, nil)
+	mockDraftRepo.On("CreateDraft", mock.Anything, mock.MatchedBy(func(d types.Draft) bool {
+		return d.TypeID == 65 && d.StatusID == 61 && d.UserID == 10
+	})).Return(types.Draft{ID: 3, TypeID: 65, StatusID: 61, UserID: 10}, nil)
+
+	created := types.Draft{ID: 3, TypeID: 65, StatusID: 61, UserID: 10}
+	submitted := created
+	submitted.StatusID = 62
+	mockDraftRepo.On("GetDraftByID", mock.Anything, types.Draft{ID: 3}).Return(created, nil)
+	mockDraftRepo.On("TransitionDraft", mock.Anything, created, submitted, types.DraftTransition{Action: services.DraftActionSubmit, UserID: 10, Comment: "Clarified"}).Return(nil)
+	mockDraftRepo.On("GetDraftByID", mock.Anything, created).Return(submitted, nil)
+	mockDraftRepo.On("GetDraftTransitions", mock.Anything, 3).Return([]types.DraftTransition{}, nil)
+
+	err := service.ModifyRequirementDescription(context.Background(), 1, "New description", "Clarified", 10)
+
+	assert.NoError(t, err)
+	mockDraftRepo.AssertExpectations(t)
+	mockRequirementRepo.AssertExpectations(t)
+	mockRequirementRepo.AssertNotCalled(t, "UpdateRequirementDescriptionAndPublishDraft", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPublish_AppliesRequirementChangeOfAuditContentDraft(t *testing.T) {
+	mockDraftRepo := new(mocks.MockDraftRepository)
+	mockRequirementRepo := new(MockRequirementRepository)
+	mockReferenceRepo := new(MockReferenceDataRepository)
+	eventBus := events.NewEventBus()
+	referenceData := services.NewReferenceDataService(mockReferenceRepo, eventBus)
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	draftService := services.NewDraftService(mockDraftRepo, referenceData, func() time.Time { return now })
+	auditContent := services.NewAuditContentService(draftService, mockRequirementRepo, new(MockQuestionRepository), new(MockEvidenceRepository), referenceData, eventBus)
+	draftService.RegisterPublisher(services.DraftTypeAuditContent, auditContent)
+
+	mockReferenceRepo.On("GetAllReferenceTypes", mock.Anything).Return(seededReferenceTypes, nil)
+	mockReferenceRepo.On("GetAllReferenceValues", mock.Anything).Return(seededReferenceValues, nil)
+
+	approved := types.Draft{
+		ID: 3, TypeID: 65, ObjectID: 1, StatusID: 62, UserID: 10, ApproverID: 20, ApprovalComment: "looks good",
+		Data: []byte(`{"content_type": "requirement", "modified_content": {"id": 1, "standard_id": 1, "level_id": 2, "reference_code": "4.1", "name": "Context", "description": "New description", "original_description": "Old description"}}`),
+	}
+	published := approved
+	published.StatusID = 64
+	published.PublishedAt = &now
+	transition := types.DraftTransition{Action: services.DraftActionPublish, UserID: 20, Comment: "publishing"}
+
+	mockDraftRepo.On("GetDraftByID", mock.Anything, types.Draft{ID: 3}).Return(approved, nil)
+	mockRequirementRepo.On("UpdateRequirementDescriptionAndPublishDraft", mock.Anything, 1, "Old description", "New description", approved, published, transition).Return(types.Requirement{ID: 1, StandardID: 1, Description: "New description"}, nil)
+	mockDraftRepo.On("GetDraftByID", mock.Anything, approved).Return(published, nil)
+	mockDraftRepo.On("GetDraftTransitions", mock.Anything, 3).Return([]types.DraftTransition{}, nil)
+
+	draft, err := draftService.Publish(context.Background(), 3, types.DraftTransitionForm{Comment: "publishing", UserID: 20})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 64, draft.StatusID)
+	mockRequirementRepo.AssertExpectations(t)
+	mockDraftRepo.AssertNotCalled(t, "TransitionDraft", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 // --- Benchmark Tests for Performance ---
 
 func BenchmarkGetRequirement(b *testing.B) {
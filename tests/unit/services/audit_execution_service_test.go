@@ -0,0 +1,482 @@
+package services_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockAuditQuestionRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditQuestionRepository) GetByAuditIDAuditQuestions(ctx context.Context, auditID int) ([]types.AuditQuestion, error) {
+	args := m.Called(ctx, auditID)
+	return args.Get(0).([]types.AuditQuestion), args.Error(1)
+}
+
+func (m *MockAuditQuestionRepository) GetByIDAuditQuestion(ctx context.Context, question types.AuditQuestion) (types.AuditQuestion, error) {
+	args := m.Called(ctx, question)
+	return args.Get(0).(types.AuditQuestion), args.Error(1)
+}
+
+func (m *MockAuditQuestionRepository) GetAuditProgress(ctx context.Context, auditID, acceptedStatusID int) (types.AuditProgress, error) {
+	args := m.Called(ctx, auditID, acceptedStatusID)
+	return args.Get(0).(types.AuditProgress), args.Error(1)
+}
+
+type MockEvidenceProvidedRepository struct {
+	mock.Mock
+}
+
+func (m *MockEvidenceProvidedRepository) GetByIDEvidenceProvided(ctx context.Context, provided types.EvidenceProvided) (types.EvidenceProvided, error) {
+	args := m.Called(ctx, provided)
+	return args.Get(0).(types.EvidenceProvided), args.Error(1)
+}
+
+func (m *MockEvidenceProvidedRepository) CreateEvidenceProvided(ctx context.Context, provided types.EvidenceProvided) (types.EvidenceProvided, error) {
+	args := m.Called(ctx, provided)
+	return args.Get(0).(types.EvidenceProvided), args.Error(1)
+}
+
+func (m *MockEvidenceProvidedRepository) UpdateEvidenceProvided(ctx context.Context, provided types.EvidenceProvided) (types.EvidenceProvided, error) {
+	args := m.Called(ctx, provided)
+	return args.Get(0).(types.EvidenceProvided), args.Error(1)
+}
+
+func (m *MockEvidenceProvidedRepository) UpdateEvidenceProvidedFile(ctx context.Context, id int, file types.EvidenceFile) error {
+	args := m.Called(ctx, id, file)
+	return args.Error(0)
+}
+
+func (m *MockEvidenceProvidedRepository) GetExpiredEvidenceProvided(ctx context.Context, asOf time.Time) ([]types.EvidenceProvided, error) {
+	args := m.Called(ctx, asOf)
+	return args.Get(0).([]types.EvidenceProvided), args.Error(1)
+}
+
+func (m *MockEvidenceProvidedRepository) UpdateEvidenceProvidedStatus(ctx context.Context, id, fromStatusID, toStatusID int) error {
+	args := m.Called(ctx, id, fromStatusID, toStatusID)
+	return args.Error(0)
+}
+
+func (m *MockEvidenceProvidedRepository) ReviewEvidenceProvided(ctx context.Context, id, fromStatusID, toStatusID int, review types.EvidenceReview) error {
+	args := m.Called(ctx, id, fromStatusID, toStatusID, review)
+	return args.Error(0)
+}
+
+func (m *MockEvidenceProvidedRepository) SupersedeEvidenceProvided(ctx context.Context, replaced, replacement types.EvidenceProvided, supersededStatusID int) (types.EvidenceProvided, error) {
+	args := m.Called(ctx, replaced, replacement, supersededStatusID)
+	return args.Get(0).(types.EvidenceProvided), args.Error(1)
+}
+
+func (m *MockEvidenceProvidedRepository) PurgeEvidenceProvided(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockEvidenceProvidedRepository) DeleteEvidenceProvided(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// Seeded evidence_provided reference data
+var (
+	providedReferenceTypes = []types.ReferenceType{
+		{ID: 8, Name: "evidence_provided.type_id"},
+		{ID: 9, Name: "evidence_provided.confidentiality_id"},
+		{ID: 10, Name: "evidence_provided.status_id"},
+	}
+	providedReferenceValues = []types.ReferenceValue{
+		{ID: 42, TypeID: 8, Code: "FILE", IsActive: true},
+		{ID: 45, TypeID: 8, Code: "TEXT", IsActive: true},
+		{ID: 46, TypeID: 8, Code: "IMAGE", IsActive: true},
+		{ID: 48, TypeID: 9, Code: "PUBLIC", IsActive: true},
+		{ID: 50, TypeID: 9, Code: "CONFIDENTIAL", IsActive: true},
+		{ID: 53, TypeID: 10, Code: "PENDING", IsActive: true},
+		{ID: 54, TypeID: 10, Code: "ACCEPTED", IsActive: true},
+		{ID: 55, TypeID: 10, Code: "REJECTED", IsActive: true},
+		{ID: 57, TypeID: 10, Code: "EXPIRED", IsActive: true},
+		{ID: 58, TypeID: 10, Code: "SUPERSEDED", IsActive: true},
+	}
+)
+
+type AuditExecutionServiceSuite struct {
+	suite.Suite
+	mockRepo            *MockAuditQuestionRepository
+	mockProvidedRepo    *MockEvidenceProvidedRepository
+	mockRequirementRepo *MockRequirementRepository
+	mockAuditPlans      *MockAuditPlanService
+	mockAssignments     *MockAuditAssignmentService
+	mockAccess          *MockEvidenceAccessService
+	service             *services.AuditExecutionService
+}
+
+func (suite *AuditExecutionServiceSuite) SetupTest() {
+	suite.mockRepo = new(MockAuditQuestionRepository)
+	suite.mockProvidedRepo = new(MockEvidenceProvidedRepository)
+	suite.mockRequirementRepo = new(MockRequirementRepository)
+	suite.mockAuditPlans = new(MockAuditPlanService)
+	suite.mockAssignments = new(MockAuditAssignmentService)
+	suite.mockAccess = new(MockEvidenceAccessService)
+
+	referenceRepo := new(MockReferenceDataRepository)
+	referenceRepo.On("GetAllReferenceTypes", mock.Anything).Return(providedReferenceTypes, nil)
+	referenceRepo.On("GetAllReferenceValues", mock.Anything).Return(providedReferenceValues, nil)
+	referenceData := services.NewReferenceDataService(referenceRepo, nil)
+
+	suite.service = services.NewAuditExecutionService(
+		suite.mockRepo, suite.mockProvidedRepo, suite.mockRequirementRepo, suite.mockAuditPlans, suite.mockAssignments, suite.mockAccess, referenceData, nil,
+	)
+}
+
+func (suite *AuditExecutionServiceSuite) TearDownTest() {
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockProvidedRepo.AssertExpectations(suite.T())
+	suite.mockAuditPlans.AssertExpectations(suite.T())
+	suite.mockAssignments.AssertExpectations(suite.T())
+	suite.mockAccess.AssertExpectations(suite.T())
+}
+
+func (suite *AuditExecutionServiceSuite) expectQuestion() {
+	question := types.AuditQuestion{ID: 100, AuditID: 2, QuestionID: 10, Evidence: []types.Evidence{{ID: 20, QuestionID: 10}}}
+	suite.mockRepo.On("GetByIDAuditQuestion", mock.Anything, types.AuditQuestion{ID: 100}).Return(question, nil)
+}
+
+func (suite *AuditExecutionServiceSuite) expectPlan(statusCode string) {
+	plan := types.AuditPlan{ID: 2, StandardID: 1, StatusVal: types.ReferenceValue{Code: statusCode}}
+	suite.mockAuditPlans.On("GetByID", mock.Anything, types.AuditPlan{ID: 2}).Return(plan, nil)
+}
+
+func providedForm() types.EvidenceProvided {
+	return types.EvidenceProvided{
+		AuditQuestionID:    100,
+		EvidenceID:         20,
+		UserID:             3,
+		Provided:           "QM-001 rev 4",
+		TypeVal:            types.ReferenceValue{ID: 42},
+		ConfidentialityVal: types.ReferenceValue{ID: 50},
+		RetentionDays:      365,
+	}
+}
+
+func (suite *AuditExecutionServiceSuite) TestProvideEvidence_StartsPending() {
+	ctx := context.Background()
+	suite.expectQuestion()
+	suite.expectPlan("IN_PROGRESS")
+	suite.mockAssignments.On("CheckTeamMember", ctx, 2, 3).Return(nil)
+
+	stored := providedForm()
+	stored.ID = 7
+	stored.StatusVal = types.ReferenceValue{ID: 53}
+	suite.mockProvidedRepo.On("CreateEvidenceProvided", ctx, mock.MatchedBy(func(p types.EvidenceProvided) bool {
+		return p.StatusVal.ID == 53 && p.AuditQuestionID == 100
+	})).Return(stored, nil)
+
+	created, err := suite.service.ProvideEvidence(ctx, providedForm())
+
+	suite.NoError(err)
+	suite.Equal("PENDING", created.StatusVal.Code)
+	suite.Equal("CONFIDENTIAL", created.ConfidentialityVal.Code)
+	suite.Equal("FILE", created.TypeVal.Code)
+}
+
+func (suite *AuditExecutionServiceSuite) TestProvideEvidence_AuditNotInProgress_ReturnsConflict() {
+	suite.expectQuestion()
+	suite.expectPlan("SCHEDULED")
+
+	_, err := suite.service.ProvideEvidence(context.Background(), providedForm())
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+	suite.mockProvidedRepo.AssertNotCalled(suite.T(), "CreateEvidenceProvided", mock.Anything, mock.Anything)
+}
+
+func (suite *AuditExecutionServiceSuite) TestProvideEvidence_NotOnTeam_ReturnsForbidden() {
+	suite.expectQuestion()
+	suite.expectPlan("IN_PROGRESS")
+	suite.mockAssignments.On("CheckTeamMember", mock.Anything, 2, 3).
+		Return(custom_errors.Forbidden(context.Background(), "user 3 is not on the team of audit plan 2"))
+
+	_, err := suite.service.ProvideEvidence(context.Background(), providedForm())
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeForbidden))
+	suite.mockProvidedRepo.AssertNotCalled(suite.T(), "CreateEvidenceProvided", mock.Anything, mock.Anything)
+}
+
+func (suite *AuditExecutionServiceSuite) TestProvideEvidence_UnexpectedEvidence_ReturnsInvalidData() {
+	suite.expectQuestion()
+	suite.expectPlan("IN_PROGRESS")
+	suite.mockAssignments.On("CheckTeamMember", mock.Anything, 2, 3).Return(nil)
+
+	provided := providedForm()
+	provided.EvidenceID = 21
+
+	_, err := suite.service.ProvideEvidence(context.Background(), provided)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+}
+
+func (suite *AuditExecutionServiceSuite) TestProvideEvidence_StatusAsConfidentiality_ReturnsInvalidData() {
+	suite.expectQuestion()
+	suite.expectPlan("IN_PROGRESS")
+	suite.mockAssignments.On("CheckTeamMember", mock.Anything, 2, 3).Return(nil)
+
+	provided := providedForm()
+	provided.ConfidentialityVal = types.ReferenceValue{ID: 53}
+
+	_, err := suite.service.ProvideEvidence(context.Background(), provided)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+}
+
+func (suite *AuditExecutionServiceSuite) TestGetQuestion_RedactsForCaller() {
+	ctx := context.Background()
+	provided := []types.EvidenceProvided{{ID: 7, AuditQuestionID: 100, TypeVal: types.ReferenceValue{ID: 42}, ConfidentialityVal: types.ReferenceValue{ID: 50}, StatusVal: types.ReferenceValue{ID: 53}}}
+	suite.mockRepo.On("GetByIDAuditQuestion", ctx, types.AuditQuestion{ID: 100}).Return(types.AuditQuestion{ID: 100, AuditID: 2, EvidenceProvided: provided}, nil)
+	suite.mockAccess.On("Redact", ctx, 9, 2, mock.AnythingOfType("[]types.EvidenceProvided")).
+		Run(func(args mock.Arguments) { args.Get(3).([]types.EvidenceProvided)[0].Redacted = true }).
+		Return(nil)
+
+	question, err := suite.service.GetQuestion(ctx, types.AuditQuestion{ID: 100}, 9)
+
+	suite.NoError(err)
+	suite.True(question.EvidenceProvided[0].Redacted)
+	suite.Equal("CONFIDENTIAL", question.EvidenceProvided[0].ConfidentialityVal.Code)
+}
+
+func (suite *AuditExecutionServiceSuite) TestGetQuestion_SatisfiedOnceEveryEvidenceAccepted() {
+	ctx := context.Background()
+	question := types.AuditQuestion{
+		ID:       100,
+		AuditID:  2,
+		Evidence: []types.Evidence{{ID: 20}, {ID: 21}},
+		EvidenceProvided: []types.EvidenceProvided{
+			{ID: 7, EvidenceID: 20, TypeVal: types.ReferenceValue{ID: 45}, ConfidentialityVal: types.ReferenceValue{ID: 48}, StatusVal: types.ReferenceValue{ID: 54}},
+			{ID: 8, EvidenceID: 21, TypeVal: types.ReferenceValue{ID: 45}, ConfidentialityVal: types.ReferenceValue{ID: 48}, StatusVal: types.ReferenceValue{ID: 58}, SupersededBy: 9},
+			{ID: 9, EvidenceID: 21, TypeVal: types.ReferenceValue{ID: 45}, ConfidentialityVal: types.ReferenceValue{ID: 48}, StatusVal: types.ReferenceValue{ID: 53}},
+		},
+	}
+	suite.mockRepo.On("GetByIDAuditQuestion", ctx, types.AuditQuestion{ID: 100}).Return(question, nil).Once()
+	suite.mockAccess.On("Redact", ctx, 3, 2, mock.Anything).Return(nil)
+
+	pending, err := suite.service.GetQuestion(ctx, types.AuditQuestion{ID: 100}, 3)
+	suite.NoError(err)
+	suite.False(pending.Satisfied)
+
+	question.EvidenceProvided[2].StatusVal = types.ReferenceValue{ID: 54}
+	suite.mockRepo.On("GetByIDAuditQuestion", ctx, types.AuditQuestion{ID: 100}).Return(question, nil).Once()
+
+	accepted, err := suite.service.GetQuestion(ctx, types.AuditQuestion{ID: 100}, 3)
+	suite.NoError(err)
+	suite.True(accepted.Satisfied)
+}
+
+func (suite *AuditExecutionServiceSuite) expectProvided(statusID int) {
+	provided := providedForm()
+	provided.ID = 7
+	provided.StatusVal = types.ReferenceValue{ID: statusID}
+	suite.mockProvidedRepo.On("GetByIDEvidenceProvided", mock.Anything, mock.MatchedBy(func(p types.EvidenceProvided) bool { return p.ID == 7 })).
+		Return(provided, nil).Once()
+}
+
+func (suite *AuditExecutionServiceSuite) TestReviewEvidence_Accepts() {
+	ctx := context.Background()
+	suite.expectProvided(53)
+	suite.expectQuestion()
+	suite.expectPlan("REVIEW")
+	suite.mockAssignments.On("CheckTeamMember", ctx, 2, 4).Return(nil)
+	suite.mockAccess.On("CheckAccess", ctx, 4, 2, mock.MatchedBy(func(p types.EvidenceProvided) bool { return p.ID == 7 }), "review").Return(nil)
+
+	decision := types.EvidenceReview{Reason: "Signed by management", ReviewedBy: 4}
+	suite.mockProvidedRepo.On("ReviewEvidenceProvided", ctx, 7, 53, 54, decision).Return(nil)
+	reviewed := providedForm()
+	reviewed.ID = 7
+	reviewed.StatusVal = types.ReferenceValue{ID: 54}
+	reviewed.Review = &decision
+	suite.mockProvidedRepo.On("GetByIDEvidenceProvided", ctx, mock.MatchedBy(func(p types.EvidenceProvided) bool { return p.ID == 7 })).Return(reviewed, nil)
+
+	result, err := suite.service.ReviewEvidence(ctx, 7, types.EvidenceReviewForm{Status: "ACCEPTED", Reason: "Signed by management", ReviewedBy: 4})
+
+	suite.NoError(err)
+	suite.Equal("ACCEPTED", result.StatusVal.Code)
+	suite.Equal("Signed by management", result.Review.Reason)
+}
+
+func (suite *AuditExecutionServiceSuite) TestReviewEvidence_WithoutReason_ReturnsInvalidData() {
+	_, err := suite.service.ReviewEvidence(context.Background(), 7, types.EvidenceReviewForm{Status: "REJECTED", Reason: "  ", ReviewedBy: 4})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+	suite.mockProvidedRepo.AssertNotCalled(suite.T(), "ReviewEvidenceProvided", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AuditExecutionServiceSuite) TestReviewEvidence_OwnEvidence_ReturnsForbidden() {
+	suite.expectProvided(53)
+	suite.expectQuestion()
+	suite.expectPlan("IN_PROGRESS")
+	suite.mockAssignments.On("CheckTeamMember", mock.Anything, 2, 3).Return(nil)
+
+	_, err := suite.service.ReviewEvidence(context.Background(), 7, types.EvidenceReviewForm{Status: "ACCEPTED", Reason: "Looks fine", ReviewedBy: 3})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeForbidden))
+	suite.mockProvidedRepo.AssertNotCalled(suite.T(), "ReviewEvidenceProvided", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AuditExecutionServiceSuite) TestReviewEvidence_AlreadyReviewed_ReturnsConflict() {
+	suite.expectProvided(55)
+	suite.expectQuestion()
+	suite.expectPlan("IN_PROGRESS")
+	suite.mockAssignments.On("CheckTeamMember", mock.Anything, 2, 4).Return(nil)
+	suite.mockAccess.On("CheckAccess", mock.Anything, 4, 2, mock.Anything, "review").Return(nil)
+
+	_, err := suite.service.ReviewEvidence(context.Background(), 7, types.EvidenceReviewForm{Status: "ACCEPTED", Reason: "Changed my mind", ReviewedBy: 4})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (suite *AuditExecutionServiceSuite) TestReviewEvidence_WithoutAccess_ReturnsForbidden() {
+	suite.expectProvided(53)
+	suite.expectQuestion()
+	suite.expectPlan("IN_PROGRESS")
+	suite.mockAssignments.On("CheckTeamMember", mock.Anything, 2, 4).Return(nil)
+	suite.mockAccess.On("CheckAccess", mock.Anything, 4, 2, mock.Anything, "review").
+		Return(custom_errors.Forbidden(context.Background(), "CLASSIFIED evidence is not accessible to user 4"))
+
+	_, err := suite.service.ReviewEvidence(context.Background(), 7, types.EvidenceReviewForm{Status: "ACCEPTED", Reason: "Looks fine", ReviewedBy: 4})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeForbidden))
+	suite.mockProvidedRepo.AssertNotCalled(suite.T(), "ReviewEvidenceProvided", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AuditExecutionServiceSuite) TestUpdateEvidence_Reviewed_ReturnsConflict() {
+	suite.expectProvided(55)
+
+	provided := providedForm()
+	provided.ID = 7
+
+	_, err := suite.service.UpdateEvidence(context.Background(), provided)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+	suite.mockProvidedRepo.AssertNotCalled(suite.T(), "UpdateEvidenceProvided", mock.Anything, mock.Anything)
+}
+
+func (suite *AuditExecutionServiceSuite) TestUpdateEvidence_RedactsForCaller() {
+	ctx := context.Background()
+	suite.expectProvided(53)
+	suite.expectQuestion()
+	suite.expectPlan("IN_PROGRESS")
+	suite.mockAssignments.On("CheckTeamMember", ctx, 2, 3).Return(nil)
+
+	provided := providedForm()
+	provided.ID = 7
+	stored := provided
+	stored.StatusVal = types.ReferenceValue{ID: 53}
+	suite.mockProvidedRepo.On("UpdateEvidenceProvided", ctx, mock.MatchedBy(func(p types.EvidenceProvided) bool { return p.ID == 7 })).Return(stored, nil)
+	suite.mockAccess.On("Redact", ctx, 3, 2, mock.AnythingOfType("[]types.EvidenceProvided")).
+		Run(func(args mock.Arguments) {
+			redacted := args.Get(3).([]types.EvidenceProvided)
+			redacted[0].Provided = ""
+			redacted[0].Redacted = true
+		}).
+		Return(nil)
+
+	result, err := suite.service.UpdateEvidence(ctx, provided)
+
+	suite.NoError(err)
+	suite.True(result.Redacted)
+	suite.Empty(result.Provided)
+	suite.Equal("CONFIDENTIAL", result.ConfidentialityVal.Code)
+}
+
+func (suite *AuditExecutionServiceSuite) TestDeleteEvidence_RemovesEvidenceOfTeamMember() {
+	ctx := context.Background()
+	suite.expectProvided(53)
+	suite.expectQuestion()
+	suite.expectPlan("IN_PROGRESS")
+	suite.mockAssignments.On("CheckTeamMember", ctx, 2, 3).Return(nil)
+	suite.mockProvidedRepo.On("DeleteEvidenceProvided", ctx, 7).Return(nil)
+
+	err := suite.service.DeleteEvidence(ctx, types.EvidenceProvided{ID: 7, UserID: 3})
+
+	suite.NoError(err)
+}
+
+func (suite *AuditExecutionServiceSuite) TestDeleteEvidence_NotOnTeam_ReturnsForbidden() {
+	suite.expectProvided(53)
+	suite.expectQuestion()
+	suite.expectPlan("IN_PROGRESS")
+	suite.mockAssignments.On("CheckTeamMember", mock.Anything, 2, 9).
+		Return(custom_errors.Forbidden(context.Background(), "user 9 is not on the team of audit plan 2"))
+
+	err := suite.service.DeleteEvidence(context.Background(), types.EvidenceProvided{ID: 7, UserID: 9})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeForbidden))
+	suite.mockProvidedRepo.AssertNotCalled(suite.T(), "DeleteEvidenceProvided", mock.Anything, mock.Anything)
+}
+
+func (suite *AuditExecutionServiceSuite) TestReplaceEvidence_SupersedesRejected() {
+	ctx := context.Background()
+	suite.expectProvided(55)
+	suite.expectQuestion()
+	suite.expectPlan("IN_PROGRESS")
+	suite.mockAssignments.On("CheckTeamMember", ctx, 2, 3).Return(nil)
+
+	replacement := providedForm()
+	replacement.Provided = "QM-001 rev 5"
+	created := replacement
+	created.ID = 9
+	created.StatusVal = types.ReferenceValue{ID: 53}
+	suite.mockProvidedRepo.On("SupersedeEvidenceProvided", ctx, mock.MatchedBy(func(p types.EvidenceProvided) bool {
+		return p.ID == 7 && p.StatusVal.ID == 55
+	}), mock.MatchedBy(func(p types.EvidenceProvided) bool {
+		return p.ID == 0 && p.StatusVal.ID == 53 && p.Provided == "QM-001 rev 5"
+	}), 58).Return(created, nil)
+	suite.mockAccess.On("Redact", ctx, 3, 2, mock.AnythingOfType("[]types.EvidenceProvided")).Return(nil)
+
+	result, err := suite.service.ReplaceEvidence(ctx, 7, replacement)
+
+	suite.NoError(err)
+	suite.Equal(9, result.ID)
+	suite.Equal("PENDING", result.StatusVal.Code)
+}
+
+func (suite *AuditExecutionServiceSuite) TestReplaceEvidence_Superseded_ReturnsConflict() {
+	suite.expectProvided(58)
+	suite.expectQuestion()
+	suite.expectPlan("IN_PROGRESS")
+	suite.mockAssignments.On("CheckTeamMember", mock.Anything, 2, 3).Return(nil)
+
+	_, err := suite.service.ReplaceEvidence(context.Background(), 7, providedForm())
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+	suite.mockProvidedRepo.AssertNotCalled(suite.T(), "SupersedeEvidenceProvided", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AuditExecutionServiceSuite) TestGetEvidenceChain_FollowsLinks() {
+	ctx := context.Background()
+	suite.expectProvided(58)
+	text := func(id, supersededBy, statusID int) types.EvidenceProvided {
+		return types.EvidenceProvided{ID: id, EvidenceID: 20, SupersededBy: supersededBy, TypeVal: types.ReferenceValue{ID: 45},
+			ConfidentialityVal: types.ReferenceValue{ID: 48}, StatusVal: types.ReferenceValue{ID: statusID}}
+	}
+	question := types.AuditQuestion{ID: 100, AuditID: 2, EvidenceProvided: []types.EvidenceProvided{
+		text(9, 0, 53), text(5, 7, 58), text(8, 0, 54), text(7, 9, 58),
+	}}
+	suite.mockRepo.On("GetByIDAuditQuestion", ctx, types.AuditQuestion{ID: 100}).Return(question, nil)
+	suite.mockAccess.On("Redact", ctx, 3, 2, mock.Anything).Return(nil)
+
+	chain, err := suite.service.GetEvidenceChain(ctx, 7, 3)
+
+	suite.NoError(err)
+	suite.Require().Len(chain, 3)
+	suite.Equal([]int{5, 7, 9}, []int{chain[0].ID, chain[1].ID, chain[2].ID})
+	suite.Equal("PENDING", chain[2].StatusVal.Code)
+}
+
+func TestAuditExecutionServiceSuite(t *testing.T) {
+	suite.Run(t, new(AuditExecutionServiceSuite))
+}
@@ -0,0 +1,255 @@
+package services_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockAuditPlanRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditPlanRepository) GetAllAuditPlans(ctx context.Context, filter types.AuditPlanFilter) ([]types.AuditPlan, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]types.AuditPlan), args.Error(1)
+}
+
+func (m *MockAuditPlanRepository) GetByIDAuditPlan(ctx context.Context, plan types.AuditPlan) (types.AuditPlan, error) {
+	args := m.Called(ctx, plan)
+	return args.Get(0).(types.AuditPlan), args.Error(1)
+}
+
+func (m *MockAuditPlanRepository) CreateAuditPlan(ctx context.Context, plan types.AuditPlan) (types.AuditPlan, error) {
+	args := m.Called(ctx, plan)
+	return args.Get(0).(types.AuditPlan), args.Error(1)
+}
+
+func (m *MockAuditPlanRepository) UpdateAuditPlan(ctx context.Context, plan types.AuditPlan) (types.AuditPlan, error) {
+	args := m.Called(ctx, plan)
+	return args.Get(0).(types.AuditPlan), args.Error(1)
+}
+
+func (m *MockAuditPlanRepository) UpdateAuditPlanStatus(ctx context.Context, id, fromStatusID, toStatusID int) error {
+	args := m.Called(ctx, id, fromStatusID, toStatusID)
+	return args.Error(0)
+}
+
+func (m *MockAuditPlanRepository) DeleteAuditPlan(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// Seeded audit_plans.status_id and audit_plans.type_id values
+var (
+	auditPlanReferenceTypes = []types.ReferenceType{
+		{ID: 2, Name: "audit_plans.status_id"},
+		{ID: 3, Name: "audit_plans.type_id"},
+	}
+	auditPlanReferenceValues = []types.ReferenceValue{
+		{ID: 6, TypeID: 2, Code: "DRAFT", IsActive: true},
+		{ID: 7, TypeID: 2, Code: "SCHEDULED", IsActive: true},
+		{ID: 8, TypeID: 2, Code: "IN_PROGRESS", IsActive: true},
+		{ID: 9, TypeID: 2, Code: "REVIEW", IsActive: true},
+		{ID: 10, TypeID: 2, Code: "COMPLETED", IsActive: true},
+		{ID: 11, TypeID: 2, Code: "CANCELLED", IsActive: true},
+		{ID: 12, TypeID: 3, Code: "GAP_ANALYSIS", IsActive: true},
+		{ID: 13, TypeID: 3, Code: "INTERNAL", IsActive: true},
+	}
+)
+
+type AuditPlanServiceSuite struct {
+	suite.Suite
+	mockRepo          *MockAuditPlanRepository
+	mockStandardRepo  *MockStandardRepository
+	mockReferenceRepo *MockReferenceDataRepository
+	eventBus          *events.EventBus
+	service           *services.AuditPlanService
+}
+
+func (suite *AuditPlanServiceSuite) SetupTest() {
+	suite.mockRepo = new(MockAuditPlanRepository)
+	suite.mockStandardRepo = new(MockStandardRepository)
+	suite.mockReferenceRepo = new(MockReferenceDataRepository)
+	suite.mockReferenceRepo.On("GetAllReferenceTypes", mock.Anything).Return(auditPlanReferenceTypes, nil).Maybe()
+	suite.mockReferenceRepo.On("GetAllReferenceValues", mock.Anything).Return(auditPlanReferenceValues, nil).Maybe()
+
+	suite.eventBus = events.NewEventBus()
+	referenceData := services.NewReferenceDataService(suite.mockReferenceRepo, nil)
+	suite.service = services.NewAuditPlanService(suite.mockRepo, suite.mockStandardRepo, referenceData, suite.eventBus)
+}
+
+func (suite *AuditPlanServiceSuite) TearDownTest() {
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockStandardRepo.AssertExpectations(suite.T())
+}
+
+// storedPlan is a plan as the repository returns it, with only the reference IDs set
+func storedPlan(statusID int) types.AuditPlan {
+	return types.AuditPlan{
+		ID:            1,
+		StandardID:    1,
+		LeadAuditorID: 3,
+		Name:          "ISO 9001 surveillance",
+		StatusVal:     types.ReferenceValue{ID: statusID},
+		TypeVal:       types.ReferenceValue{ID: 13},
+	}
+}
+
+func (suite *AuditPlanServiceSuite) TestCreate_StartsAsDraft() {
+	ctx := context.Background()
+	plan := types.AuditPlan{StandardID: 1, LeadAuditorID: 3, Name: "ISO 9001 surveillance", TypeVal: types.ReferenceValue{ID: 13}}
+
+	suite.mockStandardRepo.On("GetByIDStandard", ctx, types.Standard{ID: 1}).Return(types.Standard{ID: 1}, nil)
+	suite.mockRepo.On("CreateAuditPlan", ctx, mock.MatchedBy(func(p types.AuditPlan) bool {
+		return p.StatusVal.ID == 6 && p.TypeVal.ID == 13
+	})).Return(storedPlan(6), nil)
+
+	created, err := suite.service.Create(ctx, plan)
+
+	suite.NoError(err)
+	suite.Equal("DRAFT", created.StatusVal.Code)
+	suite.Equal("INTERNAL", created.TypeVal.Code)
+}
+
+func (suite *AuditPlanServiceSuite) TestCreate_StatusAsType_ReturnsInvalidData() {
+	ctx := context.Background()
+	plan := types.AuditPlan{StandardID: 1, LeadAuditorID: 3, Name: "ISO 9001 surveillance", TypeVal: types.ReferenceValue{ID: 7}}
+
+	suite.mockStandardRepo.On("GetByIDStandard", ctx, types.Standard{ID: 1}).Return(types.Standard{ID: 1}, nil)
+
+	_, err := suite.service.Create(ctx, plan)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+	suite.mockRepo.AssertNotCalled(suite.T(), "CreateAuditPlan", mock.Anything, mock.Anything)
+}
+
+func (suite *AuditPlanServiceSuite) TestChangeStatus_FollowsLifecycle() {
+	ctx := context.Background()
+	lifecycle := []struct{ fromID, toID int }{{6, 7}, {7, 8}, {8, 9}, {9, 10}}
+
+	for _, step := range lifecycle {
+		suite.mockRepo.On("GetByIDAuditPlan", ctx, mock.Anything).Return(storedPlan(step.fromID), nil).Once()
+		suite.mockRepo.On("UpdateAuditPlanStatus", ctx, 1, step.fromID, step.toID).Return(nil).Once()
+		suite.mockRepo.On("GetByIDAuditPlan", ctx, mock.Anything).Return(storedPlan(step.toID), nil).Once()
+	}
+
+	for _, code := range []string{"SCHEDULED", "IN_PROGRESS", "REVIEW", "COMPLETED"} {
+		updated, err := suite.service.ChangeStatus(ctx, types.AuditPlan{ID: 1}, code)
+		suite.NoError(err)
+		suite.Equal(code, updated.StatusVal.Code)
+	}
+}
+
+func (suite *AuditPlanServiceSuite) TestChangeStatus_IllegalTransition_ReturnsConflict() {
+	ctx := context.Background()
+	cases := []struct {
+		fromID int
+		to     string
+	}{
+		{6, "IN_PROGRESS"}, // DRAFT cannot skip SCHEDULED
+		{8, "SCHEDULED"},   // no way back
+		{10, "CANCELLED"},  // COMPLETED is final
+		{11, "DRAFT"},      // CANCELLED is final
+	}
+
+	for _, tc := range cases {
+		suite.mockRepo.On("GetByIDAuditPlan", ctx, types.AuditPlan{ID: 1}).Return(storedPlan(tc.fromID), nil).Once()
+
+		_, err := suite.service.ChangeStatus(ctx, types.AuditPlan{ID: 1}, tc.to)
+		suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict), "from %d to %s", tc.fromID, tc.to)
+	}
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateAuditPlanStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AuditPlanServiceSuite) TestChangeStatus_UnknownCode_ReturnsInvalidData() {
+	ctx := context.Background()
+	suite.mockRepo.On("GetByIDAuditPlan", ctx, types.AuditPlan{ID: 1}).Return(storedPlan(6), nil)
+
+	_, err := suite.service.ChangeStatus(ctx, types.AuditPlan{ID: 1}, "ARCHIVED")
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+}
+
+func (suite *AuditPlanServiceSuite) TestChangeStatus_PublishesEvent() {
+	ctx := context.Background()
+	received := make(chan events.Event, 1)
+	suite.eventBus.Subscribe(events.EntityChanged, func(ctx context.Context, event events.Event) error {
+		received <- event
+		return nil
+	})
+
+	suite.mockRepo.On("GetByIDAuditPlan", ctx, mock.Anything).Return(storedPlan(6), nil).Once()
+	suite.mockRepo.On("UpdateAuditPlanStatus", ctx, 1, 6, 11).Return(nil)
+	suite.mockRepo.On("GetByIDAuditPlan", ctx, mock.Anything).Return(storedPlan(11), nil).Once()
+
+	_, err := suite.service.ChangeStatus(ctx, types.AuditPlan{ID: 1}, "CANCELLED")
+	suite.NoError(err)
+
+	select {
+	case event := <-received:
+		payload, err := events.GetEntityChangePayload(event)
+		suite.NoError(err)
+		suite.Equal(events.EntityAuditPlan, payload.EntityType)
+		suite.Equal(events.ChangeUpdated, payload.ChangeType)
+	case <-time.After(time.Second):
+		suite.Fail("expected an audit plan event")
+	}
+}
+
+func (suite *AuditPlanServiceSuite) TestUpdate_ClosedPlan_ReturnsConflict() {
+	ctx := context.Background()
+	suite.mockRepo.On("GetByIDAuditPlan", ctx, types.AuditPlan{ID: 1, Name: "Renamed"}).Return(storedPlan(10), nil)
+
+	_, err := suite.service.Update(ctx, types.AuditPlan{ID: 1, Name: "Renamed"})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateAuditPlan", mock.Anything, mock.Anything)
+}
+
+func (suite *AuditPlanServiceSuite) TestDelete_OnlyDraftOrCancelled() {
+	ctx := context.Background()
+
+	suite.mockRepo.On("GetByIDAuditPlan", ctx, types.AuditPlan{ID: 1}).Return(storedPlan(8), nil).Once()
+	err := suite.service.Delete(ctx, types.AuditPlan{ID: 1})
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+
+	suite.mockRepo.On("GetByIDAuditPlan", ctx, types.AuditPlan{ID: 1}).Return(storedPlan(11), nil).Once()
+	suite.mockRepo.On("DeleteAuditPlan", ctx, 1).Return(nil).Once()
+	suite.NoError(suite.service.Delete(ctx, types.AuditPlan{ID: 1}))
+}
+
+func (suite *AuditPlanServiceSuite) TestGetAll_ResolvesFilterCodes() {
+	ctx := context.Background()
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	filter := types.AuditPlanFilter{StandardID: 1, AuditorID: 3, Status: "SCHEDULED", Type: "INTERNAL", From: from}
+
+	expected := filter
+	expected.StatusID = 7
+	expected.TypeID = 13
+	suite.mockRepo.On("GetAllAuditPlans", ctx, expected).Return([]types.AuditPlan{storedPlan(7)}, nil)
+
+	plans, err := suite.service.GetAll(ctx, filter)
+
+	suite.NoError(err)
+	suite.Len(plans, 1)
+	suite.Equal("SCHEDULED", plans[0].StatusVal.Code)
+}
+
+func (suite *AuditPlanServiceSuite) TestGetAll_UnknownStatus_ReturnsInvalidData() {
+	_, err := suite.service.GetAll(context.Background(), types.AuditPlanFilter{Status: "ARCHIVED"})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+	suite.mockRepo.AssertNotCalled(suite.T(), "GetAllAuditPlans", mock.Anything, mock.Anything)
+}
+
+func TestAuditPlanServiceSuite(t *testing.T) {
+	suite.Run(t, new(AuditPlanServiceSuite))
+}
@@ -0,0 +1,177 @@
+package services_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) GetUserBySessionToken(ctx context.Context, tokenHash string, asOf time.Time) (types.User, error) {
+	args := m.Called(ctx, tokenHash, asOf)
+	return args.Get(0).(types.User), args.Error(1)
+}
+
+func (m *MockSessionRepository) CreateSession(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	args := m.Called(ctx, userID, tokenHash, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) DeleteSession(ctx context.Context, tokenHash string) error {
+	args := m.Called(ctx, tokenHash)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) DeleteByUserIDSessions(ctx context.Context, userID int) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) DeleteExpiredSessions(ctx context.Context, asOf time.Time) error {
+	args := m.Called(ctx, asOf)
+	return args.Error(0)
+}
+
+type AuthServiceSuite struct {
+	suite.Suite
+	mockUsers    *MockUserRepository
+	mockSessions *MockSessionRepository
+	now          time.Time
+	service      *services.AuthService
+}
+
+func (suite *AuthServiceSuite) SetupTest() {
+	suite.mockUsers = new(MockUserRepository)
+	suite.mockSessions = new(MockSessionRepository)
+	suite.now = time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	referenceRepo := new(MockReferenceDataRepository)
+	referenceRepo.On("GetAllReferenceTypes", mock.Anything).Return(userReferenceTypes, nil).Maybe()
+	referenceRepo.On("GetAllReferenceValues", mock.Anything).Return(userReferenceValues, nil).Maybe()
+	referenceData := services.NewReferenceDataService(referenceRepo, nil)
+
+	clock := func() time.Time { return suite.now }
+	suite.service = services.NewAuthService(suite.mockUsers, suite.mockSessions, referenceData, 12*time.Hour, clock, nil)
+}
+
+func (suite *AuthServiceSuite) TearDownTest() {
+	suite.mockUsers.AssertExpectations(suite.T())
+	suite.mockSessions.AssertExpectations(suite.T())
+}
+
+func (suite *AuthServiceSuite) expectCredentials(password string) types.User {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	suite.Require().NoError(err)
+
+	user := types.User{ID: 7, Email: "alice@example.com", Name: "Alice", RoleVal: types.ReferenceValue{ID: 2}, IsActive: true}
+	suite.mockUsers.On("GetCredentialsUser", mock.Anything, "alice@example.com").Return(user, string(hash), nil)
+	return user
+}
+
+func (suite *AuthServiceSuite) TestLogin_OpensSession() {
+	suite.expectCredentials("correct horse battery")
+	suite.mockSessions.On("DeleteExpiredSessions", mock.Anything, suite.now).Return(nil)
+	suite.mockSessions.On("CreateSession", mock.Anything, 7, mock.AnythingOfType("string"), suite.now.Add(12*time.Hour)).Return(nil)
+	suite.mockUsers.On("UpdateUserLastLogin", mock.Anything, 7).Return(nil)
+
+	session, err := suite.service.Login(context.Background(), " alice@example.com ", "correct horse battery")
+
+	suite.NoError(err)
+	suite.NotEmpty(session.Token)
+	suite.Equal(suite.now.Add(12*time.Hour), session.ExpiresAt)
+	suite.Equal("LEAD", session.User.RoleVal.Code)
+
+	// Only the hash of the token is stored
+	storedHash := suite.mockSessions.Calls[1].Arguments.String(2)
+	suite.NotEqual(session.Token, storedHash)
+	suite.Len(storedHash, 64)
+}
+
+func (suite *AuthServiceSuite) TestLogin_WrongPassword() {
+	suite.expectCredentials("correct horse battery")
+
+	_, err := suite.service.Login(context.Background(), "alice@example.com", "wrong password")
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeUnauthorized))
+	suite.mockSessions.AssertNotCalled(suite.T(), "CreateSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AuthServiceSuite) TestLogin_UnknownEmailFailsLikeWrongPassword() {
+	suite.mockUsers.On("GetCredentialsUser", mock.Anything, "nobody@example.com").
+		Return(types.User{}, "", custom_errors.NotFound(context.Background(), "User"))
+
+	_, err := suite.service.Login(context.Background(), "nobody@example.com", "whatever password")
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeUnauthorized))
+}
+
+func (suite *AuthServiceSuite) TestLogin_UserWithoutPassword() {
+	user := types.User{ID: 7, Email: "alice@example.com", RoleVal: types.ReferenceValue{ID: 2}, IsActive: true}
+	suite.mockUsers.On("GetCredentialsUser", mock.Anything, "alice@example.com").Return(user, "", nil)
+
+	_, err := suite.service.Login(context.Background(), "alice@example.com", "")
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeUnauthorized))
+}
+
+func (suite *AuthServiceSuite) TestAuthenticate_ExpiredSession() {
+	suite.mockSessions.On("GetUserBySessionToken", mock.Anything, mock.AnythingOfType("string"), suite.now).
+		Return(types.User{}, custom_errors.NotFound(context.Background(), "Session"))
+
+	_, err := suite.service.Authenticate(context.Background(), "token")
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeUnauthorized))
+}
+
+func (suite *AuthServiceSuite) TestAuthenticate_HydratesRole() {
+	user := types.User{ID: 7, RoleVal: types.ReferenceValue{ID: 1}, IsActive: true}
+	suite.mockSessions.On("GetUserBySessionToken", mock.Anything, mock.AnythingOfType("string"), suite.now).Return(user, nil)
+
+	result, err := suite.service.Authenticate(context.Background(), "token")
+
+	suite.NoError(err)
+	suite.Equal("ADMIN", result.RoleVal.Code)
+}
+
+func (suite *AuthServiceSuite) TestSetPassword_EndsSessions() {
+	suite.mockUsers.On("UpdateUserPassword", mock.Anything, 7, mock.AnythingOfType("string")).Return(nil)
+	suite.mockSessions.On("DeleteByUserIDSessions", mock.Anything, 7).Return(nil)
+
+	err := suite.service.SetPassword(context.Background(), 7, "a new long password")
+
+	suite.NoError(err)
+	hash := suite.mockUsers.Calls[0].Arguments.String(2)
+	suite.NoError(bcrypt.CompareHashAndPassword([]byte(hash), []byte("a new long password")))
+}
+
+func (suite *AuthServiceSuite) TestBootstrap_CreatesAdmin() {
+	suite.mockUsers.On("GetCredentialsUser", mock.Anything, "admin@example.com").
+		Return(types.User{}, "", custom_errors.NotFound(context.Background(), "User"))
+	expected := types.User{Email: "admin@example.com", Name: "admin", RoleVal: types.ReferenceValue{ID: 1}}
+	suite.mockUsers.On("CreateUser", mock.Anything, expected).Return(types.User{ID: 1, Email: "admin@example.com"}, nil)
+	suite.mockUsers.On("UpdateUserPassword", mock.Anything, 1, mock.AnythingOfType("string")).Return(nil)
+	suite.mockSessions.On("DeleteByUserIDSessions", mock.Anything, 1).Return(nil)
+
+	suite.NoError(suite.service.Bootstrap(context.Background(), " Admin@Example.com", "first admin password"))
+}
+
+func (suite *AuthServiceSuite) TestBootstrap_KeepsExistingPassword() {
+	suite.expectCredentials("correct horse battery")
+
+	suite.NoError(suite.service.Bootstrap(context.Background(), "alice@example.com", "another password"))
+	suite.mockUsers.AssertNotCalled(suite.T(), "UpdateUserPassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthServiceSuite(t *testing.T) {
+	suite.Run(t, new(AuthServiceSuite))
+}
@@ -0,0 +1,163 @@
+package services_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuthorizationServiceSuite struct {
+	suite.Suite
+	mockAssignments *MockAuditAssignmentRepository
+	mockQuestions   *MockAuditQuestionRepository
+	mockComments    *MockCommentRepository
+	mockFindings    *MockFindingRepository
+	mockActions     *MockCorrectiveActionRepository
+	mockEvidence    *MockEvidenceProvidedRepository
+	service         *services.AuthorizationService
+}
+
+func (suite *AuthorizationServiceSuite) SetupTest() {
+	suite.mockAssignments = new(MockAuditAssignmentRepository)
+	suite.mockQuestions = new(MockAuditQuestionRepository)
+	suite.mockComments = new(MockCommentRepository)
+	suite.mockFindings = new(MockFindingRepository)
+	suite.mockActions = new(MockCorrectiveActionRepository)
+	suite.mockEvidence = new(MockEvidenceProvidedRepository)
+	suite.service = services.NewAuthorizationService(suite.mockAssignments, suite.mockQuestions, suite.mockComments, suite.mockFindings, suite.mockActions, suite.mockEvidence)
+}
+
+func (suite *AuthorizationServiceSuite) TearDownTest() {
+	suite.mockAssignments.AssertExpectations(suite.T())
+	suite.mockQuestions.AssertExpectations(suite.T())
+	suite.mockComments.AssertExpectations(suite.T())
+	suite.mockFindings.AssertExpectations(suite.T())
+	suite.mockActions.AssertExpectations(suite.T())
+	suite.mockEvidence.AssertExpectations(suite.T())
+}
+
+func signedIn(id int, role string) context.Context {
+	user := types.User{ID: id, RoleVal: types.ReferenceValue{Code: role}, IsActive: true}
+	return services.WithCurrentUser(context.Background(), user)
+}
+
+func (suite *AuthorizationServiceSuite) TestRequireRole() {
+	suite.NoError(services.RequireRole(signedIn(7, services.UserRoleLead), services.UserRoleAdmin, services.UserRoleLead))
+
+	err := services.RequireRole(signedIn(7, services.UserRoleViewer), services.UserRoleAdmin, services.UserRoleLead)
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeForbidden))
+
+	err = services.RequireRole(context.Background(), services.UserRoleAdmin)
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeUnauthorized))
+}
+
+func (suite *AuthorizationServiceSuite) TestCheckAuditAccess_AdminIsNotChecked() {
+	suite.NoError(suite.service.CheckAuditAccess(signedIn(1, services.UserRoleAdmin), 4))
+	suite.mockAssignments.AssertNotCalled(suite.T(), "IsAuditTeamMember", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AuthorizationServiceSuite) TestCheckAuditAccess_TeamMember() {
+	suite.mockAssignments.On("IsAuditTeamMember", mock.Anything, 4, 7).Return(true, nil)
+
+	suite.NoError(suite.service.CheckAuditAccess(signedIn(7, services.UserRoleLead), 4))
+}
+
+func (suite *AuthorizationServiceSuite) TestCheckAuditAccess_NotOnTeam() {
+	suite.mockAssignments.On("IsAuditTeamMember", mock.Anything, 4, 8).Return(false, nil)
+
+	err := suite.service.CheckAuditAccess(signedIn(8, services.UserRoleLead), 4)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeForbidden))
+}
+
+func (suite *AuthorizationServiceSuite) TestCheckAuditParticipation_TeamMember() {
+	suite.mockAssignments.On("IsAuditTeamMember", mock.Anything, 4, 7).Return(true, nil)
+
+	suite.NoError(suite.service.CheckAuditParticipation(signedIn(7, services.UserRoleSupport), 4))
+	suite.mockAssignments.AssertNotCalled(suite.T(), "IsAuditFollowUpOwner", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AuthorizationServiceSuite) TestCheckAuditParticipation_ClientFollowingUpFinding() {
+	suite.mockAssignments.On("IsAuditTeamMember", mock.Anything, 4, 20).Return(false, nil)
+	suite.mockAssignments.On("IsAuditFollowUpOwner", mock.Anything, 4, 20).Return(true, nil)
+
+	suite.NoError(suite.service.CheckAuditParticipation(signedIn(20, services.UserRoleClient), 4))
+}
+
+func (suite *AuthorizationServiceSuite) TestCheckAuditParticipation_ClientOfOtherAudit() {
+	suite.mockAssignments.On("IsAuditTeamMember", mock.Anything, 4, 21).Return(false, nil)
+	suite.mockAssignments.On("IsAuditFollowUpOwner", mock.Anything, 4, 21).Return(false, nil)
+
+	err := suite.service.CheckAuditParticipation(signedIn(21, services.UserRoleClient), 4)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeForbidden))
+}
+
+func (suite *AuthorizationServiceSuite) TestCheckAuditParticipation_ViewerIsNotAParticipant() {
+	suite.mockAssignments.On("IsAuditTeamMember", mock.Anything, 4, 22).Return(false, nil)
+
+	err := suite.service.CheckAuditParticipation(signedIn(22, services.UserRoleViewer), 4)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeForbidden))
+	suite.mockAssignments.AssertNotCalled(suite.T(), "IsAuditFollowUpOwner", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AuthorizationServiceSuite) TestCheckAuditParticipation_Anonymous() {
+	err := suite.service.CheckAuditParticipation(context.Background(), 4)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeUnauthorized))
+}
+
+func (suite *AuthorizationServiceSuite) TestIsOnAudit_Anonymous() {
+	member, err := suite.service.IsOnAudit(context.Background(), 4)
+
+	suite.NoError(err)
+	suite.False(member)
+}
+
+func (suite *AuthorizationServiceSuite) TestAuditPlanOfComment_FollowsAuditQuestion() {
+	suite.mockComments.On("GetByIDComment", mock.Anything, types.Comment{ID: 12}).Return(types.Comment{ID: 12, AuditQuestionID: 100}, nil)
+	suite.mockQuestions.On("GetByIDAuditQuestion", mock.Anything, types.AuditQuestion{ID: 100}).Return(types.AuditQuestion{ID: 100, AuditID: 4}, nil)
+
+	auditPlanID, err := suite.service.AuditPlanOfComment(context.Background(), 12)
+
+	suite.NoError(err)
+	suite.Equal(4, auditPlanID)
+}
+
+func (suite *AuthorizationServiceSuite) TestAuditPlanOfFinding_MissingFinding_ReturnsNotFound() {
+	suite.mockFindings.On("GetByIDFinding", mock.Anything, types.Finding{ID: 9}).Return(types.Finding{}, custom_errors.NotFound(context.Background(), "Finding"))
+
+	_, err := suite.service.AuditPlanOfFinding(context.Background(), 9)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func (suite *AuthorizationServiceSuite) TestAuditPlanOfCorrectiveAction_FollowsFinding() {
+	suite.mockActions.On("GetByIDCorrectiveAction", mock.Anything, types.CorrectiveAction{ID: 5}).Return(types.CorrectiveAction{ID: 5, FindingID: 9}, nil)
+	suite.mockFindings.On("GetByIDFinding", mock.Anything, types.Finding{ID: 9}).Return(types.Finding{ID: 9, AuditID: 4}, nil)
+
+	auditPlanID, err := suite.service.AuditPlanOfCorrectiveAction(context.Background(), 5)
+
+	suite.NoError(err)
+	suite.Equal(4, auditPlanID)
+}
+
+func (suite *AuthorizationServiceSuite) TestAuditPlanOfEvidence_FollowsAuditQuestion() {
+	suite.mockEvidence.On("GetByIDEvidenceProvided", mock.Anything, types.EvidenceProvided{ID: 30}).Return(types.EvidenceProvided{ID: 30, AuditQuestionID: 100}, nil)
+	suite.mockQuestions.On("GetByIDAuditQuestion", mock.Anything, types.AuditQuestion{ID: 100}).Return(types.AuditQuestion{ID: 100, AuditID: 4}, nil)
+
+	auditPlanID, err := suite.service.AuditPlanOfEvidence(context.Background(), 30)
+
+	suite.NoError(err)
+	suite.Equal(4, auditPlanID)
+}
+
+func TestAuthorizationServiceSuite(t *testing.T) {
+	suite.Run(t, new(AuthorizationServiceSuite))
+}
@@ -0,0 +1,202 @@
+package services_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockCommentRepository struct {
+	mock.Mock
+}
+
+func (m *MockCommentRepository) GetByAuditQuestionIDComments(ctx context.Context, auditQuestionID int) ([]types.Comment, error) {
+	args := m.Called(ctx, auditQuestionID)
+	return args.Get(0).([]types.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) GetByIDComment(ctx context.Context, comment types.Comment) (types.Comment, error) {
+	args := m.Called(ctx, comment)
+	return args.Get(0).(types.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) CreateAuditQuestionComment(ctx context.Context, auditQuestionID int, comment types.Comment, mentionIDs []int) (types.Comment, error) {
+	args := m.Called(ctx, auditQuestionID, comment, mentionIDs)
+	return args.Get(0).(types.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) UpdateComment(ctx context.Context, comment types.Comment, editedBy int, mentionIDs []int) (types.Comment, error) {
+	args := m.Called(ctx, comment, editedBy, mentionIDs)
+	return args.Get(0).(types.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) DeleteComment(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) GetRevisionsComment(ctx context.Context, commentID int) ([]types.CommentRevision, error) {
+	args := m.Called(ctx, commentID)
+	return args.Get(0).([]types.CommentRevision), args.Error(1)
+}
+
+func (m *MockCommentRepository) GetMentionedUserIDs(ctx context.Context, handles []string) (map[string][]int, error) {
+	args := m.Called(ctx, handles)
+	return args.Get(0).(map[string][]int), args.Error(1)
+}
+
+type CommentServiceSuite struct {
+	suite.Suite
+	mockRepo       *MockCommentRepository
+	mockQuestions  *MockAuditQuestionRepository
+	mockAuditPlans *MockAuditPlanService
+	service        *services.CommentService
+}
+
+func (suite *CommentServiceSuite) SetupTest() {
+	suite.mockRepo = new(MockCommentRepository)
+	suite.mockQuestions = new(MockAuditQuestionRepository)
+	suite.mockAuditPlans = new(MockAuditPlanService)
+	suite.service = services.NewCommentService(suite.mockRepo, suite.mockQuestions, suite.mockAuditPlans, nil)
+}
+
+func (suite *CommentServiceSuite) TearDownTest() {
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockQuestions.AssertExpectations(suite.T())
+	suite.mockAuditPlans.AssertExpectations(suite.T())
+}
+
+func (suite *CommentServiceSuite) expectAudit(statusCode string) {
+	suite.mockQuestions.On("GetByIDAuditQuestion", mock.Anything, types.AuditQuestion{ID: 100}).Return(types.AuditQuestion{ID: 100, AuditID: 2}, nil)
+	plan := types.AuditPlan{ID: 2, StatusVal: types.ReferenceValue{Code: statusCode}}
+	suite.mockAuditPlans.On("GetByID", mock.Anything, types.AuditPlan{ID: 2}).Return(plan, nil)
+}
+
+func (suite *CommentServiceSuite) expectComment(comment types.Comment) {
+	suite.mockRepo.On("GetByIDComment", mock.Anything, types.Comment{ID: comment.ID}).Return(comment, nil)
+}
+
+func (suite *CommentServiceSuite) TestAdd_ClosedAudit_ReturnsConflict() {
+	suite.expectAudit("COMPLETED")
+
+	_, err := suite.service.Add(context.Background(), 100, types.Comment{UserID: 3, Text: "Ask for the signed copy"})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+	suite.mockRepo.AssertNotCalled(suite.T(), "CreateAuditQuestionComment", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *CommentServiceSuite) TestAdd_DuringReview() {
+	ctx := context.Background()
+	suite.expectAudit("REVIEW")
+
+	comment := types.Comment{UserID: 3, Text: "Ask for the signed copy"}
+	suite.mockRepo.On("CreateAuditQuestionComment", ctx, 100, comment, []int(nil)).Return(types.Comment{ID: 5, UserID: 3, Text: comment.Text}, nil)
+
+	created, err := suite.service.Add(ctx, 100, comment)
+
+	suite.NoError(err)
+	suite.Equal(5, created.ID)
+}
+
+func (suite *CommentServiceSuite) TestAdd_ResolvesUnambiguousMentions() {
+	ctx := context.Background()
+	suite.expectAudit("IN_PROGRESS")
+
+	comment := types.Comment{UserID: 3, Text: "@Bob and @carol@example.com, see @bob. Ask @sam or mail dave@example.com"}
+	suite.mockRepo.On("GetMentionedUserIDs", ctx, []string{"bob", "carol@example.com", "sam"}).
+		Return(map[string][]int{"bob": {8}, "carol@example.com": {9}, "sam": {11, 12}}, nil)
+	suite.mockRepo.On("CreateAuditQuestionComment", ctx, 100, comment, []int{8, 9}).Return(types.Comment{ID: 5}, nil)
+
+	_, err := suite.service.Add(ctx, 100, comment)
+
+	suite.NoError(err)
+}
+
+func (suite *CommentServiceSuite) TestAdd_ReplyToOtherQuestion_ReturnsInvalidData() {
+	suite.expectAudit("IN_PROGRESS")
+	suite.expectComment(types.Comment{ID: 4, AuditQuestionID: 101, UserID: 8})
+
+	_, err := suite.service.Add(context.Background(), 100, types.Comment{UserID: 3, Text: "Agreed", ParentID: 4})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+	suite.mockRepo.AssertNotCalled(suite.T(), "CreateAuditQuestionComment", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *CommentServiceSuite) TestEdit_OtherAuthor_ReturnsForbidden() {
+	suite.expectComment(types.Comment{ID: 5, AuditQuestionID: 100, UserID: 8, Text: "Not available yet"})
+
+	_, err := suite.service.Edit(context.Background(), 5, types.CommentEditForm{UserID: 3, Text: "Available"})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeForbidden))
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateComment", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *CommentServiceSuite) TestEdit_KeepsRevisionThroughRepository() {
+	ctx := context.Background()
+	suite.expectComment(types.Comment{ID: 5, AuditQuestionID: 100, UserID: 3, Text: "Not available yet"})
+	suite.expectAudit("IN_PROGRESS")
+
+	editedAt := time.Now()
+	edited := types.Comment{ID: 5, AuditQuestionID: 100, UserID: 3, Text: "Available from Monday", EditedAt: &editedAt}
+	suite.mockRepo.On("UpdateComment", ctx, types.Comment{ID: 5, AuditQuestionID: 100, UserID: 3, Text: "Available from Monday"}, 3, []int(nil)).
+		Return(edited, nil)
+
+	comment, err := suite.service.Edit(ctx, 5, types.CommentEditForm{UserID: 3, Text: "Available from Monday"})
+
+	suite.NoError(err)
+	suite.Equal(&editedAt, comment.EditedAt)
+}
+
+func (suite *CommentServiceSuite) TestDelete_Deleted_ReturnsNotFound() {
+	suite.expectComment(types.Comment{ID: 5, AuditQuestionID: 100, UserID: 3, Deleted: true})
+
+	_, err := suite.service.Delete(context.Background(), 5, 3)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+	suite.mockRepo.AssertNotCalled(suite.T(), "DeleteComment", mock.Anything, mock.Anything)
+}
+
+func (suite *CommentServiceSuite) TestDelete_ByAuthor() {
+	ctx := context.Background()
+	suite.expectComment(types.Comment{ID: 5, AuditQuestionID: 100, UserID: 3, Text: "Not available yet"})
+	suite.expectAudit("IN_PROGRESS")
+	suite.mockRepo.On("DeleteComment", ctx, 5).Return(nil)
+
+	comment, err := suite.service.Delete(ctx, 5, 3)
+
+	suite.NoError(err)
+	suite.True(comment.Deleted)
+	suite.Empty(comment.Text)
+	suite.Equal(100, comment.AuditQuestionID)
+}
+
+func (suite *CommentServiceSuite) TestGetThread_NestsReplies() {
+	ctx := context.Background()
+	suite.mockQuestions.On("GetByIDAuditQuestion", ctx, types.AuditQuestion{ID: 100}).Return(types.AuditQuestion{ID: 100, AuditID: 2}, nil)
+	suite.mockRepo.On("GetByAuditQuestionIDComments", ctx, 100).Return([]types.Comment{
+		{ID: 5, Deleted: true},
+		{ID: 6, Text: "Second thread"},
+		{ID: 7, ParentID: 5, Text: "Reply to a deleted comment"},
+		{ID: 8, ParentID: 7, Text: "Nested reply"},
+	}, nil)
+
+	thread, err := suite.service.GetThread(ctx, 100)
+
+	suite.NoError(err)
+	suite.Require().Len(thread, 2)
+	suite.Equal(5, thread[0].ID)
+	suite.Require().Len(thread[0].Replies, 1)
+	suite.Equal(7, thread[0].Replies[0].ID)
+	suite.Equal(8, thread[0].Replies[0].Replies[0].ID)
+	suite.Empty(thread[1].Replies)
+}
+
+func TestCommentServiceSuite(t *testing.T) {
+	suite.Run(t, new(CommentServiceSuite))
+}
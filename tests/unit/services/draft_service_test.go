@@ -1,6 +1,7 @@
 package services_test
 
 import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
 	"ISO_Auditing_Tool/pkg/services"
 	"ISO_Auditing_Tool/pkg/types"
 	"ISO_Auditing_Tool/tests/unit/repositories/mocks"
@@ -19,6 +20,15 @@ type MockDraftRepository struct {
 	mock.Mock
 }
 
+type MockDraftPublisher struct {
+	mock.Mock
+}
+
+func (m *MockDraftPublisher) PublishDraft(ctx context.Context, current, next types.Draft, transition types.DraftTransition) error {
+	args := m.Called(ctx, current, next, transition)
+	return args.Error(0)
+}
+
 // Define test suites
 type DraftServiceSuccessSuite struct {
 	suite.Suite
@@ -35,7 +45,7 @@ type DraftServiceErrorSuite struct {
 // SetupTest initializes test dependencies before each test
 func (suite *DraftServiceSuccessSuite) SetupTest() {
 	suite.mockRepo = new(mocks.MockDraftRepository)
-	suite.service = services.NewDraftService(suite.mockRepo)
+	suite.service = services.NewDraftService(suite.mockRepo, newDraftReferenceData(), nil)
 }
 
 // TearDownTest cleans up after each test
@@ -46,7 +56,7 @@ func (suite *DraftServiceSuccessSuite) TearDownTest() {
 // SetupTest initializes test dependencies before each test
 func (suite *DraftServiceErrorSuite) SetupTest() {
 	suite.mockRepo = new(mocks.MockDraftRepository)
-	suite.service = services.NewDraftService(suite.mockRepo)
+	suite.service = services.NewDraftService(suite.mockRepo, newDraftReferenceData(), nil)
 }
 
 // TearDownTest cleans up after each test
@@ -54,14 +64,36 @@ func (suite *DraftServiceErrorSuite) TearDownTest() {
 	suite.mockRepo.AssertExpectations(suite.T())
 }
 
+// Seeded drafts reference data
+var (
+	draftReferenceTypes = []types.ReferenceType{
+		{ID: 11, Name: "drafts.type_id"},
+		{ID: 12, Name: "drafts.status_id"},
+	}
+	draftReferenceValues = []types.ReferenceValue{
+		{ID: 59, TypeID: 11, Code: "STANDARD", IsActive: true},
+		{ID: 61, TypeID: 12, Code: "DRAFT_DRAFT", IsActive: true},
+		{ID: 62, TypeID: 12, Code: "DRAFT_PENDING_APPROVAL", IsActive: true},
+		{ID: 63, TypeID: 12, Code: "DRAFT_REJECTED", IsActive: true},
+		{ID: 64, TypeID: 12, Code: "DRAFT_PUBLISHED", IsActive: true},
+	}
+)
+
 // Helper functions
+func newDraftReferenceData() services.ReferenceDataServiceInterface {
+	referenceRepo := new(MockReferenceDataRepository)
+	referenceRepo.On("GetAllReferenceTypes", mock.Anything).Return(draftReferenceTypes, nil).Maybe()
+	referenceRepo.On("GetAllReferenceValues", mock.Anything).Return(draftReferenceValues, nil).Maybe()
+	return services.NewReferenceDataService(referenceRepo, nil)
+}
+
 func createTestDraft() types.Draft {
 	now := time.Now().UTC().Truncate(time.Second)
 	return types.Draft{
 		ID:              1,
-		TypeID:          1,  // 1 might represent "standard"
+		TypeID:          59, // STANDARD
 		ObjectID:        42, // ID of the standard being drafted
-		StatusID:        1,  // 1 might represent "pending"
+		StatusID:        61, // DRAFT_DRAFT
 		Version:         1,
 		Data:            []byte(`{"name": "ISO 27001", "description": "Information Security Standard"}`),
 		Diff:            []byte(`{"name": {"old": "ISO 27000", "new": "ISO 27001"}}`),
@@ -125,7 +157,8 @@ func (suite *DraftServiceSuccessSuite) TestUpdate_WhenValidDraft_ReturnsUpdatedD
 	updatedDraft.Data = []byte(`{"name": "ISO 27001:2022", "description": "Updated Information Security Standard"}`)
 	updatedDraft.Diff = []byte(`{"description": {"old": "Information Security Standard", "new": "Updated Information Security Standard"}}`)
 
-	suite.mockRepo.On("UpdateDraft", ctx, draft).Return(updatedDraft, nil)
+	suite.mockRepo.On("GetDraftByID", ctx, draft).Return(draft, nil)
+	suite.mockRepo.On("UpdateDraft", ctx, draft, draft.StatusID).Return(updatedDraft, nil)
 
 	// Act
 	result, err := suite.service.Update(ctx, draft)
@@ -165,7 +198,8 @@ func (suite *DraftServiceErrorSuite) TestUpdate_WhenRepositoryFails_ReturnsError
 	draft := createTestDraft()
 
 	expectedErr := errors.New("database error")
-	suite.mockRepo.On("UpdateDraft", ctx, draft).Return(types.Draft{}, expectedErr)
+	suite.mockRepo.On("GetDraftByID", ctx, draft).Return(draft, nil)
+	suite.mockRepo.On("UpdateDraft", ctx, draft, draft.StatusID).Return(types.Draft{}, expectedErr)
 
 	// Act
 	result, err := suite.service.Update(ctx, draft)
@@ -176,8 +210,212 @@ func (suite *DraftServiceErrorSuite) TestUpdate_WhenRepositoryFails_ReturnsError
 	assert.Equal(suite.T(), types.Draft{}, result)
 }
 
+// TestUpdate_WhenDraftIsPending_ReturnsConflict tests that submitted drafts are no longer edited
+func (suite *DraftServiceErrorSuite) TestUpdate_WhenDraftIsPending_ReturnsConflict() {
+	// Arrange
+	ctx := context.Background()
+	draft := createTestDraft()
+	pending := draft
+	pending.StatusID = 62
+
+	suite.mockRepo.On("GetDraftByID", ctx, draft).Return(pending, nil)
+
+	// Act
+	_, err := suite.service.Update(ctx, draft)
+
+	// Assert
+	assert.True(suite.T(), custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+// TestUpdate_WhenUserIsNotAuthor_ReturnsForbidden tests that only authors edit their drafts
+func (suite *DraftServiceErrorSuite) TestUpdate_WhenUserIsNotAuthor_ReturnsForbidden() {
+	// Arrange
+	ctx := context.Background()
+	draft := createTestDraft()
+	draft.UserID = 11
+
+	suite.mockRepo.On("GetDraftByID", ctx, draft).Return(createTestDraft(), nil)
+
+	// Act
+	_, err := suite.service.Update(ctx, draft)
+
+	// Assert
+	assert.True(suite.T(), custom_errors.IsErrorCode(err, custom_errors.ErrCodeForbidden))
+}
+
+// TestDelete_WhenDraftWasSubmitted_ReturnsConflict tests that drafts keep their approval history
+func (suite *DraftServiceErrorSuite) TestDelete_WhenDraftWasSubmitted_ReturnsConflict() {
+	// Arrange
+	ctx := context.Background()
+	draft := createTestDraft()
+
+	suite.mockRepo.On("GetDraftTransitions", ctx, draft.ID).
+		Return([]types.DraftTransition{{ID: 1, DraftID: draft.ID, Action: services.DraftActionSubmit}}, nil)
+
+	// Act
+	service := &services.DraftService{Repo: suite.mockRepo}
+	_, err := service.Delete(ctx, draft)
+
+	// Assert
+	assert.True(suite.T(), custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+	suite.mockRepo.AssertNotCalled(suite.T(), "DeleteDraft", mock.Anything, mock.Anything)
+}
+
+// DraftWorkflowSuite covers the submit, approve, reject and publish steps
+type DraftWorkflowSuite struct {
+	suite.Suite
+	mockRepo      *mocks.MockDraftRepository
+	mockPublisher *MockDraftPublisher
+	now           time.Time
+	service       *services.DraftService
+}
+
+func (suite *DraftWorkflowSuite) SetupTest() {
+	suite.mockRepo = new(mocks.MockDraftRepository)
+	suite.now = time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return suite.now }
+	suite.mockPublisher = new(MockDraftPublisher)
+	suite.service = services.NewDraftService(suite.mockRepo, newDraftReferenceData(), clock)
+	suite.service.RegisterPublisher("STANDARD", suite.mockPublisher)
+}
+
+func (suite *DraftWorkflowSuite) TearDownTest() {
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockPublisher.AssertExpectations(suite.T())
+}
+
+// expectDraft returns the draft with the given status and approver when it is loaded
+func (suite *DraftWorkflowSuite) expectDraft(statusID, approverID int) types.Draft {
+	draft := createTestDraft()
+	draft.StatusID = statusID
+	draft.ApproverID = approverID
+	suite.mockRepo.On("GetDraftByID", mock.Anything, types.Draft{ID: draft.ID}).Return(draft, nil).Once()
+	return draft
+}
+
+// expectReload returns the draft with its history once the transition is stored
+func (suite *DraftWorkflowSuite) expectReload(draft types.Draft) {
+	suite.mockRepo.On("GetDraftByID", mock.Anything, draft).Return(draft, nil).Once()
+	suite.mockRepo.On("GetDraftTransitions", mock.Anything, draft.ID).Return([]types.DraftTransition{}, nil).Once()
+}
+
+func (suite *DraftWorkflowSuite) TestSubmit_MovesDraftToPendingApproval() {
+	current := suite.expectDraft(61, 0)
+	next := current
+	next.StatusID = 62
+	transition := types.DraftTransition{Action: services.DraftActionSubmit, UserID: 10, Comment: "ready for review"}
+	suite.mockRepo.On("TransitionDraft", mock.Anything, current, next, transition).Return(nil)
+	suite.expectReload(current)
+
+	_, err := suite.service.Submit(context.Background(), 1, types.DraftTransitionForm{Comment: " ready for review ", UserID: 10})
+
+	suite.NoError(err)
+}
+
+func (suite *DraftWorkflowSuite) TestSubmit_RejectsDraftOfAnotherUser() {
+	suite.expectDraft(61, 0)
+
+	_, err := suite.service.Submit(context.Background(), 1, types.DraftTransitionForm{Comment: "ready", UserID: 11})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeForbidden))
+}
+
+func (suite *DraftWorkflowSuite) TestApprove_RecordsApproverAndComment() {
+	current := suite.expectDraft(62, 0)
+	next := current
+	next.ApproverID = 20
+	next.ApprovalComment = "looks good"
+	transition := types.DraftTransition{Action: services.DraftActionApprove, UserID: 20, Comment: "looks good"}
+	suite.mockRepo.On("TransitionDraft", mock.Anything, current, next, transition).Return(nil)
+	suite.expectReload(current)
+
+	_, err := suite.service.Approve(context.Background(), 1, types.DraftTransitionForm{Comment: "looks good", UserID: 20})
+
+	suite.NoError(err)
+}
+
+func (suite *DraftWorkflowSuite) TestApprove_RejectsSelfApproval() {
+	suite.expectDraft(62, 0)
+
+	_, err := suite.service.Approve(context.Background(), 1, types.DraftTransitionForm{Comment: "fine by me", UserID: 10})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeForbidden))
+	suite.mockRepo.AssertNotCalled(suite.T(), "TransitionDraft", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *DraftWorkflowSuite) TestApprove_RejectsDraftThatWasNotSubmitted() {
+	suite.expectDraft(61, 0)
+
+	_, err := suite.service.Approve(context.Background(), 1, types.DraftTransitionForm{Comment: "looks good", UserID: 20})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (suite *DraftWorkflowSuite) TestApprove_RequiresComment() {
+	_, err := suite.service.Approve(context.Background(), 1, types.DraftTransitionForm{Comment: "  ", UserID: 20})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+}
+
+func (suite *DraftWorkflowSuite) TestReject_SendsDraftBackToAuthor() {
+	current := suite.expectDraft(62, 0)
+	next := current
+	next.StatusID = 63
+	next.ApproverID = 20
+	next.ApprovalComment = "missing scope"
+	transition := types.DraftTransition{Action: services.DraftActionReject, UserID: 20, Comment: "missing scope"}
+	suite.mockRepo.On("TransitionDraft", mock.Anything, current, next, transition).Return(nil)
+	suite.expectReload(current)
+
+	_, err := suite.service.Reject(context.Background(), 1, types.DraftTransitionForm{Comment: "missing scope", UserID: 20})
+
+	suite.NoError(err)
+}
+
+func (suite *DraftWorkflowSuite) TestPublish_AppliesDraftThroughPublisherOfItsType() {
+	current := suite.expectDraft(62, 20)
+	next := current
+	next.StatusID = 64
+	next.PublishedAt = &suite.now
+	transition := types.DraftTransition{Action: services.DraftActionPublish, UserID: 20, Comment: "publishing"}
+	suite.mockPublisher.On("PublishDraft", mock.Anything, current, next, transition).Return(nil)
+	suite.expectReload(current)
+
+	_, err := suite.service.Publish(context.Background(), 1, types.DraftTransitionForm{Comment: "publishing", UserID: 20})
+
+	suite.NoError(err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "TransitionDraft", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *DraftWorkflowSuite) TestPublish_RejectsTypeWithoutPublisher() {
+	suite.service.Publishers = map[string]services.DraftPublisher{}
+	suite.expectDraft(62, 20)
+
+	_, err := suite.service.Publish(context.Background(), 1, types.DraftTransitionForm{Comment: "publishing", UserID: 20})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+	suite.mockRepo.AssertNotCalled(suite.T(), "TransitionDraft", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *DraftWorkflowSuite) TestPublish_RequiresApproval() {
+	suite.expectDraft(62, 0)
+
+	_, err := suite.service.Publish(context.Background(), 1, types.DraftTransitionForm{Comment: "publishing", UserID: 20})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (suite *DraftWorkflowSuite) TestPublish_RejectsPublishedDraft() {
+	suite.expectDraft(64, 20)
+
+	_, err := suite.service.Publish(context.Background(), 1, types.DraftTransitionForm{Comment: "again", UserID: 20})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
 // Run all the test suites
 func TestDraftServiceSuites(t *testing.T) {
 	suite.Run(t, new(DraftServiceSuccessSuite))
 	suite.Run(t, new(DraftServiceErrorSuite))
+	suite.Run(t, new(DraftWorkflowSuite))
 }
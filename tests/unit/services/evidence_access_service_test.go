@@ -0,0 +1,260 @@
+package services_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockEvidenceAccessRepository struct {
+	mock.Mock
+}
+
+func (m *MockEvidenceAccessRepository) GetUserRoleID(ctx context.Context, userID int) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockEvidenceAccessRepository) GetByEvidenceProvidedIDEvidenceAccessGrants(ctx context.Context, providedID int) ([]types.EvidenceAccessGrant, error) {
+	args := m.Called(ctx, providedID)
+	return args.Get(0).([]types.EvidenceAccessGrant), args.Error(1)
+}
+
+func (m *MockEvidenceAccessRepository) GetGrantedIDsEvidenceAccessGrants(ctx context.Context, userID int, providedIDs []int) (map[int]bool, error) {
+	args := m.Called(ctx, userID, providedIDs)
+	return args.Get(0).(map[int]bool), args.Error(1)
+}
+
+func (m *MockEvidenceAccessRepository) CreateEvidenceAccessGrant(ctx context.Context, grant types.EvidenceAccessGrant) error {
+	args := m.Called(ctx, grant)
+	return args.Error(0)
+}
+
+func (m *MockEvidenceAccessRepository) DeleteEvidenceAccessGrant(ctx context.Context, providedID, userID int) error {
+	args := m.Called(ctx, providedID, userID)
+	return args.Error(0)
+}
+
+type MockEvidenceAccessService struct {
+	mock.Mock
+}
+
+func (m *MockEvidenceAccessService) Redact(ctx context.Context, userID, auditPlanID int, provided []types.EvidenceProvided) error {
+	args := m.Called(ctx, userID, auditPlanID, provided)
+	return args.Error(0)
+}
+
+func (m *MockEvidenceAccessService) CheckAccess(ctx context.Context, userID, auditPlanID int, provided types.EvidenceProvided, action string) error {
+	args := m.Called(ctx, userID, auditPlanID, provided, action)
+	return args.Error(0)
+}
+
+func (m *MockEvidenceAccessService) GetGrants(ctx context.Context, providedID, userID int) ([]types.EvidenceAccessGrant, error) {
+	args := m.Called(ctx, providedID, userID)
+	return args.Get(0).([]types.EvidenceAccessGrant), args.Error(1)
+}
+
+func (m *MockEvidenceAccessService) Grant(ctx context.Context, providedID int, form types.EvidenceAccessGrantForm) ([]types.EvidenceAccessGrant, error) {
+	args := m.Called(ctx, providedID, form)
+	return args.Get(0).([]types.EvidenceAccessGrant), args.Error(1)
+}
+
+func (m *MockEvidenceAccessService) Revoke(ctx context.Context, providedID, userID, revokedBy int) error {
+	args := m.Called(ctx, providedID, userID, revokedBy)
+	return args.Error(0)
+}
+
+var (
+	accessReferenceTypes = []types.ReferenceType{
+		{ID: 1, Name: "users.role_id"},
+		{ID: 9, Name: "evidence_provided.confidentiality_id"},
+	}
+	accessReferenceValues = []types.ReferenceValue{
+		{ID: 1, TypeID: 1, Code: "ADMIN", IsActive: true},
+		{ID: 2, TypeID: 1, Code: "LEAD", IsActive: true},
+		{ID: 3, TypeID: 1, Code: "SUPPORT", IsActive: true},
+		{ID: 4, TypeID: 1, Code: "VIEWER", IsActive: true},
+		{ID: 5, TypeID: 1, Code: "CLIENT", IsActive: true},
+		{ID: 48, TypeID: 9, Code: "PUBLIC", IsActive: true},
+		{ID: 49, TypeID: 9, Code: "INTERNAL", IsActive: true},
+		{ID: 50, TypeID: 9, Code: "CONFIDENTIAL", IsActive: true},
+		{ID: 51, TypeID: 9, Code: "RESTRICTED", IsActive: true},
+		{ID: 52, TypeID: 9, Code: "CLASSIFIED", IsActive: true},
+	}
+)
+
+type EvidenceAccessServiceSuite struct {
+	suite.Suite
+	mockRepo         *MockEvidenceAccessRepository
+	mockProvidedRepo *MockEvidenceProvidedRepository
+	mockQuestions    *MockAuditQuestionRepository
+	mockAssignments  *MockAuditAssignmentRepository
+	service          *services.EvidenceAccessService
+}
+
+func (suite *EvidenceAccessServiceSuite) SetupTest() {
+	suite.mockRepo = new(MockEvidenceAccessRepository)
+	suite.mockProvidedRepo = new(MockEvidenceProvidedRepository)
+	suite.mockQuestions = new(MockAuditQuestionRepository)
+	suite.mockAssignments = new(MockAuditAssignmentRepository)
+
+	referenceRepo := new(MockReferenceDataRepository)
+	referenceRepo.On("GetAllReferenceTypes", mock.Anything).Return(accessReferenceTypes, nil)
+	referenceRepo.On("GetAllReferenceValues", mock.Anything).Return(accessReferenceValues, nil)
+	referenceData := services.NewReferenceDataService(referenceRepo, nil)
+
+	suite.service = services.NewEvidenceAccessService(
+		suite.mockRepo, suite.mockProvidedRepo, suite.mockQuestions, suite.mockAssignments, referenceData, nil,
+	)
+}
+
+func (suite *EvidenceAccessServiceSuite) TearDownTest() {
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockProvidedRepo.AssertExpectations(suite.T())
+	suite.mockQuestions.AssertExpectations(suite.T())
+	suite.mockAssignments.AssertExpectations(suite.T())
+}
+
+// expectUser makes userID an active user with the given role, on the team of audit 2 or not
+func (suite *EvidenceAccessServiceSuite) expectUser(userID, roleID int, member bool) {
+	suite.mockRepo.On("GetUserRoleID", mock.Anything, userID).Return(roleID, nil)
+	suite.mockAssignments.On("IsAuditTeamMember", mock.Anything, 2, userID).Return(member, nil)
+}
+
+// providedOfEveryLevel returns provided evidence 1 to 5, PUBLIC through CLASSIFIED
+func providedOfEveryLevel() []types.EvidenceProvided {
+	provided := []types.EvidenceProvided{}
+	for i, confidentialityID := range []int{48, 49, 50, 51, 52} {
+		provided = append(provided, types.EvidenceProvided{
+			ID: i + 1, Provided: "content", File: &types.EvidenceFile{Name: "file.pdf"},
+			ConfidentialityVal: types.ReferenceValue{ID: confidentialityID},
+		})
+	}
+	return provided
+}
+
+// visible lists the IDs of the provided evidence left unredacted
+func visible(provided []types.EvidenceProvided) []int {
+	ids := []int{}
+	for _, item := range provided {
+		if !item.Redacted {
+			ids = append(ids, item.ID)
+		}
+	}
+	return ids
+}
+
+func (suite *EvidenceAccessServiceSuite) TestRedact_Anonymous_OnlyPublic() {
+	provided := providedOfEveryLevel()
+
+	err := suite.service.Redact(context.Background(), 0, 2, provided)
+
+	suite.NoError(err)
+	suite.Equal([]int{1}, visible(provided))
+	suite.Empty(provided[1].Provided)
+	suite.Nil(provided[1].File)
+}
+
+func (suite *EvidenceAccessServiceSuite) TestRedact_Client_NeverRestricted() {
+	suite.expectUser(8, 5, false)
+	suite.mockRepo.On("GetGrantedIDsEvidenceAccessGrants", mock.Anything, 8, []int{5}).Return(map[int]bool{5: true}, nil)
+	provided := providedOfEveryLevel()
+
+	err := suite.service.Redact(context.Background(), 8, 2, provided)
+
+	suite.NoError(err)
+	suite.Equal([]int{1, 2, 3}, visible(provided))
+}
+
+func (suite *EvidenceAccessServiceSuite) TestRedact_ViewerOffTeam_UpToInternal() {
+	suite.expectUser(8, 4, false)
+	suite.mockRepo.On("GetGrantedIDsEvidenceAccessGrants", mock.Anything, 8, []int{5}).Return(map[int]bool{}, nil)
+	provided := providedOfEveryLevel()
+
+	err := suite.service.Redact(context.Background(), 8, 2, provided)
+
+	suite.NoError(err)
+	suite.Equal([]int{1, 2}, visible(provided))
+}
+
+func (suite *EvidenceAccessServiceSuite) TestRedact_TeamMember_ClassifiedNeedsGrant() {
+	suite.expectUser(3, 3, true)
+	suite.mockRepo.On("GetGrantedIDsEvidenceAccessGrants", mock.Anything, 3, []int{5}).Return(map[int]bool{}, nil)
+	provided := providedOfEveryLevel()
+
+	err := suite.service.Redact(context.Background(), 3, 2, provided)
+
+	suite.NoError(err)
+	suite.Equal([]int{1, 2, 3, 4}, visible(provided))
+}
+
+func (suite *EvidenceAccessServiceSuite) TestRedact_GrantedAdmin_SeesClassified() {
+	suite.expectUser(1, 1, false)
+	suite.mockRepo.On("GetGrantedIDsEvidenceAccessGrants", mock.Anything, 1, []int{5}).Return(map[int]bool{5: true}, nil)
+	provided := providedOfEveryLevel()
+
+	err := suite.service.Redact(context.Background(), 1, 2, provided)
+
+	suite.NoError(err)
+	suite.Equal([]int{1, 2, 3, 4, 5}, visible(provided))
+}
+
+func (suite *EvidenceAccessServiceSuite) TestCheckAccess_Denied_ReturnsForbidden() {
+	suite.expectUser(8, 5, false)
+	provided := types.EvidenceProvided{ID: 4, ConfidentialityVal: types.ReferenceValue{ID: 51}}
+
+	err := suite.service.CheckAccess(context.Background(), 8, 2, provided, "download")
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeForbidden))
+}
+
+// expectProvided loads provided evidence 5 of audit 2
+func (suite *EvidenceAccessServiceSuite) expectProvided() {
+	suite.mockProvidedRepo.On("GetByIDEvidenceProvided", mock.Anything, types.EvidenceProvided{ID: 5}).
+		Return(types.EvidenceProvided{ID: 5, AuditQuestionID: 100}, nil)
+	suite.mockQuestions.On("GetByIDAuditQuestion", mock.Anything, types.AuditQuestion{ID: 100}).Return(types.AuditQuestion{ID: 100, AuditID: 2}, nil)
+}
+
+func (suite *EvidenceAccessServiceSuite) TestGrant_ByViewer_ReturnsForbidden() {
+	suite.expectProvided()
+	suite.expectUser(8, 4, false)
+
+	_, err := suite.service.Grant(context.Background(), 5, types.EvidenceAccessGrantForm{UserID: 9, GrantedBy: 8})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeForbidden))
+}
+
+func (suite *EvidenceAccessServiceSuite) TestGrant_ToClient_ReturnsInvalidData() {
+	suite.expectProvided()
+	suite.expectUser(2, 2, true)
+	suite.mockRepo.On("GetUserRoleID", mock.Anything, 9).Return(5, nil)
+
+	_, err := suite.service.Grant(context.Background(), 5, types.EvidenceAccessGrantForm{UserID: 9, GrantedBy: 2})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+	suite.mockRepo.AssertNotCalled(suite.T(), "CreateEvidenceAccessGrant", mock.Anything, mock.Anything)
+}
+
+func (suite *EvidenceAccessServiceSuite) TestGrant_ByLeadAuditor() {
+	ctx := context.Background()
+	suite.expectProvided()
+	suite.expectUser(2, 2, true)
+	suite.mockRepo.On("GetUserRoleID", ctx, 9).Return(4, nil)
+	suite.mockRepo.On("CreateEvidenceAccessGrant", ctx, types.EvidenceAccessGrant{EvidenceProvidedID: 5, UserID: 9, GrantedBy: 2}).Return(nil)
+	suite.mockRepo.On("GetByEvidenceProvidedIDEvidenceAccessGrants", ctx, 5).
+		Return([]types.EvidenceAccessGrant{{EvidenceProvidedID: 5, UserID: 9, GrantedBy: 2}}, nil)
+
+	grants, err := suite.service.Grant(ctx, 5, types.EvidenceAccessGrantForm{UserID: 9, GrantedBy: 2})
+
+	suite.NoError(err)
+	suite.Len(grants, 1)
+}
+
+func TestEvidenceAccessServiceSuite(t *testing.T) {
+	suite.Run(t, new(EvidenceAccessServiceSuite))
+}
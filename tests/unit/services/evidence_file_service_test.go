@@ -0,0 +1,205 @@
+package services_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/storage"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type EvidenceFileServiceSuite struct {
+	suite.Suite
+	mockProvidedRepo *MockEvidenceProvidedRepository
+	mockQuestions    *MockAuditQuestionRepository
+	mockAuditPlans   *MockAuditPlanService
+	mockAssignments  *MockAuditAssignmentService
+	mockAccess       *MockEvidenceAccessService
+	blobDir          string
+	service          *services.EvidenceFileService
+}
+
+func (suite *EvidenceFileServiceSuite) SetupTest() {
+	suite.mockProvidedRepo = new(MockEvidenceProvidedRepository)
+	suite.mockQuestions = new(MockAuditQuestionRepository)
+	suite.mockAuditPlans = new(MockAuditPlanService)
+	suite.mockAssignments = new(MockAuditAssignmentService)
+	suite.mockAccess = new(MockEvidenceAccessService)
+
+	referenceRepo := new(MockReferenceDataRepository)
+	referenceRepo.On("GetAllReferenceTypes", mock.Anything).Return(providedReferenceTypes, nil)
+	referenceRepo.On("GetAllReferenceValues", mock.Anything).Return(providedReferenceValues, nil)
+	referenceData := services.NewReferenceDataService(referenceRepo, nil)
+
+	suite.blobDir = suite.T().TempDir()
+	blobs, err := storage.NewLocalBlobStore(suite.blobDir)
+	suite.Require().NoError(err)
+
+	suite.service = services.NewEvidenceFileService(
+		suite.mockProvidedRepo, suite.mockQuestions, suite.mockAuditPlans, suite.mockAssignments, suite.mockAccess, referenceData, blobs, 1024, nil,
+	)
+}
+
+func (suite *EvidenceFileServiceSuite) TearDownTest() {
+	suite.mockProvidedRepo.AssertExpectations(suite.T())
+	suite.mockQuestions.AssertExpectations(suite.T())
+	suite.mockAuditPlans.AssertExpectations(suite.T())
+	suite.mockAssignments.AssertExpectations(suite.T())
+	suite.mockAccess.AssertExpectations(suite.T())
+}
+
+// expectProvided loads provided evidence 7 of the given type for a running audit that user 3 is on
+func (suite *EvidenceFileServiceSuite) expectProvided(typeID int) types.EvidenceProvided {
+	provided := types.EvidenceProvided{
+		ID: 7, EvidenceID: 20, AuditQuestionID: 100, UserID: 3,
+		TypeVal: types.ReferenceValue{ID: typeID}, ConfidentialityVal: types.ReferenceValue{ID: 48}, StatusVal: types.ReferenceValue{ID: 53},
+	}
+	suite.mockProvidedRepo.On("GetByIDEvidenceProvided", mock.Anything, types.EvidenceProvided{ID: 7, UserID: 3}).Return(provided, nil).Once()
+	suite.mockQuestions.On("GetByIDAuditQuestion", mock.Anything, types.AuditQuestion{ID: 100}).Return(types.AuditQuestion{ID: 100, AuditID: 2}, nil)
+	suite.mockAuditPlans.On("GetByID", mock.Anything, types.AuditPlan{ID: 2}).
+		Return(types.AuditPlan{ID: 2, StatusVal: types.ReferenceValue{Code: "IN_PROGRESS"}}, nil)
+	suite.mockAssignments.On("CheckTeamMember", mock.Anything, 2, 3).Return(nil)
+	return provided
+}
+
+// blobs lists the files left in the blob directory
+func (suite *EvidenceFileServiceSuite) blobs() []string {
+	var files []string
+	filepath.WalkDir(suite.blobDir, func(path string, entry os.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files
+}
+
+func (suite *EvidenceFileServiceSuite) TestUpload_RecordsDigest() {
+	ctx := context.Background()
+	provided := suite.expectProvided(42)
+	content := "%PDF-1.7 management review minutes"
+	sum := sha256.Sum256([]byte(content))
+
+	var recorded types.EvidenceFile
+	suite.mockProvidedRepo.On("UpdateEvidenceProvidedFile", ctx, 7, mock.AnythingOfType("types.EvidenceFile")).
+		Run(func(args mock.Arguments) { recorded = args.Get(2).(types.EvidenceFile) }).
+		Return(nil)
+	suite.mockProvidedRepo.On("GetByIDEvidenceProvided", ctx, provided).Return(provided, nil)
+	suite.mockAccess.On("Redact", ctx, 3, 2, mock.AnythingOfType("[]types.EvidenceProvided")).Return(nil)
+
+	_, err := suite.service.Upload(ctx, types.EvidenceProvided{ID: 7, UserID: 3}, "minutes.pdf", strings.NewReader(content))
+
+	suite.NoError(err)
+	suite.Equal(hex.EncodeToString(sum[:]), recorded.SHA256)
+	suite.Equal(int64(len(content)), recorded.Size)
+	suite.Equal("application/pdf", recorded.MIMEType)
+	suite.Equal("minutes.pdf", recorded.Name)
+	suite.True(strings.HasPrefix(recorded.Key, "evidence/7/"))
+	suite.Len(suite.blobs(), 1)
+}
+
+func (suite *EvidenceFileServiceSuite) TestUpload_RedactsResultForCaller() {
+	ctx := context.Background()
+	provided := suite.expectProvided(42)
+	stored := provided
+	stored.File = &types.EvidenceFile{Key: "evidence/7/a", Name: "minutes.pdf"}
+	suite.mockProvidedRepo.On("UpdateEvidenceProvidedFile", ctx, 7, mock.AnythingOfType("types.EvidenceFile")).Return(nil)
+	suite.mockProvidedRepo.On("GetByIDEvidenceProvided", ctx, provided).Return(stored, nil)
+	suite.mockAccess.On("Redact", ctx, 3, 2, mock.AnythingOfType("[]types.EvidenceProvided")).
+		Run(func(args mock.Arguments) {
+			redacted := args.Get(3).([]types.EvidenceProvided)
+			redacted[0].File = nil
+			redacted[0].Redacted = true
+		}).
+		Return(nil)
+
+	result, err := suite.service.Upload(ctx, types.EvidenceProvided{ID: 7, UserID: 3}, "minutes.pdf", strings.NewReader("%PDF-1.7 minutes"))
+
+	suite.NoError(err)
+	suite.True(result.Redacted)
+	suite.Nil(result.File)
+}
+
+func (suite *EvidenceFileServiceSuite) TestUpload_ImageWithTextContent_ReturnsInvalidData() {
+	suite.expectProvided(46)
+
+	_, err := suite.service.Upload(context.Background(), types.EvidenceProvided{ID: 7, UserID: 3}, "photo.jpg", strings.NewReader("not a picture"))
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+	suite.Empty(suite.blobs())
+}
+
+func (suite *EvidenceFileServiceSuite) TestUpload_TooLarge_ReturnsInvalidData() {
+	suite.expectProvided(42)
+
+	_, err := suite.service.Upload(context.Background(), types.EvidenceProvided{ID: 7, UserID: 3}, "big.bin", strings.NewReader(strings.Repeat("x", 2048)))
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+	suite.Empty(suite.blobs())
+	suite.mockProvidedRepo.AssertNotCalled(suite.T(), "UpdateEvidenceProvidedFile", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *EvidenceFileServiceSuite) TestUpload_TextEvidence_ReturnsInvalidData() {
+	suite.expectProvided(45)
+
+	_, err := suite.service.Upload(context.Background(), types.EvidenceProvided{ID: 7, UserID: 3}, "notes.txt", strings.NewReader("notes"))
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+}
+
+// expectDownload loads provided evidence 7 of audit 2 for user 3, who may see it unless denied
+func (suite *EvidenceFileServiceSuite) expectDownload(file *types.EvidenceFile, denied error) {
+	provided := types.EvidenceProvided{ID: 7, AuditQuestionID: 100, File: file}
+	suite.mockProvidedRepo.On("GetByIDEvidenceProvided", mock.Anything, types.EvidenceProvided{ID: 7, UserID: 3}).Return(provided, nil)
+	suite.mockQuestions.On("GetByIDAuditQuestion", mock.Anything, types.AuditQuestion{ID: 100}).Return(types.AuditQuestion{ID: 100, AuditID: 2}, nil)
+	suite.mockAccess.On("CheckAccess", mock.Anything, 3, 2, provided, "download").Return(denied)
+}
+
+func (suite *EvidenceFileServiceSuite) TestDownload_WithoutFile_ReturnsNotFound() {
+	suite.expectDownload(nil, nil)
+
+	_, _, err := suite.service.Download(context.Background(), types.EvidenceProvided{ID: 7, UserID: 3})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func (suite *EvidenceFileServiceSuite) TestDownload_Denied_ReturnsForbidden() {
+	ctx := context.Background()
+	suite.expectDownload(&types.EvidenceFile{Key: "evidence/7/abc"}, custom_errors.Forbidden(ctx, "RESTRICTED evidence"))
+
+	_, _, err := suite.service.Download(ctx, types.EvidenceProvided{ID: 7, UserID: 3})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeForbidden))
+}
+
+func (suite *EvidenceFileServiceSuite) TestDownload_StreamsStoredContent() {
+	ctx := context.Background()
+	blobs, err := storage.NewLocalBlobStore(suite.blobDir)
+	suite.Require().NoError(err)
+	suite.Require().NoError(blobs.Put(ctx, "evidence/7/abc", strings.NewReader("minutes")))
+
+	suite.expectDownload(&types.EvidenceFile{Key: "evidence/7/abc", Name: "minutes.txt", Size: 7}, nil)
+
+	got, content, err := suite.service.Download(ctx, types.EvidenceProvided{ID: 7, UserID: 3})
+	suite.Require().NoError(err)
+	defer content.Close()
+
+	data, err := io.ReadAll(content)
+	suite.NoError(err)
+	suite.Equal("minutes", string(data))
+	suite.Equal("minutes.txt", got.Name)
+}
+
+func TestEvidenceFileServiceSuite(t *testing.T) {
+	suite.Run(t, new(EvidenceFileServiceSuite))
+}
@@ -0,0 +1,109 @@
+package services_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type EvidenceServiceSuite struct {
+	suite.Suite
+	mockRepo         *MockEvidenceRepository
+	mockQuestionRepo *MockQuestionRepository
+	published        chan events.EntityChangePayload
+	service          *services.EvidenceService
+}
+
+func (suite *EvidenceServiceSuite) SetupTest() {
+	suite.mockRepo = new(MockEvidenceRepository)
+	suite.mockQuestionRepo = new(MockQuestionRepository)
+	eventBus := events.NewEventBus()
+	suite.published = make(chan events.EntityChangePayload, 10)
+	eventBus.Subscribe(events.EntityChanged, func(ctx context.Context, event events.Event) error {
+		payload, err := events.GetEntityChangePayload(event)
+		if err == nil {
+			suite.published <- payload
+		}
+		return err
+	})
+
+	suite.service = services.NewEvidenceService(suite.mockRepo, suite.mockQuestionRepo, eventBus)
+}
+
+func (suite *EvidenceServiceSuite) TearDownTest() {
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockQuestionRepo.AssertExpectations(suite.T())
+}
+
+func (suite *EvidenceServiceSuite) nextEvent() events.EntityChangePayload {
+	select {
+	case payload := <-suite.published:
+		return payload
+	case <-time.After(time.Second):
+		suite.FailNow("expected an EntityChanged event")
+		return events.EntityChangePayload{}
+	}
+}
+
+func (suite *EvidenceServiceSuite) TestCreate_ValidType_PublishesEventWithQuestion() {
+	documentType := types.ReferenceValue{ID: 31, Code: "DOCUMENT", Name: "Document"}
+	suite.mockQuestionRepo.On("GetByIDQuestion", mock.Anything, types.Question{ID: 8}).
+		Return(types.Question{ID: 8, RequirementID: 4}, nil)
+	suite.mockRepo.On("GetEvidenceType", mock.Anything, 31).Return(documentType, nil)
+	suite.mockRepo.On("CreateEvidence", mock.Anything, mock.MatchedBy(func(e types.Evidence) bool {
+		return e.QuestionID == 8 && e.TypeVal.Code == "DOCUMENT"
+	})).Return(types.Evidence{ID: 15, QuestionID: 8, TypeVal: documentType, Expected: "Scope statement"}, nil)
+
+	evidence, err := suite.service.Create(context.Background(), types.Evidence{
+		QuestionID: 8, TypeVal: types.ReferenceValue{ID: 31}, Expected: "Scope statement",
+	})
+
+	suite.NoError(err)
+	suite.Equal(15, evidence.ID)
+
+	payload := suite.nextEvent()
+	suite.Equal(events.EntityEvidence, payload.EntityType)
+	suite.Equal(15, payload.EntityID)
+	suite.Equal(events.EntityQuestion, payload.ParentType)
+	suite.Equal(8, payload.ParentID)
+}
+
+func (suite *EvidenceServiceSuite) TestCreate_InvalidType_ReturnsInvalidData() {
+	suite.mockQuestionRepo.On("GetByIDQuestion", mock.Anything, types.Question{ID: 8}).
+		Return(types.Question{ID: 8, RequirementID: 4}, nil)
+	suite.mockRepo.On("GetEvidenceType", mock.Anything, 11).
+		Return(types.ReferenceValue{}, custom_errors.InvalidData(context.Background(), "type_id is not a valid evidence type"))
+
+	_, err := suite.service.Create(context.Background(), types.Evidence{
+		QuestionID: 8, TypeVal: types.ReferenceValue{ID: 11}, Expected: "Scope statement",
+	})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+	suite.mockRepo.AssertNotCalled(suite.T(), "CreateEvidence", mock.Anything, mock.Anything)
+}
+
+func (suite *EvidenceServiceSuite) TestReorder_PublishesQuestionEvent() {
+	suite.mockQuestionRepo.On("GetByIDQuestion", mock.Anything, types.Question{ID: 8}).
+		Return(types.Question{ID: 8, RequirementID: 4}, nil)
+	suite.mockRepo.On("ReorderEvidence", mock.Anything, 8, []int{16, 15}).Return(nil)
+
+	err := suite.service.Reorder(context.Background(), 8, []int{16, 15})
+
+	suite.NoError(err)
+	payload := suite.nextEvent()
+	suite.Equal(events.EntityQuestion, payload.EntityType)
+	suite.Equal(8, payload.EntityID)
+	suite.Equal(events.EntityRequirement, payload.ParentType)
+	suite.Equal(4, payload.ParentID)
+}
+
+func TestEvidenceService(t *testing.T) {
+	suite.Run(t, new(EvidenceServiceSuite))
+}
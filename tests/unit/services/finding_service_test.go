@@ -0,0 +1,411 @@
+package services_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockFindingRepository struct {
+	mock.Mock
+}
+
+func (m *MockFindingRepository) GetAllFindings(ctx context.Context, filter types.FindingFilter) ([]types.Finding, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]types.Finding), args.Error(1)
+}
+
+func (m *MockFindingRepository) GetByIDFinding(ctx context.Context, finding types.Finding) (types.Finding, error) {
+	args := m.Called(ctx, finding)
+	return args.Get(0).(types.Finding), args.Error(1)
+}
+
+func (m *MockFindingRepository) CreateFinding(ctx context.Context, finding types.Finding) (types.Finding, error) {
+	args := m.Called(ctx, finding)
+	return args.Get(0).(types.Finding), args.Error(1)
+}
+
+func (m *MockFindingRepository) UpdateFinding(ctx context.Context, finding types.Finding) (types.Finding, error) {
+	args := m.Called(ctx, finding)
+	return args.Get(0).(types.Finding), args.Error(1)
+}
+
+func (m *MockFindingRepository) UpdateFindingStatus(ctx context.Context, id, fromStatusID, toStatusID int, reason string) error {
+	args := m.Called(ctx, id, fromStatusID, toStatusID, reason)
+	return args.Error(0)
+}
+
+func (m *MockFindingRepository) DeleteFinding(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockCorrectiveActionRepository struct {
+	mock.Mock
+}
+
+func (m *MockCorrectiveActionRepository) GetByFindingIDCorrectiveActions(ctx context.Context, findingID int) ([]types.CorrectiveAction, error) {
+	args := m.Called(ctx, findingID)
+	return args.Get(0).([]types.CorrectiveAction), args.Error(1)
+}
+
+func (m *MockCorrectiveActionRepository) GetByIDCorrectiveAction(ctx context.Context, action types.CorrectiveAction) (types.CorrectiveAction, error) {
+	args := m.Called(ctx, action)
+	return args.Get(0).(types.CorrectiveAction), args.Error(1)
+}
+
+func (m *MockCorrectiveActionRepository) CreateCorrectiveAction(ctx context.Context, action types.CorrectiveAction) (types.CorrectiveAction, error) {
+	args := m.Called(ctx, action)
+	return args.Get(0).(types.CorrectiveAction), args.Error(1)
+}
+
+func (m *MockCorrectiveActionRepository) UpdateCorrectiveAction(ctx context.Context, action types.CorrectiveAction) (types.CorrectiveAction, error) {
+	args := m.Called(ctx, action)
+	return args.Get(0).(types.CorrectiveAction), args.Error(1)
+}
+
+func (m *MockCorrectiveActionRepository) CompleteCorrectiveAction(ctx context.Context, id int, note string) error {
+	args := m.Called(ctx, id, note)
+	return args.Error(0)
+}
+
+func (m *MockCorrectiveActionRepository) VerifyCorrectiveAction(ctx context.Context, id, userID int) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+// Seeded findings reference data
+var (
+	findingReferenceTypes = []types.ReferenceType{
+		{ID: 4, Name: "findings.type_id"},
+		{ID: 5, Name: "findings.severity_id"},
+		{ID: 6, Name: "findings.status_id"},
+	}
+	findingReferenceValues = []types.ReferenceValue{
+		{ID: 20, TypeID: 4, Code: "NON_COMPLIANCE", IsActive: true},
+		{ID: 21, TypeID: 4, Code: "OBSERVATION", IsActive: true},
+		{ID: 25, TypeID: 5, Code: "CRITICAL", IsActive: true},
+		{ID: 26, TypeID: 5, Code: "MAJOR", IsActive: true},
+		{ID: 30, TypeID: 6, Code: "OPEN", IsActive: true},
+		{ID: 31, TypeID: 6, Code: "IN_PROGRESS", IsActive: true},
+		{ID: 32, TypeID: 6, Code: "PENDING_REVIEW", IsActive: true},
+		{ID: 33, TypeID: 6, Code: "CLOSED", IsActive: true},
+		{ID: 34, TypeID: 6, Code: "WAIVED", IsActive: true},
+	}
+)
+
+type FindingServiceSuite struct {
+	suite.Suite
+	mockRepo           *MockFindingRepository
+	mockActions        *MockCorrectiveActionRepository
+	mockAuditQuestions *MockAuditQuestionRepository
+	mockAuditPlans     *MockAuditPlanService
+	mockAssignments    *MockAuditAssignmentService
+	service            *services.FindingService
+}
+
+func (suite *FindingServiceSuite) SetupTest() {
+	suite.mockRepo = new(MockFindingRepository)
+	suite.mockActions = new(MockCorrectiveActionRepository)
+	suite.mockAuditQuestions = new(MockAuditQuestionRepository)
+	suite.mockAuditPlans = new(MockAuditPlanService)
+	suite.mockAssignments = new(MockAuditAssignmentService)
+
+	referenceRepo := new(MockReferenceDataRepository)
+	referenceRepo.On("GetAllReferenceTypes", mock.Anything).Return(findingReferenceTypes, nil).Maybe()
+	referenceRepo.On("GetAllReferenceValues", mock.Anything).Return(findingReferenceValues, nil).Maybe()
+	referenceData := services.NewReferenceDataService(referenceRepo, nil)
+
+	suite.service = services.NewFindingService(
+		suite.mockRepo, suite.mockActions, suite.mockAuditQuestions, suite.mockAuditPlans, suite.mockAssignments, referenceData, nil,
+	)
+}
+
+func (suite *FindingServiceSuite) TearDownTest() {
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockActions.AssertExpectations(suite.T())
+	suite.mockAuditQuestions.AssertExpectations(suite.T())
+	suite.mockAuditPlans.AssertExpectations(suite.T())
+	suite.mockAssignments.AssertExpectations(suite.T())
+}
+
+func (suite *FindingServiceSuite) expectAudit(statusCode string) {
+	question := types.AuditQuestion{ID: 100, AuditID: 2, QuestionID: 10}
+	suite.mockAuditQuestions.On("GetByIDAuditQuestion", mock.Anything, types.AuditQuestion{ID: 100}).Return(question, nil)
+	plan := types.AuditPlan{ID: 2, StandardID: 1, StatusVal: types.ReferenceValue{Code: statusCode}}
+	suite.mockAuditPlans.On("GetByID", mock.Anything, types.AuditPlan{ID: 2}).Return(plan, nil)
+}
+
+// expectFinding loads finding 9 of audit 2, owned by user 4, in the given status with its actions
+func (suite *FindingServiceSuite) expectFinding(statusID int, actions []types.CorrectiveAction) {
+	finding := types.Finding{
+		ID: 9, AuditID: 2, AuditQuestionID: 100, ResponsibleUserID: 4,
+		TypeVal: types.ReferenceValue{ID: 20}, SeverityVal: types.ReferenceValue{ID: 26}, StatusVal: types.ReferenceValue{ID: statusID},
+	}
+	suite.mockRepo.On("GetByIDFinding", mock.Anything, mock.MatchedBy(func(f types.Finding) bool { return f.ID == 9 })).Return(finding, nil)
+	suite.mockActions.On("GetByFindingIDCorrectiveActions", mock.Anything, 9).Return(actions, nil).Maybe()
+}
+
+func findingForm() types.Finding {
+	return types.Finding{
+		AuditQuestionID:   100,
+		TypeVal:           types.ReferenceValue{ID: 20},
+		SeverityVal:       types.ReferenceValue{ID: 26},
+		Description:       "Management review minutes are not signed",
+		DueDate:           time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC),
+		ResponsibleUserID: 4,
+		CreatedBy:         3,
+	}
+}
+
+func (suite *FindingServiceSuite) TestCreate_RaisesOpenFinding() {
+	ctx := context.Background()
+	suite.expectAudit("IN_PROGRESS")
+	suite.mockAssignments.On("CheckTeamMember", ctx, 2, 3).Return(nil)
+
+	stored := findingForm()
+	stored.ID = 9
+	stored.AuditID = 2
+	stored.QuestionID = 10
+	stored.StatusVal = types.ReferenceValue{ID: 30}
+	suite.mockRepo.On("CreateFinding", ctx, mock.MatchedBy(func(f types.Finding) bool {
+		return f.AuditID == 2 && f.QuestionID == 10 && f.StatusVal.ID == 30 && f.SeverityVal.Code == "MAJOR"
+	})).Return(stored, nil)
+
+	created, err := suite.service.Create(ctx, findingForm())
+
+	suite.NoError(err)
+	suite.Equal("OPEN", created.StatusVal.Code)
+	suite.Equal("NON_COMPLIANCE", created.TypeVal.Code)
+}
+
+func (suite *FindingServiceSuite) TestCreate_ScheduledAudit_ReturnsConflict() {
+	suite.expectAudit("SCHEDULED")
+
+	_, err := suite.service.Create(context.Background(), findingForm())
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (suite *FindingServiceSuite) TestCreate_NotOnTeam_ReturnsForbidden() {
+	suite.expectAudit("REVIEW")
+	suite.mockAssignments.On("CheckTeamMember", mock.Anything, 2, 3).
+		Return(custom_errors.Forbidden(context.Background(), "user 3 is not on the team of audit plan 2"))
+
+	_, err := suite.service.Create(context.Background(), findingForm())
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeForbidden))
+	suite.mockRepo.AssertNotCalled(suite.T(), "CreateFinding", mock.Anything, mock.Anything)
+}
+
+func (suite *FindingServiceSuite) TestCreate_TypeAsSeverity_ReturnsInvalidData() {
+	suite.expectAudit("IN_PROGRESS")
+	suite.mockAssignments.On("CheckTeamMember", mock.Anything, 2, 3).Return(nil)
+
+	finding := findingForm()
+	finding.SeverityVal = types.ReferenceValue{ID: 21}
+
+	_, err := suite.service.Create(context.Background(), finding)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+}
+
+func (suite *FindingServiceSuite) TestGetAll_ResolvesFilterCodes() {
+	ctx := context.Background()
+	suite.mockRepo.On("GetAllFindings", ctx, types.FindingFilter{AuditID: 2, Severity: "CRITICAL", SeverityID: 25, Status: "OPEN", StatusID: 30}).
+		Return([]types.Finding{{ID: 9, TypeVal: types.ReferenceValue{ID: 20}, SeverityVal: types.ReferenceValue{ID: 25}, StatusVal: types.ReferenceValue{ID: 30}}}, nil)
+
+	findings, err := suite.service.GetAll(ctx, types.FindingFilter{AuditID: 2, Severity: "CRITICAL", Status: "OPEN"})
+
+	suite.NoError(err)
+	suite.Len(findings, 1)
+	suite.Equal("CRITICAL", findings[0].SeverityVal.Code)
+}
+
+func (suite *FindingServiceSuite) TestGetAll_NarrowsToAuditsOfSignedInUser() {
+	ctx := signedIn(7, services.UserRoleSupport)
+	suite.mockRepo.On("GetAllFindings", ctx, types.FindingFilter{AuditorID: 7}).Return([]types.Finding{}, nil)
+
+	_, err := suite.service.GetAll(ctx, types.FindingFilter{})
+
+	suite.NoError(err)
+}
+
+func (suite *FindingServiceSuite) TestGetAll_ClientGetsFindingsTheyFollowUp() {
+	ctx := signedIn(20, services.UserRoleClient)
+	suite.mockRepo.On("GetAllFindings", ctx, types.FindingFilter{FollowUpUserID: 20}).Return([]types.Finding{}, nil)
+
+	_, err := suite.service.GetAll(ctx, types.FindingFilter{})
+
+	suite.NoError(err)
+}
+
+func (suite *FindingServiceSuite) TestGetAll_OtherAuditor_ReturnsForbidden() {
+	ctx := signedIn(7, services.UserRoleLead)
+
+	_, err := suite.service.GetAll(ctx, types.FindingFilter{AuditorID: 8})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeForbidden))
+	suite.mockRepo.AssertNotCalled(suite.T(), "GetAllFindings", mock.Anything, mock.Anything)
+}
+
+func (suite *FindingServiceSuite) TestGetAll_AdminSeesAllAudits() {
+	ctx := signedIn(1, services.UserRoleAdmin)
+	suite.mockRepo.On("GetAllFindings", ctx, types.FindingFilter{}).Return([]types.Finding{}, nil)
+
+	_, err := suite.service.GetAll(ctx, types.FindingFilter{})
+
+	suite.NoError(err)
+}
+
+func (suite *FindingServiceSuite) TestGetAll_UnknownSeverity_ReturnsInvalidData() {
+	_, err := suite.service.GetAll(context.Background(), types.FindingFilter{Severity: "URGENT"})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+}
+
+func (suite *FindingServiceSuite) TestGetOverdue_SplitsByDueDate() {
+	ctx := context.Background()
+	asOf := time.Date(2026, 10, 17, 15, 30, 0, 0, time.UTC)
+	overdue := types.Finding{ID: 8, DueDate: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), TypeVal: types.ReferenceValue{ID: 20}, SeverityVal: types.ReferenceValue{ID: 25}, StatusVal: types.ReferenceValue{ID: 31}}
+	dueToday := types.Finding{ID: 9, DueDate: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC), TypeVal: types.ReferenceValue{ID: 20}, SeverityVal: types.ReferenceValue{ID: 25}, StatusVal: types.ReferenceValue{ID: 30}}
+	suite.mockRepo.On("GetAllFindings", ctx, types.FindingFilter{StatusIDs: []int{30, 31, 32}, DueTo: time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC)}).
+		Return([]types.Finding{overdue, dueToday}, nil)
+
+	report, err := suite.service.GetOverdue(ctx, asOf, 7)
+
+	suite.NoError(err)
+	suite.Len(report.Overdue, 1)
+	suite.Equal(8, report.Overdue[0].ID)
+	suite.Len(report.DueSoon, 1)
+	suite.Equal(9, report.DueSoon[0].ID)
+}
+
+func (suite *FindingServiceSuite) TestDelete_InProgressFinding_ReturnsConflict() {
+	suite.expectFinding(31, []types.CorrectiveAction{})
+
+	err := suite.service.Delete(context.Background(), types.Finding{ID: 9})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+	suite.mockRepo.AssertNotCalled(suite.T(), "DeleteFinding", mock.Anything, mock.Anything)
+}
+
+func (suite *FindingServiceSuite) TestChangeStatus_SkipsLifecycle_ReturnsConflict() {
+	suite.expectFinding(30, []types.CorrectiveAction{})
+
+	_, err := suite.service.ChangeStatus(context.Background(), types.Finding{ID: 9}, "CLOSED", 3)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateFindingStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *FindingServiceSuite) TestChangeStatus_OwnerStartsWork() {
+	ctx := context.Background()
+	suite.expectFinding(30, []types.CorrectiveAction{})
+	suite.mockRepo.On("UpdateFindingStatus", ctx, 9, 30, 31, "").Return(nil)
+
+	_, err := suite.service.ChangeStatus(ctx, types.Finding{ID: 9}, "IN_PROGRESS", 4)
+
+	suite.NoError(err)
+	suite.mockAssignments.AssertNotCalled(suite.T(), "CheckTeamMember", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *FindingServiceSuite) TestChangeStatus_CloseWithUnverifiedAction_ReturnsConflict() {
+	done := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	suite.expectFinding(32, []types.CorrectiveAction{
+		{ID: 1, FindingID: 9, CompletedAt: &done, VerifiedAt: &done},
+		{ID: 2, FindingID: 9, CompletedAt: &done},
+	})
+	suite.mockAssignments.On("CheckTeamMember", mock.Anything, 2, 3).Return(nil)
+
+	_, err := suite.service.ChangeStatus(context.Background(), types.Finding{ID: 9}, "CLOSED", 3)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateFindingStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *FindingServiceSuite) TestChangeStatus_CloseWithVerifiedActions() {
+	ctx := context.Background()
+	done := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	suite.expectFinding(32, []types.CorrectiveAction{{ID: 1, FindingID: 9, CompletedAt: &done, VerifiedAt: &done}})
+	suite.mockAssignments.On("CheckTeamMember", ctx, 2, 3).Return(nil)
+	suite.mockRepo.On("UpdateFindingStatus", ctx, 9, 32, 33, "").Return(nil)
+
+	_, err := suite.service.ChangeStatus(ctx, types.Finding{ID: 9}, "CLOSED", 3)
+
+	suite.NoError(err)
+}
+
+func (suite *FindingServiceSuite) TestChangeStatus_OwnerCannotClose_ReturnsForbidden() {
+	done := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	suite.expectFinding(32, []types.CorrectiveAction{{ID: 1, FindingID: 9, CompletedAt: &done, VerifiedAt: &done}})
+	suite.mockAssignments.On("CheckTeamMember", mock.Anything, 2, 4).
+		Return(custom_errors.Forbidden(context.Background(), "user 4 is not on the team of audit plan 2"))
+
+	_, err := suite.service.ChangeStatus(context.Background(), types.Finding{ID: 9}, "CLOSED", 4)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeForbidden))
+}
+
+func (suite *FindingServiceSuite) TestChangeStatus_WaiveWithoutReason_ReturnsInvalidData() {
+	suite.expectFinding(31, []types.CorrectiveAction{})
+	suite.mockAssignments.On("CheckTeamMember", mock.Anything, 2, 3).Return(nil)
+
+	_, err := suite.service.ChangeStatus(context.Background(), types.Finding{ID: 9, StatusReason: "  "}, "WAIVED", 3)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+}
+
+func (suite *FindingServiceSuite) TestChangeStatus_WaiveStoresReason() {
+	ctx := context.Background()
+	suite.expectFinding(31, []types.CorrectiveAction{})
+	suite.mockAssignments.On("CheckTeamMember", ctx, 2, 3).Return(nil)
+	suite.mockRepo.On("UpdateFindingStatus", ctx, 9, 31, 34, "Process retired").Return(nil)
+
+	_, err := suite.service.ChangeStatus(ctx, types.Finding{ID: 9, StatusReason: "Process retired"}, "WAIVED", 3)
+
+	suite.NoError(err)
+}
+
+func (suite *FindingServiceSuite) TestChangeStatus_ReviewWithoutActions_ReturnsConflict() {
+	suite.expectFinding(31, []types.CorrectiveAction{})
+
+	_, err := suite.service.ChangeStatus(context.Background(), types.Finding{ID: 9}, "PENDING_REVIEW", 4)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (suite *FindingServiceSuite) TestVerifyCorrectiveAction_ClosedFinding_ReturnsConflict() {
+	ctx := context.Background()
+	suite.mockActions.On("GetByIDCorrectiveAction", ctx, mock.MatchedBy(func(a types.CorrectiveAction) bool { return a.ID == 1 })).
+		Return(types.CorrectiveAction{ID: 1, FindingID: 9}, nil)
+	suite.expectFinding(33, nil)
+
+	_, err := suite.service.VerifyCorrectiveAction(ctx, types.CorrectiveAction{ID: 1}, 3)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+	suite.mockActions.AssertNotCalled(suite.T(), "VerifyCorrectiveAction", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *FindingServiceSuite) TestVerifyCorrectiveAction_ByTeamMember() {
+	ctx := context.Background()
+	suite.mockActions.On("GetByIDCorrectiveAction", ctx, mock.MatchedBy(func(a types.CorrectiveAction) bool { return a.ID == 1 })).
+		Return(types.CorrectiveAction{ID: 1, FindingID: 9}, nil)
+	suite.expectFinding(32, nil)
+	suite.mockAssignments.On("CheckTeamMember", ctx, 2, 3).Return(nil)
+	suite.mockActions.On("VerifyCorrectiveAction", ctx, 1, 3).Return(nil)
+
+	_, err := suite.service.VerifyCorrectiveAction(ctx, types.CorrectiveAction{ID: 1}, 3)
+
+	suite.NoError(err)
+}
+
+func TestFindingServiceSuite(t *testing.T) {
+	suite.Run(t, new(FindingServiceSuite))
+}
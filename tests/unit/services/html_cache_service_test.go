@@ -117,6 +117,26 @@ func (m *MockRequirementRepository) UpdateRequirement(ctx context.Context, requi
 	return args.Get(0).(types.Requirement), args.Error(1)
 }
 
+func (m *MockRequirementRepository) GetByStandardIDRequirements(ctx context.Context, standardID int) ([]types.Requirement, error) {
+	args := m.Called(ctx, standardID)
+	return args.Get(0).([]types.Requirement), args.Error(1)
+}
+
+func (m *MockRequirementRepository) CreateRequirement(ctx context.Context, requirement types.Requirement) (types.Requirement, error) {
+	args := m.Called(ctx, requirement)
+	return args.Get(0).(types.Requirement), args.Error(1)
+}
+
+func (m *MockRequirementRepository) MoveRequirement(ctx context.Context, requirement types.Requirement, position int) (types.Requirement, error) {
+	args := m.Called(ctx, requirement, position)
+	return args.Get(0).(types.Requirement), args.Error(1)
+}
+
+func (m *MockRequirementRepository) ReorderRequirements(ctx context.Context, standardID, parentID int, requirementIDs []int) error {
+	args := m.Called(ctx, standardID, parentID, requirementIDs)
+	return args.Error(0)
+}
+
 // Instead of mocking EventBus, we'll use the real one
 // and just verify the results of its operations
 
@@ -0,0 +1,120 @@
+package services_test
+
+import (
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// MockFindingService only implements what the overdue finding job uses
+type MockFindingService struct {
+	services.FindingServiceInterface
+	mock.Mock
+}
+
+func (m *MockFindingService) GetOverdue(ctx context.Context, asOf time.Time, warningDays int) (types.OverdueFindingReport, error) {
+	args := m.Called(ctx, asOf, warningDays)
+	return args.Get(0).(types.OverdueFindingReport), args.Error(1)
+}
+
+type OverdueFindingJobSuite struct {
+	suite.Suite
+	mockFindings *MockFindingService
+	now          time.Time
+	mu           sync.Mutex
+	published    []events.Event
+	job          *services.OverdueFindingJob
+}
+
+func (suite *OverdueFindingJobSuite) SetupTest() {
+	suite.mockFindings = new(MockFindingService)
+	suite.now = time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	suite.published = nil
+
+	eventBus := events.NewEventBus()
+	record := func(ctx context.Context, event events.Event) error {
+		suite.mu.Lock()
+		defer suite.mu.Unlock()
+		suite.published = append(suite.published, event)
+		return nil
+	}
+	eventBus.Subscribe(events.FindingDueSoon, record)
+	eventBus.Subscribe(events.FindingOverdue, record)
+
+	suite.job = services.NewOverdueFindingJob(suite.mockFindings, eventBus, time.Hour, 7, func() time.Time { return suite.now })
+}
+
+func dueReport(asOf time.Time, overdue, dueSoon []types.Finding) types.OverdueFindingReport {
+	return types.OverdueFindingReport{AsOf: asOf, WarningDays: 7, Overdue: overdue, DueSoon: dueSoon}
+}
+
+func (suite *OverdueFindingJobSuite) TestCheck_PublishesOncePerState() {
+	ctx := context.Background()
+	finding := types.Finding{ID: 9, AuditID: 2, ResponsibleUserID: 4, DueDate: time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)}
+	suite.mockFindings.On("GetOverdue", ctx, suite.now, 7).Return(dueReport(suite.now, nil, []types.Finding{finding}), nil).Twice()
+
+	_, err := suite.job.Check(ctx)
+	suite.NoError(err)
+	_, err = suite.job.Check(ctx)
+	suite.NoError(err)
+
+	suite.Require().Len(suite.published, 1)
+	suite.Equal(events.FindingDueSoon, suite.published[0].Type)
+	suite.Equal(3, suite.published[0].Payload.(events.FindingDuePayload).DaysLeft)
+
+	// Four days later the same finding is overdue and reported again
+	suite.now = suite.now.AddDate(0, 0, 4)
+	suite.mockFindings.On("GetOverdue", ctx, suite.now, 7).Return(dueReport(suite.now, []types.Finding{finding}, nil), nil).Once()
+
+	_, err = suite.job.Check(ctx)
+
+	suite.NoError(err)
+	suite.Require().Len(suite.published, 2)
+	suite.Equal(events.FindingOverdue, suite.published[1].Type)
+	suite.Equal(-1, suite.published[1].Payload.(events.FindingDuePayload).DaysLeft)
+}
+
+func (suite *OverdueFindingJobSuite) TestCheck_Error_PublishesNothing() {
+	ctx := context.Background()
+	suite.mockFindings.On("GetOverdue", ctx, suite.now, 7).Return(types.OverdueFindingReport{}, errors.New("database is down"))
+
+	_, err := suite.job.Check(ctx)
+
+	suite.Error(err)
+	suite.Empty(suite.published)
+}
+
+func (suite *OverdueFindingJobSuite) TestRun_StopsWhenContextIsCancelled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	checked := make(chan struct{})
+	suite.mockFindings.On("GetOverdue", mock.Anything, suite.now, 7).
+		Run(func(mock.Arguments) { close(checked) }).
+		Return(dueReport(suite.now, nil, nil), nil).Once()
+
+	done := make(chan struct{})
+	go func() {
+		suite.job.Run(ctx)
+		close(done)
+	}()
+
+	<-checked
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		suite.Fail("Run did not return after the context was cancelled")
+	}
+}
+
+func TestOverdueFindingJobSuite(t *testing.T) {
+	suite.Run(t, new(OverdueFindingJobSuite))
+}
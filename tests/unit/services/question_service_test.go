@@ -0,0 +1,114 @@
+package services_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type QuestionServiceSuite struct {
+	suite.Suite
+	mockRepo            *MockQuestionRepository
+	mockRequirementRepo *MockRequirementRepository
+	published           chan events.EntityChangePayload
+	service             *services.QuestionService
+}
+
+func (suite *QuestionServiceSuite) SetupTest() {
+	suite.mockRepo = new(MockQuestionRepository)
+	suite.mockRequirementRepo = new(MockRequirementRepository)
+	eventBus := events.NewEventBus()
+	suite.published = make(chan events.EntityChangePayload, 10)
+	eventBus.Subscribe(events.EntityChanged, func(ctx context.Context, event events.Event) error {
+		payload, err := events.GetEntityChangePayload(event)
+		if err == nil {
+			suite.published <- payload
+		}
+		return err
+	})
+
+	suite.service = services.NewQuestionService(suite.mockRepo, suite.mockRequirementRepo, eventBus)
+}
+
+func (suite *QuestionServiceSuite) TearDownTest() {
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockRequirementRepo.AssertExpectations(suite.T())
+}
+
+func (suite *QuestionServiceSuite) nextEvent() events.EntityChangePayload {
+	select {
+	case payload := <-suite.published:
+		return payload
+	case <-time.After(time.Second):
+		suite.FailNow("expected an EntityChanged event")
+		return events.EntityChangePayload{}
+	}
+}
+
+func (suite *QuestionServiceSuite) TestCreate_PublishesEventWithRequirement() {
+	suite.mockRequirementRepo.On("GetByIDRequirement", mock.Anything, types.Requirement{ID: 4}).
+		Return(types.Requirement{ID: 4, StandardID: 1}, nil)
+	suite.mockRepo.On("CreateQuestion", mock.Anything, types.Question{RequirementID: 4, Question: "Is the scope documented?"}).
+		Return(types.Question{ID: 8, RequirementID: 4, Question: "Is the scope documented?", SortOrder: 1}, nil)
+
+	question, err := suite.service.Create(context.Background(), types.Question{RequirementID: 4, Question: "Is the scope documented?"})
+
+	suite.NoError(err)
+	suite.Equal(8, question.ID)
+
+	payload := suite.nextEvent()
+	suite.Equal(events.EntityQuestion, payload.EntityType)
+	suite.Equal(8, payload.EntityID)
+	suite.Equal(events.ChangeCreated, payload.ChangeType)
+	suite.Equal(events.EntityRequirement, payload.ParentType)
+	suite.Equal(4, payload.ParentID)
+}
+
+func (suite *QuestionServiceSuite) TestCreate_UnknownRequirement_ReturnsNotFound() {
+	suite.mockRequirementRepo.On("GetByIDRequirement", mock.Anything, types.Requirement{ID: 9}).
+		Return(types.Requirement{}, custom_errors.NotFound(context.Background(), "Requirement"))
+
+	_, err := suite.service.Create(context.Background(), types.Question{RequirementID: 9, Question: "Is the scope documented?"})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+	suite.mockRepo.AssertNotCalled(suite.T(), "CreateQuestion", mock.Anything, mock.Anything)
+}
+
+func (suite *QuestionServiceSuite) TestDelete_PublishesDeletedEvent() {
+	suite.mockRepo.On("GetByIDQuestion", mock.Anything, types.Question{ID: 8}).
+		Return(types.Question{ID: 8, RequirementID: 4}, nil)
+	suite.mockRepo.On("DeleteQuestion", mock.Anything, 8).Return(nil)
+
+	err := suite.service.Delete(context.Background(), types.Question{ID: 8})
+
+	suite.NoError(err)
+	payload := suite.nextEvent()
+	suite.Equal(events.ChangeDeleted, payload.ChangeType)
+	suite.Equal(4, payload.ParentID)
+}
+
+func (suite *QuestionServiceSuite) TestReorder_PublishesRequirementEvent() {
+	suite.mockRequirementRepo.On("GetByIDRequirement", mock.Anything, types.Requirement{ID: 4}).
+		Return(types.Requirement{ID: 4, StandardID: 1}, nil)
+	suite.mockRepo.On("ReorderQuestions", mock.Anything, 4, []int{9, 8}).Return(nil)
+
+	err := suite.service.Reorder(context.Background(), 4, []int{9, 8})
+
+	suite.NoError(err)
+	payload := suite.nextEvent()
+	suite.Equal(events.EntityRequirement, payload.EntityType)
+	suite.Equal(4, payload.EntityID)
+	suite.Equal(events.EntityStandard, payload.ParentType)
+	suite.Equal(1, payload.ParentID)
+}
+
+func TestQuestionService(t *testing.T) {
+	suite.Run(t, new(QuestionServiceSuite))
+}
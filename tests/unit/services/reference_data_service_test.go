@@ -0,0 +1,163 @@
+package services_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockReferenceDataRepository struct {
+	mock.Mock
+}
+
+func (m *MockReferenceDataRepository) GetAllReferenceTypes(ctx context.Context) ([]types.ReferenceType, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]types.ReferenceType), args.Error(1)
+}
+
+func (m *MockReferenceDataRepository) GetAllReferenceValues(ctx context.Context) ([]types.ReferenceValue, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]types.ReferenceValue), args.Error(1)
+}
+
+func (m *MockReferenceDataRepository) GetByIDReferenceValue(ctx context.Context, value types.ReferenceValue) (types.ReferenceValue, error) {
+	args := m.Called(ctx, value)
+	return args.Get(0).(types.ReferenceValue), args.Error(1)
+}
+
+func (m *MockReferenceDataRepository) CreateReferenceValue(ctx context.Context, value types.ReferenceValue) (types.ReferenceValue, error) {
+	args := m.Called(ctx, value)
+	return args.Get(0).(types.ReferenceValue), args.Error(1)
+}
+
+func (m *MockReferenceDataRepository) UpdateReferenceValue(ctx context.Context, value types.ReferenceValue) (types.ReferenceValue, error) {
+	args := m.Called(ctx, value)
+	return args.Get(0).(types.ReferenceValue), args.Error(1)
+}
+
+func (m *MockReferenceDataRepository) DeleteReferenceValue(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// Subset of the seeded reference data
+var (
+	seededReferenceTypes = []types.ReferenceType{
+		{ID: 7, Name: "evidence.type_id"},
+		{ID: 11, Name: "drafts.type_id"},
+		{ID: 12, Name: "drafts.status_id"},
+	}
+	seededReferenceValues = []types.ReferenceValue{
+		{ID: 36, TypeID: 7, Code: "DOCUMENT", IsActive: true},
+		{ID: 41, TypeID: 7, Code: "ANALYSIS", IsActive: false},
+		{ID: 59, TypeID: 11, Code: "STANDARD", IsActive: true},
+		{ID: 61, TypeID: 12, Code: "DRAFT_DRAFT", IsActive: true},
+		{ID: 62, TypeID: 12, Code: "DRAFT_PENDING_APPROVAL", IsActive: true},
+		{ID: 64, TypeID: 12, Code: "DRAFT_PUBLISHED", IsActive: true},
+		{ID: 65, TypeID: 11, Code: "AUDIT_CONTENT", IsActive: true},
+	}
+)
+
+type ReferenceDataServiceSuite struct {
+	suite.Suite
+	mockRepo *MockReferenceDataRepository
+	eventBus *events.EventBus
+	service  *services.ReferenceDataService
+}
+
+func (suite *ReferenceDataServiceSuite) SetupTest() {
+	suite.mockRepo = new(MockReferenceDataRepository)
+	suite.eventBus = events.NewEventBus()
+	suite.service = services.NewReferenceDataService(suite.mockRepo, suite.eventBus)
+}
+
+func (suite *ReferenceDataServiceSuite) TearDownTest() {
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ReferenceDataServiceSuite) expectLoad(times int) {
+	suite.mockRepo.On("GetAllReferenceTypes", mock.Anything).Return(seededReferenceTypes, nil).Times(times)
+	suite.mockRepo.On("GetAllReferenceValues", mock.Anything).Return(seededReferenceValues, nil).Times(times)
+}
+
+func (suite *ReferenceDataServiceSuite) TestResolve_ByTypeAndCode_LoadsOnce() {
+	suite.expectLoad(1)
+
+	status, err := suite.service.Resolve(context.Background(), "drafts.status_id", "DRAFT_PENDING_APPROVAL")
+	suite.NoError(err)
+	suite.Equal(62, status.ID)
+
+	draftType, err := suite.service.ResolveID(context.Background(), "drafts.type_id", "AUDIT_CONTENT")
+	suite.NoError(err)
+	suite.Equal(65, draftType)
+}
+
+func (suite *ReferenceDataServiceSuite) TestResolve_CodeOfOtherType_ReturnsNotFound() {
+	suite.expectLoad(1)
+
+	_, err := suite.service.Resolve(context.Background(), "drafts.type_id", "DRAFT_PENDING_APPROVAL")
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func (suite *ReferenceDataServiceSuite) TestValidate_RejectsOtherTypeAndInactive() {
+	suite.expectLoad(1)
+
+	_, err := suite.service.Validate(context.Background(), "evidence.type_id", 62)
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+
+	_, err = suite.service.Validate(context.Background(), "evidence.type_id", 41)
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+
+	value, err := suite.service.Validate(context.Background(), "evidence.type_id", 36)
+	suite.NoError(err)
+	suite.Equal("DOCUMENT", value.Code)
+}
+
+func (suite *ReferenceDataServiceSuite) TestReferenceValueEvent_InvalidatesCache() {
+	suite.expectLoad(2)
+
+	_, err := suite.service.GetTypes(context.Background())
+	suite.NoError(err)
+
+	suite.eventBus.Publish(context.Background(), events.NewReferenceValueEvent(36, events.ChangeUpdated, 7, "", nil))
+
+	referenceTypes, err := suite.service.GetTypes(context.Background())
+	suite.NoError(err)
+	suite.Len(referenceTypes, 3)
+}
+
+func (suite *ReferenceDataServiceSuite) TestCreate_DuplicateCode_ReturnsConflict() {
+	suite.expectLoad(1)
+
+	_, err := suite.service.Create(context.Background(), "evidence.type_id", types.ReferenceValue{Code: "DOCUMENT", Name: "Document"})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+	suite.mockRepo.AssertNotCalled(suite.T(), "CreateReferenceValue", mock.Anything, mock.Anything)
+}
+
+func (suite *ReferenceDataServiceSuite) TestCreate_NewCode_ReloadsCache() {
+	suite.expectLoad(2)
+	created := types.ReferenceValue{ID: 66, TypeID: 7, Code: "SAMPLE", Name: "Sample", IsActive: true, CreatedAt: time.Now()}
+	suite.mockRepo.On("CreateReferenceValue", mock.Anything, types.ReferenceValue{TypeID: 7, Code: "SAMPLE", Name: "Sample", IsActive: true}).
+		Return(created, nil)
+
+	value, err := suite.service.Create(context.Background(), "evidence.type_id", types.ReferenceValue{Code: "SAMPLE", Name: "Sample", IsActive: true})
+	suite.NoError(err)
+	suite.Equal(66, value.ID)
+
+	// The write dropped the cache, so the next read goes back to the database
+	_, err = suite.service.GetType(context.Background(), "evidence.type_id")
+	suite.NoError(err)
+}
+
+func TestReferenceDataService(t *testing.T) {
+	suite.Run(t, new(ReferenceDataServiceSuite))
+}
@@ -0,0 +1,114 @@
+package services_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockRequirementLevelRepository struct {
+	mock.Mock
+}
+
+func (m *MockRequirementLevelRepository) GetByIDRequirementLevel(ctx context.Context, level types.RequirementLevel) (types.RequirementLevel, error) {
+	args := m.Called(ctx, level)
+	return args.Get(0).(types.RequirementLevel), args.Error(1)
+}
+
+func (m *MockRequirementLevelRepository) GetByStandardIDRequirementLevels(ctx context.Context, standardID int) ([]types.RequirementLevel, error) {
+	args := m.Called(ctx, standardID)
+	return args.Get(0).([]types.RequirementLevel), args.Error(1)
+}
+
+func (m *MockRequirementLevelRepository) CreateRequirementLevel(ctx context.Context, level types.RequirementLevel) (types.RequirementLevel, error) {
+	args := m.Called(ctx, level)
+	return args.Get(0).(types.RequirementLevel), args.Error(1)
+}
+
+func (m *MockRequirementLevelRepository) UpdateRequirementLevel(ctx context.Context, level types.RequirementLevel) (types.RequirementLevel, error) {
+	args := m.Called(ctx, level)
+	return args.Get(0).(types.RequirementLevel), args.Error(1)
+}
+
+func (m *MockRequirementLevelRepository) DeleteRequirementLevel(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRequirementLevelRepository) CountRequirementsByLevel(ctx context.Context, levelID int) (int, error) {
+	args := m.Called(ctx, levelID)
+	return args.Int(0), args.Error(1)
+}
+
+type RequirementLevelServiceSuite struct {
+	suite.Suite
+	mockRepo         *MockRequirementLevelRepository
+	mockStandardRepo *MockStandardRepository
+	service          *services.RequirementLevelService
+}
+
+func (suite *RequirementLevelServiceSuite) SetupTest() {
+	suite.mockRepo = new(MockRequirementLevelRepository)
+	suite.mockStandardRepo = new(MockStandardRepository)
+	suite.service = services.NewRequirementLevelService(suite.mockRepo, suite.mockStandardRepo)
+}
+
+func (suite *RequirementLevelServiceSuite) TearDownTest() {
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockStandardRepo.AssertExpectations(suite.T())
+}
+
+func (suite *RequirementLevelServiceSuite) TestCreate_DuplicateOrder_ReturnsConflict() {
+	suite.mockStandardRepo.On("GetByIDStandard", mock.Anything, types.Standard{ID: 1}).Return(types.Standard{ID: 1}, nil)
+	suite.mockRepo.On("GetByStandardIDRequirementLevels", mock.Anything, 1).Return(standardLevels, nil)
+
+	_, err := suite.service.Create(context.Background(), types.RequirementLevel{StandardID: 1, LevelOrder: 2, LevelName: "Section"})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+	suite.mockRepo.AssertNotCalled(suite.T(), "CreateRequirementLevel", mock.Anything, mock.Anything)
+}
+
+func (suite *RequirementLevelServiceSuite) TestCreate_NewOrder_CreatesLevel() {
+	level := types.RequirementLevel{StandardID: 1, LevelOrder: 4, LevelName: "Subrequirement"}
+	suite.mockStandardRepo.On("GetByIDStandard", mock.Anything, types.Standard{ID: 1}).Return(types.Standard{ID: 1}, nil)
+	suite.mockRepo.On("GetByStandardIDRequirementLevels", mock.Anything, 1).Return(standardLevels, nil)
+	suite.mockRepo.On("CreateRequirementLevel", mock.Anything, level).
+		Return(types.RequirementLevel{ID: 4, StandardID: 1, LevelOrder: 4, LevelName: "Subrequirement"}, nil)
+
+	created, err := suite.service.Create(context.Background(), level)
+
+	suite.NoError(err)
+	suite.Equal(4, created.ID)
+}
+
+func (suite *RequirementLevelServiceSuite) TestUpdate_ChangeOrderOfUsedLevel_ReturnsConflict() {
+	suite.mockRepo.On("GetByIDRequirementLevel", mock.Anything, types.RequirementLevel{ID: 2, LevelOrder: 5, LevelName: "Subclause"}).
+		Return(standardLevels[1], nil)
+	suite.mockRepo.On("CountRequirementsByLevel", mock.Anything, 2).Return(3, nil)
+
+	_, err := suite.service.Update(context.Background(), types.RequirementLevel{ID: 2, LevelOrder: 5, LevelName: "Subclause"})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (suite *RequirementLevelServiceSuite) TestUpdate_RenameOnly_SkipsUsageCheck() {
+	suite.mockRepo.On("GetByIDRequirementLevel", mock.Anything, types.RequirementLevel{ID: 2, LevelOrder: 2, LevelName: "Section"}).
+		Return(standardLevels[1], nil)
+	suite.mockRepo.On("UpdateRequirementLevel", mock.Anything, types.RequirementLevel{ID: 2, StandardID: 1, LevelOrder: 2, LevelName: "Section"}).
+		Return(types.RequirementLevel{ID: 2, StandardID: 1, LevelOrder: 2, LevelName: "Section"}, nil)
+
+	updated, err := suite.service.Update(context.Background(), types.RequirementLevel{ID: 2, LevelOrder: 2, LevelName: "Section"})
+
+	suite.NoError(err)
+	suite.Equal("Section", updated.LevelName)
+	suite.mockRepo.AssertNotCalled(suite.T(), "CountRequirementsByLevel", mock.Anything, mock.Anything)
+}
+
+func TestRequirementLevelService(t *testing.T) {
+	suite.Run(t, new(RequirementLevelServiceSuite))
+}
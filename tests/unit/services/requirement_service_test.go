@@ -0,0 +1,242 @@
+package services_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/events"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RequirementServiceSuite struct {
+	suite.Suite
+	mockRepo         *MockRequirementRepository
+	mockStandardRepo *MockStandardRepository
+	mockLevelRepo    *MockRequirementLevelRepository
+	eventBus         *events.EventBus
+	published        chan events.EntityChangePayload
+	service          *services.RequirementService
+}
+
+func (suite *RequirementServiceSuite) SetupTest() {
+	suite.mockRepo = new(MockRequirementRepository)
+	suite.mockStandardRepo = new(MockStandardRepository)
+	suite.mockLevelRepo = new(MockRequirementLevelRepository)
+	suite.eventBus = events.NewEventBus()
+	suite.published = make(chan events.EntityChangePayload, 10)
+	suite.eventBus.Subscribe(events.EntityChanged, func(ctx context.Context, event events.Event) error {
+		payload, err := events.GetEntityChangePayload(event)
+		if err == nil {
+			suite.published <- payload
+		}
+		return err
+	})
+
+	suite.service = services.NewRequirementService(suite.mockRepo, suite.mockStandardRepo, suite.mockLevelRepo, suite.eventBus)
+}
+
+func (suite *RequirementServiceSuite) TearDownTest() {
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockStandardRepo.AssertExpectations(suite.T())
+	suite.mockLevelRepo.AssertExpectations(suite.T())
+}
+
+func (suite *RequirementServiceSuite) nextEvent() events.EntityChangePayload {
+	select {
+	case payload := <-suite.published:
+		return payload
+	case <-time.After(time.Second):
+		suite.FailNow("expected an EntityChanged event")
+		return events.EntityChangePayload{}
+	}
+}
+
+// Standard 1 tree:
+//
+//	10
+//	├── 11
+//	│   └── 13
+//	└── 12
+//	20
+var flatRequirements = []types.Requirement{
+	{ID: 10, StandardID: 1, LevelID: 1, ParentID: 0, SortOrder: 1},
+	{ID: 20, StandardID: 1, LevelID: 1, ParentID: 0, SortOrder: 2},
+	{ID: 11, StandardID: 1, LevelID: 2, ParentID: 10, SortOrder: 1},
+	{ID: 12, StandardID: 1, LevelID: 2, ParentID: 10, SortOrder: 2},
+	{ID: 13, StandardID: 1, LevelID: 3, ParentID: 11, SortOrder: 1},
+}
+
+// Standard 1 levels, the ID matches the level_order
+var standardLevels = []types.RequirementLevel{
+	{ID: 1, StandardID: 1, LevelOrder: 1, LevelName: "Clause"},
+	{ID: 2, StandardID: 1, LevelOrder: 2, LevelName: "Subclause"},
+	{ID: 3, StandardID: 1, LevelOrder: 3, LevelName: "Requirement"},
+}
+
+func (suite *RequirementServiceSuite) TestGetTree_NestsChildrenInOrder() {
+	suite.mockStandardRepo.On("GetByIDStandard", mock.Anything, types.Standard{ID: 1}).Return(types.Standard{ID: 1}, nil)
+	suite.mockRepo.On("GetByStandardIDRequirements", mock.Anything, 1).Return(append([]types.Requirement{}, flatRequirements...), nil)
+
+	tree, err := suite.service.GetTree(context.Background(), 1)
+
+	suite.NoError(err)
+	suite.Len(tree, 2)
+	suite.Equal(10, tree[0].ID)
+	suite.Equal(20, tree[1].ID)
+	suite.Len(tree[0].Children, 2)
+	suite.Equal(11, tree[0].Children[0].ID)
+	suite.Equal(13, tree[0].Children[0].Children[0].ID)
+	suite.Empty(tree[1].Children)
+}
+
+func (suite *RequirementServiceSuite) TestGetTree_UnknownStandard_ReturnsNotFound() {
+	suite.mockStandardRepo.On("GetByIDStandard", mock.Anything, types.Standard{ID: 9}).
+		Return(types.Standard{}, custom_errors.NotFound(context.Background(), "Standard"))
+
+	_, err := suite.service.GetTree(context.Background(), 9)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeNotFound))
+}
+
+func (suite *RequirementServiceSuite) TestMove_UnderDescendant_ReturnsConflict() {
+	suite.mockRepo.On("GetByIDRequirement", mock.Anything, types.Requirement{ID: 10}).Return(flatRequirements[0], nil)
+	suite.mockRepo.On("GetByIDRequirement", mock.Anything, types.Requirement{ID: 13}).Return(flatRequirements[4], nil)
+	suite.mockRepo.On("GetByStandardIDRequirements", mock.Anything, 1).Return(flatRequirements, nil)
+
+	_, err := suite.service.Move(context.Background(), types.Requirement{ID: 10}, 13, 0)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+	suite.mockRepo.AssertNotCalled(suite.T(), "MoveRequirement", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *RequirementServiceSuite) TestMove_UnderItself_ReturnsConflict() {
+	suite.mockRepo.On("GetByIDRequirement", mock.Anything, types.Requirement{ID: 11}).Return(flatRequirements[2], nil)
+
+	_, err := suite.service.Move(context.Background(), types.Requirement{ID: 11}, 11, 0)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (suite *RequirementServiceSuite) TestMove_ToOtherStandard_ReturnsConflict() {
+	suite.mockRepo.On("GetByIDRequirement", mock.Anything, types.Requirement{ID: 11}).Return(flatRequirements[2], nil)
+	suite.mockRepo.On("GetByIDRequirement", mock.Anything, types.Requirement{ID: 99}).
+		Return(types.Requirement{ID: 99, StandardID: 2}, nil)
+
+	_, err := suite.service.Move(context.Background(), types.Requirement{ID: 11}, 99, 0)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (suite *RequirementServiceSuite) TestMove_MissingParent_ReturnsInvalidData() {
+	suite.mockRepo.On("GetByIDRequirement", mock.Anything, types.Requirement{ID: 11}).Return(flatRequirements[2], nil)
+	suite.mockRepo.On("GetByIDRequirement", mock.Anything, types.Requirement{ID: 77}).
+		Return(types.Requirement{}, custom_errors.NotFound(context.Background(), "Requirement"))
+
+	_, err := suite.service.Move(context.Background(), types.Requirement{ID: 11}, 77, 0)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+}
+
+func (suite *RequirementServiceSuite) TestMove_ValidParent_PublishesEventWithParent() {
+	moved := types.Requirement{ID: 11, StandardID: 1, LevelID: 2, ParentID: 20, SortOrder: 1}
+	suite.mockRepo.On("GetByIDRequirement", mock.Anything, types.Requirement{ID: 11}).Return(flatRequirements[2], nil)
+	suite.mockRepo.On("GetByIDRequirement", mock.Anything, types.Requirement{ID: 20}).Return(flatRequirements[1], nil)
+	suite.mockRepo.On("GetByStandardIDRequirements", mock.Anything, 1).Return(flatRequirements, nil)
+	suite.mockLevelRepo.On("GetByIDRequirementLevel", mock.Anything, types.RequirementLevel{ID: 2}).Return(standardLevels[1], nil)
+	suite.mockRepo.On("MoveRequirement", mock.Anything, mock.MatchedBy(func(r types.Requirement) bool {
+		return r.ID == 11 && r.ParentID == 20
+	}), 0).Return(moved, nil)
+
+	result, err := suite.service.Move(context.Background(), types.Requirement{ID: 11}, 20, 0)
+
+	suite.NoError(err)
+	suite.Equal(20, result.ParentID)
+
+	payload := suite.nextEvent()
+	suite.Equal(events.EntityRequirement, payload.EntityType)
+	suite.Equal(11, payload.EntityID)
+	suite.Equal(events.ChangeUpdated, payload.ChangeType)
+	suite.Equal(events.EntityStandard, payload.ParentType)
+	suite.Equal(1, payload.ParentID)
+}
+
+func (suite *RequirementServiceSuite) TestMove_ToOtherDepth_ReturnsInvalidData() {
+	suite.mockRepo.On("GetByIDRequirement", mock.Anything, types.Requirement{ID: 12}).Return(flatRequirements[3], nil)
+	suite.mockRepo.On("GetByIDRequirement", mock.Anything, types.Requirement{ID: 11}).Return(flatRequirements[2], nil)
+	suite.mockRepo.On("GetByStandardIDRequirements", mock.Anything, 1).Return(flatRequirements, nil)
+	suite.mockLevelRepo.On("GetByIDRequirementLevel", mock.Anything, types.RequirementLevel{ID: 2}).Return(standardLevels[1], nil)
+
+	_, err := suite.service.Move(context.Background(), types.Requirement{ID: 12}, 11, 0)
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+	suite.mockRepo.AssertNotCalled(suite.T(), "MoveRequirement", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *RequirementServiceSuite) TestCreate_LevelMatchesDepth_CreatesRequirement() {
+	suite.mockStandardRepo.On("GetByIDStandard", mock.Anything, types.Standard{ID: 1}).Return(types.Standard{ID: 1}, nil)
+	suite.mockRepo.On("GetByIDRequirement", mock.Anything, types.Requirement{ID: 11}).Return(flatRequirements[2], nil)
+	suite.mockRepo.On("GetByStandardIDRequirements", mock.Anything, 1).Return(flatRequirements, nil)
+	suite.mockLevelRepo.On("GetByIDRequirementLevel", mock.Anything, types.RequirementLevel{ID: 3}).Return(standardLevels[2], nil)
+	suite.mockRepo.On("CreateRequirement", mock.Anything, mock.Anything).
+		Return(types.Requirement{ID: 14, StandardID: 1, LevelID: 3, ParentID: 11}, nil)
+
+	created, err := suite.service.Create(context.Background(), types.Requirement{StandardID: 1, LevelID: 3, ParentID: 11, Name: "Scope"})
+
+	suite.NoError(err)
+	suite.Equal(14, created.ID)
+	suite.nextEvent()
+}
+
+func (suite *RequirementServiceSuite) TestCreate_LevelOfOtherDepth_ReturnsInvalidData() {
+	suite.mockStandardRepo.On("GetByIDStandard", mock.Anything, types.Standard{ID: 1}).Return(types.Standard{ID: 1}, nil)
+	suite.mockLevelRepo.On("GetByIDRequirementLevel", mock.Anything, types.RequirementLevel{ID: 2}).Return(standardLevels[1], nil)
+
+	_, err := suite.service.Create(context.Background(), types.Requirement{StandardID: 1, LevelID: 2, Name: "Scope"})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+	suite.mockRepo.AssertNotCalled(suite.T(), "CreateRequirement", mock.Anything, mock.Anything)
+}
+
+func (suite *RequirementServiceSuite) TestCreate_LevelOfOtherStandard_ReturnsInvalidData() {
+	suite.mockStandardRepo.On("GetByIDStandard", mock.Anything, types.Standard{ID: 1}).Return(types.Standard{ID: 1}, nil)
+	suite.mockLevelRepo.On("GetByIDRequirementLevel", mock.Anything, types.RequirementLevel{ID: 5}).
+		Return(types.RequirementLevel{ID: 5, StandardID: 2, LevelOrder: 1}, nil)
+
+	_, err := suite.service.Create(context.Background(), types.Requirement{StandardID: 1, LevelID: 5, Name: "Scope"})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+}
+
+func (suite *RequirementServiceSuite) TestCreate_ParentInOtherStandard_ReturnsConflict() {
+	suite.mockStandardRepo.On("GetByIDStandard", mock.Anything, types.Standard{ID: 1}).Return(types.Standard{ID: 1}, nil)
+	suite.mockRepo.On("GetByIDRequirement", mock.Anything, types.Requirement{ID: 99}).
+		Return(types.Requirement{ID: 99, StandardID: 2}, nil)
+
+	_, err := suite.service.Create(context.Background(), types.Requirement{StandardID: 1, ParentID: 99, Name: "Scope"})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (suite *RequirementServiceSuite) TestReorder_PublishesEventPerChild() {
+	suite.mockStandardRepo.On("GetByIDStandard", mock.Anything, types.Standard{ID: 1}).Return(types.Standard{ID: 1}, nil)
+	suite.mockRepo.On("GetByIDRequirement", mock.Anything, types.Requirement{ID: 10}).Return(flatRequirements[0], nil)
+	suite.mockRepo.On("ReorderRequirements", mock.Anything, 1, 10, []int{12, 11}).Return(nil)
+
+	err := suite.service.Reorder(context.Background(), 1, 10, []int{12, 11})
+
+	suite.NoError(err)
+	for range 2 {
+		payload := suite.nextEvent()
+		suite.Equal(events.EntityStandard, payload.ParentType)
+		suite.Equal(1, payload.ParentID)
+	}
+}
+
+func TestRequirementService(t *testing.T) {
+	suite.Run(t, new(RequirementServiceSuite))
+}
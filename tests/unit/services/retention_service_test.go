@@ -0,0 +1,118 @@
+package services_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/storage"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RetentionServiceSuite struct {
+	suite.Suite
+	mockProvidedRepo *MockEvidenceProvidedRepository
+	blobs            storage.BlobStore
+	now              time.Time
+	service          *services.RetentionService
+}
+
+func (suite *RetentionServiceSuite) SetupTest() {
+	suite.mockProvidedRepo = new(MockEvidenceProvidedRepository)
+
+	referenceRepo := new(MockReferenceDataRepository)
+	referenceRepo.On("GetAllReferenceTypes", mock.Anything).Return(providedReferenceTypes, nil)
+	referenceRepo.On("GetAllReferenceValues", mock.Anything).Return(providedReferenceValues, nil)
+	referenceData := services.NewReferenceDataService(referenceRepo, nil)
+
+	blobs, err := storage.NewLocalBlobStore(suite.T().TempDir())
+	suite.Require().NoError(err)
+	suite.blobs = blobs
+
+	suite.now = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	suite.service = services.NewRetentionService(
+		suite.mockProvidedRepo, referenceData, blobs, 30, time.Hour, func() time.Time { return suite.now }, nil,
+	)
+}
+
+func (suite *RetentionServiceSuite) TearDownTest() {
+	suite.mockProvidedRepo.AssertExpectations(suite.T())
+}
+
+// expectDue returns provided evidence 7, expired 10 days ago and still PENDING, and evidence 8,
+// expired 40 days ago, already EXPIRED and with a stored file
+func (suite *RetentionServiceSuite) expectDue() (types.EvidenceProvided, types.EvidenceProvided) {
+	recent := types.EvidenceProvided{
+		ID: 7, AuditQuestionID: 100, StatusVal: types.ReferenceValue{ID: 53},
+		ExpiresAt: suite.now.AddDate(0, 0, -10),
+	}
+	old := types.EvidenceProvided{
+		ID: 8, AuditQuestionID: 100, StatusVal: types.ReferenceValue{ID: 57},
+		ExpiresAt: suite.now.AddDate(0, 0, -40),
+		File:      &types.EvidenceFile{Key: "evidence/8/abc", Name: "minutes.pdf"},
+	}
+	suite.mockProvidedRepo.On("GetExpiredEvidenceProvided", mock.Anything, suite.now).Return([]types.EvidenceProvided{recent, old}, nil)
+	return recent, old
+}
+
+func (suite *RetentionServiceSuite) TestReport_ChangesNothing() {
+	suite.expectDue()
+
+	report, err := suite.service.Report(context.Background())
+
+	suite.NoError(err)
+	suite.True(report.DryRun)
+	suite.Len(report.Expire, 1)
+	suite.Equal(7, report.Expire[0].EvidenceProvidedID)
+	suite.Len(report.Purge, 1)
+	suite.Equal(8, report.Purge[0].EvidenceProvidedID)
+	suite.True(report.Purge[0].HasFile)
+	suite.Equal(suite.now.AddDate(0, 0, -10), report.Purge[0].PurgeAfter)
+}
+
+func (suite *RetentionServiceSuite) TestSweep_WithoutApproval_OnlyExpires() {
+	ctx := context.Background()
+	suite.expectDue()
+	suite.mockProvidedRepo.On("UpdateEvidenceProvidedStatus", ctx, 7, 53, 57).Return(nil)
+
+	report, err := suite.service.Sweep(ctx, 0, nil)
+
+	suite.NoError(err)
+	suite.False(report.DryRun)
+	suite.Len(report.Expire, 1)
+	suite.Empty(report.Purge)
+}
+
+func (suite *RetentionServiceSuite) TestSweep_PurgesApprovedFile() {
+	ctx := context.Background()
+	_, old := suite.expectDue()
+	suite.mockProvidedRepo.On("UpdateEvidenceProvidedStatus", ctx, 7, 53, 57).Return(nil)
+	suite.mockProvidedRepo.On("GetByIDEvidenceProvided", ctx, types.EvidenceProvided{ID: 8}).Return(old, nil)
+	suite.mockProvidedRepo.On("PurgeEvidenceProvided", ctx, 8).Return(nil)
+	suite.Require().NoError(suite.blobs.Put(ctx, "evidence/8/abc", strings.NewReader("minutes")))
+
+	// 7 is not past its grace period and is skipped
+	report, err := suite.service.Sweep(ctx, 5, []int{7, 8})
+
+	suite.NoError(err)
+	suite.Equal(5, report.ApprovedBy)
+	suite.Len(report.Purge, 1)
+	suite.Empty(report.Purge[0].Error)
+	_, err = suite.blobs.Get(ctx, "evidence/8/abc")
+	suite.ErrorIs(err, storage.ErrBlobNotFound)
+}
+
+func (suite *RetentionServiceSuite) TestSweep_PurgeWithoutApprover_ReturnsInvalidData() {
+	_, err := suite.service.Sweep(context.Background(), 0, []int{8})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+}
+
+func TestRetentionServiceSuite(t *testing.T) {
+	suite.Run(t, new(RetentionServiceSuite))
+}
@@ -0,0 +1,153 @@
+package services_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/oidc"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"ISO_Auditing_Tool/tests/testutils"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+var ssoReferenceValues = append([]types.ReferenceValue{
+	{ID: 4, TypeID: 1, Code: "VIEWER", IsActive: true},
+}, userReferenceValues...)
+
+type SSOServiceSuite struct {
+	suite.Suite
+	provider     *testutils.OIDCProvider
+	mockUsers    *MockUserRepository
+	mockSessions *MockSessionRepository
+	service      *services.SSOService
+}
+
+func (suite *SSOServiceSuite) SetupTest() {
+	suite.provider = testutils.NewOIDCProvider(suite.T())
+	suite.mockUsers = new(MockUserRepository)
+	suite.mockSessions = new(MockSessionRepository)
+
+	referenceRepo := new(MockReferenceDataRepository)
+	referenceRepo.On("GetAllReferenceTypes", mock.Anything).Return(userReferenceTypes, nil).Maybe()
+	referenceRepo.On("GetAllReferenceValues", mock.Anything).Return(ssoReferenceValues, nil).Maybe()
+	referenceData := services.NewReferenceDataService(referenceRepo, nil)
+
+	client, err := oidc.NewClient(oidc.Config{
+		IssuerURL:    suite.provider.Issuer(),
+		ClientID:     suite.provider.ClientID,
+		ClientSecret: suite.provider.ClientSecret,
+		RedirectURL:  "http://localhost:8080/web/sso/callback",
+	})
+	suite.Require().NoError(err)
+
+	auth := services.NewAuthService(suite.mockUsers, suite.mockSessions, referenceData, time.Hour, nil, nil)
+	groupRoles := map[string]string{"iso-admins": "ADMIN", "iso-leads": "LEAD"}
+	suite.service = services.NewSSOService(client, groupRoles, false, suite.mockUsers, referenceData, auth, nil)
+}
+
+func (suite *SSOServiceSuite) TearDownTest() {
+	suite.mockUsers.AssertExpectations(suite.T())
+	suite.mockSessions.AssertExpectations(suite.T())
+}
+
+// signIn sends the user through the stand-in provider and completes the sign in
+func (suite *SSOServiceSuite) signIn() (types.Session, error) {
+	attempt, err := suite.service.Begin(context.Background())
+	suite.Require().NoError(err)
+
+	back := suite.provider.Authorize(suite.T(), attempt.URL)
+	return suite.service.Complete(context.Background(), attempt, back.Query().Get("state"), back.Query().Get("code"))
+}
+
+func (suite *SSOServiceSuite) expectSession(userID int) {
+	suite.mockSessions.On("DeleteExpiredSessions", mock.Anything, mock.Anything).Return(nil)
+	suite.mockSessions.On("CreateSession", mock.Anything, userID, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+	suite.mockUsers.On("UpdateUserLastLogin", mock.Anything, userID).Return(nil)
+}
+
+func (suite *SSOServiceSuite) TestComplete_ExistingUserByEmail() {
+	user := types.User{ID: 7, Email: "alice@example.com", RoleVal: types.ReferenceValue{ID: 3}, IsActive: true}
+	suite.mockUsers.On("GetCredentialsUser", mock.Anything, "alice@example.com").Return(user, "", nil)
+	suite.expectSession(7)
+
+	session, err := suite.signIn()
+
+	suite.NoError(err)
+	suite.Equal(7, session.User.ID)
+	suite.Equal("SUPPORT", session.User.RoleVal.Code)
+	suite.mockUsers.AssertNotCalled(suite.T(), "UpdateUserRole", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *SSOServiceSuite) TestComplete_GroupsMapToHighestRole() {
+	suite.provider.User.Groups = []string{"staff", "iso-leads", "iso-admins"}
+	user := types.User{ID: 7, Email: "alice@example.com", RoleVal: types.ReferenceValue{ID: 3}, IsActive: true}
+	suite.mockUsers.On("GetCredentialsUser", mock.Anything, "alice@example.com").Return(user, "", nil)
+	suite.mockUsers.On("UpdateUserRole", mock.Anything, 7, 1).Return(nil)
+	suite.expectSession(7)
+
+	session, err := suite.signIn()
+
+	suite.NoError(err)
+	suite.Equal("ADMIN", session.User.RoleVal.Code)
+}
+
+func (suite *SSOServiceSuite) TestComplete_UnknownEmailWithoutProvisioning() {
+	suite.mockUsers.On("GetCredentialsUser", mock.Anything, "alice@example.com").
+		Return(types.User{}, "", custom_errors.NotFound(context.Background(), "User"))
+
+	_, err := suite.signIn()
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeUnauthorized))
+	suite.mockUsers.AssertNotCalled(suite.T(), "CreateUser", mock.Anything, mock.Anything)
+}
+
+func (suite *SSOServiceSuite) TestComplete_ProvisionsViewer() {
+	suite.service.ProvisionUsers = true
+	suite.mockUsers.On("GetCredentialsUser", mock.Anything, "alice@example.com").
+		Return(types.User{}, "", custom_errors.NotFound(context.Background(), "User"))
+	expected := types.User{Email: "alice@example.com", Name: "Alice", RoleVal: types.ReferenceValue{ID: 4}}
+	created := types.User{ID: 9, Email: "alice@example.com", Name: "Alice", RoleVal: types.ReferenceValue{ID: 4}, IsActive: true}
+	suite.mockUsers.On("CreateUser", mock.Anything, expected).Return(created, nil)
+	suite.expectSession(9)
+
+	session, err := suite.signIn()
+
+	suite.NoError(err)
+	suite.Equal("VIEWER", session.User.RoleVal.Code)
+}
+
+func (suite *SSOServiceSuite) TestComplete_UnverifiedEmail() {
+	suite.provider.User.EmailVerified = false
+
+	_, err := suite.signIn()
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeUnauthorized))
+}
+
+func (suite *SSOServiceSuite) TestComplete_EmailNotSaidToBeVerified() {
+	suite.provider.Claims = map[string]any{"email_verified": nil}
+
+	_, err := suite.signIn()
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeUnauthorized))
+}
+
+func (suite *SSOServiceSuite) TestComplete_StateOfAnotherAttempt() {
+	attempt, err := suite.service.Begin(context.Background())
+	suite.Require().NoError(err)
+	back := suite.provider.Authorize(suite.T(), attempt.URL)
+
+	other, err := suite.service.Begin(context.Background())
+	suite.Require().NoError(err)
+	_, err = suite.service.Complete(context.Background(), other, back.Query().Get("state"), back.Query().Get("code"))
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeUnauthorized))
+}
+
+func TestSSOServiceSuite(t *testing.T) {
+	suite.Run(t, new(SSOServiceSuite))
+}
@@ -0,0 +1,223 @@
+package services_test
+
+import (
+	"ISO_Auditing_Tool/pkg/custom_errors"
+	"ISO_Auditing_Tool/pkg/services"
+	"ISO_Auditing_Tool/pkg/types"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) GetAllUsers(ctx context.Context, filter types.UserFilter) ([]types.User, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]types.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByIDUser(ctx context.Context, user types.User) (types.User, error) {
+	args := m.Called(ctx, user)
+	return args.Get(0).(types.User), args.Error(1)
+}
+
+func (m *MockUserRepository) CreateUser(ctx context.Context, user types.User) (types.User, error) {
+	args := m.Called(ctx, user)
+	return args.Get(0).(types.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetCredentialsUser(ctx context.Context, email string) (types.User, string, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).(types.User), args.String(1), args.Error(2)
+}
+
+func (m *MockUserRepository) UpdateUserPassword(ctx context.Context, id int, passwordHash string) error {
+	args := m.Called(ctx, id, passwordHash)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateUserLastLogin(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateUserRole(ctx context.Context, id, roleID int) error {
+	args := m.Called(ctx, id, roleID)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ReactivateUser(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) DeactivateUser(ctx context.Context, id, reassignTo int, openStatusIDs []int) (types.UserDeactivation, error) {
+	args := m.Called(ctx, id, reassignTo, openStatusIDs)
+	return args.Get(0).(types.UserDeactivation), args.Error(1)
+}
+
+var (
+	userReferenceTypes = []types.ReferenceType{
+		{ID: 1, Name: "users.role_id"},
+		{ID: 6, Name: "findings.status_id"},
+	}
+	userReferenceValues = []types.ReferenceValue{
+		{ID: 1, TypeID: 1, Code: "ADMIN", IsActive: true},
+		{ID: 2, TypeID: 1, Code: "LEAD", IsActive: true},
+		{ID: 3, TypeID: 1, Code: "SUPPORT", IsActive: true},
+		{ID: 5, TypeID: 1, Code: "LEGACY", IsActive: false},
+		{ID: 30, TypeID: 6, Code: "OPEN", IsActive: true},
+		{ID: 31, TypeID: 6, Code: "IN_PROGRESS", IsActive: true},
+		{ID: 32, TypeID: 6, Code: "PENDING_REVIEW", IsActive: true},
+		{ID: 33, TypeID: 6, Code: "CLOSED", IsActive: true},
+	}
+)
+
+type UserServiceSuite struct {
+	suite.Suite
+	mockRepo *MockUserRepository
+	service  *services.UserService
+}
+
+func (suite *UserServiceSuite) SetupTest() {
+	suite.mockRepo = new(MockUserRepository)
+
+	referenceRepo := new(MockReferenceDataRepository)
+	referenceRepo.On("GetAllReferenceTypes", mock.Anything).Return(userReferenceTypes, nil).Maybe()
+	referenceRepo.On("GetAllReferenceValues", mock.Anything).Return(userReferenceValues, nil).Maybe()
+	referenceData := services.NewReferenceDataService(referenceRepo, nil)
+
+	suite.service = services.NewUserService(suite.mockRepo, referenceData, nil)
+}
+
+func (suite *UserServiceSuite) TearDownTest() {
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *UserServiceSuite) expectUser(id, roleID int, active bool) {
+	user := types.User{ID: id, Email: "alice@example.com", Name: "Alice", RoleVal: types.ReferenceValue{ID: roleID}, IsActive: active}
+	suite.mockRepo.On("GetByIDUser", mock.Anything, types.User{ID: id}).Return(user, nil)
+}
+
+func (suite *UserServiceSuite) TestCreate_NormalizesEmailAndHydratesRole() {
+	expected := types.User{Email: "alice@example.com", Name: "Alice", RoleVal: userReferenceValues[1]}
+	created := types.User{ID: 7, Email: "alice@example.com", Name: "Alice", RoleVal: types.ReferenceValue{ID: 2}, IsActive: true}
+	suite.mockRepo.On("CreateUser", mock.Anything, expected).Return(created, nil)
+
+	user, err := suite.service.Create(context.Background(), types.UserForm{Email: " Alice@Example.com ", Name: "Alice", RoleID: 2})
+
+	suite.NoError(err)
+	suite.Equal(7, user.ID)
+	suite.Equal("LEAD", user.RoleVal.Code)
+}
+
+func (suite *UserServiceSuite) TestCreate_RejectsRoleOfAnotherType() {
+	_, err := suite.service.Create(context.Background(), types.UserForm{Email: "alice@example.com", Name: "Alice", RoleID: 30})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+	suite.mockRepo.AssertNotCalled(suite.T(), "CreateUser", mock.Anything, mock.Anything)
+}
+
+func (suite *UserServiceSuite) TestCreate_RejectsInactiveRole() {
+	_, err := suite.service.Create(context.Background(), types.UserForm{Email: "alice@example.com", Name: "Alice", RoleID: 5})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+}
+
+func (suite *UserServiceSuite) TestCreate_DuplicateEmail() {
+	conflict := custom_errors.Conflict(context.Background(), "User", "with email alice@example.com already exists")
+	suite.mockRepo.On("CreateUser", mock.Anything, mock.Anything).Return(types.User{}, conflict)
+
+	_, err := suite.service.Create(context.Background(), types.UserForm{Email: "alice@example.com", Name: "Alice", RoleID: 2})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (suite *UserServiceSuite) TestChangeRole() {
+	suite.expectUser(7, 2, true)
+	suite.mockRepo.On("UpdateUserRole", mock.Anything, 7, 3).Return(nil)
+
+	user, err := suite.service.ChangeRole(context.Background(), 7, 3)
+
+	suite.NoError(err)
+	suite.Equal("SUPPORT", user.RoleVal.Code)
+}
+
+func (suite *UserServiceSuite) TestChangeRole_SameRoleChangesNothing() {
+	suite.expectUser(7, 2, true)
+
+	user, err := suite.service.ChangeRole(context.Background(), 7, 2)
+
+	suite.NoError(err)
+	suite.Equal("LEAD", user.RoleVal.Code)
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateUserRole", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *UserServiceSuite) TestDeactivate_ReassignsOpenFindings() {
+	suite.expectUser(7, 2, true)
+	suite.expectUser(8, 3, true)
+	report := types.UserDeactivation{ReassignTo: 8, Reassigned: []int{40, 41}, Flagged: []int{}}
+	suite.mockRepo.On("DeactivateUser", mock.Anything, 7, 8, []int{30, 31, 32}).Return(report, nil)
+
+	result, err := suite.service.Deactivate(context.Background(), 7, types.UserDeactivationForm{ReassignTo: 8})
+
+	suite.NoError(err)
+	suite.Equal([]int{40, 41}, result.Reassigned)
+	suite.Equal(7, result.User.ID)
+	suite.False(result.User.IsActive)
+}
+
+func (suite *UserServiceSuite) TestDeactivate_FlagsOpenFindingsWithoutReassignee() {
+	suite.expectUser(7, 2, true)
+	report := types.UserDeactivation{Reassigned: []int{}, Flagged: []int{40}}
+	suite.mockRepo.On("DeactivateUser", mock.Anything, 7, 0, []int{30, 31, 32}).Return(report, nil)
+
+	result, err := suite.service.Deactivate(context.Background(), 7, types.UserDeactivationForm{})
+
+	suite.NoError(err)
+	suite.Equal([]int{40}, result.Flagged)
+}
+
+func (suite *UserServiceSuite) TestDeactivate_RejectsInactiveReassignee() {
+	suite.expectUser(7, 2, true)
+	suite.expectUser(8, 3, false)
+
+	_, err := suite.service.Deactivate(context.Background(), 7, types.UserDeactivationForm{ReassignTo: 8})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+	suite.mockRepo.AssertNotCalled(suite.T(), "DeactivateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *UserServiceSuite) TestDeactivate_RejectsReassigningToSelf() {
+	suite.expectUser(7, 2, true)
+
+	_, err := suite.service.Deactivate(context.Background(), 7, types.UserDeactivationForm{ReassignTo: 7})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeInvalidData))
+}
+
+func (suite *UserServiceSuite) TestDeactivate_AlreadyInactive() {
+	suite.expectUser(7, 2, false)
+
+	_, err := suite.service.Deactivate(context.Background(), 7, types.UserDeactivationForm{})
+
+	suite.True(custom_errors.IsErrorCode(err, custom_errors.ErrCodeConflict))
+}
+
+func (suite *UserServiceSuite) TestReactivate() {
+	suite.expectUser(7, 2, false)
+	suite.mockRepo.On("ReactivateUser", mock.Anything, 7).Return(nil)
+
+	user, err := suite.service.Reactivate(context.Background(), 7)
+
+	suite.NoError(err)
+	suite.True(user.IsActive)
+}
+
+func TestUserServiceSuite(t *testing.T) {
+	suite.Run(t, new(UserServiceSuite))
+}
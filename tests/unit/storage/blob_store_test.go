@@ -0,0 +1,186 @@
+package storage_test
+
+import (
+	"ISO_Auditing_Tool/pkg/storage"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// s3StandIn is an in-memory stand-in for an S3 compatible server. It records the requests it
+// receives and serves path style object URLs of one bucket.
+type s3StandIn struct {
+	mu       sync.Mutex
+	objects  map[string][]byte
+	requests []*http.Request
+}
+
+func newS3StandIn(t *testing.T) (*s3StandIn, *httptest.Server) {
+	standIn := &s3StandIn{objects: make(map[string][]byte)}
+	server := httptest.NewServer(standIn)
+	t.Cleanup(server.Close)
+	return standIn, server
+}
+
+func (s *s3StandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r)
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=test-key/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		if r.ContentLength < 0 {
+			w.WriteHeader(http.StatusLengthRequired)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		s.objects[r.URL.Path] = body
+	case http.MethodGet:
+		body, ok := s.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(body)
+	case http.MethodDelete:
+		delete(s.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func newS3Store(t *testing.T, endpoint string) storage.BlobStore {
+	store, err := storage.NewS3BlobStore(storage.S3Config{
+		Endpoint: endpoint, Region: "eu-west-1", Bucket: "evidence",
+		AccessKeyID: "test-key", SecretAccessKey: "test-secret",
+	})
+	require.NoError(t, err)
+	return store
+}
+
+func TestS3BlobStore_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	standIn, server := newS3StandIn(t)
+	store := newS3Store(t, server.URL)
+
+	require.NoError(t, store.Put(ctx, "evidence/7/abc", strings.NewReader("minutes")))
+	assert.Equal(t, []byte("minutes"), standIn.objects["/evidence/evidence/7/abc"])
+
+	put := standIn.requests[0]
+	assert.Equal(t, int64(7), put.ContentLength)
+	assert.Equal(t, "UNSIGNED-PAYLOAD", put.Header.Get("x-amz-content-sha256"))
+	assert.Contains(t, put.Header.Get("Authorization"), "/eu-west-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=")
+
+	content, err := store.Get(ctx, "evidence/7/abc")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(content)
+	content.Close()
+	assert.Equal(t, "minutes", string(data))
+
+	require.NoError(t, store.Delete(ctx, "evidence/7/abc"))
+	_, err = store.Get(ctx, "evidence/7/abc")
+	assert.ErrorIs(t, err, storage.ErrBlobNotFound)
+}
+
+func TestS3BlobStore_EscapesKeys(t *testing.T) {
+	standIn, server := newS3StandIn(t)
+	store := newS3Store(t, server.URL)
+
+	require.NoError(t, store.Put(context.Background(), "evidence/7/report 2026+final.pdf", strings.NewReader("x")))
+
+	assert.Equal(t, "/evidence/evidence/7/report%202026%2Bfinal.pdf", standIn.requests[0].URL.EscapedPath())
+}
+
+func TestS3BlobStore_StalledEndpoint_TimesOut(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	store, err := storage.NewS3BlobStore(storage.S3Config{
+		Endpoint: server.URL, Bucket: "evidence", Timeout: 50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := store.Get(context.Background(), "evidence/7/abc")
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		assert.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Get did not give up on a stalled endpoint")
+	}
+}
+
+func TestLocalBlobStore_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store, err := storage.NewLocalBlobStore(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put(ctx, "evidence/7/abc", strings.NewReader("minutes")))
+
+	content, err := store.Get(ctx, "evidence/7/abc")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(content)
+	content.Close()
+	assert.Equal(t, "minutes", string(data))
+
+	require.NoError(t, store.Delete(ctx, "evidence/7/abc"))
+	_, err = store.Get(ctx, "evidence/7/abc")
+	assert.ErrorIs(t, err, storage.ErrBlobNotFound)
+}
+
+func TestLocalBlobStore_RejectsEscapingKeys(t *testing.T) {
+	store, err := storage.NewLocalBlobStore(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"../outside", "/etc/passwd", "evidence/../../outside", ""} {
+		assert.Error(t, store.Put(context.Background(), key, strings.NewReader("x")), key)
+	}
+}
+
+func TestPutWithDigest_HashesAndSniffs(t *testing.T) {
+	store, err := storage.NewLocalBlobStore(t.TempDir())
+	require.NoError(t, err)
+	content := "\x89PNG\r\n\x1a\n" + strings.Repeat("p", 4096)
+	sum := sha256.Sum256([]byte(content))
+
+	digest, err := storage.PutWithDigest(context.Background(), store, "evidence/7/png", strings.NewReader(content), 1<<20)
+
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(sum[:]), digest.SHA256)
+	assert.Equal(t, int64(len(content)), digest.Size)
+	assert.Equal(t, "image/png", digest.MIMEType)
+}
+
+func TestPutWithDigest_TooLarge_LeavesNothing(t *testing.T) {
+	ctx := context.Background()
+	store, err := storage.NewLocalBlobStore(t.TempDir())
+	require.NoError(t, err)
+
+	_, err = storage.PutWithDigest(ctx, store, "evidence/7/big", strings.NewReader(strings.Repeat("x", 100)), 10)
+
+	assert.ErrorIs(t, err, storage.ErrBlobTooLarge)
+	_, err = store.Get(ctx, "evidence/7/big")
+	assert.ErrorIs(t, err, storage.ErrBlobNotFound)
+}
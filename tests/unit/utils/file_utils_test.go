@@ -31,12 +31,12 @@ func (suite *TestFileUtils) checkFilesForMigration(file string, direction string
 }
 
 func (suite *TestFileUtils) TestNoFileWithUp_ReturnsAllUpFiles() {
-	output := []string{"001_base_tables.up.sql", "002_base_tables.up.sql"}
+	output := []string{"001_base_tables.up.sql", "002_requirement_sort_order.up.sql", "003_question_evidence_order.up.sql", "004_requirement_level_order.up.sql", "005_audit_content_draft_type.up.sql", "006_audit_plan_scope.up.sql", "007_evidence_provided_audit_question.up.sql", "008_audit_support_auditor_unique.up.sql", "009_corrective_action_workflow.up.sql", "010_evidence_provided_files.up.sql", "011_evidence_access_grants.up.sql", "012_evidence_review.up.sql", "013_comment_threads.up.sql", "014_finding_needs_owner.up.sql", "015_user_sessions.up.sql", "016_api_tokens.up.sql", "017_activity_log.up.sql", "018_draft_transitions.up.sql", "019_draft_transitions_restrict.up.sql"}
 	suite.checkFilesForMigration("", "up", output)
 }
 
 func (suite *TestFileUtils) TestNoFileWithDown_ReturnsDownUpFiles() {
-	output := []string{"001_base_tables.down.sql", "002_base_tables.down.sql"}
+	output := []string{"001_base_tables.down.sql", "002_requirement_sort_order.down.sql", "003_question_evidence_order.down.sql", "004_requirement_level_order.down.sql", "005_audit_content_draft_type.down.sql", "006_audit_plan_scope.down.sql", "007_evidence_provided_audit_question.down.sql", "008_audit_support_auditor_unique.down.sql", "009_corrective_action_workflow.down.sql", "010_evidence_provided_files.down.sql", "011_evidence_access_grants.down.sql", "012_evidence_review.down.sql", "013_comment_threads.down.sql", "014_finding_needs_owner.down.sql", "015_user_sessions.down.sql", "016_api_tokens.down.sql", "017_activity_log.down.sql", "018_draft_transitions.down.sql", "019_draft_transitions_restrict.down.sql"}
 	suite.checkFilesForMigration("", "down", output)
 }
 